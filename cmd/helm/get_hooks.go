@@ -68,7 +68,7 @@ func newGetHooksCmd(client helm.Interface, out io.Writer) *cobra.Command {
 }
 
 func (g *getHooksCmd) run() error {
-	res, err := g.client.ReleaseContent(g.release, helm.ContentReleaseVersion(g.version))
+	res, err := g.client.ReleaseContent(g.release, helm.ContentReleaseVersion(g.version), helm.ContentExcludeChart(true))
 	if err != nil {
 		fmt.Fprintln(g.out, g.release)
 		return prettyError(err)
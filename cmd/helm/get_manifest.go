@@ -72,7 +72,7 @@ func newGetManifestCmd(client helm.Interface, out io.Writer) *cobra.Command {
 
 // getManifest implements 'helm get manifest'
 func (g *getManifestCmd) run() error {
-	res, err := g.client.ReleaseContent(g.release, helm.ContentReleaseVersion(g.version))
+	res, err := g.client.ReleaseContent(g.release, helm.ContentReleaseVersion(g.version), helm.ContentExcludeChart(true))
 	if err != nil {
 		return prettyError(err)
 	}
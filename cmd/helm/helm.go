@@ -171,6 +171,7 @@ Environment:
 - $HELM_TLS_ENABLE:     Enable TLS connection between Helm and Tiller (default "false")
 - $HELM_TLS_VERIFY:     Enable TLS connection between Helm and Tiller and verify Tiller server certificate (default "false")
 - $HELM_TLS_HOSTNAME:   The hostname or IP address used to verify the Tiller server certificate (default "127.0.0.1")
+- $HELM_TILLER_AUTH_TOKEN: Bearer token sent to Tiller when it was started with --auth-token-file
 - $HELM_KEY_PASSPHRASE: Set HELM_KEY_PASSPHRASE to the passphrase of your PGP private key. If set, you will not be prompted for the passphrase while signing helm charts
 
 `
@@ -369,6 +370,9 @@ func ensureHelmClient(h helm.Interface) helm.Interface {
 
 func newClient() helm.Interface {
 	options := []helm.Option{helm.Host(settings.TillerHost), helm.ConnectTimeout(settings.TillerConnectionTimeout)}
+	if token := os.Getenv("HELM_TILLER_AUTH_TOKEN"); token != "" {
+		options = append(options, helm.WithAuthToken(token))
+	}
 
 	if settings.TLSVerify || settings.TLSEnable {
 		debug("Host=%q, Key=%q, Cert=%q, CA=%q\n", settings.TLSServerName, settings.TLSKeyFile, settings.TLSCertFile, settings.TLSCaCertFile)
@@ -79,6 +79,8 @@ type listCmd struct {
 	colWidth    uint
 	output      string
 	byChartName bool
+	chart       string
+	chartVer    string
 }
 
 type listResult struct {
@@ -136,6 +138,8 @@ func newListCmd(client helm.Interface, out io.Writer) *cobra.Command {
 	f.UintVar(&list.colWidth, "col-width", 60, "Specifies the max column width of output")
 	f.StringVar(&list.output, "output", "", "Output the specified format (json or yaml)")
 	f.BoolVarP(&list.byChartName, "chart-name", "c", false, "Sort by chart name")
+	f.StringVar(&list.chart, "chart", "", "Show releases deployed from a chart of this name")
+	f.StringVar(&list.chartVer, "chart-version", "", "Show releases deployed from this chart version")
 
 	// TODO: Do we want this as a feature of 'helm list'?
 	//f.BoolVar(&list.superseded, "history", true, "show historical releases")
@@ -170,6 +174,7 @@ func (l *listCmd) run() error {
 		helm.ReleaseListOrder(int32(sortOrder)),
 		helm.ReleaseListStatuses(stats),
 		helm.ReleaseListNamespace(l.namespace),
+		helm.ReleaseListChart(l.chart, l.chartVer),
 	)
 
 	if err != nil {
@@ -48,6 +48,7 @@ type rollbackCmd struct {
 	wait          bool
 	description   string
 	cleanupOnFail bool
+	reuseValues   bool
 }
 
 func newRollbackCmd(c helm.Interface, out io.Writer) *cobra.Command {
@@ -89,6 +90,7 @@ func newRollbackCmd(c helm.Interface, out io.Writer) *cobra.Command {
 	f.BoolVar(&rollback.wait, "wait", false, "If set, will wait until all Pods, PVCs, Services, and minimum number of Pods of a Deployment are in a ready state before marking the release as successful. It will wait for as long as --timeout")
 	f.StringVar(&rollback.description, "description", "", "Specify a description for the release")
 	f.BoolVar(&rollback.cleanupOnFail, "cleanup-on-fail", false, "Allow deletion of new resources created in this rollback when rollback failed")
+	f.BoolVar(&rollback.reuseValues, "reuse-values", false, "Keep the current release's values, merged over those of the revision rolled back to, and render that revision's chart with them")
 
 	// set defaults from environment
 	settings.InitTLS(f)
@@ -107,7 +109,8 @@ func (r *rollbackCmd) run() error {
 		helm.RollbackTimeout(r.timeout),
 		helm.RollbackWait(r.wait),
 		helm.RollbackDescription(r.description),
-		helm.RollbackCleanupOnFail(r.cleanupOnFail))
+		helm.RollbackCleanupOnFail(r.cleanupOnFail),
+		helm.RollbackReuseValues(r.reuseValues))
 	if err != nil {
 		return prettyError(err)
 	}
@@ -51,6 +51,12 @@ func TestRollbackCmd(t *testing.T) {
 			flags:    []string{"--description", "foo"},
 			expected: "Rollback was a success.",
 		},
+		{
+			name:     "rollback a release reusing values",
+			args:     []string{"funny-honey", "1"},
+			flags:    []string{"--reuse-values"},
+			expected: "Rollback was a success.",
+		},
 		{
 			name: "rollback a release without revision",
 			args: []string{"funny-honey"},
@@ -113,6 +113,7 @@ type upgradeCmd struct {
 	subNotes      bool
 	description   string
 	cleanupOnFail bool
+	restart       bool
 
 	certFile string
 	keyFile  string
@@ -182,6 +183,7 @@ func newUpgradeCmd(client helm.Interface, out io.Writer) *cobra.Command {
 	f.BoolVar(&upgrade.subNotes, "render-subchart-notes", false, "Render subchart notes along with parent")
 	f.StringVar(&upgrade.description, "description", "", "Specify the description to use for the upgrade, rather than the default")
 	f.BoolVar(&upgrade.cleanupOnFail, "cleanup-on-fail", false, "Allow deletion of new resources created in this upgrade when upgrade failed")
+	f.BoolVar(&upgrade.restart, "restart", false, "Roll the Deployments and StatefulSets of the release as part of the upgrade, even if their pod templates did not change. With --wait, waits for the new pods")
 	bindOutputFlag(cmd, &upgrade.output)
 
 	f.MarkDeprecated("disable-hooks", "Use --no-hooks instead")
@@ -280,7 +282,8 @@ func (u *upgradeCmd) run() error {
 		helm.UpgradeSubNotes(u.subNotes),
 		helm.UpgradeWait(u.wait),
 		helm.UpgradeDescription(u.description),
-		helm.UpgradeCleanupOnFail(u.cleanupOnFail))
+		helm.UpgradeCleanupOnFail(u.cleanupOnFail),
+		helm.UpgradeRestart(u.restart))
 	if err != nil {
 		fmt.Fprintf(u.out, "UPGRADE FAILED\nError: %v\n", prettyError(err))
 		if u.atomic {
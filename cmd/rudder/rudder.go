@@ -109,7 +109,7 @@ func (r *ReleaseModuleServiceServer) DeleteRelease(ctx context.Context, in *rudd
 		return resp, fmt.Errorf("Could not get apiVersions from Kubernetes: %v", err)
 	}
 
-	kept, errs := tiller.DeleteRelease(rel, vs, kubeClient)
+	kept, keptResources, errs := tiller.DeleteRelease(rel, vs, kubeClient)
 	rel.Manifest = kept
 
 	allErrors := ""
@@ -122,7 +122,8 @@ func (r *ReleaseModuleServiceServer) DeleteRelease(ctx context.Context, in *rudd
 	}
 
 	return &rudderAPI.DeleteReleaseResponse{
-		Release: rel,
+		Release:       rel,
+		KeptResources: keptResources,
 	}, err
 }
 
@@ -0,0 +1,171 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+type logLevel int
+
+const (
+	levelDebug logLevel = iota
+	levelInfo
+	levelWarn
+	levelError
+)
+
+var logLevelNames = []string{"debug", "info", "warn", "error"}
+
+func (l logLevel) String() string { return logLevelNames[l] }
+
+func parseLogLevel(s string) (logLevel, error) {
+	for i, name := range logLevelNames {
+		if s == name {
+			return logLevel(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown log level %q, expected one of %s", s, strings.Join(logLevelNames, ", "))
+}
+
+// logConfig holds the settings shared by every logger created by newLogger.
+type logConfig struct {
+	out   io.Writer
+	json  bool
+	level logLevel
+	mu    sync.Mutex // serializes JSON entries written to out
+}
+
+var logging = &logConfig{out: os.Stderr, level: levelInfo}
+
+// configureLogging applies the --log-format and --log-level flags.
+func configureLogging(format, level string) error {
+	switch format {
+	case "text":
+		logging.json = false
+	case "json":
+		logging.json = true
+	default:
+		return fmt.Errorf("unknown log format %q, expected text or json", format)
+	}
+	l, err := parseLogLevel(level)
+	if err != nil {
+		return err
+	}
+	logging.level = l
+	return nil
+}
+
+// leveledLogger writes the messages of one of Tiller's components, either in
+// the standard log format or as one JSON object per line.
+type leveledLogger struct {
+	component string
+	text      *log.Logger
+	config    *logConfig
+}
+
+type logEntry struct {
+	Time      string `json:"time"`
+	Level     string `json:"level"`
+	Component string `json:"component,omitempty"`
+	Message   string `json:"msg"`
+}
+
+func (l *leveledLogger) output(level logLevel, msg string) {
+	if level < l.config.level {
+		return
+	}
+	if !l.config.json {
+		// Skip output and its caller, so that Lshortfile reports the line
+		// that logged the message.
+		l.text.Output(3, msg)
+		return
+	}
+	b, err := json.Marshal(logEntry{
+		Time:      time.Now().UTC().Format(time.RFC3339Nano),
+		Level:     level.String(),
+		Component: l.component,
+		Message:   msg,
+	})
+	if err != nil {
+		return
+	}
+	l.config.mu.Lock()
+	defer l.config.mu.Unlock()
+	l.config.out.Write(append(b, '\n'))
+}
+
+// Printf logs an informational message. Messages prefixed with "warning:" or
+// "error:", as the packages Tiller is built from write them, are logged at
+// that level instead.
+func (l *leveledLogger) Printf(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	level := levelInfo
+	for _, p := range []struct {
+		prefix string
+		level  logLevel
+	}{{"warning: ", levelWarn}, {"error: ", levelError}} {
+		if strings.HasPrefix(strings.ToLower(msg), p.prefix) {
+			level = p.level
+			if l.config.json {
+				msg = msg[len(p.prefix):]
+			}
+			break
+		}
+	}
+	l.output(level, msg)
+}
+
+// Debugf logs a message useful when debugging Tiller.
+func (l *leveledLogger) Debugf(format string, args ...interface{}) {
+	l.output(levelDebug, fmt.Sprintf(format, args...))
+}
+
+// Warnf logs a message about a problem Tiller works around.
+func (l *leveledLogger) Warnf(format string, args ...interface{}) {
+	l.output(levelWarn, fmt.Sprintf(format, args...))
+}
+
+// Errorf logs a message about a failure.
+func (l *leveledLogger) Errorf(format string, args ...interface{}) {
+	l.output(levelError, fmt.Sprintf(format, args...))
+}
+
+// Fatalf logs an error and exits.
+func (l *leveledLogger) Fatalf(format string, args ...interface{}) {
+	l.output(levelError, fmt.Sprintf(format, args...))
+	os.Exit(1)
+}
+
+func newLogger(prefix string) *leveledLogger {
+	textPrefix := prefix
+	if len(prefix) > 0 {
+		textPrefix = fmt.Sprintf("[%s] ", prefix)
+	}
+	return &leveledLogger{
+		component: prefix,
+		text:      log.New(logging.out, textPrefix, log.Flags()),
+		config:    logging,
+	}
+}
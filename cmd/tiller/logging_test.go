@@ -0,0 +1,88 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestLeveledLoggerJSON(t *testing.T) {
+	var buf bytes.Buffer
+	defer func(c *logConfig) { logging = c }(logging)
+	logging = &logConfig{out: &buf}
+	if err := configureLogging("json", "info"); err != nil {
+		t.Fatal(err)
+	}
+
+	l := newLogger("tiller")
+	l.Debugf("hidden")
+	l.Printf("installed %s", "happy-panda")
+	l.Printf("warning: Failed to record release %s", "happy-panda")
+	l.Errorf("Probes server died")
+
+	var entries []logEntry
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var e logEntry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("expected a JSON object per line, got %q: %s", line, err)
+		}
+		entries = append(entries, e)
+	}
+	expect := []logEntry{
+		{Level: "info", Component: "tiller", Message: "installed happy-panda"},
+		{Level: "warn", Component: "tiller", Message: "Failed to record release happy-panda"},
+		{Level: "error", Component: "tiller", Message: "Probes server died"},
+	}
+	if len(entries) != len(expect) {
+		t.Fatalf("expected %d entries, got %d: %v", len(expect), len(entries), entries)
+	}
+	for i, e := range expect {
+		if entries[i].Time == "" {
+			t.Errorf("expected entry %d to have a time", i)
+		}
+		entries[i].Time = ""
+		if entries[i] != e {
+			t.Errorf("expected entry %d to be %+v, got %+v", i, e, entries[i])
+		}
+	}
+}
+
+func TestLeveledLoggerText(t *testing.T) {
+	var buf bytes.Buffer
+	defer func(c *logConfig) { logging = c }(logging)
+	logging = &logConfig{out: &buf}
+	if err := configureLogging("text", "warn"); err != nil {
+		t.Fatal(err)
+	}
+
+	l := newLogger("main")
+	l.Printf("Starting Tiller")
+	l.Printf("warning: something is off")
+	if got := buf.String(); strings.Contains(got, "Starting Tiller") || !strings.Contains(got, "[main] ") || !strings.Contains(got, "warning: something is off") {
+		t.Errorf("unexpected text output %q", got)
+	}
+
+	if err := configureLogging("xml", "info"); err == nil {
+		t.Error("expected an unknown format to be rejected")
+	}
+	if err := configureLogging("text", "verbose"); err == nil {
+		t.Error("expected an unknown level to be rejected")
+	}
+}
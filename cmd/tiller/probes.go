@@ -17,9 +17,20 @@ limitations under the License.
 package main
 
 import (
+	"fmt"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 
+	goprom "github.com/grpc-ecosystem/go-grpc-prometheus"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/tiller/environment"
 )
 
 func readinessProbe(w http.ResponseWriter, r *http.Request) {
@@ -30,14 +41,126 @@ func livenessProbe(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
-func newProbesMux() *http.ServeMux {
+// newProbesMux serves the readiness and liveness probes. When live is not
+// nil, liveness fails whenever it returns an error.
+func newProbesMux(live func() error) *http.ServeMux {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/readiness", readinessProbe)
-	mux.HandleFunc("/liveness", livenessProbe)
+	if live == nil {
+		mux.HandleFunc("/liveness", livenessProbe)
+	} else {
+		mux.HandleFunc("/liveness", func(w http.ResponseWriter, r *http.Request) {
+			if err := live(); err != nil {
+				http.Error(w, err.Error(), http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		})
+	}
 	return mux
 }
 
+// addReadyzHandlers serves /readyz, which fails while ping reports that the
+// storage backend is unreachable, and /livez, which only reports that the
+// process is up so that storage outages do not get Tiller restarted.
+func addReadyzHandlers(mux *http.ServeMux, ping func() error) {
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if err := ping(); err != nil {
+			http.Error(w, fmt.Sprintf("storage backend unreachable: %s", err), http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/livez", livenessProbe)
+}
+
+// renderCheck periodically runs render and remembers its last result, so
+// that liveness reflects whether Tiller can still render charts.
+type renderCheck struct {
+	render func() error
+
+	mu  sync.Mutex
+	err error
+}
+
+// check runs render once and records the result.
+func (c *renderCheck) check() {
+	err := c.render()
+	c.mu.Lock()
+	c.err = err
+	c.mu.Unlock()
+}
+
+// run checks immediately and then every interval until stop is closed.
+func (c *renderCheck) run(interval time.Duration, stop <-chan struct{}) {
+	c.check()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.check()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// live returns the error of the last check, if any.
+func (c *renderCheck) live() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return fmt.Errorf("render self-test failed: %s", c.err)
+	}
+	return nil
+}
+
+var selfTestChart = &chart.Chart{
+	Metadata: &chart.Metadata{Name: "tiller-self-test", Version: "0.1.0"},
+	Templates: []*chart.Template{
+		{Name: "templates/configmap.yaml", Data: []byte("kind: ConfigMap\nmetadata:\n  name: {{ .Release.Name }}\ndata:\n  message: {{ .Values.message | quote }}\n")},
+	},
+	Values: &chart.Config{Raw: "message: ok\n"},
+}
+
+// renderSelfTest renders a small built-in chart through eng and verifies the
+// output.
+func renderSelfTest(eng environment.Engine) error {
+	vals, err := chartutil.ToRenderValues(selfTestChart, &chart.Config{Raw: "{}"}, chartutil.ReleaseOptions{Name: "self-test", Namespace: "default"})
+	if err != nil {
+		return err
+	}
+	out, err := eng.Render(selfTestChart, vals)
+	if err != nil {
+		return err
+	}
+	if m := out["tiller-self-test/templates/configmap.yaml"]; !strings.Contains(m, "name: self-test") || !strings.Contains(m, `message: "ok"`) {
+		return fmt.Errorf("unexpected output %q", m)
+	}
+	return nil
+}
+
 func addPrometheusHandler(mux *http.ServeMux) {
 	// Register HTTP handler for the global Prometheus registry.
 	mux.Handle("/metrics", promhttp.Handler())
 }
+
+// registerMetrics registers collectors with reg and initializes the gRPC
+// metrics of every method served by srv. It is safe to call more than once:
+// collectors that are already registered are kept, and registration failures
+// are logged rather than fatal, as Tiller can serve releases without metrics.
+func registerMetrics(reg prometheus.Registerer, srv *grpc.Server, collectors ...prometheus.Collector) {
+	for _, c := range collectors {
+		if err := reg.Register(c); err != nil {
+			if _, ok := err.(prometheus.AlreadyRegisteredError); ok {
+				logger.Debugf("Metrics collector already registered, keeping the existing one")
+				continue
+			}
+			logger.Warnf("Cannot register metrics collector: %s", err)
+		}
+	}
+	if srv != nil {
+		goprom.Register(srv)
+	}
+}
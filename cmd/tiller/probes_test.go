@@ -17,13 +17,23 @@ limitations under the License.
 package main
 
 import (
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/engine"
+	"k8s.io/helm/pkg/proto/hapi/chart"
 )
 
 func TestProbesServer(t *testing.T) {
-	mux := newProbesMux()
+	mux := newProbesMux(nil)
 	srv := httptest.NewServer(mux)
 	defer srv.Close()
 	resp, err := http.Get(srv.URL + "/readiness")
@@ -43,6 +53,81 @@ func TestProbesServer(t *testing.T) {
 	}
 }
 
+type failingEngine struct{}
+
+func (failingEngine) Render(*chart.Chart, chartutil.Values) (map[string]string, error) {
+	return nil, errors.New("engine is broken")
+}
+
+func TestDeepLiveness(t *testing.T) {
+	check := &renderCheck{render: func() error { return renderSelfTest(engine.New()) }}
+	srv := httptest.NewServer(newProbesMux(check.live))
+	defer srv.Close()
+
+	check.check()
+	resp, err := http.Get(srv.URL + "/liveness")
+	if err != nil {
+		t.Fatalf("GET /liveness returned an error (%s)", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET /liveness returned status code %d, expected %d", resp.StatusCode, http.StatusOK)
+	}
+
+	check.render = func() error { return renderSelfTest(failingEngine{}) }
+	check.check()
+	resp, err = http.Get(srv.URL + "/liveness")
+	if err != nil {
+		t.Fatalf("GET /liveness returned an error (%s)", err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("GET /liveness returned status code %d, expected %d", resp.StatusCode, http.StatusServiceUnavailable)
+	}
+
+	resp, err = http.Get(srv.URL + "/readiness")
+	if err != nil {
+		t.Fatalf("GET /readiness returned an error (%s)", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET /readiness returned status code %d, expected %d", resp.StatusCode, http.StatusOK)
+	}
+}
+
+func TestReadyz(t *testing.T) {
+	var storageErr error
+	mux := newProbesMux(nil)
+	addReadyzHandlers(mux, func() error { return storageErr })
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	for _, path := range []string{"/readyz", "/livez"} {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s returned an error (%s)", path, err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("GET %s returned status code %d, expected %d", path, resp.StatusCode, http.StatusOK)
+		}
+	}
+
+	storageErr = errors.New("connection refused")
+	resp, err := http.Get(srv.URL + "/readyz")
+	if err != nil {
+		t.Fatalf("GET /readyz returned an error (%s)", err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("GET /readyz returned status code %d, expected %d", resp.StatusCode, http.StatusServiceUnavailable)
+	}
+
+	// a storage outage must not fail liveness
+	resp, err = http.Get(srv.URL + "/livez")
+	if err != nil {
+		t.Fatalf("GET /livez returned an error (%s)", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET /livez returned status code %d, expected %d", resp.StatusCode, http.StatusOK)
+	}
+}
+
 func TestPrometheus(t *testing.T) {
 	mux := http.NewServeMux()
 	addPrometheusHandler(mux)
@@ -56,3 +141,25 @@ func TestPrometheus(t *testing.T) {
 		t.Fatalf("GET /metrics returned status code %d, expected %d", resp.StatusCode, http.StatusOK)
 	}
 }
+
+func TestRegisterMetricsTwice(t *testing.T) {
+	logger = newLogger("main")
+	reg := prometheus.NewRegistry()
+	srv := grpc.NewServer()
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+
+	counter := prometheus.NewCounter(prometheus.CounterOpts{Name: "tiller_test_total", Help: "Test counter."})
+	registerMetrics(reg, srv, counter)
+	// A second registration, as after a restart within the same process,
+	// must keep the existing collector rather than panic.
+	registerMetrics(reg, srv, counter)
+	registerMetrics(reg, srv, prometheus.NewCounter(prometheus.CounterOpts{Name: "tiller_test_total", Help: "Test counter."}))
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(families) != 1 || families[0].GetName() != "tiller_test_total" {
+		t.Errorf("Expected only tiller_test_total to be registered, got %v", families)
+	}
+}
@@ -17,7 +17,10 @@ limitations under the License.
 package main // import "k8s.io/helm/cmd/tiller"
 
 import (
+	"compress/gzip"
+	"context"
 	"crypto/tls"
+	"encoding/base64"
 	"flag"
 	"fmt"
 	"io/ioutil"
@@ -25,17 +28,25 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"syscall"
+	"text/template"
 	"time"
 
-	goprom "github.com/grpc-ecosystem/go-grpc-prometheus"
+	"github.com/go-redis/redis"
+	"github.com/prometheus/client_golang/prometheus"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/health"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/keepalive"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/klog"
 
 	// Import to initialize client auth plugins.
@@ -47,6 +58,7 @@ import (
 	"k8s.io/helm/pkg/storage/driver"
 	"k8s.io/helm/pkg/tiller"
 	"k8s.io/helm/pkg/tiller/environment"
+	"k8s.io/helm/pkg/tiller/metrics"
 	"k8s.io/helm/pkg/tlsutil"
 	"k8s.io/helm/pkg/version"
 )
@@ -67,23 +79,33 @@ const (
 	storageConfigMap = "configmap"
 	storageSecret    = "secret"
 	storageSQL       = "sql"
+	storageRedis     = "redis"
+
+	encryptionVault = "vault"
 
 	traceAddr = ":44136"
 
 	// defaultMaxHistory sets the maximum number of releases to 0: unlimited
 	defaultMaxHistory = 0
+
+	// deepLivenessInterval is how often --deep-liveness renders its chart.
+	deepLivenessInterval = 30 * time.Second
 )
 
 var (
-	grpcAddr      = flag.String("listen", fmt.Sprintf(":%v", environment.DefaultTillerPort), "address:port to listen on")
-	probeAddr     = flag.String("probe-listen", fmt.Sprintf(":%v", environment.DefaultTillerProbePort), "address:port to listen on for probes")
+	grpcAddr      = flag.String("listen", fmt.Sprintf(":%v", environment.DefaultTillerPort), "address:port to listen on, with IPv6 addresses in brackets as in [::1]:44134")
+	probeAddr     = flag.String("probe-listen", fmt.Sprintf(":%v", environment.DefaultTillerProbePort), "address:port to listen on for probes, with IPv6 addresses in brackets")
 	enableProbing = flag.Bool("probe", true, "enable probing over http")
 	enableTracing = flag.Bool("trace", false, "enable rpc tracing")
-	store         = flag.String("storage", storageConfigMap, "storage driver to use. One of 'configmap', 'memory', 'sql' or 'secret'")
+	store         = flag.String("storage", storageConfigMap, "storage driver to use. One of 'configmap', 'memory', 'sql', 'redis' or 'secret'")
 
 	sqlDialect          = flag.String("sql-dialect", "postgres", "SQL dialect to use (only postgres is supported for now")
 	sqlConnectionString = flag.String("sql-connection-string", "", "SQL connection string to use")
 
+	redisAddr   = flag.String("redis-addr", "localhost:6379", "address:port of the Redis server used by the redis storage driver")
+	redisPrefix = flag.String("redis-prefix", "helm", "prefix of the keys the redis storage driver stores releases under")
+	redisTTL    = flag.Duration("redis-ttl", 0, "how long the redis storage driver keeps superseded, deleted and failed release records after they were last written, with 0 keeping them forever")
+
 	remoteReleaseModules = flag.Bool("experimental-release", false, "enable experimental release modules")
 
 	tlsEnable    = flag.Bool("tls", tlsEnableEnvVarDefault(), "enable TLS")
@@ -92,8 +114,102 @@ var (
 	certFile     = flag.String("tls-cert", tlsDefaultsFromEnv("tls-cert"), "path to TLS certificate file")
 	caCertFile   = flag.String("tls-ca-cert", tlsDefaultsFromEnv("tls-ca-cert"), "trust certificates signed by this CA")
 	maxHistory   = flag.Int("history-max", historyMaxFromEnv(), "maximum number of releases kept in release history, with 0 meaning no limit")
+	kubeTimeout  = flag.Duration("kube-request-timeout", 0, "timeout for individual requests to the Kubernetes API server, with 0 meaning no timeout")
+	valuesFile   = flag.String("default-values-file", "", "name of a values file in charts to use as default values beneath user supplied values")
+	maxRender    = flag.Int64("max-render-bytes", 0, "maximum size in bytes of a chart's rendered templates, with 0 meaning no limit")
 	printVersion = flag.Bool("version", false, "print the version number")
 
+	deleteParallelism = flag.Int("delete-parallelism", 1, "maximum number of resources of the same kind deleted at once when uninstalling a release")
+
+	encryptionKeys    = flag.String("storage-encryption-keys", "", "path to a file of 'id=base64-key' lines used to encrypt releases stored by the secret driver, and to read them when migrating from it")
+	encryptionPrimary = flag.String("storage-encryption-primary", "", "id of the key in --storage-encryption-keys used to encrypt newly written releases")
+
+	chartRepoURL       = flag.String("repo-url", "", "chart repository used for install chart references that do not name a repository")
+	chartRepoAllowlist = flag.String("repo-allowlist", "", "comma-separated list of additional chart repository URLs Tiller may download charts from")
+
+	enableAudit = flag.Bool("audit", false, "record who performed each operation on a release, in a ConfigMap per release beside the release records")
+
+	allowDuplicates    = flag.Bool("allow-duplicate-resources", false, "when a chart renders the same resource more than once, apply the last one instead of failing")
+	setOwnerReferences = flag.Bool("set-owner-references", false, "make release records owners of the release's resources in the storage namespace, so deleting a record garbage collects them. Resources with the keep resource policy are left unowned")
+
+	warmReleaseCache = flag.Duration("warm-release-cache", 0, "interval at which release listings are loaded into an in-memory cache ahead of client requests, with 0 disabling the cache")
+
+	deletionTimeout       = flag.Duration("deletion-timeout", 0, "time to wait on uninstall for deleted resources to be gone from the cluster before reporting them as stuck, with 0 meaning no wait")
+	forceFinalizerRemoval = flag.Bool("force-finalizer-removal", false, "remove the finalizers of resources still present when --deletion-timeout expires")
+
+	releaseNameTemplate = flag.String("release-name-template", "", "Go template used to generate the names of releases installed without one, with access to .Namespace, .ChartName and the sprig functions")
+
+	deletionPropagation = flag.String("deletion-propagation", string(metav1.DeletePropagationBackground), "propagation policy used when deleting a release's resources. One of 'Background', 'Foreground' or 'Orphan'")
+
+	compressionLevel = flag.Int("storage-compression-level", driver.DefaultCompressionLevel, "gzip level release records are compressed with, from -2 (Huffman only) and -1 (gzip's default) to 9 (smallest). Records written with any level can always be read")
+	formatHeaders    = flag.Bool("storage-format-headers", false, "prefix release records with a header naming their encoding. Tiller versions without this flag cannot read such records, so enabling this is a one-way migration")
+
+	skipUnchangedUpgrades = flag.Bool("skip-unchanged-upgrades", false, "return the deployed release instead of creating a new version when an upgrade renders the same manifests from the same values")
+
+	namespaceLabels         = flag.String("namespace-labels", "", "comma-separated list of key=value labels added to the namespaces Tiller creates for releases")
+	labelExistingNamespaces = flag.Bool("label-existing-namespaces", false, "also add --namespace-labels to existing namespaces releases are installed into")
+
+	parallelHooks = flag.Bool("parallel-hooks", false, "run hooks that share a hook weight concurrently, waiting for all of them before moving to the next weight")
+
+	deepLiveness = flag.Bool("deep-liveness", false, "fail the liveness probe when a built-in chart, rendered every 30 seconds, can no longer be rendered")
+
+	fieldManager = flag.String("field-manager", "", "update resources with server-side apply under this field manager, leaving fields owned by other managers untouched. Upgrades fail on field ownership conflicts, even with --force")
+
+	verifyImages   = flag.Bool("verify-images", false, "check that the container images a release references exist in their registries before installing or upgrading it")
+	registryConfig = flag.String("registry-config", "", "path to a Docker config.json holding the registry credentials used by --verify-images")
+
+	shutdownTimeout = flag.Duration("shutdown-timeout", 30*time.Second, "time to let in-flight requests finish after a SIGTERM before stopping Tiller")
+
+	indexedLabels = flag.String("indexed-labels", "", "comma-separated list of chart annotations copied to the labels of release records so releases can be queried by them, with the configmap and secret storage drivers")
+
+	separateContent = flag.Bool("separate-release-content", false, "store each release's manifest and hooks in a ConfigMap or Secret of their own, next to the release record, so they can be fetched without reading the chart, with the configmap and secret storage drivers")
+
+	tlsMinVersion   = flag.String("tls-min-version", "1.2", "minimum TLS version accepted: 1.0, 1.1, 1.2 or 1.3")
+	tlsCipherSuites = flag.String("tls-cipher-suites", "", "comma-separated list of IANA cipher suite names accepted for TLS 1.2 and earlier. If empty, Go's defaults are used")
+	tlsReload       = flag.Bool("tls-reload", false, "load the TLS certificate and key again when they change on disk, so rotated certificates are served without a restart")
+
+	statusWebhookURL      = flag.String("status-webhook-url", "", "URL to POST a JSON event to when a release enters one of --status-webhook-statuses")
+	statusWebhookStatuses = flag.String("status-webhook-statuses", "FAILED", "comma-separated list of release statuses reported by --status-webhook-url")
+
+	renderParallelism = flag.Int("render-parallelism", 1, "number of subcharts rendered concurrently, with 1 rendering serially")
+
+	enforceQuotas = flag.Bool("enforce-quotas", false, "reject installs whose estimated resource footprint, from the helm.sh/estimated-resources chart annotation or the manifest's pod resources, exceeds the namespace's ResourceQuotas")
+
+	tlsSessionTickets = flag.Bool("tls-session-tickets", true, "issue TLS session tickets so clients can resume sessions")
+	tlsOCSPStaple     = flag.String("tls-ocsp-staple", "", "path to a DER-encoded OCSP response stapled to the TLS certificate")
+	tlsOCSPResponder  = flag.String("tls-ocsp-responder", "", "URL of an OCSP responder to fetch the staple from when --tls-ocsp-staple is not set. --tls-cert must then also contain the issuing certificate")
+
+	deletedReleaseTTL = flag.Duration("deleted-release-ttl", 0, "age after which the records of deleted releases are purged from storage, with 0 keeping them forever")
+
+	maxValuesBytes = flag.Int64("max-values-bytes", 0, "maximum size in bytes of the values supplied with an install or upgrade, with 0 meaning no limit")
+
+	recordAnnotations = flag.String("release-record-annotations", "", "comma-separated list of key=value annotations set on the release records written, with the configmap and secret storage drivers")
+
+	retryBudget = flag.Int("retry-budget", 0, "number of retries of failed operations, such as writing release records, allowed per minute across all releases, with 0 disabling retries")
+
+	listenNetwork = flag.String("listen-network", "tcp", "network the gRPC and probe servers listen on: tcp, or tcp4 or tcp6 to only use IPv4 or IPv6")
+
+	logFormat    = flag.String("log-format", "text", "format of the log output: text, or json for one JSON object per line")
+	logLevelFlag = flag.String("log-level", "info", "minimum level of the messages logged: debug, info, warn or error")
+
+	autoRollbackWindow = flag.Duration("auto-rollback-window", 0, "how long to watch the Deployments of an upgraded release, rolling it back to the previous version if they become unhealthy, with 0 disabling automatic rollbacks")
+
+	encryption      = flag.String("encryption", "", "service used to encrypt release records before they are stored: vault, with any storage driver but memory")
+	vaultAddr       = flag.String("vault-addr", os.Getenv("VAULT_ADDR"), "address of the Vault server used by --encryption=vault. The token is read from $VAULT_TOKEN")
+	vaultTransitKey = flag.String("vault-transit-key", "", "name of the transit key used by --encryption=vault")
+
+	targetNamespace = flag.String("target-namespace", "", "namespace releases are installed into when the install request names none. Release records are still stored in Tiller's own namespace")
+
+	validateSchema = flag.Bool("validate", false, "validate the rendered manifests of installs and upgrades against the OpenAPI schema of the cluster before applying them, reporting the errors of each invalid template")
+
+	migrateFrom = flag.String("migrate-from", "", "storage driver to copy the release records of on startup, into the one set by --storage: configmap or secret. Records already stored are skipped")
+
+	authTokenFile = flag.String("auth-token-file", "", "path to a file of bearer tokens, one per line, one of which gRPC calls must carry in their authorization metadata")
+
+	auditLogPath = flag.String("audit-log", "", "file to append a JSON record of each install, upgrade, rollback and uninstall to, or - for standard output")
+
+	releaseLockTimeout = flag.Duration("release-lock-timeout", 30*time.Second, "how long an operation changing a release waits for another one in progress on the same release before failing with a retriable error, with 0 waiting as long as the request allows")
+
 	// rootServer is the root gRPC server.
 	//
 	// Each gRPC service registers itself to this server during start().
@@ -104,7 +220,7 @@ var (
 	// Any changes to env should be done before rootServer.Serve() is called.
 	env = environment.New()
 
-	logger *log.Logger
+	logger *leveledLogger
 )
 
 func main() {
@@ -120,35 +236,120 @@ func main() {
 	if *enableTracing {
 		log.SetFlags(log.Lshortfile)
 	}
+	logErr := configureLogging(*logFormat, *logLevelFlag)
 	logger = newLogger("main")
+	if logErr != nil {
+		logger.Fatalf("Invalid logging flags: %s", logErr)
+	}
 
 	start()
 }
 
 func start() {
+	if err := validateListenAddr(*listenNetwork, *grpcAddr); err != nil {
+		logger.Fatalf("Invalid --listen: %s", err)
+	}
+	if *enableProbing {
+		if err := validateListenAddr(*listenNetwork, *probeAddr); err != nil {
+			logger.Fatalf("Invalid --probe-listen: %s", err)
+		}
+	}
+	if err := validateStorageFlags(*store, *sqlConnectionString, *redisAddr); err != nil {
+		logger.Fatalf("Invalid storage flags: %s", err)
+	}
 
 	healthSrv := health.NewServer()
 	healthSrv.SetServingStatus("Tiller", healthpb.HealthCheckResponse_NOT_SERVING)
 
-	clientset, err := kube.New(nil).KubernetesClientSet()
+	kubeFlags := kubeConfigFlags(*kubeTimeout)
+	clientset, err := kube.New(kubeFlags).KubernetesClientSet()
 	if err != nil {
 		logger.Fatalf("Cannot initialize Kubernetes connection: %s", err)
 	}
 
+	var keyring *driver.Keyring
+	if *encryptionKeys != "" {
+		if *store != storageSecret && *migrateFrom != storageSecret {
+			logger.Fatalf("--storage-encryption-keys is only supported with the %q storage driver", storageSecret)
+		}
+		if keyring, err = loadKeyring(*encryptionKeys, *encryptionPrimary); err != nil {
+			logger.Fatalf("Cannot load storage encryption keys: %v", err)
+		}
+	}
+
+	var labelKeys []string
+	if *indexedLabels != "" {
+		if *store != storageConfigMap && *store != storageSecret {
+			logger.Fatalf("--indexed-labels is only supported with the %q and %q storage drivers", storageConfigMap, storageSecret)
+		}
+		labelKeys = strings.Split(*indexedLabels, ",")
+		if err := driver.ValidateIndexedLabels(labelKeys); err != nil {
+			logger.Fatalf("Invalid --indexed-labels: %s", err)
+		}
+	}
+
+	if *separateContent && *store != storageConfigMap && *store != storageSecret {
+		logger.Fatalf("--separate-release-content is only supported with the %q and %q storage drivers", storageConfigMap, storageSecret)
+	}
+
+	var annotations map[string]string
+	if *recordAnnotations != "" {
+		if *store != storageConfigMap && *store != storageSecret {
+			logger.Fatalf("--release-record-annotations is only supported with the %q and %q storage drivers", storageConfigMap, storageSecret)
+		}
+		if annotations, err = driver.ParseRecordAnnotations(*recordAnnotations); err != nil {
+			logger.Fatalf("Invalid --release-record-annotations: %s", err)
+		}
+	}
+
+	var encrypter driver.Encrypter
+	if err := validateEncryptionFlags(*encryption, *store, *vaultAddr, *vaultTransitKey); err != nil {
+		logger.Fatalf("Invalid --encryption: %s", err)
+	}
+	if *encryption == encryptionVault {
+		token := os.Getenv("VAULT_TOKEN")
+		if token == "" {
+			logger.Fatalf("Invalid --encryption: $VAULT_TOKEN is required with --encryption=%s", encryptionVault)
+		}
+		encrypter = driver.NewVaultTransit(*vaultAddr, *vaultTransitKey, token)
+	}
+
+	if err := validateMigrateFlags(*migrateFrom, *store); err != nil {
+		logger.Fatalf("Invalid --migrate-from: %s", err)
+	}
+
+	if err := validateCompressionLevel(*compressionLevel); err != nil {
+		logger.Fatalf("Invalid --storage-compression-level: %s", err)
+	}
+	driver.WriteFormatHeaders = *formatHeaders
+
 	switch *store {
 	case storageMemory:
 		env.Releases = storage.Init(driver.NewMemory())
 	case storageConfigMap:
 		cfgmaps := driver.NewConfigMaps(clientset.CoreV1().ConfigMaps(namespace()))
 		cfgmaps.Log = newLogger("storage/driver").Printf
+		cfgmaps.IndexedLabels = labelKeys
+		cfgmaps.SeparateContent = *separateContent
+		cfgmaps.Annotations = annotations
+		cfgmaps.Encrypter = encrypter
+		cfgmaps.CompressionLevel = *compressionLevel
 
 		env.Releases = storage.Init(cfgmaps)
+		env.Releases.IndexedLabels = labelKeys
 		env.Releases.Log = newLogger("storage").Printf
 	case storageSecret:
 		secrets := driver.NewSecrets(clientset.CoreV1().Secrets(namespace()))
 		secrets.Log = newLogger("storage/driver").Printf
+		secrets.IndexedLabels = labelKeys
+		secrets.SeparateContent = *separateContent
+		secrets.Annotations = annotations
+		secrets.Encrypter = encrypter
+		secrets.CompressionLevel = *compressionLevel
+		secrets.Keyring = keyring
 
 		env.Releases = storage.Init(secrets)
+		env.Releases.IndexedLabels = labelKeys
 		env.Releases.Log = newLogger("storage").Printf
 	case storageSQL:
 		sqlDriver, err := driver.NewSQL(
@@ -159,17 +360,117 @@ func start() {
 		if err != nil {
 			logger.Fatalf("Cannot initialize SQL storage driver: %v", err)
 		}
+		sqlDriver.CompressionLevel = *compressionLevel
+		sqlDriver.Encrypter = encrypter
 
 		env.Releases = storage.Init(sqlDriver)
 		env.Releases.Log = newLogger("storage").Printf
+	case storageRedis:
+		client := redis.NewClient(&redis.Options{Addr: *redisAddr})
+		if err := client.Ping().Err(); err != nil {
+			logger.Fatalf("Cannot initialize Redis storage driver: %v", err)
+		}
+		redisDriver := driver.NewRedis(client, *redisPrefix)
+		redisDriver.Log = newLogger("storage/driver").Printf
+		redisDriver.TTL = *redisTTL
+		redisDriver.CompressionLevel = *compressionLevel
+		redisDriver.Encrypter = encrypter
+
+		env.Releases = storage.Init(redisDriver)
+		env.Releases.Log = newLogger("storage").Printf
 	}
 
 	if *maxHistory > 0 {
 		env.Releases.MaxHistory = *maxHistory
 	}
 
-	kubeClient := kube.New(nil)
+	if *migrateFrom != "" {
+		var src driver.Driver
+		switch *migrateFrom {
+		case storageConfigMap:
+			cfgmaps := driver.NewConfigMaps(clientset.CoreV1().ConfigMaps(namespace()))
+			cfgmaps.Log = newLogger("storage/driver").Printf
+			cfgmaps.Encrypter = encrypter
+			src = cfgmaps
+		case storageSecret:
+			secrets := driver.NewSecrets(clientset.CoreV1().Secrets(namespace()))
+			secrets.Log = newLogger("storage/driver").Printf
+			secrets.Encrypter = encrypter
+			secrets.Keyring = keyring
+			src = secrets
+		}
+		res, err := env.Releases.MigrateFrom(src)
+		if err != nil {
+			logger.Fatalf("Cannot migrate releases from the %s storage driver: %v", *migrateFrom, err)
+		}
+		logger.Printf("Migrated %d releases from the %s storage driver, skipped %d already stored, failed %d", res.Migrated, *migrateFrom, res.Skipped, res.Failed)
+	}
+
+	if *warmReleaseCache > 0 {
+		env.Releases.EnableCache()
+	}
+
+	var nameTemplate *template.Template
+	if *releaseNameTemplate != "" {
+		nameTemplate, err = tiller.ParseReleaseNameTemplate(*releaseNameTemplate)
+		if err != nil {
+			logger.Fatalf("Invalid --release-name-template: %s", err)
+		}
+	}
+
+	var registryAuths map[string]tiller.RegistryAuth
+	if *registryConfig != "" {
+		registryAuths, err = tiller.LoadRegistryAuths(*registryConfig)
+		if err != nil {
+			logger.Fatalf("Cannot load --registry-config: %s", err)
+		}
+	}
+
+	var auditLog *tiller.AuditLog
+	switch *auditLogPath {
+	case "":
+	case "-":
+		auditLog = tiller.NewAuditLog(os.Stdout)
+	default:
+		f, err := os.OpenFile(*auditLogPath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+		if err != nil {
+			logger.Fatalf("Cannot open --audit-log: %s", err)
+		}
+		auditLog = tiller.NewAuditLog(f)
+	}
+
+	var statusWatcher *tiller.StatusWatcher
+	if *statusWebhookURL != "" {
+		statuses, err := tiller.ParseStatuses(strings.Split(*statusWebhookStatuses, ","))
+		if err != nil {
+			logger.Fatalf("Invalid --status-webhook-statuses: %s", err)
+		}
+		statusWatcher = tiller.NewStatusWatcher(*statusWebhookURL, statuses)
+	}
+
+	kubeClient := kube.New(kubeFlags)
 	kubeClient.Log = newLogger("kube").Printf
+	switch p := metav1.DeletionPropagation(*deletionPropagation); p {
+	case metav1.DeletePropagationBackground, metav1.DeletePropagationForeground, metav1.DeletePropagationOrphan:
+		kubeClient.DeletionPropagation = p
+	default:
+		logger.Fatalf("Unknown --deletion-propagation %q, expected one of Background, Foreground or Orphan", *deletionPropagation)
+	}
+	if *namespaceLabels != "" {
+		nsLabels, err := labels.ConvertSelectorToLabelsMap(*namespaceLabels)
+		if err != nil {
+			logger.Fatalf("Invalid --namespace-labels: %s", err)
+		}
+		kubeClient.NamespaceLabels = nsLabels
+	}
+	kubeClient.LabelExistingNamespaces = *labelExistingNamespaces
+	if *targetNamespace != "" {
+		if errs := validation.IsDNS1123Label(*targetNamespace); len(errs) > 0 {
+			logger.Fatalf("Invalid --target-namespace: %s", strings.Join(errs, "; "))
+		}
+	}
+	kubeClient.FieldManager = *fieldManager
+	kubeClient.SchemaValidation = *validateSchema
 	env.KubeClient = kubeClient
 
 	if *tlsEnable || *tlsVerify {
@@ -181,7 +482,20 @@ func start() {
 
 	var opts []grpc.ServerOption
 	if *tlsEnable || *tlsVerify {
-		cfg, err := tlsutil.ServerConfig(tlsOptions())
+		tlsOpts := tlsOptions()
+		tlsOpts.ReloadCertificates = *tlsReload
+		tlsOpts.SessionTicketsDisabled = !*tlsSessionTickets
+		tlsOpts.OCSPStapleFile = *tlsOCSPStaple
+		tlsOpts.OCSPResponder = *tlsOCSPResponder
+		if tlsOpts.MinVersion, err = tlsutil.ParseVersion(*tlsMinVersion); err != nil {
+			logger.Fatalf("Invalid --tls-min-version: %s", err)
+		}
+		if *tlsCipherSuites != "" {
+			if tlsOpts.CipherSuites, err = tlsutil.ParseCipherSuites(strings.Split(*tlsCipherSuites, ",")); err != nil {
+				logger.Fatalf("Invalid --tls-cipher-suites: %s", err)
+			}
+		}
+		cfg, err := tlsutil.ServerConfig(tlsOpts)
 		if err != nil {
 			logger.Fatalf("Could not create server TLS configuration: %v", err)
 		}
@@ -196,13 +510,31 @@ func start() {
 		MinTime: time.Duration(20) * time.Second, // For compatibility with the client keepalive.ClientParameters
 	}))
 
-	rootServer = tiller.NewServer(opts...)
+	var auth tiller.Authenticator
+	if *authTokenFile != "" {
+		tokens, err := tiller.LoadBearerTokens(*authTokenFile)
+		if err != nil {
+			logger.Fatalf("Invalid --auth-token-file: %s", err)
+		}
+		if !*tlsEnable && !*tlsVerify {
+			logger.Warnf("Bearer tokens are sent in plaintext without --tls")
+		}
+		auth = tiller.BearerTokenAuth(tokens)
+	}
+
+	rootServer = tiller.NewAuthenticatedServer(auth, opts...)
 	healthpb.RegisterHealthServer(rootServer, healthSrv)
 
-	lstn, err := net.Listen("tcp", *grpcAddr)
+	lstn, err := listen(*listenNetwork, *grpcAddr)
 	if err != nil {
 		logger.Fatalf("Server died: %s", err)
 	}
+	var probeLstn net.Listener
+	if *enableProbing {
+		if probeLstn, err = listen(*listenNetwork, *probeAddr); err != nil {
+			logger.Fatalf("Server died: %s", err)
+		}
+	}
 
 	logger.Printf("Starting Tiller %s (tls=%t)", version.GetVersion(), *tlsEnable || *tlsVerify)
 	logger.Printf("GRPC listening on %s", *grpcAddr)
@@ -221,43 +553,232 @@ func start() {
 	go func() {
 		svc := tiller.NewReleaseServer(env, clientset, *remoteReleaseModules)
 		svc.Log = newLogger("tiller").Printf
+		svc.MaxRenderBytes = *maxRender
+		svc.MaxValuesBytes = *maxValuesBytes
+		svc.RenderParallelism = *renderParallelism
+		svc.DefaultValuesFile = *valuesFile
+		svc.ChartRepoURL = *chartRepoURL
+		svc.Audit = *enableAudit
+		if *enableAudit && *store != storageMemory {
+			svc.AuditStore = tiller.NewConfigMapAuditStore(clientset.CoreV1().ConfigMaps(namespace()))
+		}
+		svc.AllowDuplicateResources = *allowDuplicates
+		svc.ReleaseNameTemplate = nameTemplate
+		svc.SkipUnchangedUpgrades = *skipUnchangedUpgrades
+		svc.ParallelHooks = *parallelHooks
+		svc.VerifyImages = *verifyImages
+		svc.EnforceQuotas = *enforceQuotas
+		svc.RegistryAuths = registryAuths
+		svc.StatusWatcher = statusWatcher
+		svc.AutoRollbackWindow = *autoRollbackWindow
+		svc.DefaultNamespace = *targetNamespace
+		svc.SchemaValidation = *validateSchema
+		svc.AuditLog = auditLog
+		svc.LockTimeout = *releaseLockTimeout
+		if *retryBudget > 0 {
+			svc.RetryBudget = tiller.NewRetryBudget(*retryBudget)
+		}
+		if *chartRepoAllowlist != "" {
+			svc.ChartRepoAllowlist = strings.Split(*chartRepoAllowlist, ",")
+		}
+		if m, ok := svc.ReleaseModule.(*tiller.LocalReleaseModule); ok {
+			m.DeleteParallelism = *deleteParallelism
+			m.SetOwnerReferences = *setOwnerReferences
+			m.StorageNamespace = namespace()
+			m.DeletionTimeout = *deletionTimeout
+			m.ForceFinalizerRemoval = *forceFinalizerRemoval
+		}
+		if *warmReleaseCache > 0 {
+			go svc.WarmReleaseCache(*warmReleaseCache, nil)
+		}
+		if *deletedReleaseTTL > 0 {
+			go svc.CollectDeletedReleases(*deletedReleaseTTL, nil)
+		}
 		services.RegisterReleaseServiceServer(rootServer, svc)
 		if err := rootServer.Serve(lstn); err != nil {
 			srvErrCh <- err
 		}
 	}()
 
+	probeSrv := &http.Server{Addr: *probeAddr}
 	go func() {
 		if !*enableProbing {
 			return
 		}
 
-		mux := newProbesMux()
+		var live func() error
+		if *deepLiveness {
+			check := &renderCheck{render: func() error { return renderSelfTest(env.EngineYard.Default()) }}
+			go check.run(deepLivenessInterval, nil)
+			live = check.live
+		}
+		mux := newProbesMux(live)
+		addReadyzHandlers(mux, env.Releases.Ping)
 
 		// Register gRPC server to prometheus to initialized matrix
-		goprom.Register(rootServer)
+		registerMetrics(prometheus.DefaultRegisterer, rootServer, metrics.Collectors(env.Releases)...)
 		addPrometheusHandler(mux)
 
-		if err := http.ListenAndServe(*probeAddr, mux); err != nil {
+		probeSrv.Handler = mux
+		if err := probeSrv.Serve(probeLstn); err != nil && err != http.ErrServerClosed {
 			probeErrCh <- err
 		}
 	}()
 
 	healthSrv.SetServingStatus("Tiller", healthpb.HealthCheckResponse_SERVING)
 
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGTERM, os.Interrupt)
+
 	select {
 	case err := <-srvErrCh:
 		logger.Fatalf("Server died: %s", err)
 	case err := <-probeErrCh:
-		logger.Printf("Probes server died: %s", err)
+		logger.Errorf("Probes server died: %s", err)
+	case sig := <-sigCh:
+		logger.Printf("Received %s, shutting down", sig)
+		healthSrv.SetServingStatus("Tiller", healthpb.HealthCheckResponse_NOT_SERVING)
+		if !*enableProbing {
+			probeSrv = nil
+		}
+		shutdown(rootServer, probeSrv, *shutdownTimeout)
+	}
+}
+
+// grpcServer is the part of *grpc.Server used to shut it down.
+type grpcServer interface {
+	GracefulStop()
+	Stop()
+}
+
+// shutdown stops srv from accepting requests and gives those in flight, such
+// as installs and upgrades, until timeout to finish before cutting them off.
+// The probe server, if any, is shut down once srv has stopped.
+func shutdown(srv grpcServer, probes *http.Server, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	stopped := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		logger.Warnf("Requests still running after %s, stopping anyway", timeout)
+		srv.Stop()
+		<-stopped
+	}
+
+	if probes == nil {
+		return
+	}
+	if err := probes.Shutdown(ctx); err != nil {
+		logger.Warnf("Probes server did not shut down cleanly: %s", err)
+		probes.Close()
+	}
+}
+
+// validateStorageFlags checks that store names a storage driver and that the
+// flags that driver requires are set.
+func validateStorageFlags(store, sqlConnectionString, redisAddr string) error {
+	switch store {
+	case storageMemory, storageConfigMap, storageSecret:
+	case storageSQL:
+		if sqlConnectionString == "" {
+			return fmt.Errorf("--sql-connection-string is required with the %q storage driver", storageSQL)
+		}
+	case storageRedis:
+		if redisAddr == "" {
+			return fmt.Errorf("--redis-addr is required with the %q storage driver", storageRedis)
+		}
+	default:
+		return fmt.Errorf("unknown --storage %q, expected one of %q, %q, %q, %q or %q", store, storageConfigMap, storageMemory, storageSecret, storageSQL, storageRedis)
+	}
+	return nil
+}
+
+// validateEncryptionFlags checks that --encryption names a supported service,
+// used with a storage driver that can encrypt records, and that the flags the
+// service needs are set.
+func validateEncryptionFlags(encryption, store, vaultAddr, transitKey string) error {
+	switch encryption {
+	case "":
+		return nil
+	case encryptionVault:
+		if vaultAddr == "" {
+			return fmt.Errorf("--vault-addr is required with --encryption=%s", encryptionVault)
+		}
+		if transitKey == "" {
+			return fmt.Errorf("--vault-transit-key is required with --encryption=%s", encryptionVault)
+		}
+	default:
+		return fmt.Errorf("unknown encryption service %q, expected %q", encryption, encryptionVault)
+	}
+	if store == storageMemory {
+		return fmt.Errorf("not supported with the %q storage driver", storageMemory)
 	}
+	return nil
 }
 
-func newLogger(prefix string) *log.Logger {
-	if len(prefix) > 0 {
-		prefix = fmt.Sprintf("[%s] ", prefix)
+// validateMigrateFlags checks that releases can be migrated from the storage
+// driver from into store.
+func validateMigrateFlags(from, store string) error {
+	switch from {
+	case "":
+		return nil
+	case storageConfigMap, storageSecret:
+	default:
+		return fmt.Errorf("unknown storage driver %q, expected %q or %q", from, storageConfigMap, storageSecret)
+	}
+	if from == store {
+		return fmt.Errorf("releases are already stored by the %q storage driver", store)
 	}
-	return log.New(os.Stderr, prefix, log.Flags())
+	return nil
+}
+
+// validateListenAddr checks that addr is a host:port address network can
+// listen on. IPv6 literals must be bracketed, as in "[::1]:44134", and an
+// empty host listens on every interface.
+func validateListenAddr(network, addr string) error {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("%q is not a host:port address, with IPv6 hosts in brackets: %s", addr, err)
+	}
+	if port == "" {
+		return fmt.Errorf("%q has no port", addr)
+	}
+	if _, err := net.LookupPort("tcp", port); err != nil {
+		return fmt.Errorf("%q has an invalid port: %s", addr, err)
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		switch {
+		case network == "tcp4" && ip.To4() == nil:
+			return fmt.Errorf("%q is not an IPv4 address, as required by --listen-network=%s", addr, network)
+		case network == "tcp6" && ip.To4() != nil:
+			return fmt.Errorf("%q is not an IPv6 address, as required by --listen-network=%s", addr, network)
+		}
+	}
+	return nil
+}
+
+// listen announces on addr using network, which must be one of the TCP
+// networks.
+func listen(network, addr string) (net.Listener, error) {
+	switch network {
+	case "tcp", "tcp4", "tcp6":
+		return net.Listen(network, addr)
+	}
+	return nil, fmt.Errorf("invalid --listen-network %q: must be tcp, tcp4 or tcp6", network)
+}
+
+// validateCompressionLevel checks that level is one compress/gzip accepts.
+func validateCompressionLevel(level int) error {
+	if level < gzip.HuffmanOnly || level > gzip.BestCompression {
+		return fmt.Errorf("%d is out of range, expected %d to %d", level, gzip.HuffmanOnly, gzip.BestCompression)
+	}
+	return nil
 }
 
 // namespace returns the namespace of tiller
@@ -276,6 +797,44 @@ func namespace() string {
 	return environment.DefaultTillerNamespace
 }
 
+// kubeConfigFlags returns the client configuration used to reach the Kubernetes
+// API server. A positive timeout bounds every single API request, so that an
+// unresponsive server fails the call instead of hanging the whole operation.
+func kubeConfigFlags(timeout time.Duration) *genericclioptions.ConfigFlags {
+	flags := genericclioptions.NewConfigFlags(true)
+	if timeout > 0 {
+		t := timeout.String()
+		flags.Timeout = &t
+	}
+	return flags
+}
+
+// loadKeyring reads a storage encryption keyring from path. Each non-empty
+// line holds a key id and a base64 encoded AES key separated by '='.
+func loadKeyring(path, primary string) (*driver.Keyring, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	keys := map[string][]byte{}
+	for i, line := range strings.Split(string(b), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("%s:%d: expected 'id=base64-key'", path, i+1)
+		}
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %s", path, i+1, err)
+		}
+		keys[strings.TrimSpace(parts[0])] = key
+	}
+	return driver.NewKeyring(primary, keys)
+}
+
 func tlsOptions() tlsutil.Options {
 	opts := tlsutil.Options{CertFile: *certFile, KeyFile: *keyFile}
 	if *tlsVerify {
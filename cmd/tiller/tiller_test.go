@@ -17,7 +17,13 @@ limitations under the License.
 package main
 
 import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"k8s.io/helm/pkg/engine"
 	"k8s.io/helm/pkg/tiller/environment"
@@ -45,3 +51,245 @@ func TestInit(t *testing.T) {
 		t.Fatalf("Template engine GoTplEngine returned nil.")
 	}
 }
+
+func TestKubeConfigFlagsTimeout(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tiller-kubeconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	kubeconfig := filepath.Join(dir, "config")
+	data := `apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: https://127.0.0.1:6443
+contexts:
+- name: test
+  context:
+    cluster: test
+current-context: test
+`
+	if err := ioutil.WriteFile(kubeconfig, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		timeout time.Duration
+		expect  time.Duration
+	}{
+		{timeout: 0, expect: 0},
+		{timeout: 30 * time.Second, expect: 30 * time.Second},
+		{timeout: 1500 * time.Millisecond, expect: 1500 * time.Millisecond},
+	} {
+		flags := kubeConfigFlags(tt.timeout)
+		flags.KubeConfig = &kubeconfig
+
+		cfg, err := flags.ToRESTConfig()
+		if err != nil {
+			t.Fatalf("failed to build rest config: %s", err)
+		}
+		if cfg.Timeout != tt.expect {
+			t.Errorf("expected timeout %s for %s, got %s", tt.expect, tt.timeout, cfg.Timeout)
+		}
+	}
+}
+
+func TestValidateCompressionLevel(t *testing.T) {
+	for _, level := range []int{-2, -1, 0, 1, 9} {
+		if err := validateCompressionLevel(level); err != nil {
+			t.Errorf("level %d: unexpected error: %s", level, err)
+		}
+	}
+	for _, level := range []int{-3, 10} {
+		if err := validateCompressionLevel(level); err == nil || !strings.Contains(err.Error(), "out of range") {
+			t.Errorf("level %d: expected an out of range error, got %v", level, err)
+		}
+	}
+}
+
+// drainingServer is a grpcServer whose GracefulStop waits for its in-flight
+// requests, signalled on done, or for Stop.
+type drainingServer struct {
+	done    chan struct{}
+	stop    chan struct{}
+	stopped bool
+}
+
+func (s *drainingServer) GracefulStop() {
+	select {
+	case <-s.done:
+	case <-s.stop:
+	}
+}
+
+func (s *drainingServer) Stop() {
+	s.stopped = true
+	close(s.stop)
+}
+
+func TestShutdown(t *testing.T) {
+	logger = newLogger("main")
+
+	srv := &drainingServer{done: make(chan struct{}), stop: make(chan struct{})}
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		close(srv.done)
+	}()
+	shutdown(srv, nil, time.Minute)
+	if srv.stopped {
+		t.Error("expected requests finishing within the timeout not to be cut off")
+	}
+
+	srv = &drainingServer{done: make(chan struct{}), stop: make(chan struct{})}
+	start := time.Now()
+	shutdown(srv, nil, 50*time.Millisecond)
+	if !srv.stopped {
+		t.Error("expected requests still running after the timeout to be cut off")
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("expected shutdown to give up after the timeout, took %s", d)
+	}
+}
+
+func TestListen(t *testing.T) {
+	l, err := listen("tcp4", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	if addr := l.Addr().(*net.TCPAddr); addr.IP.To4() == nil {
+		t.Errorf("expected an IPv4 listener on tcp4, got %s", addr)
+	}
+
+	if l, err := listen("tcp6", "[::1]:0"); err == nil {
+		defer l.Close()
+		if addr := l.Addr().(*net.TCPAddr); addr.IP.To4() != nil {
+			t.Errorf("expected an IPv6 listener on tcp6, got %s", addr)
+		}
+	} else {
+		t.Logf("skipping tcp6: %s", err)
+	}
+
+	if _, err := listen("udp", "localhost:0"); err == nil {
+		t.Error("expected udp to be rejected")
+	}
+}
+
+func TestValidateListenAddr(t *testing.T) {
+	tests := []struct {
+		network, addr string
+		errContains   string
+	}{
+		{network: "tcp", addr: ":44134"},
+		{network: "tcp", addr: "0.0.0.0:44134"},
+		{network: "tcp", addr: "[::1]:44134"},
+		{network: "tcp", addr: "[::]:44134"},
+		{network: "tcp", addr: "localhost:44134"},
+		{network: "tcp4", addr: "127.0.0.1:44134"},
+		{network: "tcp6", addr: "[::1]:44134"},
+		{network: "tcp", addr: "44134", errContains: "not a host:port address"},
+		{network: "tcp", addr: "::1:44134", errContains: "not a host:port address"},
+		{network: "tcp", addr: "localhost:", errContains: "has no port"},
+		{network: "tcp", addr: ":70000", errContains: "invalid port"},
+		{network: "tcp4", addr: "[::1]:44134", errContains: "not an IPv4 address"},
+		{network: "tcp6", addr: "127.0.0.1:44134", errContains: "not an IPv6 address"},
+	}
+	for _, tt := range tests {
+		err := validateListenAddr(tt.network, tt.addr)
+		if tt.errContains == "" {
+			if err != nil {
+				t.Errorf("%+v: unexpected error: %s", tt, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+			t.Errorf("%+v: expected an error containing %q, got %v", tt, tt.errContains, err)
+		}
+	}
+}
+
+func TestValidateStorageFlags(t *testing.T) {
+	tests := []struct {
+		store, sqlConnectionString, redisAddr string
+		errContains                           string
+	}{
+		{store: storageConfigMap},
+		{store: storageMemory},
+		{store: storageSecret},
+		{store: storageSQL, sqlConnectionString: "postgresql://localhost/helm"},
+		{store: storageRedis, redisAddr: "localhost:6379"},
+		{store: "configmaps", errContains: `unknown --storage "configmaps"`},
+		{store: "", errContains: "unknown --storage"},
+		{store: storageSQL, errContains: "--sql-connection-string is required"},
+		{store: storageRedis, errContains: "--redis-addr is required"},
+	}
+	for _, tt := range tests {
+		err := validateStorageFlags(tt.store, tt.sqlConnectionString, tt.redisAddr)
+		if tt.errContains == "" {
+			if err != nil {
+				t.Errorf("%q: unexpected error: %s", tt.store, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+			t.Errorf("%q: expected an error containing %q, got %v", tt.store, tt.errContains, err)
+		}
+	}
+}
+
+func TestValidateEncryptionFlags(t *testing.T) {
+	tests := []struct {
+		encryption, store, vaultAddr, transitKey string
+		errContains                              string
+	}{
+		{store: storageSQL},
+		{encryption: "vault", store: storageConfigMap, vaultAddr: "https://vault:8200", transitKey: "helm"},
+		{encryption: "vault", store: storageSecret, vaultAddr: "https://vault:8200", transitKey: "helm"},
+		{encryption: "vault", store: storageSQL, vaultAddr: "https://vault:8200", transitKey: "helm"},
+		{encryption: "vault", store: storageRedis, vaultAddr: "https://vault:8200", transitKey: "helm"},
+		{encryption: "vault", store: storageMemory, vaultAddr: "https://vault:8200", transitKey: "helm", errContains: "not supported with"},
+		{encryption: "vault", store: storageSecret, transitKey: "helm", errContains: "--vault-addr is required"},
+		{encryption: "vault", store: storageSecret, vaultAddr: "https://vault:8200", errContains: "--vault-transit-key is required"},
+		{encryption: "kms", store: storageSecret, errContains: `unknown encryption service "kms"`},
+	}
+	for _, tt := range tests {
+		err := validateEncryptionFlags(tt.encryption, tt.store, tt.vaultAddr, tt.transitKey)
+		if tt.errContains == "" {
+			if err != nil {
+				t.Errorf("%+v: unexpected error: %s", tt, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+			t.Errorf("%+v: expected an error containing %q, got %v", tt, tt.errContains, err)
+		}
+	}
+}
+
+func TestValidateMigrateFlags(t *testing.T) {
+	tests := []struct {
+		from, store string
+		errContains string
+	}{
+		{store: storageConfigMap},
+		{from: storageConfigMap, store: storageSecret},
+		{from: storageSecret, store: storageSQL},
+		{from: storageSecret, store: storageSecret, errContains: "already stored"},
+		{from: storageSQL, store: storageSecret, errContains: `unknown storage driver "sql"`},
+	}
+	for _, tt := range tests {
+		err := validateMigrateFlags(tt.from, tt.store)
+		if tt.errContains == "" {
+			if err != nil {
+				t.Errorf("%+v: unexpected error: %s", tt, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+			t.Errorf("%+v: expected an error containing %q, got %v", tt, tt.errContains, err)
+		}
+	}
+}
@@ -39,7 +39,7 @@ func startTracing(addr string) {
 
 	go func() {
 		if err := http.ListenAndServe(addr, nil); err != nil {
-			logger.Printf("tracing error: %s", err)
+			logger.Errorf("tracing error: %s", err)
 		}
 	}()
 }
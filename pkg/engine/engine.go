@@ -18,12 +18,16 @@ package engine
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"log"
 	"path"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"text/template"
+	"text/template/parse"
 
 	"github.com/Masterminds/sprig"
 
@@ -41,8 +45,22 @@ type Engine struct {
 	Strict bool
 	// In LintMode, some 'required' template values may be missing, so don't fail
 	LintMode bool
+	// MaxRenderBytes caps the combined size of all rendered templates. Rendering
+	// is aborted with ErrRenderSizeExceeded as soon as the cap is crossed. Values
+	// of 0 or less disable the check.
+	MaxRenderBytes int64
+	// Parallelism bounds how many of a chart's subcharts are rendered at the
+	// same time. The templates of each chart are still rendered in order.
+	// Charts whose templates change values, for example with set, are
+	// rendered serially, as templates of other charts may read the changes.
+	// Values of 1 or less render every template serially.
+	Parallelism int
 }
 
+// ErrRenderSizeExceeded indicates that rendering was aborted because the output
+// grew beyond Engine.MaxRenderBytes.
+var ErrRenderSizeExceeded = errors.New("rendered output exceeds the maximum allowed size")
+
 // New creates a new Go template Engine instance.
 //
 // The FuncMap is initialized here. You may modify the FuncMap _prior to_ the
@@ -264,18 +282,147 @@ func (e *Engine) renderWithReferences(tpls map[string]renderable, referenceTpls
 		}
 	}
 
+	var written int64
+	if e.Parallelism <= 1 || changesValues(t) {
+		return e.renderFiles(t, tpls, files, &written)
+	}
+
+	// Charts only share named templates, which t holds parsed for all of
+	// them, so each chart's files can be executed independently.
+	var charts [][]string
+	index := map[string]int{}
+	for _, file := range files {
+		base := tpls[file].basePath
+		i, ok := index[base]
+		if !ok {
+			i = len(charts)
+			index[base] = i
+			charts = append(charts, nil)
+		}
+		charts[i] = append(charts[i], file)
+	}
+
+	results := make([]map[string]string, len(charts))
+	errs := make([]error, len(charts))
+	sem := make(chan struct{}, e.Parallelism)
+	var wg sync.WaitGroup
+	for i, chartFiles := range charts {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, chartFiles []string) {
+			defer func() {
+				if r := recover(); r != nil {
+					errs[i] = fmt.Errorf("rendering template failed: %v", r)
+				}
+				<-sem
+				wg.Done()
+			}()
+			results[i], errs[i] = e.renderFiles(t, tpls, chartFiles, &written)
+		}(i, chartFiles)
+	}
+	wg.Wait()
+
+	// Report errors in the order serial rendering would have met them.
 	rendered = make(map[string]string, len(files))
+	for i := range charts {
+		if errs[i] != nil {
+			return map[string]string{}, errs[i]
+		}
+		for file, out := range results[i] {
+			rendered[file] = out
+		}
+	}
+	return rendered, nil
+}
+
+// valueChangers are the template functions that change the maps they are
+// given, and tpl, which runs templates taken from values that might.
+var valueChangers = map[string]bool{
+	"set":            true,
+	"unset":          true,
+	"merge":          true,
+	"mergeOverwrite": true,
+	"tpl":            true,
+}
+
+// changesValues reports whether any template of t calls a function that
+// changes the maps it is given. A chart's values share maps with its
+// parent's, so such templates can only be rendered in order.
+func changesValues(t *template.Template) bool {
+	for _, tpl := range t.Templates() {
+		if tpl.Tree != nil && callsValueChanger(tpl.Tree.Root) {
+			return true
+		}
+	}
+	return false
+}
+
+func callsValueChanger(node parse.Node) bool {
+	switch n := node.(type) {
+	case *parse.IdentifierNode:
+		return valueChangers[n.Ident]
+	case *parse.ListNode:
+		if n == nil {
+			return false
+		}
+		for _, c := range n.Nodes {
+			if callsValueChanger(c) {
+				return true
+			}
+		}
+	case *parse.ActionNode:
+		return callsValueChanger(n.Pipe)
+	case *parse.PipeNode:
+		if n == nil {
+			return false
+		}
+		for _, c := range n.Cmds {
+			if callsValueChanger(c) {
+				return true
+			}
+		}
+	case *parse.CommandNode:
+		for _, c := range n.Args {
+			if callsValueChanger(c) {
+				return true
+			}
+		}
+	case *parse.IfNode:
+		return callsValueChanger(n.Pipe) || callsValueChanger(n.List) || callsValueChanger(n.ElseList)
+	case *parse.RangeNode:
+		return callsValueChanger(n.Pipe) || callsValueChanger(n.List) || callsValueChanger(n.ElseList)
+	case *parse.WithNode:
+		return callsValueChanger(n.Pipe) || callsValueChanger(n.List) || callsValueChanger(n.ElseList)
+	case *parse.TemplateNode:
+		return callsValueChanger(n.Pipe)
+	}
+	return false
+}
+
+// renderFiles executes the named templates of t, adding the size of the
+// output to written, which may be shared with concurrent calls.
+func (e *Engine) renderFiles(t *template.Template, tpls map[string]renderable, files []string, written *int64) (map[string]string, error) {
+	rendered := make(map[string]string, len(files))
 	var buf bytes.Buffer
+	out := newCappedWriter(&buf, e.MaxRenderBytes, written)
 	for _, file := range files {
 		// Don't render partials. We don't care about the direct output of partials.
 		// They are only included from other templates.
 		if strings.HasPrefix(path.Base(file), "_") {
 			continue
 		}
-		// At render time, add information about the template that is being rendered.
-		vals := tpls[file].vals
+		// At render time, add information about the template that is being
+		// rendered. The values are shared by all templates of a chart, so
+		// this is done on a copy.
+		vals := make(chartutil.Values, len(tpls[file].vals)+1)
+		for k, v := range tpls[file].vals {
+			vals[k] = v
+		}
 		vals["Template"] = map[string]interface{}{"Name": file, "BasePath": tpls[file].basePath}
-		if err := t.ExecuteTemplate(&buf, file, vals); err != nil {
+		if err := t.ExecuteTemplate(out, file, vals); err != nil {
+			if out.exceeded {
+				return map[string]string{}, ErrRenderSizeExceeded
+			}
 			return map[string]string{}, fmt.Errorf("render error in %q: %s", file, err)
 		}
 
@@ -289,6 +436,28 @@ func (e *Engine) renderWithReferences(tpls map[string]renderable, referenceTpls
 	return rendered, nil
 }
 
+// cappedWriter forwards writes to buf until more than max bytes in total have
+// been counted in written, after which every write fails. Writers rendering
+// the same chart concurrently share written. A max of 0 or less never fails.
+type cappedWriter struct {
+	buf      *bytes.Buffer
+	max      int64
+	written  *int64
+	exceeded bool
+}
+
+func newCappedWriter(buf *bytes.Buffer, max int64, written *int64) *cappedWriter {
+	return &cappedWriter{buf: buf, max: max, written: written}
+}
+
+func (w *cappedWriter) Write(p []byte) (int, error) {
+	if n := atomic.AddInt64(w.written, int64(len(p))); w.max > 0 && n > w.max {
+		w.exceeded = true
+		return 0, ErrRenderSizeExceeded
+	}
+	return w.buf.Write(p)
+}
+
 func sortTemplates(tpls map[string]renderable) []string {
 	keys := make([]string, len(tpls))
 	i := 0
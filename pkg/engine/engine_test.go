@@ -18,8 +18,11 @@ package engine
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
 	"sync"
 	"testing"
+	"text/template"
 
 	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/proto/hapi/chart"
@@ -589,3 +592,103 @@ func TestAlterFuncMap(t *testing.T) {
 	}
 
 }
+
+func TestRenderParallelMatchesSerial(t *testing.T) {
+	subchart := func(name string) *chart.Chart {
+		return &chart.Chart{
+			Metadata: &chart.Metadata{Name: name},
+			Templates: []*chart.Template{
+				{Name: "templates/_helpers.tpl", Data: []byte(`{{define "` + name + `.fullname"}}{{.Release.Name}}-{{.Chart.Name}}{{end}}`)},
+				{Name: "templates/configmap", Data: []byte(`name: {{include "` + name + `.fullname" .}}
+template: {{.Template.Name}}
+replicas: {{.Values.replicas}}
+shared: {{include "umbrella.label" .}}`)},
+				{Name: "templates/service", Data: []byte(`port: {{.Values.port | default 80}}`)},
+			},
+		}
+	}
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "umbrella"},
+		Templates: []*chart.Template{
+			{Name: "templates/_helpers.tpl", Data: []byte(`{{define "umbrella.label"}}app={{.Chart.Name}}{{end}}`)},
+			{Name: "templates/notes", Data: []byte(`{{include "umbrella.label" .}} {{include "db.fullname" .}} {{.Values.db.ready}}`)},
+		},
+	}
+	newValues := func() chartutil.Values {
+		values := chartutil.Values{"db": map[string]interface{}{"ready": "no"}}
+		for i := 0; i < 8; i++ {
+			values[fmt.Sprintf("svc%d", i)] = map[string]interface{}{"replicas": i, "port": 8000 + i}
+		}
+		return chartutil.Values{"Chart": c.Metadata, "Release": chartutil.Values{"Name": "big"}, "Values": values}
+	}
+	for i := 0; i < 8; i++ {
+		c.Dependencies = append(c.Dependencies, subchart(fmt.Sprintf("svc%d", i)))
+	}
+	c.Dependencies = append(c.Dependencies, subchart("db"))
+	vals := newValues()
+
+	serial, err := New().Render(c, vals)
+	if err != nil {
+		t.Fatalf("Failed to render serially: %s", err)
+	}
+	if len(serial) != 2*9+1 {
+		t.Fatalf("Expected 19 rendered templates, got %d", len(serial))
+	}
+
+	e := New()
+	e.Parallelism = 4
+	for i := 0; i < 5; i++ {
+		parallel, err := e.Render(c, vals)
+		if err != nil {
+			t.Fatalf("Failed to render in parallel: %s", err)
+		}
+		if !reflect.DeepEqual(serial, parallel) {
+			t.Fatalf("Expected parallel output to equal serial output\nserial:   %v\nparallel: %v", serial, parallel)
+		}
+	}
+
+	// A subchart changing its values, which its parent reads, is rendered
+	// as it would be serially.
+	db := c.Dependencies[len(c.Dependencies)-1]
+	db.Templates = append(db.Templates, &chart.Template{Name: "templates/ready", Data: []byte(`{{$_ := set .Values "ready" "yes"}}`)})
+	serial, err = New().Render(c, newValues())
+	if err != nil {
+		t.Fatalf("Failed to render serially: %s", err)
+	}
+	if got := serial["umbrella/templates/notes"]; !strings.HasSuffix(got, " yes") {
+		t.Fatalf("Expected the parent to read the value set by the subchart, got %q", got)
+	}
+	for i := 0; i < 5; i++ {
+		parallel, err := e.Render(c, newValues())
+		if err != nil {
+			t.Fatalf("Failed to render in parallel: %s", err)
+		}
+		if !reflect.DeepEqual(serial, parallel) {
+			t.Fatalf("Expected parallel output to equal serial output with set\nserial:   %v\nparallel: %v", serial, parallel)
+		}
+	}
+	db.Templates = db.Templates[:len(db.Templates)-1]
+
+	// The size limit applies to the output of all charts together.
+	e.MaxRenderBytes = 100
+	if _, err := e.Render(c, vals); err != ErrRenderSizeExceeded {
+		t.Errorf("Expected %v, got %v", ErrRenderSizeExceeded, err)
+	}
+}
+
+func TestChangesValues(t *testing.T) {
+	for tpl, expect := range map[string]bool{
+		`{{.Values.name}}`:                 false,
+		`{{$_ := set .Values "name" "x"}}`: true,
+		`{{if .Values.on}}{{range .Values.list}}{{unset $ "a"}}{{end}}{{end}}`: true,
+		`{{with .Values}}{{else}}{{merge . $}}{{end}}`:                         true,
+		`{{template "x" (mergeOverwrite . $)}}`:                                true,
+		`{{tpl .Values.raw .}}`:                                                true,
+		`settings: {{"set"}}`:                                                  false,
+	} {
+		tmpl := template.Must(template.New("t").Funcs(FuncMap()).Parse(tpl))
+		if got := changesValues(tmpl); got != expect {
+			t.Errorf("%s: expected %t, got %t", tpl, expect, got)
+		}
+	}
+}
@@ -25,10 +25,12 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
 
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/release"
 	rls "k8s.io/helm/pkg/proto/hapi/services"
 )
 
@@ -97,6 +99,8 @@ func (h *Client) InstallReleaseWithContext(ctx context.Context, chstr, ns string
 }
 
 // InstallReleaseFromChart installs a new chart and returns the release response.
+// If the install fails, the response still carries the results of the hooks it
+// ran.
 func (h *Client) InstallReleaseFromChart(chart *chart.Chart, ns string, opts ...InstallOption) (*rls.InstallReleaseResponse, error) {
 	return h.installReleaseFromChartWithContext(NewContext(), chart, ns, opts...)
 }
@@ -192,6 +196,8 @@ func (h *Client) UpdateReleaseWithContext(ctx context.Context, rlsName string, c
 }
 
 // UpdateReleaseFromChart updates a release to a new/different chart.
+// If the upgrade fails, the response still carries the results of the hooks it
+// ran.
 func (h *Client) UpdateReleaseFromChart(rlsName string, chart *chart.Chart, opts ...UpdateOption) (*rls.UpdateReleaseResponse, error) {
 	return h.updateReleaseFromChartWithContext(NewContext(), rlsName, chart, opts...)
 }
@@ -368,6 +374,9 @@ func (h *Client) connect(ctx context.Context) (conn *grpc.ClientConn, err error)
 	default:
 		opts = append(opts, grpc.WithInsecure())
 	}
+	if h.opts.authToken != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(bearerToken(h.opts.authToken)))
+	}
 	ctx, cancel := context.WithTimeout(ctx, h.opts.connectTimeout)
 	defer cancel()
 	if conn, err = grpc.DialContext(ctx, h.opts.host, opts...); err != nil {
@@ -416,7 +425,11 @@ func (h *Client) install(ctx context.Context, req *rls.InstallReleaseRequest) (*
 	defer c.Close()
 
 	rlc := rls.NewReleaseServiceClient(c)
-	return rlc.InstallRelease(ctx, req)
+	res, err := rlc.InstallRelease(ctx, req)
+	if results := hookResults(err); len(results) > 0 {
+		res = &rls.InstallReleaseResponse{HookResults: results}
+	}
+	return res, err
 }
 
 // delete executes tiller.UninstallRelease RPC.
@@ -440,7 +453,27 @@ func (h *Client) update(ctx context.Context, req *rls.UpdateReleaseRequest) (*rl
 	defer c.Close()
 
 	rlc := rls.NewReleaseServiceClient(c)
-	return rlc.UpdateRelease(ctx, req)
+	res, err := rlc.UpdateRelease(ctx, req)
+	if results := hookResults(err); len(results) > 0 {
+		res = &rls.UpdateReleaseResponse{HookResults: results}
+	}
+	return res, err
+}
+
+// hookResults returns the results of the hooks a failed install or upgrade
+// ran, which Tiller attaches to its error.
+func hookResults(err error) []*release.HookResult {
+	st, ok := status.FromError(err)
+	if !ok || err == nil {
+		return nil
+	}
+	var results []*release.HookResult
+	for _, d := range st.Details() {
+		if r, ok := d.(*release.HookResult); ok {
+			results = append(results, r)
+		}
+	}
+	return results
 }
 
 // rollback executes tiller.RollbackRelease RPC.
@@ -563,3 +596,17 @@ func (h *Client) ping(ctx context.Context) error {
 		return fmt.Errorf("tiller healthcheck returned an unknown status")
 	}
 }
+
+// bearerToken sends a token in the authorization metadata of every rpc.
+type bearerToken string
+
+// GetRequestMetadata returns the authorization metadata holding the token.
+func (t bearerToken) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+// RequireTransportSecurity allows tokens over plaintext connections, as
+// Tiller is often reached through a port-forward.
+func (t bearerToken) RequireTransportSecurity() bool {
+	return false
+}
@@ -83,6 +83,8 @@ type options struct {
 	testReq rls.TestReleaseRequest
 	// connectTimeout specifies the time duration Helm will wait to establish a connection to tiller
 	connectTimeout time.Duration
+	// authToken is the bearer token sent with every rpc, if set
+	authToken string
 }
 
 // Host specifies the host address of the Tiller release server, (default = ":44134").
@@ -100,6 +102,14 @@ func WithTLS(cfg *tls.Config) Option {
 	}
 }
 
+// WithAuthToken specifies a bearer token sent to Tiller with every rpc, for
+// servers started with --auth-token-file.
+func WithAuthToken(token string) Option {
+	return func(opts *options) {
+		opts.authToken = token
+	}
+}
+
 // BeforeCall returns an option that allows intercepting a helm client rpc
 // before being sent OTA to tiller. The intercepting function should return
 // an error to indicate that the call should not proceed or nil otherwise.
@@ -166,6 +176,15 @@ func ReleaseListNamespace(namespace string) ReleaseListOption {
 	}
 }
 
+// ReleaseListChart specifies the name and version of the chart the listed
+// releases were deployed from. An empty name or version matches any.
+func ReleaseListChart(name, version string) ReleaseListOption {
+	return func(opts *options) {
+		opts.listReq.ChartName = name
+		opts.listReq.ChartVersion = version
+	}
+}
+
 // InstallOption allows specifying various settings
 // configurable by the helm client user for overriding
 // the defaults used when running the `helm install` command.
@@ -178,6 +197,14 @@ func ValueOverrides(raw []byte) InstallOption {
 	}
 }
 
+// InstallSetValues specifies key=value overrides, in the syntax of the --set
+// flag, that the server applies in order over the install's values.
+func InstallSetValues(values ...string) InstallOption {
+	return func(opts *options) {
+		opts.instReq.SetValues = values
+	}
+}
+
 // ReleaseName specifies the name of the release when installing.
 func ReleaseName(name string) InstallOption {
 	return func(opts *options) {
@@ -318,6 +345,13 @@ func UpgradeCleanupOnFail(cleanupOnFail bool) UpdateOption {
 	}
 }
 
+// UpgradeRestart will (if true) roll the Deployments and StatefulSets of the release after upgrade.
+func UpgradeRestart(restart bool) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.Restart = restart
+	}
+}
+
 // RollbackCleanupOnFail allows deletion of new resources created in this rollback when rollback failed
 func RollbackCleanupOnFail(cleanupOnFail bool) RollbackOption {
 	return func(opts *options) {
@@ -325,6 +359,14 @@ func RollbackCleanupOnFail(cleanupOnFail bool) RollbackOption {
 	}
 }
 
+// RollbackReuseValues will (if true) keep the values of the current release,
+// merged over those of the revision rolled back to.
+func RollbackReuseValues(reuse bool) RollbackOption {
+	return func(opts *options) {
+		opts.rollbackReq.ReuseValues = reuse
+	}
+}
+
 // DeleteDisableHooks will disable hooks for a deletion operation.
 func DeleteDisableHooks(disable bool) DeleteOption {
 	return func(opts *options) {
@@ -381,6 +423,13 @@ func InstallSubNotes(enable bool) InstallOption {
 	}
 }
 
+// InstallBaseRevision specifies the revision the release is first stored at
+func InstallBaseRevision(revision int32) InstallOption {
+	return func(opts *options) {
+		opts.instReq.BaseRevision = revision
+	}
+}
+
 // UpgradeSubNotes will (if true) instruct Tiller to render SubChart Notes
 func UpgradeSubNotes(enable bool) UpdateOption {
 	return func(opts *options) {
@@ -478,6 +527,14 @@ func ContentReleaseVersion(version int32) ContentOption {
 	}
 }
 
+// ContentExcludeChart will instruct Tiller to leave out everything but the
+// metadata of the release's chart.
+func ContentExcludeChart(exclude bool) ContentOption {
+	return func(opts *options) {
+		opts.contentReq.ExcludeChart = exclude
+	}
+}
+
 // StatusOption allows setting optional attributes when
 // performing a GetReleaseStatus tiller rpc.
 type StatusOption func(*options)
@@ -519,6 +576,14 @@ func WithMaxHistory(max int32) HistoryOption {
 	}
 }
 
+// WithHistoryNamespace limits a release history query to the
+// revisions installed in namespace.
+func WithHistoryNamespace(namespace string) HistoryOption {
+	return func(opts *options) {
+		opts.histReq.Namespace = namespace
+	}
+}
+
 // NewContext creates a versioned context.
 func NewContext() context.Context {
 	return FromContext(context.TODO())
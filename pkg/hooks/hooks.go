@@ -29,6 +29,15 @@ const (
 	HookDeleteAnno = "helm.sh/hook-delete-policy"
 	// HookDeleteTimeoutAnno is the label name for the timeout value for delete policies
 	HookDeleteTimeoutAnno = "helm.sh/hook-delete-timeout"
+	// HookRequiresApprovalAnno is the label name for pausing a release before a hook until it is approved
+	HookRequiresApprovalAnno = "helm.sh/hook-requires-approval"
+)
+
+const (
+	// TestLabel is the label set to "true" on every resource created by a release test run
+	TestLabel = "helm.sh/test"
+	// TestReleaseLabel is the label holding the name of the release a test resource belongs to
+	TestReleaseLabel = "helm.sh/test-release"
 )
 
 // Types of hooks
@@ -42,11 +42,13 @@ import (
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/jsonmergepatch"
 	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apimachinery/pkg/watch"
@@ -73,6 +75,31 @@ var metadataAccessor = meta.NewAccessor()
 type Client struct {
 	cmdutil.Factory
 	Log func(string, ...interface{})
+
+	// DeletionPropagation is the propagation policy used when deleting
+	// resources, and when an update removes resources that are no longer in
+	// the manifest. It defaults to background deletion.
+	DeletionPropagation metav1.DeletionPropagation
+
+	// NamespaceLabels are added to the namespaces created for releases.
+	NamespaceLabels map[string]string
+
+	// LabelExistingNamespaces adds NamespaceLabels to existing namespaces
+	// releases are installed into as well.
+	LabelExistingNamespaces bool
+
+	// FieldManager, when set, updates resources with server-side apply under
+	// this field manager instead of client-computed patches. Fields owned by
+	// other managers are not overwritten; the update fails on conflict, even
+	// with force. Resources not yet applied by this manager, such as those
+	// last updated with client-side patches, are taken over on their first
+	// apply.
+	FieldManager string
+
+	// SchemaValidation makes Validate check resources against the OpenAPI
+	// schema served by the API server, catching unknown fields and missing
+	// required ones.
+	SchemaValidation bool
 }
 
 // New creates a new Client.
@@ -97,30 +124,173 @@ var nopLogger = func(_ string, _ ...interface{}) {}
 // ResourceActorFunc performs an action on a single resource.
 type ResourceActorFunc func(*resource.Info) error
 
+// AppliedResource identifies the version of an object that was left in the
+// cluster by a create or update.
+type AppliedResource struct {
+	Kind            string
+	Namespace       string
+	Name            string
+	UID             string
+	ResourceVersion string
+}
+
 // Create creates Kubernetes resources from an io.reader.
 //
 // Namespace will set the namespace.
 func (c *Client) Create(namespace string, reader io.Reader, timeout int64, shouldWait bool) error {
+	_, err := c.CreateWithResult(namespace, reader, CreateOptions{Timeout: timeout, ShouldWait: shouldWait})
+	return err
+}
+
+// CreateOptions provides options to control create behavior
+type CreateOptions struct {
+	Timeout    int64
+	ShouldWait bool
+	// Owner, if set, becomes an owner of the created resources
+	Owner *Owner
+	// Release, if set, names the release the resources are created for. It
+	// is recorded on resources annotated to be kept, and an existing kept
+	// resource recording the same release, left behind when that release was
+	// deleted, is taken over instead of failing the create.
+	Release string
+}
+
+// Owner is an object that applied resources are made dependents of, so that
+// deleting it lets Kubernetes garbage collect them.
+//
+// Garbage collection does not cross namespaces, so only namespaced resources
+// in the owner's namespace get an owner reference. Resources annotated to be
+// kept never get one, as they must survive the deletion of the owner.
+type Owner struct {
+	Namespace string
+	Reference metav1.OwnerReference
+}
+
+// setReleaseName records the release name on the resources in infos that are
+// annotated to be kept.
+func setReleaseName(infos Result, release string) error {
+	if release == "" {
+		return nil
+	}
+	for _, info := range infos {
+		annotations, err := metadataAccessor.Annotations(info.Object)
+		if err != nil {
+			return err
+		}
+		if !ResourcePolicyIsKeep(annotations) {
+			continue
+		}
+		annotations[ReleaseNameAnno] = release
+		if err := metadataAccessor.SetAnnotations(info.Object, annotations); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setPodTemplateAnnotations sets annotations on the pod templates of the
+// Deployments and StatefulSets in infos.
+func setPodTemplateAnnotations(infos Result, annotations map[string]string) error {
+	if len(annotations) == 0 {
+		return nil
+	}
+	for _, info := range infos {
+		switch info.Mapping.GroupVersionKind.Kind {
+		case "Deployment", "StatefulSet":
+		default:
+			continue
+		}
+		obj, ok := info.Object.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+		for k, v := range annotations {
+			if err := unstructured.SetNestedField(obj.Object, v, "spec", "template", "metadata", "annotations", k); err != nil {
+				return fmt.Errorf("could not annotate the pod template of %s/%s: %s", info.Mapping.GroupVersionKind.Kind, info.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// setOwner adds the owner's reference to the resources in infos that can be
+// its dependents, replacing any existing reference to the same object.
+func setOwner(infos Result, owner *Owner) error {
+	if owner == nil {
+		return nil
+	}
+	for _, info := range infos {
+		if !info.Namespaced() || info.Namespace != owner.Namespace {
+			continue
+		}
+		obj, err := meta.Accessor(info.Object)
+		if err != nil {
+			return err
+		}
+		if ResourcePolicyIsKeep(obj.GetAnnotations()) {
+			continue
+		}
+		refs := []metav1.OwnerReference{owner.Reference}
+		for _, ref := range obj.GetOwnerReferences() {
+			if ref.UID != owner.Reference.UID {
+				refs = append(refs, ref)
+			}
+		}
+		obj.SetOwnerReferences(refs)
+	}
+	return nil
+}
+
+// CreateWithResult creates Kubernetes resources from an io.reader like Create,
+// and returns the UID and resource version of every object created.
+func (c *Client) CreateWithResult(namespace string, reader io.Reader, opts CreateOptions) ([]AppliedResource, error) {
 	client, err := c.KubernetesClientSet()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if err := ensureNamespace(client, namespace); err != nil {
-		return err
+	if err := ensureLabeledNamespace(client, namespace, c.NamespaceLabels, c.LabelExistingNamespaces); err != nil {
+		return nil, err
 	}
 	c.Log("building resources from manifest")
 	infos, buildErr := c.BuildUnstructured(namespace, reader)
 	if buildErr != nil {
-		return buildErr
+		return nil, buildErr
+	}
+	if err := setOwner(infos, opts.Owner); err != nil {
+		return nil, err
+	}
+	if err := setReleaseName(infos, opts.Release); err != nil {
+		return nil, err
 	}
 	c.Log("creating %d resource(s)", len(infos))
-	if err := perform(infos, createResource); err != nil {
-		return err
+	if err := perform(infos, func(info *resource.Info) error {
+		return createResource(info, c.FieldManager, opts.Release)
+	}); err != nil {
+		return nil, err
 	}
-	if shouldWait {
-		return c.waitForResources(time.Duration(timeout)*time.Second, infos)
+	if opts.ShouldWait {
+		if err := c.waitForResources(time.Duration(opts.Timeout)*time.Second, infos); err != nil {
+			return nil, err
+		}
 	}
-	return nil
+	return appliedResources(infos), nil
+}
+
+// appliedResources describes the objects held by infos, which must have been
+// refreshed from the API server response.
+func appliedResources(infos Result) []AppliedResource {
+	applied := make([]AppliedResource, 0, len(infos))
+	for _, info := range infos {
+		uid, _ := metadataAccessor.UID(info.Object)
+		applied = append(applied, AppliedResource{
+			Kind:            info.Mapping.GroupVersionKind.Kind,
+			Namespace:       info.Namespace,
+			Name:            info.Name,
+			UID:             string(uid),
+			ResourceVersion: info.ResourceVersion,
+		})
+	}
+	return applied
 }
 
 func (c *Client) newBuilder(namespace string, reader io.Reader) *resource.Result {
@@ -160,16 +330,19 @@ func (c *Client) BuildUnstructured(namespace string, reader io.Reader) (Result,
 
 // Validate reads Kubernetes manifests and validates the content.
 //
-// This function does not actually do schema validation of manifests. Adding
-// validation now breaks existing clients of helm: https://github.com/helm/helm/issues/5750
+// Manifests are only checked against the OpenAPI schema when SchemaValidation
+// is set. It is off by default because enabling it unconditionally breaks
+// existing clients of helm: https://github.com/helm/helm/issues/5750
 func (c *Client) Validate(namespace string, reader io.Reader) error {
-	_, err := c.NewBuilder().
+	b := c.NewBuilder().
 		Unstructured().
 		ContinueOnError().
 		NamespaceParam(namespace).
-		DefaultNamespace().
-		// Schema(c.validator()). // No schema validation
-		Stream(reader, "").
+		DefaultNamespace()
+	if c.SchemaValidation {
+		b = b.Schema(c.validator())
+	}
+	_, err := b.Stream(reader, "").
 		Flatten().
 		Do().Infos()
 	return scrubValidationError(err)
@@ -345,6 +518,15 @@ type UpdateOptions struct {
 	ShouldWait bool
 	// Allow deletion of new resources created in this update when update failed
 	CleanupOnFail bool
+	// Owner, if set, becomes an owner of the created and updated resources
+	Owner *Owner
+	// Release, if set, is recorded on the resources annotated to be kept, as
+	// with CreateOptions.
+	Release string
+	// PodTemplateAnnotations are set on the pod templates of the Deployments
+	// and StatefulSets updated. Changing them rolls the pods out again, as
+	// part of the update and before any wait.
+	PodTemplateAnnotations map[string]string
 }
 
 // UpdateWithOptions reads the current configuration and a target configuration from io.reader
@@ -355,15 +537,31 @@ type UpdateOptions struct {
 // Namespace will set the namespaces. UpdateOptions provides additional parameters to control
 // update behavior.
 func (c *Client) UpdateWithOptions(namespace string, originalReader, targetReader io.Reader, opts UpdateOptions) error {
+	_, err := c.UpdateWithResult(namespace, originalReader, targetReader, opts)
+	return err
+}
+
+// UpdateWithResult updates resources like UpdateWithOptions, and returns the
+// UID and resource version of every object of the target configuration.
+func (c *Client) UpdateWithResult(namespace string, originalReader, targetReader io.Reader, opts UpdateOptions) ([]AppliedResource, error) {
 	original, err := c.BuildUnstructured(namespace, originalReader)
 	if err != nil {
-		return fmt.Errorf("failed decoding reader into objects: %s", err)
+		return nil, fmt.Errorf("failed decoding reader into objects: %s", err)
 	}
 
 	c.Log("building resources from updated manifest")
 	target, err := c.BuildUnstructured(namespace, targetReader)
 	if err != nil {
-		return fmt.Errorf("failed decoding reader into objects: %s", err)
+		return nil, fmt.Errorf("failed decoding reader into objects: %s", err)
+	}
+	if err := setOwner(target, opts.Owner); err != nil {
+		return nil, err
+	}
+	if err := setReleaseName(target, opts.Release); err != nil {
+		return nil, err
+	}
+	if err := setPodTemplateAnnotations(target, opts.PodTemplateAnnotations); err != nil {
+		return nil, err
 	}
 
 	newlyCreatedResources := []*resource.Info{}
@@ -376,13 +574,14 @@ func (c *Client) UpdateWithOptions(namespace string, originalReader, targetReade
 		}
 
 		helper := resource.NewHelper(info.Client, info.Mapping)
-		if _, err := helper.Get(info.Namespace, info.Name, info.Export); err != nil {
+		live, err := helper.Get(info.Namespace, info.Name, info.Export)
+		if err != nil {
 			if !errors.IsNotFound(err) {
 				return fmt.Errorf("Could not get information about the resource: %s", err)
 			}
 
 			// Since the resource does not exist, create it.
-			if err := createResource(info); err != nil {
+			if err := createResource(info, c.FieldManager, ""); err != nil {
 				return fmt.Errorf("failed to create resource: %s", err)
 			}
 			newlyCreatedResources = append(newlyCreatedResources, info)
@@ -407,7 +606,7 @@ func (c *Client) UpdateWithOptions(namespace string, originalReader, targetReade
 			)
 		}
 
-		if err := updateResource(c, info, originalInfo.Object, opts.Force, opts.Recreate); err != nil {
+		if err := updateResource(c, info, originalInfo.Object, live, opts.Force, opts.Recreate); err != nil {
 			c.Log("error updating the resource %q:\n\t %v", info.Name, err)
 			updateErrors = append(updateErrors, err.Error())
 		}
@@ -424,9 +623,9 @@ func (c *Client) UpdateWithOptions(namespace string, originalReader, targetReade
 
 	switch {
 	case err != nil:
-		return fmt.Errorf(strings.Join(append([]string{err.Error()}, cleanupErrors...), " && "))
+		return nil, fmt.Errorf(strings.Join(append([]string{err.Error()}, cleanupErrors...), " && "))
 	case len(updateErrors) != 0:
-		return fmt.Errorf(strings.Join(append(updateErrors, cleanupErrors...), " && "))
+		return nil, fmt.Errorf(strings.Join(append(updateErrors, cleanupErrors...), " && "))
 	}
 
 	for _, info := range original.Difference(target) {
@@ -445,7 +644,7 @@ func (c *Client) UpdateWithOptions(namespace string, originalReader, targetReade
 			continue
 		}
 
-		if err := deleteResource(info); err != nil {
+		if err := deleteResource(info, c.deletionPropagation()); err != nil {
 			c.Log("Failed to delete %q, err: %s", info.Name, err)
 		}
 	}
@@ -455,19 +654,20 @@ func (c *Client) UpdateWithOptions(namespace string, originalReader, targetReade
 		if opts.CleanupOnFail && err != nil {
 			c.Log("Cleanup on fail enabled: cleaning up newly created resources due to wait failure during update")
 			cleanupErrors = c.cleanup(newlyCreatedResources)
-			return fmt.Errorf(strings.Join(append([]string{err.Error()}, cleanupErrors...), " && "))
+			return nil, fmt.Errorf(strings.Join(append([]string{err.Error()}, cleanupErrors...), " && "))
+		}
+		if err != nil {
+			return nil, err
 		}
-
-		return err
 	}
-	return nil
+	return appliedResources(target), nil
 }
 
 func (c *Client) cleanup(newlyCreatedResources []*resource.Info) (cleanupErrors []string) {
 	for _, info := range newlyCreatedResources {
 		kind := info.Mapping.GroupVersionKind.Kind
 		c.Log("Deleting newly created %s with the name %q in %s...", kind, info.Name, info.Namespace)
-		if err := deleteResource(info); err != nil {
+		if err := deleteResource(info, c.deletionPropagation()); err != nil {
 			c.Log("Error deleting newly created %s with the name %q in %s: %s", kind, info.Name, info.Namespace, err)
 			cleanupErrors = append(cleanupErrors, err.Error())
 		}
@@ -494,7 +694,7 @@ func (c *Client) DeleteWithTimeout(namespace string, reader io.Reader, timeout i
 	}
 	err = perform(infos, func(info *resource.Info) error {
 		c.Log("Starting delete for %q %s", info.Name, info.Mapping.GroupVersionKind.Kind)
-		err := deleteResource(info)
+		err := deleteResource(info, c.deletionPropagation())
 		return c.skipIfNotFound(err)
 	})
 	if err != nil {
@@ -538,9 +738,74 @@ func waitUntilAllResourceDeleted(infos Result, timeout time.Duration) error {
 	})
 }
 
-func (c *Client) watchTimeout(t time.Duration) ResourceActorFunc {
+// removeFinalizersPatch is a JSON merge patch that clears an object's finalizers.
+var removeFinalizersPatch = []byte(`{"metadata":{"finalizers":null}}`)
+
+// WaitForDeletion waits up to timeout for the resources in reader to be removed
+// from the cluster, and returns the resources that still exist afterwards in
+// the form "Kind/name".
+//
+// If removeFinalizers is true, the finalizers of the resources still present
+// when the timeout expires are cleared, and the resources are waited for once
+// more so the API server can complete their deletion.
+//
+// A timeout of 0 waits until every resource is gone.
+func (c *Client) WaitForDeletion(namespace string, reader io.Reader, timeout time.Duration, removeFinalizers bool) ([]string, error) {
+	infos, err := c.BuildUnstructured(namespace, reader)
+	if err != nil {
+		return nil, err
+	}
+	remaining, err := waitForRemaining(infos, timeout)
+	if err != nil || len(remaining) == 0 || !removeFinalizers {
+		return resourceNames(remaining), err
+	}
+
+	for _, info := range remaining {
+		c.Log("Removing finalizers from %q %s", info.Name, info.Mapping.GroupVersionKind.Kind)
+		_, err := resource.NewHelper(info.Client, info.Mapping).Patch(info.Namespace, info.Name, types.MergePatchType, removeFinalizersPatch, nil)
+		if err := c.skipIfNotFound(err); err != nil {
+			return resourceNames(remaining), fmt.Errorf("removing finalizers from %s/%s: %s", info.Mapping.GroupVersionKind.Kind, info.Name, err)
+		}
+	}
+	remaining, err = waitForRemaining(remaining, timeout)
+	return resourceNames(remaining), err
+}
+
+// waitForRemaining polls infos until none of them exist or timeout expires,
+// and returns the ones still found. An expired timeout is not an error.
+func waitForRemaining(infos Result, timeout time.Duration) (Result, error) {
+	var remaining Result
+	err := wait.PollImmediate(2*time.Second, timeout, func() (bool, error) {
+		remaining = nil
+		for _, info := range infos {
+			err := info.Get()
+			if errors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return false, err
+			}
+			remaining = append(remaining, info)
+		}
+		return len(remaining) == 0, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		err = nil
+	}
+	return remaining, err
+}
+
+func resourceNames(infos Result) []string {
+	names := make([]string, 0, len(infos))
+	for _, info := range infos {
+		names = append(names, info.Mapping.GroupVersionKind.Kind+"/"+info.Name)
+	}
+	return names
+}
+
+func (c *Client) watchTimeout(ctx context.Context, t time.Duration) ResourceActorFunc {
 	return func(info *resource.Info) error {
-		return c.watchUntilReady(t, info)
+		return c.watchUntilReady(ctx, t, info)
 	}
 }
 
@@ -557,17 +822,25 @@ func (c *Client) watchTimeout(t time.Duration) ResourceActorFunc {
 //
 // Handling for other kinds will be added as necessary.
 func (c *Client) WatchUntilReady(namespace string, reader io.Reader, timeout int64, shouldWait bool) error {
+	return c.WatchUntilReadyContext(context.Background(), namespace, reader, timeout, shouldWait)
+}
+
+// WatchUntilReadyContext is like WatchUntilReady, but stops watching as soon
+// as ctx is done.
+func (c *Client) WatchUntilReadyContext(ctx context.Context, namespace string, reader io.Reader, timeout int64, shouldWait bool) error {
 	infos, err := c.BuildUnstructured(namespace, reader)
 	if err != nil {
 		return err
 	}
 	// For jobs, there's also the option to do poll c.Jobs(namespace).Get():
 	// https://github.com/adamreese/kubernetes/blob/master/test/e2e/job.go#L291-L300
-	return perform(infos, c.watchTimeout(time.Duration(timeout)*time.Second))
+	return perform(infos, c.watchTimeout(ctx, time.Duration(timeout)*time.Second))
 }
 
 // WaitUntilCRDEstablished polls the given CRD until it reaches the established
 // state. A CRD needs to reach the established state before CRs can be created.
+// The cached discovery information is then refreshed, so that the resources
+// built afterwards can be of the kinds the CRDs define.
 //
 // If a naming conflict condition is found, this function will return an error.
 func (c *Client) WaitUntilCRDEstablished(reader io.Reader, timeout time.Duration) error {
@@ -576,7 +849,25 @@ func (c *Client) WaitUntilCRDEstablished(reader io.Reader, timeout time.Duration
 		return err
 	}
 
-	return perform(infos, c.pollCRDEstablished(timeout))
+	if err := perform(infos, c.pollCRDEstablished(timeout)); err != nil {
+		return err
+	}
+	c.resetRESTMapper()
+	return nil
+}
+
+// resetRESTMapper replaces the discovery information cached for the REST
+// mapper with the current one from the API server.
+func (c *Client) resetRESTMapper() {
+	dc, err := c.ToDiscoveryClient()
+	if err != nil {
+		c.Log("warning: could not refresh discovery information: %s", err)
+		return
+	}
+	dc.Invalidate()
+	if _, _, err := dc.ServerGroupsAndResources(); err != nil {
+		c.Log("warning: could not refresh discovery information: %s", err)
+	}
 }
 
 func (c *Client) pollCRDEstablished(t time.Duration) ResourceActorFunc {
@@ -649,16 +940,109 @@ func batchPerform(infos Result, fn ResourceActorFunc, errs chan<- error) {
 	}
 }
 
-func createResource(info *resource.Info) error {
-	obj, err := resource.NewHelper(info.Client, info.Mapping).Create(info.Namespace, true, info.Object, nil)
+// createResource creates the resource described by info. If it already
+// exists, was kept by the resource policy when release was deleted, and is
+// kept by the new manifest too, the live object is adopted instead.
+func createResource(info *resource.Info, fieldManager, release string) error {
+	var opts *metav1.CreateOptions
+	if fieldManager != "" {
+		opts = &metav1.CreateOptions{FieldManager: fieldManager}
+	}
+	helper := resource.NewHelper(info.Client, info.Mapping)
+	obj, err := helper.Create(info.Namespace, true, info.Object, opts)
+	if errors.IsAlreadyExists(err) {
+		if live, ok := keptFor(helper, info, release); ok {
+			// The resource was left behind by the resource policy when an
+			// earlier release was deleted: take it over instead of failing
+			// the install.
+			obj, err = adoptResource(helper, info, live)
+		}
+	}
 	if err != nil {
 		return err
 	}
 	return info.Refresh(obj, true)
 }
 
-func deleteResource(info *resource.Info) error {
-	policy := metav1.DeletePropagationBackground
+// keptFor returns the live object described by info if both it and the object
+// about to be created are annotated to be kept on deletion, and the live object
+// was created by the named release. Resources kept before the release name was
+// recorded are attributed to a release by their instance label instead.
+func keptFor(helper *resource.Helper, info *resource.Info, release string) (runtime.Object, bool) {
+	if release == "" {
+		return nil, false
+	}
+	annotations, err := metadataAccessor.Annotations(info.Object)
+	if err != nil || !ResourcePolicyIsKeep(annotations) {
+		return nil, false
+	}
+	live, err := helper.Get(info.Namespace, info.Name, false)
+	if err != nil {
+		return nil, false
+	}
+	annotations, err = metadataAccessor.Annotations(live)
+	if err != nil || !ResourcePolicyIsKeep(annotations) {
+		return nil, false
+	}
+	if name, ok := annotations[ReleaseNameAnno]; ok {
+		return live, name == release
+	}
+	liveLabels, err := metadataAccessor.Labels(live)
+	if err != nil {
+		return nil, false
+	}
+	return live, liveLabels["app.kubernetes.io/instance"] == release || liveLabels["release"] == release
+}
+
+// adoptResource patches live with the object described by info. The rendered
+// object is both the original and the modified configuration of a three-way
+// patch, so fields only set on the live object, such as the volume a claim is
+// bound to, are left alone.
+func adoptResource(helper *resource.Helper, info *resource.Info, live runtime.Object) (runtime.Object, error) {
+	liveData, err := json.Marshal(live)
+	if err != nil {
+		return nil, fmt.Errorf("serializing live configuration: %s", err)
+	}
+	newData, err := json.Marshal(info.Object)
+	if err != nil {
+		return nil, fmt.Errorf("serializing target configuration: %s", err)
+	}
+
+	var (
+		patch     []byte
+		patchType types.PatchType
+	)
+	versionedObject, err := asVersioned(info)
+	_, isUnstructured := versionedObject.(runtime.Unstructured)
+	_, isCRD := versionedObject.(*apiextv1beta1.CustomResourceDefinition)
+	switch {
+	case runtime.IsNotRegisteredError(err), isUnstructured, isCRD:
+		patchType = types.MergePatchType
+		patch, err = jsonmergepatch.CreateThreeWayJSONMergePatch(newData, newData, liveData)
+	case err != nil:
+		return nil, fmt.Errorf("failed to get versionedObject: %s", err)
+	default:
+		patchType = types.StrategicMergePatchType
+		var lookup strategicpatch.LookupPatchMeta
+		if lookup, err = strategicpatch.NewPatchMetaFromStruct(versionedObject); err == nil {
+			patch, err = strategicpatch.CreateThreeWayMergePatch(newData, newData, liveData, lookup, true)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create three-way patch: %s", err)
+	}
+	return helper.Patch(info.Namespace, info.Name, patchType, patch, nil)
+}
+
+// deletionPropagation returns the propagation policy for deleting resources.
+func (c *Client) deletionPropagation() metav1.DeletionPropagation {
+	if c.DeletionPropagation == "" {
+		return metav1.DeletePropagationBackground
+	}
+	return c.DeletionPropagation
+}
+
+func deleteResource(info *resource.Info, policy metav1.DeletionPropagation) error {
 	opts := &metav1.DeleteOptions{PropagationPolicy: &policy}
 	_, err := resource.NewHelper(info.Client, info.Mapping).DeleteWithOptions(info.Namespace, info.Name, opts)
 	return err
@@ -713,8 +1097,53 @@ func createPatch(target *resource.Info, current runtime.Object) ([]byte, types.P
 	}
 }
 
-func updateResource(c *Client, target *resource.Info, currentObj runtime.Object, force bool, recreate bool) error {
-	patch, patchType, err := createPatch(target, currentObj)
+// createApplyPatch returns the target configuration as a server-side apply
+// patch.
+func createApplyPatch(target *resource.Info) ([]byte, types.PatchType, error) {
+	data, err := json.Marshal(target.Object)
+	if err != nil {
+		return nil, types.ApplyPatchType, fmt.Errorf("serializing target configuration: %s", err)
+	}
+	return data, types.ApplyPatchType, nil
+}
+
+// appliedBy reports whether obj has been updated with server-side apply by
+// the field manager.
+func appliedBy(obj runtime.Object, manager string) bool {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return false
+	}
+	for _, f := range accessor.GetManagedFields() {
+		if f.Manager == manager && f.Operation == metav1.ManagedFieldsOperationApply {
+			return true
+		}
+	}
+	return false
+}
+
+func updateResource(c *Client, target *resource.Info, currentObj, liveObj runtime.Object, force bool, recreate bool) error {
+	var (
+		patch     []byte
+		patchType types.PatchType
+		opts      *metav1.PatchOptions
+		err       error
+	)
+	if c.FieldManager != "" {
+		patch, patchType, err = createApplyPatch(target)
+		opts = &metav1.PatchOptions{FieldManager: c.FieldManager}
+		// The fields of a resource last written with client-side patches
+		// are owned by the managers of those patches. The first server-side
+		// apply takes them over rather than conflict with Tiller's own
+		// earlier writes.
+		if !appliedBy(liveObj, c.FieldManager) {
+			c.Log("Taking over the fields of %s %q with its first server-side apply", target.Mapping.GroupVersionKind.Kind, target.Name)
+			takeOver := true
+			opts.Force = &takeOver
+		}
+	} else {
+		patch, patchType, err = createPatch(target, currentObj)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create patch: %s", err)
 	}
@@ -729,20 +1158,25 @@ func updateResource(c *Client, target *resource.Info, currentObj runtime.Object,
 		// send patch to server
 		helper := resource.NewHelper(target.Client, target.Mapping)
 
-		obj, err := helper.Patch(target.Namespace, target.Name, patchType, patch, nil)
+		obj, err := helper.Patch(target.Namespace, target.Name, patchType, patch, opts)
 		if err != nil {
 			kind := target.Mapping.GroupVersionKind.Kind
 			log.Printf("Cannot patch %s: %q (%v)", kind, target.Name, err)
 
+			// A field ownership conflict is not resolved by recreating the
+			// resource, which would discard the other managers' fields.
+			if c.FieldManager != "" && errors.IsConflict(err) {
+				return fmt.Errorf("server-side apply of %s %q conflicts with fields owned by other managers: %s", kind, target.Name, err)
+			}
 			if force {
 				// Attempt to delete...
-				if err := deleteResource(target); err != nil {
+				if err := deleteResource(target, metav1.DeletePropagationBackground); err != nil {
 					return err
 				}
 				log.Printf("Deleted %s: %q", kind, target.Name)
 
 				// ... and recreate
-				if err := createResource(target); err != nil {
+				if err := createResource(target, c.FieldManager, ""); err != nil {
 					return fmt.Errorf("Failed to recreate resource: %s", err)
 				}
 				log.Printf("Created a new %s called %q\n", kind, target.Name)
@@ -836,7 +1270,7 @@ func getSelectorFromObject(obj runtime.Object) (map[string]string, bool) {
 	}
 }
 
-func (c *Client) watchUntilReady(timeout time.Duration, info *resource.Info) error {
+func (c *Client) watchUntilReady(ctx context.Context, timeout time.Duration, info *resource.Info) error {
 	// Use a selector on the name of the resource. This should be unique for the
 	// given version and kind
 	selector, err := fields.ParseSelector(fmt.Sprintf("metadata.name=%s", info.Name))
@@ -854,7 +1288,7 @@ func (c *Client) watchUntilReady(timeout time.Duration, info *resource.Info) err
 	// In the future, we might want to add some special logic for types
 	// like Ingress, Volume, etc.
 
-	ctx, cancel := watchtools.ContextWithOptionalTimeout(context.Background(), timeout)
+	ctx, cancel := watchtools.ContextWithOptionalTimeout(ctx, timeout)
 	defer cancel()
 	_, err = watchtools.ListWatchUntil(ctx, lw, func(e watch.Event) (bool, error) {
 		switch e.Type {
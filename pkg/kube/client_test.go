@@ -18,6 +18,8 @@ package kube
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
@@ -26,12 +28,18 @@ import (
 	"testing"
 	"time"
 
+	batch "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 	apiextv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest/fake"
 	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
@@ -246,6 +254,274 @@ func TestUpdate(t *testing.T) {
 	}
 }
 
+func TestUpdateWithResult(t *testing.T) {
+	current := newPodList("starfish")
+	target := newPodList("starfish", "dolphin")
+	target.Items[0].Spec.Containers[0].Ports = []v1.ContainerPort{{Name: "https", ContainerPort: 443}}
+
+	patched := target.Items[0]
+	patched.UID = "starfish-uid"
+	patched.ResourceVersion = "42"
+	created := target.Items[1]
+	created.UID = "dolphin-uid"
+	created.ResourceVersion = "7"
+
+	tf := cmdtesting.NewTestFactory()
+	defer tf.Cleanup()
+
+	tf.UnstructuredClient = &fake.RESTClient{
+		NegotiatedSerializer: unstructuredSerializer,
+		Client: fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			p, m := req.URL.Path, req.Method
+			switch {
+			case p == "/namespaces/default/pods/starfish" && m == "GET":
+				return newResponse(200, &current.Items[0])
+			case p == "/namespaces/default/pods/starfish" && m == "PATCH":
+				return newResponse(200, &patched)
+			case p == "/namespaces/default/pods/dolphin" && m == "GET":
+				return newResponse(404, notFoundBody())
+			case p == "/namespaces/default/pods" && m == "POST":
+				return newResponse(200, &created)
+			default:
+				t.Fatalf("unexpected request: %s %s", req.Method, req.URL.Path)
+				return nil, nil
+			}
+		}),
+	}
+
+	c := &Client{
+		Factory: tf,
+		Log:     nopLogger,
+	}
+
+	applied, err := c.UpdateWithResult(v1.NamespaceDefault, objBody(&current), objBody(&target), UpdateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []AppliedResource{
+		{Kind: "Pod", Namespace: "default", Name: "starfish", UID: "starfish-uid", ResourceVersion: "42"},
+		{Kind: "Pod", Namespace: "default", Name: "dolphin", UID: "dolphin-uid", ResourceVersion: "7"},
+	}
+	if len(applied) != len(expected) {
+		t.Fatalf("expected %d applied resources, got %d: %v", len(expected), len(applied), applied)
+	}
+	for i, e := range expected {
+		if applied[i] != e {
+			t.Errorf("expected applied resource %+v, got %+v", e, applied[i])
+		}
+	}
+}
+
+func TestUpdateFieldManager(t *testing.T) {
+	current := newPodList("starfish")
+	target := newPodList("starfish", "dolphin")
+	target.Items[0].Spec.Containers[0].Ports = []v1.ContainerPort{{Name: "https", ContainerPort: 443}}
+
+	// The live pod carries a label set by another field manager, and has
+	// been applied by Tiller before.
+	live := current.Items[0]
+	live.Labels = map[string]string{"scaled-by": "autoscaler"}
+	live.ManagedFields = []metav1.ManagedFieldsEntry{
+		{Manager: "autoscaler", Operation: metav1.ManagedFieldsOperationUpdate},
+		{Manager: "tiller", Operation: metav1.ManagedFieldsOperationApply},
+	}
+
+	var conflict bool
+	wantForce := ""
+	tf := cmdtesting.NewTestFactory()
+	defer tf.Cleanup()
+
+	tf.UnstructuredClient = &fake.RESTClient{
+		NegotiatedSerializer: unstructuredSerializer,
+		Client: fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			p, m := req.URL.Path, req.Method
+			switch {
+			case p == "/namespaces/default/pods/starfish" && m == "GET":
+				return newResponse(200, &live)
+			case p == "/namespaces/default/pods/starfish" && m == "PATCH":
+				if ct := req.Header.Get("Content-Type"); ct != string(types.ApplyPatchType) {
+					t.Errorf("expected patch content type %q, got %q", types.ApplyPatchType, ct)
+				}
+				if fm := req.URL.Query().Get("fieldManager"); fm != "tiller" {
+					t.Errorf("expected field manager %q, got %q", "tiller", fm)
+				}
+				if force := req.URL.Query().Get("force"); force != wantForce {
+					t.Errorf("expected force=%q, got force=%q", wantForce, force)
+				}
+				var applied v1.Pod
+				if err := json.NewDecoder(req.Body).Decode(&applied); err != nil {
+					t.Fatalf("decoding apply patch: %s", err)
+				}
+				if _, ok := applied.Labels["scaled-by"]; ok {
+					t.Errorf("expected apply patch not to mention fields owned by another manager, got labels %v", applied.Labels)
+				}
+				if ports := applied.Spec.Containers[0].Ports; len(ports) != 1 || ports[0].ContainerPort != 443 {
+					t.Errorf("expected apply patch to carry the target ports, got %v", ports)
+				}
+				if conflict {
+					return newResponse(409, &metav1.Status{
+						Status: metav1.StatusFailure,
+						Code:   http.StatusConflict,
+						Reason: metav1.StatusReasonConflict,
+					})
+				}
+				merged := target.Items[0]
+				merged.Labels = live.Labels
+				return newResponse(200, &merged)
+			case p == "/namespaces/default/pods/dolphin" && m == "GET":
+				return newResponse(404, notFoundBody())
+			case p == "/namespaces/default/pods" && m == "POST":
+				if fm := req.URL.Query().Get("fieldManager"); fm != "tiller" {
+					t.Errorf("expected create field manager %q, got %q", "tiller", fm)
+				}
+				return newResponse(200, &target.Items[1])
+			default:
+				t.Fatalf("unexpected request: %s %s", req.Method, req.URL.Path)
+				return nil, nil
+			}
+		}),
+	}
+
+	c := &Client{
+		Factory:      tf,
+		Log:          nopLogger,
+		FieldManager: "tiller",
+	}
+
+	if _, err := c.UpdateWithResult(v1.NamespaceDefault, objBody(&current), objBody(&target), UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the conflict is returned rather than resolved by recreating the pod
+	conflict = true
+	if _, err := c.UpdateWithResult(v1.NamespaceDefault, objBody(&current), objBody(&target), UpdateOptions{Force: true}); err == nil {
+		t.Error("expected a field ownership conflict to fail the update")
+	}
+
+	// a pod last written with client-side patches is taken over
+	conflict = false
+	live.ManagedFields = []metav1.ManagedFieldsEntry{{Manager: "tiller", Operation: metav1.ManagedFieldsOperationUpdate}}
+	wantForce = "true"
+	if _, err := c.UpdateWithResult(v1.NamespaceDefault, objBody(&current), objBody(&target), UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUpdateWithResultOwnerReferences(t *testing.T) {
+	current := newPodList("starfish")
+	target := newPodList("starfish", "dolphin")
+	target.Items[0].Spec.Containers[0].Ports = []v1.ContainerPort{{Name: "https", ContainerPort: 443}}
+
+	owner := &Owner{
+		Namespace: v1.NamespaceDefault,
+		Reference: metav1.OwnerReference{APIVersion: "v1", Kind: "ConfigMap", Name: "starfish.v2", UID: "record-uid"},
+	}
+
+	bodies := map[string]string{}
+	tf := cmdtesting.NewTestFactory()
+	defer tf.Cleanup()
+
+	tf.UnstructuredClient = &fake.RESTClient{
+		NegotiatedSerializer: unstructuredSerializer,
+		Client: fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			p, m := req.URL.Path, req.Method
+			if req.Body != nil {
+				b, _ := ioutil.ReadAll(req.Body)
+				bodies[m] = string(b)
+			}
+			switch {
+			case p == "/namespaces/default/pods/starfish" && m == "GET":
+				return newResponse(200, &current.Items[0])
+			case p == "/namespaces/default/pods/starfish" && m == "PATCH":
+				return newResponse(200, &target.Items[0])
+			case p == "/namespaces/default/pods/dolphin" && m == "GET":
+				return newResponse(404, notFoundBody())
+			case p == "/namespaces/default/pods" && m == "POST":
+				return newResponse(200, &target.Items[1])
+			default:
+				t.Fatalf("unexpected request: %s %s", req.Method, req.URL.Path)
+				return nil, nil
+			}
+		}),
+	}
+
+	c := &Client{
+		Factory: tf,
+		Log:     nopLogger,
+	}
+
+	if _, err := c.UpdateWithResult(v1.NamespaceDefault, objBody(&current), objBody(&target), UpdateOptions{Owner: owner}); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range []string{"PATCH", "POST"} {
+		if !strings.Contains(bodies[m], `"ownerReferences"`) || !strings.Contains(bodies[m], `"uid":"record-uid"`) {
+			t.Errorf("expected %s body to carry the owner reference, got %s", m, bodies[m])
+		}
+	}
+}
+
+func TestSetOwnerSkips(t *testing.T) {
+	owner := &Owner{
+		Namespace: "kube-system",
+		Reference: metav1.OwnerReference{APIVersion: "v1", Kind: "ConfigMap", Name: "starfish.v1", UID: "record-uid"},
+	}
+	newInfo := func(namespace string, scope meta.RESTScope) *resource.Info {
+		obj := &unstructured.Unstructured{}
+		obj.SetName("starfish")
+		obj.SetNamespace(namespace)
+		return &resource.Info{
+			Name:      "starfish",
+			Namespace: namespace,
+			Object:    obj,
+			Mapping:   &meta.RESTMapping{Scope: scope},
+		}
+	}
+	same := newInfo("kube-system", meta.RESTScopeNamespace)
+	other := newInfo("default", meta.RESTScopeNamespace)
+	cluster := newInfo("", meta.RESTScopeRoot)
+	kept := newInfo("kube-system", meta.RESTScopeNamespace)
+	kept.Object.(*unstructured.Unstructured).SetAnnotations(map[string]string{ResourcePolicyAnno: "keep"})
+
+	if err := setOwner(Result{same, other, cluster, kept}, owner); err != nil {
+		t.Fatal(err)
+	}
+	if refs := same.Object.(*unstructured.Unstructured).GetOwnerReferences(); len(refs) != 1 || refs[0].UID != "record-uid" {
+		t.Errorf("expected resource in the owner's namespace to be owned, got %v", refs)
+	}
+	for _, info := range []*resource.Info{other, cluster, kept} {
+		if refs := info.Object.(*unstructured.Unstructured).GetOwnerReferences(); len(refs) != 0 {
+			t.Errorf("expected %q resource %v not to be owned, got %v", info.Namespace, info.Object.(*unstructured.Unstructured).GetAnnotations(), refs)
+		}
+	}
+}
+
+func TestSetPodTemplateAnnotations(t *testing.T) {
+	newInfo := func(kind string) *resource.Info {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{}}}
+		obj.SetName("web")
+		return &resource.Info{
+			Name:    "web",
+			Object:  obj,
+			Mapping: &meta.RESTMapping{GroupVersionKind: schema.GroupVersionKind{Kind: kind}},
+		}
+	}
+	deployment, statefulSet, service := newInfo("Deployment"), newInfo("StatefulSet"), newInfo("Service")
+
+	if err := setPodTemplateAnnotations(Result{deployment, statefulSet, service}, map[string]string{"helm.sh/restarted-revision": "2"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, info := range []*resource.Info{deployment, statefulSet} {
+		got, _, _ := unstructured.NestedString(info.Object.(*unstructured.Unstructured).Object, "spec", "template", "metadata", "annotations", "helm.sh/restarted-revision")
+		if got != "2" {
+			t.Errorf("expected the pod template of the %s to be annotated, got %q", info.Mapping.GroupVersionKind.Kind, got)
+		}
+	}
+	if _, found, _ := unstructured.NestedFieldNoCopy(service.Object.(*unstructured.Unstructured).Object, "spec", "template"); found {
+		t.Error("expected a Service to be left alone")
+	}
+}
+
 func TestUpdateNonManagedResourceError(t *testing.T) {
 	actual := newPodList("starfish")
 	current := newPodList()
@@ -283,6 +559,164 @@ func TestUpdateNonManagedResourceError(t *testing.T) {
 	}
 }
 
+func TestCreateTakesOverKeptResource(t *testing.T) {
+	pod := newPod("squid")
+	pod.Annotations = map[string]string{ResourcePolicyAnno: "keep"}
+	live := pod
+	live.ResourceVersion = "42"
+	live.Annotations = map[string]string{ResourcePolicyAnno: "keep", ReleaseNameAnno: "octopus"}
+
+	var adopted bool
+	tf := cmdtesting.NewTestFactory()
+	defer tf.Cleanup()
+	tf.UnstructuredClient = &fake.RESTClient{
+		NegotiatedSerializer: unstructuredSerializer,
+		Client: fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			p, m := req.URL.Path, req.Method
+			switch {
+			case p == "/namespaces/default/pods" && m == "POST":
+				return newResponse(409, &metav1.Status{
+					Status: metav1.StatusFailure,
+					Code:   http.StatusConflict,
+					Reason: metav1.StatusReasonAlreadyExists,
+				})
+			case p == "/namespaces/default/pods/squid" && m == "GET":
+				return newResponse(200, &live)
+			case p == "/namespaces/default/pods/squid" && m == "PATCH":
+				adopted = true
+				return newResponse(200, &live)
+			default:
+				t.Fatalf("unexpected request: %s %s", req.Method, req.URL.Path)
+				return nil, nil
+			}
+		}),
+	}
+	c := &Client{Factory: tf, Log: nopLogger}
+
+	infos, err := c.BuildUnstructured(v1.NamespaceDefault, objBody(&pod))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := setReleaseName(infos, "octopus"); err != nil {
+		t.Fatal(err)
+	}
+	if annotations, _ := metadataAccessor.Annotations(infos[0].Object); annotations[ReleaseNameAnno] != "octopus" {
+		t.Errorf("expected the kept pod to record its release, got %v", annotations)
+	}
+	if err := createResource(infos[0], "", "octopus"); err != nil {
+		t.Fatalf("expected the kept pod to be taken over, got %s", err)
+	}
+	if !adopted {
+		t.Error("expected the kept pod to be patched")
+	}
+
+	// a resource kept by another release, or created without one, is never
+	// taken over
+	for _, release := range []string{"squid-ink", ""} {
+		adopted = false
+		if err := createResource(infos[0], "", release); !errors.IsAlreadyExists(err) {
+			t.Errorf("%q: expected an already exists error, got %v", release, err)
+		}
+		if adopted {
+			t.Errorf("%q: expected a pod kept by another release to be left alone", release)
+		}
+	}
+
+	// a resource kept before its release was recorded is taken over by the
+	// release named in its instance label
+	adopted = false
+	live.Annotations = map[string]string{ResourcePolicyAnno: "keep"}
+	live.Labels = map[string]string{"release": "octopus"}
+	if err := createResource(infos[0], "", "octopus"); err != nil {
+		t.Fatalf("expected the legacy kept pod to be taken over, got %s", err)
+	}
+	if !adopted {
+		t.Error("expected the legacy kept pod to be patched")
+	}
+	adopted = false
+	if err := createResource(infos[0], "", "squid-ink"); !errors.IsAlreadyExists(err) {
+		t.Errorf("expected an already exists error, got %v", err)
+	}
+	if adopted {
+		t.Error("expected a legacy pod of another release to be left alone")
+	}
+
+	// a resource Helm did not keep is never taken over
+	adopted = false
+	live.Annotations = nil
+	if err := createResource(infos[0], "", "octopus"); !errors.IsAlreadyExists(err) {
+		t.Errorf("expected an already exists error, got %v", err)
+	}
+	if adopted {
+		t.Error("expected a pod that was not kept to be left alone")
+	}
+}
+
+func TestCreateTakesOverBoundPersistentVolumeClaim(t *testing.T) {
+	claim := v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "data",
+			Namespace:   v1.NamespaceDefault,
+			Annotations: map[string]string{ResourcePolicyAnno: "keep"},
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+		},
+	}
+	live := *claim.DeepCopy()
+	live.ResourceVersion = "42"
+	live.Annotations[ReleaseNameAnno] = "octopus"
+	live.Spec.VolumeName = "pvc-0123"
+	live.Status.Phase = v1.ClaimBound
+
+	var patch string
+	tf := cmdtesting.NewTestFactory()
+	defer tf.Cleanup()
+	tf.UnstructuredClient = &fake.RESTClient{
+		NegotiatedSerializer: unstructuredSerializer,
+		Client: fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			p, m := req.URL.Path, req.Method
+			switch {
+			case p == "/namespaces/default/persistentvolumeclaims" && m == "POST":
+				return newResponse(409, &metav1.Status{
+					Status: metav1.StatusFailure,
+					Code:   http.StatusConflict,
+					Reason: metav1.StatusReasonAlreadyExists,
+				})
+			case p == "/namespaces/default/persistentvolumeclaims/data" && m == "GET":
+				return newResponse(200, &live)
+			case p == "/namespaces/default/persistentvolumeclaims/data" && m == "PATCH":
+				data, _ := ioutil.ReadAll(req.Body)
+				patch = string(data)
+				return newResponse(200, &live)
+			default:
+				t.Fatalf("unexpected request: %s %s", req.Method, req.URL.Path)
+				return nil, nil
+			}
+		}),
+	}
+	c := &Client{Factory: tf, Log: nopLogger}
+
+	infos, err := c.BuildUnstructured(v1.NamespaceDefault, objBody(&claim))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := setReleaseName(infos, "octopus"); err != nil {
+		t.Fatal(err)
+	}
+	if err := createResource(infos[0], "", "octopus"); err != nil {
+		t.Fatalf("expected the bound claim to be taken over, got %s", err)
+	}
+	if patch == "" {
+		t.Fatal("expected the bound claim to be patched")
+	}
+	for _, field := range []string{"volumeName", "resourceVersion", "status"} {
+		if strings.Contains(patch, field) {
+			t.Errorf("expected the patch to leave %s of the bound claim alone, got %s", field, patch)
+		}
+	}
+}
+
 func TestDeleteWithTimeout(t *testing.T) {
 	testCases := map[string]struct {
 		deleteTimeout int64
@@ -331,6 +765,91 @@ func TestDeleteWithTimeout(t *testing.T) {
 	}
 }
 
+func TestDeletePropagation(t *testing.T) {
+	for _, tt := range []struct {
+		policy metav1.DeletionPropagation
+		expect metav1.DeletionPropagation
+	}{
+		{"", metav1.DeletePropagationBackground},
+		{metav1.DeletePropagationBackground, metav1.DeletePropagationBackground},
+		{metav1.DeletePropagationForeground, metav1.DeletePropagationForeground},
+		{metav1.DeletePropagationOrphan, metav1.DeletePropagationOrphan},
+	} {
+		c := newTestClient()
+		c.DeletionPropagation = tt.policy
+
+		var got *metav1.DeletionPropagation
+		service := newService("my-service")
+		c.TestFactory.UnstructuredClient = &fake.RESTClient{
+			GroupVersion:         schema.GroupVersion{Version: "v1"},
+			NegotiatedSerializer: unstructuredSerializer,
+			Client: fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+				if req.Method == "DELETE" {
+					opts := &metav1.DeleteOptions{}
+					if err := json.NewDecoder(req.Body).Decode(opts); err != nil {
+						t.Fatalf("decoding delete options: %s", err)
+					}
+					got = opts.PropagationPolicy
+				}
+				return newResponse(200, &service)
+			}),
+		}
+
+		if err := c.Delete(metav1.NamespaceDefault, strings.NewReader(testServiceManifest)); err != nil {
+			t.Fatal(err)
+		}
+		if got == nil || *got != tt.expect {
+			t.Errorf("policy %q: expected propagation policy %q, got %v", tt.policy, tt.expect, got)
+		}
+		c.Cleanup()
+	}
+}
+
+func TestWaitForDeletion(t *testing.T) {
+	for _, removeFinalizers := range []bool{false, true} {
+		t.Run(fmt.Sprintf("removeFinalizers=%t", removeFinalizers), func(t *testing.T) {
+			c := newTestClient()
+			defer c.Cleanup()
+
+			service := newService("my-service")
+			service.Finalizers = []string{"example.com/stuck"}
+			patched := false
+			c.TestFactory.UnstructuredClient = &fake.RESTClient{
+				GroupVersion:         schema.GroupVersion{Version: "v1"},
+				NegotiatedSerializer: unstructuredSerializer,
+				Client: fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+					switch req.Method {
+					case "PATCH":
+						patched = true
+						return newResponse(200, &service)
+					case "GET":
+						if patched {
+							return newResponse(404, notFoundBody())
+						}
+						return newResponse(200, &service)
+					}
+					t.Fatalf("unexpected request: %s %s", req.Method, req.URL.Path)
+					return nil, nil
+				}),
+			}
+
+			remaining, err := c.WaitForDeletion(metav1.NamespaceDefault, strings.NewReader(testServiceManifest), time.Millisecond, removeFinalizers)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if patched != removeFinalizers {
+				t.Errorf("expected finalizers to be removed: %t, got %t", removeFinalizers, patched)
+			}
+			if removeFinalizers && len(remaining) != 0 {
+				t.Errorf("expected no remaining resources, got %v", remaining)
+			}
+			if !removeFinalizers && (len(remaining) != 1 || remaining[0] != "Service/my-service") {
+				t.Errorf("expected Service/my-service to remain, got %v", remaining)
+			}
+		})
+	}
+}
+
 func TestBuild(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -563,6 +1082,9 @@ func TestWaitUntilCRDEstablished(t *testing.T) {
 				}),
 			}
 
+			discovery := &refreshingDiscoveryClient{}
+			c.Client.Factory = &discoveryFactory{TestFactory: c.TestFactory, discovery: discovery}
+
 			err := c.WaitUntilCRDEstablished(strings.NewReader(crdManifest), 5*time.Second)
 			if err != nil && tc.success {
 				t.Errorf("%s: expected no error, but got %v", name, err)
@@ -570,10 +1092,80 @@ func TestWaitUntilCRDEstablished(t *testing.T) {
 			if err == nil && !tc.success {
 				t.Errorf("%s: expected error, but didn't get one", name)
 			}
+			if refreshed := discovery.invalidated && discovery.refreshed; refreshed != tc.success {
+				t.Errorf("%s: expected discovery information to be refreshed only once established, refreshed: %t", name, refreshed)
+			}
 		}(tn)
 	}
 }
 
+// discoveryFactory is a TestFactory returning discovery as its discovery
+// client.
+type discoveryFactory struct {
+	*cmdtesting.TestFactory
+	discovery discovery.CachedDiscoveryInterface
+}
+
+func (f *discoveryFactory) ToDiscoveryClient() (discovery.CachedDiscoveryInterface, error) {
+	return f.discovery, nil
+}
+
+// refreshingDiscoveryClient records whether its cache was invalidated and
+// refilled afterwards. It discovers nothing.
+type refreshingDiscoveryClient struct {
+	discovery.CachedDiscoveryInterface
+	invalidated, refreshed bool
+}
+
+func (d *refreshingDiscoveryClient) Invalidate() {
+	d.invalidated = true
+}
+
+func (d *refreshingDiscoveryClient) ServerGroupsAndResources() ([]*metav1.APIGroup, []*metav1.APIResourceList, error) {
+	d.refreshed = d.invalidated
+	return nil, nil, nil
+}
+
+func TestWaitForResourcesReportsNotReady(t *testing.T) {
+	c := newTestClient()
+	defer c.Cleanup()
+
+	completions := int32(1)
+	job := batch.Job{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "batch/v1", Kind: "Job"},
+		ObjectMeta: metav1.ObjectMeta{Name: "migrate", Namespace: metav1.NamespaceDefault},
+		Spec:       batch.JobSpec{Completions: &completions},
+	}
+	svc := newService("ready")
+	svc.Spec.ClusterIP = "10.0.0.1"
+	c.TestFactory.Client = &fake.RESTClient{
+		NegotiatedSerializer: unstructuredSerializer,
+		Client: fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			switch p := req.URL.Path; p {
+			case "/apis/batch/v1/namespaces/default/jobs/migrate":
+				return newResponse(200, &job)
+			case "/api/v1/namespaces/default/services/ready":
+				return newResponse(200, &svc)
+			default:
+				t.Fatalf("unexpected request: %s %s", req.Method, p)
+				return nil, nil
+			}
+		}),
+	}
+
+	infos, err := c.Build(metav1.NamespaceDefault, strings.NewReader(testWaitManifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = c.waitForResources(3*time.Second, infos)
+	if err == nil {
+		t.Fatal("expected the wait to time out")
+	}
+	if !strings.Contains(err.Error(), "resources not ready: Job/migrate") || strings.Contains(err.Error(), "Service/ready") {
+		t.Errorf("expected only the incomplete job to be reported, got %q", err)
+	}
+}
+
 func newCrdWithStatus(name string, status apiextv1beta1.CustomResourceDefinitionStatus) apiextv1beta1.CustomResourceDefinition {
 	crd := apiextv1beta1.CustomResourceDefinition{
 		ObjectMeta: metav1.ObjectMeta{
@@ -891,3 +1483,25 @@ status:
   conditions: []
   storedVersions: []
 `
+
+const testWaitManifest = `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+spec:
+  template:
+    spec:
+      containers:
+      - name: migrate
+        image: migrate
+      restartPolicy: Never
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: ready
+spec:
+  ports:
+  - port: 80
+`
@@ -17,33 +17,69 @@ limitations under the License.
 package kube // import "k8s.io/helm/pkg/kube"
 
 import (
+	"encoding/json"
+
 	"k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 )
 
-func createNamespace(client kubernetes.Interface, namespace string) error {
+func createNamespace(client kubernetes.Interface, namespace string, extraLabels map[string]string) error {
+	labels := map[string]string{}
+	for k, v := range extraLabels {
+		labels[k] = v
+	}
+	labels["name"] = namespace
 	ns := &v1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: namespace,
-			Labels: map[string]string{
-				"name": namespace,
-			},
+			Name:   namespace,
+			Labels: labels,
 		},
 	}
 	_, err := client.CoreV1().Namespaces().Create(ns)
 	return err
 }
 
+// labelNamespace adds the labels missing from an existing namespace.
+func labelNamespace(client kubernetes.Interface, ns *v1.Namespace, labels map[string]string) error {
+	missing := map[string]string{}
+	for k, v := range labels {
+		if ns.Labels[k] != v {
+			missing[k] = v
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"labels": missing},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = client.CoreV1().Namespaces().Patch(ns.Name, types.MergePatchType, patch)
+	return err
+}
+
 func getNamespace(client kubernetes.Interface, namespace string) (*v1.Namespace, error) {
 	return client.CoreV1().Namespaces().Get(namespace, metav1.GetOptions{})
 }
 
 func ensureNamespace(client kubernetes.Interface, namespace string) error {
-	_, err := getNamespace(client, namespace)
+	return ensureLabeledNamespace(client, namespace, nil, false)
+}
+
+// ensureLabeledNamespace creates namespace with labels if it does not exist.
+// If labelExisting is true, labels are also added to an existing namespace.
+func ensureLabeledNamespace(client kubernetes.Interface, namespace string, labels map[string]string, labelExisting bool) error {
+	ns, err := getNamespace(client, namespace)
+	if err == nil && labelExisting {
+		return labelNamespace(client, ns, labels)
+	}
 	if err != nil && errors.IsNotFound(err) {
-		err = createNamespace(client, namespace)
+		err = createNamespace(client, namespace, labels)
 
 		// If multiple commands which run `ensureNamespace` are run in
 		// parallel, then protect against the race condition in which
@@ -17,8 +17,10 @@ limitations under the License.
 package kube // import "k8s.io/helm/pkg/kube"
 
 import (
+	"reflect"
 	"testing"
 
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
 )
@@ -35,3 +37,40 @@ func TestEnsureNamespace(t *testing.T) {
 		t.Fatalf("unexpected error: %s", err)
 	}
 }
+
+func TestEnsureLabeledNamespace(t *testing.T) {
+	labels := map[string]string{"team": "payments", "netpol": "allow"}
+	client := fake.NewSimpleClientset(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "existing",
+		Labels: map[string]string{"team": "other", "keep": "me"},
+	}})
+
+	if err := ensureLabeledNamespace(client, "created", labels, false); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ns, err := client.CoreV1().Namespaces().Get("created", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expect := map[string]string{"name": "created", "team": "payments", "netpol": "allow"}
+	if !reflect.DeepEqual(ns.Labels, expect) {
+		t.Errorf("expected created namespace labels %v, got %v", expect, ns.Labels)
+	}
+
+	if err := ensureLabeledNamespace(client, "existing", labels, false); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ns, _ = client.CoreV1().Namespaces().Get("existing", metav1.GetOptions{})
+	if ns.Labels["team"] != "other" || ns.Labels["netpol"] != "" {
+		t.Errorf("expected existing namespace to be left alone, got labels %v", ns.Labels)
+	}
+
+	if err := ensureLabeledNamespace(client, "existing", labels, true); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ns, _ = client.CoreV1().Namespaces().Get("existing", metav1.GetOptions{})
+	expect = map[string]string{"keep": "me", "team": "payments", "netpol": "allow"}
+	if !reflect.DeepEqual(ns.Labels, expect) {
+		t.Errorf("expected existing namespace labels %v, got %v", expect, ns.Labels)
+	}
+}
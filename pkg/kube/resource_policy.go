@@ -20,6 +20,11 @@ const (
 	// ResourcePolicyAnno is the annotation name for a resource policy
 	ResourcePolicyAnno = "helm.sh/resource-policy"
 
+	// ReleaseNameAnno is the annotation naming the release that created a
+	// resource annotated to be kept, so that the resource is only taken over
+	// when a release of that name is installed again.
+	ReleaseNameAnno = "helm.sh/release-name"
+
 	// deletePolicy is the resource policy type for delete
 	//
 	// This resource policy type allows explicitly opting in to the default
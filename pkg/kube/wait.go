@@ -17,11 +17,14 @@ limitations under the License.
 package kube // import "k8s.io/helm/pkg/kube"
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	appsv1beta1 "k8s.io/api/apps/v1beta1"
 	appsv1beta2 "k8s.io/api/apps/v1beta2"
+	batch "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 	extensions "k8s.io/api/extensions/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -38,8 +41,9 @@ type deployment struct {
 	deployment  *appsv1.Deployment
 }
 
-// waitForResources polls to get the current status of all pods, PVCs, and Services
-// until all are ready or a timeout is reached
+// waitForResources polls to get the current status of all pods, PVCs, Services
+// and Jobs until all are ready or a timeout is reached. On timeout, the error
+// names the resources that were not ready at the last poll.
 func (c *Client) waitForResources(timeout time.Duration, created Result) error {
 	c.Log("beginning wait for %d resources with timeout of %v", len(created), timeout)
 
@@ -47,11 +51,13 @@ func (c *Client) waitForResources(timeout time.Duration, created Result) error {
 	if err != nil {
 		return err
 	}
-	return wait.Poll(2*time.Second, timeout, func() (bool, error) {
+	var notReady []string
+	err = wait.Poll(2*time.Second, timeout, func() (bool, error) {
 		pods := []v1.Pod{}
 		services := []v1.Service{}
 		pvc := []v1.PersistentVolumeClaim{}
 		deployments := []deployment{}
+		jobs := []batch.Job{}
 		for _, v := range created {
 			switch value := asVersionedOrUnstructured(v).(type) {
 			case *v1.ReplicationController:
@@ -208,24 +214,46 @@ func (c *Client) waitForResources(timeout time.Duration, created Result) error {
 					return false, err
 				}
 				services = append(services, *svc)
+			case *batch.Job:
+				job, err := kcs.BatchV1().Jobs(value.Namespace).Get(value.Name, metav1.GetOptions{})
+				if err != nil {
+					return false, err
+				}
+				if jobFailed(job) {
+					return false, fmt.Errorf("job failed: %s", job.Name)
+				}
+				jobs = append(jobs, *job)
 			}
 		}
-		isReady := c.podsReady(pods) && c.servicesReady(services) && c.volumesReady(pvc) && c.deploymentsReady(deployments)
-		return isReady, nil
+		notReady = nil
+		notReady = append(notReady, c.podsReady(pods)...)
+		notReady = append(notReady, c.servicesReady(services)...)
+		notReady = append(notReady, c.volumesReady(pvc)...)
+		notReady = append(notReady, c.deploymentsReady(deployments)...)
+		notReady = append(notReady, c.jobsReady(jobs)...)
+		return len(notReady) == 0, nil
 	})
+	if err == wait.ErrWaitTimeout && len(notReady) > 0 {
+		return fmt.Errorf("%s: resources not ready: %s", err, strings.Join(notReady, ", "))
+	}
+	return err
 }
 
-func (c *Client) podsReady(pods []v1.Pod) bool {
+// podsReady returns the names of the pods that are not ready.
+func (c *Client) podsReady(pods []v1.Pod) []string {
+	var notReady []string
 	for _, pod := range pods {
 		if !isPodReady(&pod) {
 			c.Log("Pod is not ready: %s/%s", pod.GetNamespace(), pod.GetName())
-			return false
+			notReady = append(notReady, "Pod/"+pod.GetName())
 		}
 	}
-	return true
+	return notReady
 }
 
-func (c *Client) servicesReady(svc []v1.Service) bool {
+// servicesReady returns the names of the services that are not ready.
+func (c *Client) servicesReady(svc []v1.Service) []string {
+	var notReady []string
 	for _, s := range svc {
 		// ExternalName Services are external to cluster so helm shouldn't be checking to see if they're 'ready' (i.e. have an IP Set)
 		if s.Spec.Type == v1.ServiceTypeExternalName {
@@ -235,35 +263,65 @@ func (c *Client) servicesReady(svc []v1.Service) bool {
 		// Make sure the service is not explicitly set to "None" before checking the IP
 		if s.Spec.ClusterIP != v1.ClusterIPNone && s.Spec.ClusterIP == "" {
 			c.Log("Service is not ready: %s/%s", s.GetNamespace(), s.GetName())
-			return false
+			notReady = append(notReady, "Service/"+s.GetName())
+			continue
 		}
 		// This checks if the service has a LoadBalancer and that balancer has an Ingress defined
 		if s.Spec.Type == v1.ServiceTypeLoadBalancer && s.Status.LoadBalancer.Ingress == nil {
 			c.Log("Service is not ready: %s/%s", s.GetNamespace(), s.GetName())
-			return false
+			notReady = append(notReady, "Service/"+s.GetName())
 		}
 	}
-	return true
+	return notReady
 }
 
-func (c *Client) volumesReady(vols []v1.PersistentVolumeClaim) bool {
+// volumesReady returns the names of the claims that are not bound.
+func (c *Client) volumesReady(vols []v1.PersistentVolumeClaim) []string {
+	var notReady []string
 	for _, v := range vols {
 		if v.Status.Phase != v1.ClaimBound {
 			c.Log("PersistentVolumeClaim is not ready: %s/%s", v.GetNamespace(), v.GetName())
-			return false
+			notReady = append(notReady, "PersistentVolumeClaim/"+v.GetName())
 		}
 	}
-	return true
+	return notReady
 }
 
-func (c *Client) deploymentsReady(deployments []deployment) bool {
+// deploymentsReady returns the names of the deployments that are not ready.
+func (c *Client) deploymentsReady(deployments []deployment) []string {
+	var notReady []string
 	for _, v := range deployments {
 		if !(v.replicaSets.Status.ReadyReplicas >= *v.deployment.Spec.Replicas-deploymentutil.MaxUnavailable(*v.deployment)) {
 			c.Log("Deployment is not ready: %s/%s", v.deployment.GetNamespace(), v.deployment.GetName())
-			return false
+			notReady = append(notReady, "Deployment/"+v.deployment.GetName())
+		}
+	}
+	return notReady
+}
+
+// jobsReady returns the names of the jobs that have not completed.
+func (c *Client) jobsReady(jobs []batch.Job) []string {
+	var notReady []string
+	for _, job := range jobs {
+		completions := int32(1)
+		if job.Spec.Completions != nil {
+			completions = *job.Spec.Completions
+		}
+		if job.Status.Succeeded < completions {
+			c.Log("Job is not complete: %s/%s", job.GetNamespace(), job.GetName())
+			notReady = append(notReady, "Job/"+job.GetName())
+		}
+	}
+	return notReady
+}
+
+func jobFailed(job *batch.Job) bool {
+	for _, c := range job.Status.Conditions {
+		if c.Type == batch.JobFailed && c.Status == v1.ConditionTrue {
+			return true
 		}
 	}
-	return true
+	return false
 }
 
 func getPods(client kubernetes.Interface, namespace string, selector map[string]string) ([]v1.Pod, error) {
@@ -69,7 +69,7 @@ func (x Hook_Event) String() string {
 	return proto.EnumName(Hook_Event_name, int32(x))
 }
 func (Hook_Event) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_hook_e64400ca8195038e, []int{0, 0}
+	return fileDescriptor_hook_b4f697a64f3520f3, []int{0, 0}
 }
 
 type Hook_DeletePolicy int32
@@ -95,7 +95,36 @@ func (x Hook_DeletePolicy) String() string {
 	return proto.EnumName(Hook_DeletePolicy_name, int32(x))
 }
 func (Hook_DeletePolicy) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_hook_e64400ca8195038e, []int{0, 1}
+	return fileDescriptor_hook_b4f697a64f3520f3, []int{0, 1}
+}
+
+type HookResult_Phase int32
+
+const (
+	HookResult_UNKNOWN   HookResult_Phase = 0
+	HookResult_SUCCEEDED HookResult_Phase = 1
+	HookResult_FAILED    HookResult_Phase = 2
+	HookResult_SKIPPED   HookResult_Phase = 3
+)
+
+var HookResult_Phase_name = map[int32]string{
+	0: "UNKNOWN",
+	1: "SUCCEEDED",
+	2: "FAILED",
+	3: "SKIPPED",
+}
+var HookResult_Phase_value = map[string]int32{
+	"UNKNOWN":   0,
+	"SUCCEEDED": 1,
+	"FAILED":    2,
+	"SKIPPED":   3,
+}
+
+func (x HookResult_Phase) String() string {
+	return proto.EnumName(HookResult_Phase_name, int32(x))
+}
+func (HookResult_Phase) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_hook_b4f697a64f3520f3, []int{1, 0}
 }
 
 // Hook defines a hook object.
@@ -116,7 +145,9 @@ type Hook struct {
 	// DeletePolicies are the policies that indicate when to delete the hook
 	DeletePolicies []Hook_DeletePolicy `protobuf:"varint,8,rep,packed,name=delete_policies,json=deletePolicies,proto3,enum=hapi.release.Hook_DeletePolicy" json:"delete_policies,omitempty"`
 	// DeleteTimeout indicates how long to wait for a resource to be deleted before timing out
-	DeleteTimeout        int64    `protobuf:"varint,9,opt,name=delete_timeout,json=deleteTimeout,proto3" json:"delete_timeout,omitempty"`
+	DeleteTimeout int64 `protobuf:"varint,9,opt,name=delete_timeout,json=deleteTimeout,proto3" json:"delete_timeout,omitempty"`
+	// RequiresApproval pauses the release before this hook runs until the release is approved
+	RequiresApproval     bool     `protobuf:"varint,10,opt,name=requires_approval,json=requiresApproval,proto3" json:"requires_approval,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -126,7 +157,7 @@ func (m *Hook) Reset()         { *m = Hook{} }
 func (m *Hook) String() string { return proto.CompactTextString(m) }
 func (*Hook) ProtoMessage()    {}
 func (*Hook) Descriptor() ([]byte, []int) {
-	return fileDescriptor_hook_e64400ca8195038e, []int{0}
+	return fileDescriptor_hook_b4f697a64f3520f3, []int{0}
 }
 func (m *Hook) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Hook.Unmarshal(m, b)
@@ -209,44 +240,128 @@ func (m *Hook) GetDeleteTimeout() int64 {
 	return 0
 }
 
+func (m *Hook) GetRequiresApproval() bool {
+	if m != nil {
+		return m.RequiresApproval
+	}
+	return false
+}
+
+// HookResult records the outcome of running a hook.
+type HookResult struct {
+	// Name is the name of the hook.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Event is the event the hook ran for.
+	Event Hook_Event `protobuf:"varint,2,opt,name=event,proto3,enum=hapi.release.Hook_Event" json:"event,omitempty"`
+	// Phase is the outcome of the hook. Hooks left unrun after an earlier
+	// hook failed are SKIPPED.
+	Phase HookResult_Phase `protobuf:"varint,3,opt,name=phase,proto3,enum=hapi.release.HookResult_Phase" json:"phase,omitempty"`
+	// DurationMs is how long the hook took to run, in milliseconds.
+	DurationMs           int64    `protobuf:"varint,4,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HookResult) Reset()         { *m = HookResult{} }
+func (m *HookResult) String() string { return proto.CompactTextString(m) }
+func (*HookResult) ProtoMessage()    {}
+func (*HookResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_hook_b4f697a64f3520f3, []int{1}
+}
+func (m *HookResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HookResult.Unmarshal(m, b)
+}
+func (m *HookResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HookResult.Marshal(b, m, deterministic)
+}
+func (dst *HookResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HookResult.Merge(dst, src)
+}
+func (m *HookResult) XXX_Size() int {
+	return xxx_messageInfo_HookResult.Size(m)
+}
+func (m *HookResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_HookResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HookResult proto.InternalMessageInfo
+
+func (m *HookResult) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *HookResult) GetEvent() Hook_Event {
+	if m != nil {
+		return m.Event
+	}
+	return Hook_UNKNOWN
+}
+
+func (m *HookResult) GetPhase() HookResult_Phase {
+	if m != nil {
+		return m.Phase
+	}
+	return HookResult_UNKNOWN
+}
+
+func (m *HookResult) GetDurationMs() int64 {
+	if m != nil {
+		return m.DurationMs
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Hook)(nil), "hapi.release.Hook")
+	proto.RegisterType((*HookResult)(nil), "hapi.release.HookResult")
 	proto.RegisterEnum("hapi.release.Hook_Event", Hook_Event_name, Hook_Event_value)
 	proto.RegisterEnum("hapi.release.Hook_DeletePolicy", Hook_DeletePolicy_name, Hook_DeletePolicy_value)
+	proto.RegisterEnum("hapi.release.HookResult_Phase", HookResult_Phase_name, HookResult_Phase_value)
 }
 
-func init() { proto.RegisterFile("hapi/release/hook.proto", fileDescriptor_hook_e64400ca8195038e) }
-
-var fileDescriptor_hook_e64400ca8195038e = []byte{
-	// 473 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x91, 0xdb, 0x8e, 0xda, 0x3c,
-	0x10, 0x80, 0x37, 0x1c, 0x02, 0x0c, 0x87, 0xf5, 0x6f, 0xfd, 0x6a, 0x2d, 0x6e, 0x16, 0x21, 0x55,
-	0xe2, 0x2a, 0x54, 0x5b, 0xf5, 0x01, 0x42, 0xe2, 0x2d, 0x88, 0x88, 0x20, 0x27, 0xa8, 0x52, 0x6f,
-	0xa2, 0x6c, 0xf1, 0x42, 0x44, 0x88, 0x23, 0x62, 0x5a, 0xf5, 0x81, 0xfb, 0x18, 0x95, 0x2a, 0x3b,
-	0x21, 0x5d, 0xa9, 0xbd, 0x9b, 0xf9, 0xe6, 0xf3, 0x78, 0xc6, 0x86, 0xb7, 0xc7, 0x38, 0x4f, 0xe6,
-	0x17, 0x9e, 0xf2, 0xb8, 0xe0, 0xf3, 0xa3, 0x10, 0x27, 0x2b, 0xbf, 0x08, 0x29, 0xf0, 0x40, 0x15,
-	0xac, 0xaa, 0x30, 0x7e, 0x38, 0x08, 0x71, 0x48, 0xf9, 0x5c, 0xd7, 0x9e, 0xaf, 0x2f, 0x73, 0x99,
-	0x9c, 0x79, 0x21, 0xe3, 0x73, 0x5e, 0xea, 0xd3, 0x5f, 0x2d, 0x68, 0x2d, 0x85, 0x38, 0x61, 0x0c,
-	0xad, 0x2c, 0x3e, 0x73, 0x62, 0x4c, 0x8c, 0x59, 0x8f, 0xe9, 0x58, 0xb1, 0x53, 0x92, 0xed, 0x49,
-	0xa3, 0x64, 0x2a, 0x56, 0x2c, 0x8f, 0xe5, 0x91, 0x34, 0x4b, 0xa6, 0x62, 0x3c, 0x86, 0xee, 0x39,
-	0xce, 0x92, 0x17, 0x5e, 0x48, 0xd2, 0xd2, 0xbc, 0xce, 0xf1, 0x7b, 0x30, 0xf9, 0x37, 0x9e, 0xc9,
-	0x82, 0xb4, 0x27, 0xcd, 0xd9, 0xe8, 0x91, 0x58, 0xaf, 0x07, 0xb4, 0xd4, 0xdd, 0x16, 0x55, 0x02,
-	0xab, 0x3c, 0xfc, 0x11, 0xba, 0x69, 0x5c, 0xc8, 0xe8, 0x72, 0xcd, 0x88, 0x39, 0x31, 0x66, 0xfd,
-	0xc7, 0xb1, 0x55, 0xae, 0x61, 0xdd, 0xd6, 0xb0, 0xc2, 0xdb, 0x1a, 0xac, 0xa3, 0x5c, 0x76, 0xcd,
-	0xf0, 0x1b, 0x30, 0xbf, 0xf3, 0xe4, 0x70, 0x94, 0xa4, 0x33, 0x31, 0x66, 0x6d, 0x56, 0x65, 0x78,
-	0x09, 0xf7, 0x7b, 0x9e, 0x72, 0xc9, 0xa3, 0x5c, 0xa4, 0xc9, 0xd7, 0x84, 0x17, 0xa4, 0xab, 0x27,
-	0x79, 0xf8, 0xc7, 0x24, 0xae, 0x36, 0xb7, 0x4a, 0xfc, 0xc1, 0x46, 0xfb, 0x3f, 0x59, 0xc2, 0x0b,
-	0xfc, 0x0e, 0x2a, 0x12, 0xa9, 0x57, 0x14, 0x57, 0x49, 0x7a, 0x13, 0x63, 0xd6, 0x64, 0xc3, 0x92,
-	0x86, 0x25, 0x9c, 0xfe, 0x34, 0xa0, 0xad, 0x37, 0xc2, 0x7d, 0xe8, 0xec, 0x36, 0xeb, 0x8d, 0xff,
-	0x79, 0x83, 0xee, 0xf0, 0x3d, 0xf4, 0xb7, 0x8c, 0x46, 0xab, 0x4d, 0x10, 0xda, 0x9e, 0x87, 0x0c,
-	0x8c, 0x60, 0xb0, 0xf5, 0x83, 0xb0, 0x26, 0x0d, 0x3c, 0x02, 0x50, 0x8a, 0x4b, 0x3d, 0x1a, 0x52,
-	0xd4, 0xd4, 0x47, 0x94, 0x51, 0x81, 0xd6, 0xad, 0xc7, 0x6e, 0xfb, 0x89, 0xd9, 0x2e, 0x45, 0xed,
-	0xba, 0xc7, 0x8d, 0x98, 0x9a, 0x30, 0x1a, 0x31, 0xdf, 0xf3, 0x16, 0xb6, 0xb3, 0x46, 0x1d, 0xfc,
-	0x1f, 0x0c, 0xb5, 0x53, 0xa3, 0x2e, 0x26, 0xf0, 0x3f, 0xa3, 0x1e, 0xb5, 0x03, 0x1a, 0x85, 0x34,
-	0x08, 0xa3, 0x60, 0xe7, 0x38, 0x34, 0x08, 0x50, 0xef, 0xaf, 0xca, 0x93, 0xbd, 0xf2, 0x76, 0x8c,
-	0x22, 0x50, 0x77, 0x3b, 0xcc, 0xad, 0xa7, 0xed, 0x4f, 0x1d, 0x18, 0xbc, 0x7e, 0x2e, 0x3c, 0x84,
-	0x9e, 0xee, 0x43, 0x5d, 0xea, 0xa2, 0x3b, 0x0c, 0x60, 0xaa, 0xc3, 0xd4, 0x45, 0x86, 0xea, 0xba,
-	0xa0, 0x4f, 0x3e, 0xa3, 0xd1, 0xd2, 0xf7, 0xd7, 0x91, 0xc3, 0xa8, 0x1d, 0xae, 0xfc, 0x0d, 0x6a,
-	0x2c, 0x7a, 0x5f, 0x3a, 0xd5, 0x07, 0x3c, 0x9b, 0xfa, 0x77, 0x3f, 0xfc, 0x0e, 0x00, 0x00, 0xff,
-	0xff, 0xce, 0x84, 0xd9, 0x98, 0xdb, 0x02, 0x00, 0x00,
+func init() { proto.RegisterFile("hapi/release/hook.proto", fileDescriptor_hook_b4f697a64f3520f3) }
+
+var fileDescriptor_hook_b4f697a64f3520f3 = []byte{
+	// 587 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x92, 0x5f, 0x6f, 0xd3, 0x30,
+	0x10, 0xc0, 0x97, 0xb6, 0xe9, 0x9f, 0xeb, 0xd6, 0x79, 0x16, 0x82, 0x68, 0x0f, 0xac, 0xaa, 0x84,
+	0x54, 0x09, 0x29, 0x45, 0x03, 0xde, 0x78, 0xc9, 0x1a, 0x8f, 0x55, 0x0d, 0x4d, 0xe4, 0xa4, 0x42,
+	0xe2, 0x25, 0xca, 0xa8, 0xb7, 0x46, 0x4b, 0x93, 0x10, 0x3b, 0x43, 0x7c, 0x25, 0xbe, 0x17, 0xdf,
+	0x03, 0xd9, 0x49, 0xca, 0xa6, 0x4d, 0xbc, 0xd9, 0xbf, 0xfb, 0xf9, 0x7c, 0x67, 0x1f, 0xbc, 0xda,
+	0x46, 0x79, 0x3c, 0x2b, 0x58, 0xc2, 0x22, 0xce, 0x66, 0xdb, 0x2c, 0xbb, 0x33, 0xf3, 0x22, 0x13,
+	0x19, 0x3e, 0x94, 0x01, 0xb3, 0x0e, 0x9c, 0x9e, 0xdd, 0x66, 0xd9, 0x6d, 0xc2, 0x66, 0x2a, 0x76,
+	0x5d, 0xde, 0xcc, 0x44, 0xbc, 0x63, 0x5c, 0x44, 0xbb, 0xbc, 0xd2, 0x27, 0xbf, 0x75, 0xe8, 0x5c,
+	0x65, 0xd9, 0x1d, 0xc6, 0xd0, 0x49, 0xa3, 0x1d, 0x33, 0xb4, 0xb1, 0x36, 0x1d, 0x50, 0xb5, 0x96,
+	0xec, 0x2e, 0x4e, 0x37, 0x46, 0xab, 0x62, 0x72, 0x2d, 0x59, 0x1e, 0x89, 0xad, 0xd1, 0xae, 0x98,
+	0x5c, 0xe3, 0x53, 0xe8, 0xef, 0xa2, 0x34, 0xbe, 0x61, 0x5c, 0x18, 0x1d, 0xc5, 0xf7, 0x7b, 0xfc,
+	0x0e, 0xba, 0xec, 0x9e, 0xa5, 0x82, 0x1b, 0xfa, 0xb8, 0x3d, 0x1d, 0x9d, 0x1b, 0xe6, 0xc3, 0x02,
+	0x4d, 0x79, 0xb7, 0x49, 0xa4, 0x40, 0x6b, 0x0f, 0x7f, 0x84, 0x7e, 0x12, 0x71, 0x11, 0x16, 0x65,
+	0x6a, 0x74, 0xc7, 0xda, 0x74, 0x78, 0x7e, 0x6a, 0x56, 0x6d, 0x98, 0x4d, 0x1b, 0x66, 0xd0, 0xb4,
+	0x41, 0x7b, 0xd2, 0xa5, 0x65, 0x8a, 0x5f, 0x42, 0xf7, 0x27, 0x8b, 0x6f, 0xb7, 0xc2, 0xe8, 0x8d,
+	0xb5, 0xa9, 0x4e, 0xeb, 0x1d, 0xbe, 0x82, 0xe3, 0x0d, 0x4b, 0x98, 0x60, 0x61, 0x9e, 0x25, 0xf1,
+	0xf7, 0x98, 0x71, 0xa3, 0xaf, 0x2a, 0x39, 0x7b, 0xa6, 0x12, 0x5b, 0x99, 0x9e, 0x14, 0x7f, 0xd1,
+	0xd1, 0xe6, 0xdf, 0x2e, 0x66, 0x1c, 0xbf, 0x81, 0x9a, 0x84, 0xf2, 0x15, 0xb3, 0x52, 0x18, 0x83,
+	0xb1, 0x36, 0x6d, 0xd3, 0xa3, 0x8a, 0x06, 0x15, 0xc4, 0x6f, 0xe1, 0xa4, 0x60, 0x3f, 0xca, 0xb8,
+	0x60, 0x3c, 0x8c, 0xf2, 0xbc, 0xc8, 0xee, 0xa3, 0xc4, 0x80, 0xb1, 0x36, 0xed, 0x53, 0xd4, 0x04,
+	0xac, 0x9a, 0x4f, 0xfe, 0x68, 0xa0, 0xab, 0xf6, 0xf1, 0x10, 0x7a, 0xeb, 0xd5, 0x72, 0xe5, 0x7e,
+	0x5d, 0xa1, 0x03, 0x7c, 0x0c, 0x43, 0x8f, 0x92, 0x70, 0xb1, 0xf2, 0x03, 0xcb, 0x71, 0x90, 0x86,
+	0x11, 0x1c, 0x7a, 0xae, 0x1f, 0xec, 0x49, 0x0b, 0x8f, 0x00, 0xa4, 0x62, 0x13, 0x87, 0x04, 0x04,
+	0xb5, 0xd5, 0x11, 0x69, 0xd4, 0xa0, 0xd3, 0xe4, 0x58, 0x7b, 0x9f, 0xa9, 0x65, 0x13, 0xa4, 0xef,
+	0x73, 0x34, 0xa4, 0xab, 0x08, 0x25, 0x21, 0x75, 0x1d, 0xe7, 0xc2, 0x9a, 0x2f, 0x51, 0x0f, 0x9f,
+	0xc0, 0x91, 0x72, 0xf6, 0xa8, 0x8f, 0x0d, 0x78, 0x41, 0x89, 0x43, 0x2c, 0x9f, 0x84, 0x01, 0xf1,
+	0x83, 0xd0, 0x5f, 0xcf, 0xe7, 0xc4, 0xf7, 0xd1, 0xe0, 0x49, 0xe4, 0xd2, 0x5a, 0x38, 0x6b, 0x4a,
+	0x10, 0xc8, 0xbb, 0xe7, 0xd4, 0xde, 0x57, 0x3b, 0x9c, 0xcc, 0xe1, 0xf0, 0xe1, 0xdb, 0xe2, 0x23,
+	0x18, 0xa8, 0x3c, 0xc4, 0x26, 0x36, 0x3a, 0xc0, 0x00, 0x5d, 0x79, 0x98, 0xd8, 0x48, 0x93, 0x59,
+	0x2f, 0xc8, 0xa5, 0x4b, 0x49, 0x78, 0xe5, 0xba, 0xcb, 0x70, 0x4e, 0x89, 0x15, 0x2c, 0xdc, 0x15,
+	0x6a, 0xc9, 0xc7, 0x02, 0xf9, 0x4d, 0x94, 0xf1, 0x32, 0x11, 0xcf, 0x8e, 0xac, 0x09, 0xba, 0x1a,
+	0x23, 0x35, 0xb3, 0xff, 0x9b, 0xb6, 0x4a, 0xc3, 0x1f, 0x40, 0xcf, 0xb7, 0x11, 0x67, 0x6a, 0x9e,
+	0x47, 0xe7, 0xaf, 0x9f, 0xfa, 0xd5, 0x65, 0xa6, 0x27, 0x2d, 0x5a, 0xc9, 0xf8, 0x0c, 0x86, 0x9b,
+	0xb2, 0x88, 0x44, 0x9c, 0xa5, 0xe1, 0x8e, 0xab, 0x99, 0x6f, 0x53, 0x68, 0xd0, 0x17, 0x3e, 0xf9,
+	0x04, 0xba, 0x3a, 0xf0, 0xf8, 0x57, 0x1f, 0x35, 0xad, 0x3d, 0x68, 0xba, 0x25, 0x3d, 0x7f, 0xb9,
+	0xf0, 0x3c, 0x62, 0xa3, 0xf6, 0xc5, 0xe0, 0x5b, 0xaf, 0xae, 0xe0, 0xba, 0xab, 0x46, 0xfe, 0xfd,
+	0xdf, 0x01, 0x00, 0xfa, 0x7f, 0xc1, 0x89, 0xf0, 0x03, 0x00, 0x00,
 }
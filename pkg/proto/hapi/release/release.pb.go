@@ -38,17 +38,24 @@ type Release struct {
 	// Version is an int32 which represents the version of the release.
 	Version int32 `protobuf:"varint,7,opt,name=version,proto3" json:"version,omitempty"`
 	// Namespace is the kubernetes namespace of the release.
-	Namespace            string   `protobuf:"bytes,8,opt,name=namespace,proto3" json:"namespace,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Namespace string `protobuf:"bytes,8,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// AppliedResources are the objects, with their UIDs and resource versions,
+	// left in the cluster by the operation that produced this release.
+	AppliedResources []*AppliedResource `protobuf:"bytes,9,rep,name=applied_resources,json=appliedResources,proto3" json:"applied_resources,omitempty"`
+	// Audit lists the operations performed on this revision of the release,
+	// oldest first. It is no longer written: Tiller now keeps the audit trail
+	// apart from the release records, and only reads this for older records.
+	Audit                []*AuditEntry `protobuf:"bytes,10,rep,name=audit,proto3" json:"audit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
 }
 
 func (m *Release) Reset()         { *m = Release{} }
 func (m *Release) String() string { return proto.CompactTextString(m) }
 func (*Release) ProtoMessage()    {}
 func (*Release) Descriptor() ([]byte, []int) {
-	return fileDescriptor_release_4bea5d16ba219619, []int{0}
+	return fileDescriptor_release_16fa735bd710d58b, []int{0}
 }
 func (m *Release) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Release.Unmarshal(m, b)
@@ -124,28 +131,48 @@ func (m *Release) GetNamespace() string {
 	return ""
 }
 
+func (m *Release) GetAppliedResources() []*AppliedResource {
+	if m != nil {
+		return m.AppliedResources
+	}
+	return nil
+}
+
+func (m *Release) GetAudit() []*AuditEntry {
+	if m != nil {
+		return m.Audit
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Release)(nil), "hapi.release.Release")
 }
 
-func init() { proto.RegisterFile("hapi/release/release.proto", fileDescriptor_release_4bea5d16ba219619) }
-
-var fileDescriptor_release_4bea5d16ba219619 = []byte{
-	// 256 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x90, 0xbf, 0x4e, 0xc3, 0x40,
-	0x0c, 0xc6, 0x95, 0x36, 0x7f, 0x1a, 0xc3, 0x82, 0x07, 0xb0, 0x22, 0x86, 0x88, 0x01, 0x22, 0x86,
-	0x54, 0x82, 0x37, 0x80, 0x05, 0xd6, 0x1b, 0xd9, 0x8e, 0xe8, 0x42, 0x4e, 0xa5, 0xe7, 0x28, 0x17,
-	0xf1, 0x2c, 0x3c, 0x2e, 0xba, 0x3f, 0x85, 0x94, 0x2e, 0x4e, 0xec, 0xdf, 0xa7, 0xcf, 0xdf, 0x19,
-	0xaa, 0x41, 0x8e, 0x7a, 0x3b, 0xa9, 0x4f, 0x25, 0xad, 0x3a, 0x7c, 0xdb, 0x71, 0xe2, 0x99, 0xf1,
-	0xdc, 0xb1, 0x36, 0xce, 0xaa, 0xab, 0x23, 0xe5, 0xc0, 0xbc, 0x0b, 0xb2, 0x7f, 0x40, 0x9b, 0x9e,
-	0x8f, 0x40, 0x37, 0xc8, 0x69, 0xde, 0x76, 0x6c, 0x7a, 0xfd, 0x11, 0xc1, 0xe5, 0x12, 0xb8, 0x1a,
-	0xe6, 0x37, 0xdf, 0x2b, 0x28, 0x44, 0xf0, 0x41, 0x84, 0xd4, 0xc8, 0xbd, 0xa2, 0xa4, 0x4e, 0x9a,
-	0x52, 0xf8, 0x7f, 0xbc, 0x85, 0xd4, 0xd9, 0xd3, 0xaa, 0x4e, 0x9a, 0xb3, 0x07, 0x6c, 0x97, 0xf9,
-	0xda, 0x57, 0xd3, 0xb3, 0xf0, 0x1c, 0xef, 0x20, 0xf3, 0xb6, 0xb4, 0xf6, 0xc2, 0x8b, 0x20, 0x0c,
-	0x9b, 0x9e, 0x5d, 0x15, 0x81, 0xe3, 0x3d, 0xe4, 0x21, 0x18, 0xa5, 0x4b, 0xcb, 0xa8, 0xf4, 0x44,
-	0x44, 0x05, 0x56, 0xb0, 0xd9, 0x4b, 0xa3, 0x7b, 0x65, 0x67, 0xca, 0x7c, 0xa8, 0xdf, 0x1e, 0x1b,
-	0xc8, 0xdc, 0x41, 0x2c, 0xe5, 0xf5, 0xfa, 0x34, 0xd9, 0x0b, 0xf3, 0x4e, 0x04, 0x01, 0x12, 0x14,
-	0x5f, 0x6a, 0xb2, 0x9a, 0x0d, 0x15, 0x75, 0xd2, 0x64, 0xe2, 0xd0, 0xe2, 0x35, 0x94, 0xee, 0x91,
-	0x76, 0x94, 0x9d, 0xa2, 0x8d, 0x5f, 0xf0, 0x37, 0x78, 0x2a, 0xdf, 0x8a, 0x68, 0xf7, 0x9e, 0xfb,
-	0x63, 0x3d, 0xfe, 0x04, 0x00, 0x00, 0xff, 0xff, 0xc8, 0x8f, 0xec, 0x97, 0xbb, 0x01, 0x00, 0x00,
+func init() {
+	proto.RegisterFile("hapi/release/release.proto", fileDescriptor_release_16fa735bd710d58b)
+}
+
+var fileDescriptor_release_16fa735bd710d58b = []byte{
+	// 318 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x91, 0xcd, 0x4a, 0xc3, 0x40,
+	0x10, 0x80, 0x89, 0x6d, 0x9a, 0x66, 0xf4, 0x60, 0xe7, 0xa0, 0x43, 0x50, 0x08, 0x0a, 0x1a, 0x3c,
+	0xa4, 0xa0, 0x4f, 0xa0, 0x22, 0xa8, 0xc7, 0x3d, 0x7a, 0x91, 0x35, 0xdd, 0xd8, 0xd0, 0x76, 0x37,
+	0xec, 0xa6, 0x82, 0xef, 0xe1, 0x03, 0xcb, 0xfe, 0x54, 0x9b, 0xea, 0x65, 0xdb, 0x9d, 0xef, 0x9b,
+	0x9f, 0xcd, 0x40, 0x36, 0xe7, 0x6d, 0x33, 0xd5, 0x62, 0x29, 0xb8, 0x11, 0x9b, 0xdf, 0xb2, 0xd5,
+	0xaa, 0x53, 0x78, 0x60, 0x59, 0x19, 0x62, 0xd9, 0x79, 0xcf, 0xe4, 0x6d, 0xbb, 0x6c, 0xc4, 0xec,
+	0x55, 0x0b, 0xa3, 0xd6, 0xba, 0x0a, 0x29, 0x19, 0xf5, 0xa5, 0xf5, 0xac, 0xe9, 0x02, 0x39, 0xee,
+	0x91, 0xb9, 0x52, 0x8b, 0x7f, 0x41, 0x23, 0x6b, 0xd5, 0x03, 0xd5, 0x9c, 0xeb, 0x6e, 0x5a, 0x29,
+	0x59, 0x37, 0xef, 0x01, 0x1c, 0x6d, 0x03, 0x7b, 0xfa, 0xf8, 0xd9, 0xd7, 0x00, 0x12, 0xe6, 0xeb,
+	0x20, 0xc2, 0x50, 0xf2, 0x95, 0xa0, 0x28, 0x8f, 0x8a, 0x94, 0xb9, 0xff, 0x78, 0x01, 0x43, 0x5b,
+	0x9e, 0xf6, 0xf2, 0xa8, 0xd8, 0xbf, 0xc6, 0x72, 0xfb, 0x79, 0xe5, 0x93, 0xac, 0x15, 0x73, 0x1c,
+	0x2f, 0x21, 0x76, 0x65, 0x69, 0xe0, 0xc4, 0x89, 0x17, 0x7d, 0xa7, 0x7b, 0x7b, 0x32, 0xcf, 0xf1,
+	0x0a, 0x46, 0x7e, 0x30, 0x1a, 0x6e, 0x97, 0x0c, 0xa6, 0x23, 0x2c, 0x18, 0x98, 0xc1, 0x78, 0xc5,
+	0x65, 0x53, 0x0b, 0xd3, 0x51, 0xec, 0x86, 0xfa, 0xb9, 0x63, 0x01, 0xb1, 0xfd, 0x20, 0x86, 0x46,
+	0xf9, 0xe0, 0xef, 0x64, 0x8f, 0x4a, 0x2d, 0x98, 0x17, 0x90, 0x20, 0xf9, 0x10, 0xda, 0x34, 0x4a,
+	0x52, 0x92, 0x47, 0x45, 0xcc, 0x36, 0x57, 0x3c, 0x81, 0xd4, 0x3e, 0xd2, 0xb4, 0xbc, 0x12, 0x34,
+	0x76, 0x0d, 0x7e, 0x03, 0xf8, 0x0c, 0x93, 0xdd, 0x8d, 0x19, 0x4a, 0x5d, 0xb7, 0xd3, 0x7e, 0xb7,
+	0x5b, 0xaf, 0xb1, 0x60, 0xb1, 0x43, 0xde, 0x0f, 0x18, 0x2c, 0x21, 0x76, 0x8b, 0x25, 0x70, 0xf9,
+	0xb4, 0x93, 0x6f, 0xd1, 0x83, 0xec, 0xf4, 0x27, 0xf3, 0xda, 0x5d, 0xfa, 0x92, 0x04, 0xf8, 0x36,
+	0x72, 0x8b, 0xba, 0xf9, 0x1e, 0x00, 0xfe, 0x87, 0x4e, 0xaf, 0x76, 0x02, 0x00, 0x00,
 }
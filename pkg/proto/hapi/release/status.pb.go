@@ -40,6 +40,9 @@ const (
 	Status_PENDING_UPGRADE Status_Code = 7
 	// Status_PENDING_ROLLBACK indicates that a rollback operation is underway.
 	Status_PENDING_ROLLBACK Status_Code = 8
+	// Status_PENDING_APPROVAL indicates that an upgrade is awaiting approval to run its post-upgrade hooks.
+	// Clients built before it was added see it as an unknown status code.
+	Status_PENDING_APPROVAL Status_Code = 9
 )
 
 var Status_Code_name = map[int32]string{
@@ -52,6 +55,7 @@ var Status_Code_name = map[int32]string{
 	6: "PENDING_INSTALL",
 	7: "PENDING_UPGRADE",
 	8: "PENDING_ROLLBACK",
+	9: "PENDING_APPROVAL",
 }
 var Status_Code_value = map[string]int32{
 	"UNKNOWN":          0,
@@ -63,13 +67,14 @@ var Status_Code_value = map[string]int32{
 	"PENDING_INSTALL":  6,
 	"PENDING_UPGRADE":  7,
 	"PENDING_ROLLBACK": 8,
+	"PENDING_APPROVAL": 9,
 }
 
 func (x Status_Code) String() string {
 	return proto.EnumName(Status_Code_name, int32(x))
 }
 func (Status_Code) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_status_88e0d7083c72b5e6, []int{0, 0}
+	return fileDescriptor_status_e683db75f3e63e75, []int{0, 0}
 }
 
 // Status defines the status of a release.
@@ -90,7 +95,7 @@ func (m *Status) Reset()         { *m = Status{} }
 func (m *Status) String() string { return proto.CompactTextString(m) }
 func (*Status) ProtoMessage()    {}
 func (*Status) Descriptor() ([]byte, []int) {
-	return fileDescriptor_status_88e0d7083c72b5e6, []int{0}
+	return fileDescriptor_status_e683db75f3e63e75, []int{0}
 }
 func (m *Status) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Status.Unmarshal(m, b)
@@ -143,29 +148,30 @@ func init() {
 	proto.RegisterEnum("hapi.release.Status_Code", Status_Code_name, Status_Code_value)
 }
 
-func init() { proto.RegisterFile("hapi/release/status.proto", fileDescriptor_status_88e0d7083c72b5e6) }
-
-var fileDescriptor_status_88e0d7083c72b5e6 = []byte{
-	// 333 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x54, 0x90, 0xd1, 0x6e, 0xa2, 0x40,
-	0x14, 0x86, 0x17, 0x45, 0xd4, 0xa3, 0x71, 0x27, 0xa3, 0xc9, 0xa2, 0xd9, 0x4d, 0x8c, 0x57, 0xde,
-	0x2c, 0x24, 0xf6, 0x09, 0xd0, 0x19, 0x0d, 0x71, 0x82, 0x04, 0x30, 0x4d, 0x7b, 0x43, 0x50, 0xa7,
-	0xd6, 0xc4, 0x30, 0x86, 0x19, 0x2e, 0xfa, 0x26, 0x7d, 0xaa, 0x3e, 0x53, 0x03, 0xd8, 0xa8, 0x97,
-	0xff, 0xff, 0x7d, 0x87, 0x73, 0x18, 0x18, 0xbe, 0x27, 0x97, 0x93, 0x9d, 0xf1, 0x33, 0x4f, 0x24,
-	0xb7, 0xa5, 0x4a, 0x54, 0x2e, 0xad, 0x4b, 0x26, 0x94, 0xc0, 0xdd, 0x02, 0x59, 0x57, 0x34, 0xfa,
-	0xf7, 0x20, 0x2a, 0x2e, 0x55, 0x2c, 0xf3, 0x93, 0xe2, 0x95, 0x3c, 0x1a, 0x1e, 0x85, 0x38, 0x9e,
-	0xb9, 0x5d, 0xa6, 0x5d, 0xfe, 0x66, 0x27, 0xe9, 0x47, 0x85, 0x26, 0x5f, 0x35, 0x30, 0xc2, 0xf2,
-	0xc3, 0xf8, 0x3f, 0xe8, 0x7b, 0x71, 0xe0, 0xa6, 0x36, 0xd6, 0xa6, 0xbd, 0xd9, 0xd0, 0xba, 0xdf,
-	0x60, 0x55, 0x8e, 0xb5, 0x10, 0x07, 0x1e, 0x94, 0x1a, 0xfe, 0x0b, 0xed, 0x8c, 0x4b, 0x91, 0x67,
-	0x7b, 0x2e, 0xcd, 0xfa, 0x58, 0x9b, 0xb6, 0x83, 0x5b, 0x81, 0x07, 0xd0, 0x48, 0x85, 0xe2, 0xd2,
-	0xd4, 0x4b, 0x52, 0x05, 0xbc, 0x84, 0xfe, 0x39, 0x91, 0x2a, 0xbe, 0x5d, 0x18, 0x67, 0x79, 0x6a,
-	0x36, 0xc6, 0xda, 0xb4, 0x33, 0xfb, 0xf3, 0xb8, 0x31, 0xe2, 0x52, 0x85, 0x85, 0x12, 0xa0, 0x62,
-	0xe6, 0x16, 0xf3, 0x74, 0xf2, 0xa9, 0x81, 0x5e, 0x9c, 0x82, 0x3b, 0xd0, 0xdc, 0x7a, 0x6b, 0x6f,
-	0xf3, 0xec, 0xa1, 0x5f, 0xb8, 0x0b, 0x2d, 0x42, 0x7d, 0xb6, 0x79, 0xa1, 0x04, 0x69, 0x05, 0x22,
-	0x94, 0xd1, 0x88, 0x12, 0x54, 0xc3, 0x3d, 0x80, 0x70, 0xeb, 0xd3, 0x20, 0xa4, 0x84, 0x12, 0x54,
-	0xc7, 0x00, 0xc6, 0xd2, 0x71, 0x19, 0x25, 0x48, 0xaf, 0xc6, 0x18, 0x8d, 0x5c, 0x6f, 0x85, 0x1a,
-	0xb8, 0x0f, 0xbf, 0x7d, 0xea, 0x11, 0xd7, 0x5b, 0xc5, 0xae, 0x17, 0x46, 0x0e, 0x63, 0xc8, 0xb8,
-	0x2f, 0xb7, 0xfe, 0x2a, 0x70, 0x08, 0x45, 0x4d, 0x3c, 0x00, 0xf4, 0x53, 0x06, 0x1b, 0xc6, 0xe6,
-	0xce, 0x62, 0x8d, 0x5a, 0xf3, 0xf6, 0x6b, 0xf3, 0xfa, 0x07, 0x3b, 0xa3, 0x7c, 0xe2, 0xa7, 0xef,
-	0x00, 0x00, 0x00, 0xff, 0xff, 0x09, 0x48, 0x18, 0xba, 0xc7, 0x01, 0x00, 0x00,
+func init() { proto.RegisterFile("hapi/release/status.proto", fileDescriptor_status_e683db75f3e63e75) }
+
+var fileDescriptor_status_e683db75f3e63e75 = []byte{
+	// 337 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x54, 0x90, 0xd1, 0x6e, 0x82, 0x30,
+	0x14, 0x86, 0x87, 0x22, 0x4a, 0x35, 0xae, 0xa9, 0x26, 0x43, 0xb3, 0x25, 0xc6, 0x2b, 0x6f, 0x06,
+	0x89, 0x7b, 0x02, 0xb4, 0xd5, 0x10, 0x1b, 0x20, 0x80, 0x5b, 0xb6, 0x1b, 0x82, 0xda, 0x39, 0x13,
+	0x43, 0x0d, 0x2d, 0x17, 0x7b, 0xa9, 0x3d, 0xd5, 0x1e, 0x64, 0x01, 0x5c, 0xd4, 0xcb, 0xff, 0xff,
+	0xbe, 0xc3, 0x39, 0x14, 0x0c, 0xbe, 0x92, 0xd3, 0xc1, 0xca, 0xd8, 0x91, 0x25, 0x82, 0x59, 0x42,
+	0x26, 0x32, 0x17, 0xe6, 0x29, 0xe3, 0x92, 0xa3, 0x4e, 0x81, 0xcc, 0x33, 0x1a, 0x3e, 0xdd, 0x88,
+	0x92, 0x09, 0x19, 0x8b, 0xfc, 0x20, 0x59, 0x25, 0x0f, 0x07, 0x7b, 0xce, 0xf7, 0x47, 0x66, 0x95,
+	0x69, 0x93, 0x7f, 0x5a, 0x49, 0xfa, 0x5d, 0xa1, 0xf1, 0x6f, 0x0d, 0x68, 0x61, 0xf9, 0x61, 0xf4,
+	0x0c, 0xd4, 0x2d, 0xdf, 0x31, 0x43, 0x19, 0x29, 0x93, 0xee, 0x74, 0x60, 0x5e, 0x6f, 0x30, 0x2b,
+	0xc7, 0x9c, 0xf3, 0x1d, 0x0b, 0x4a, 0x0d, 0x3d, 0x02, 0x3d, 0x63, 0x82, 0xe7, 0xd9, 0x96, 0x09,
+	0xa3, 0x3e, 0x52, 0x26, 0x7a, 0x70, 0x29, 0x50, 0x1f, 0x34, 0x52, 0x2e, 0x99, 0x30, 0xd4, 0x92,
+	0x54, 0x01, 0x2d, 0x40, 0xef, 0x98, 0x08, 0x19, 0x5f, 0x2e, 0x8c, 0xb3, 0x3c, 0x35, 0x1a, 0x23,
+	0x65, 0xd2, 0x9e, 0x3e, 0xdc, 0x6e, 0x8c, 0x98, 0x90, 0x61, 0xa1, 0x04, 0xb0, 0x98, 0xb9, 0xc4,
+	0x3c, 0x1d, 0xff, 0x28, 0x40, 0x2d, 0x4e, 0x41, 0x6d, 0xd0, 0x5c, 0xbb, 0x2b, 0xd7, 0x7b, 0x73,
+	0xe1, 0x1d, 0xea, 0x80, 0x16, 0x26, 0x3e, 0xf5, 0xde, 0x09, 0x86, 0x4a, 0x81, 0x30, 0xa1, 0x24,
+	0x22, 0x18, 0xd6, 0x50, 0x17, 0x80, 0x70, 0xed, 0x93, 0x20, 0x24, 0x98, 0x60, 0x58, 0x47, 0x00,
+	0x68, 0x0b, 0xdb, 0xa1, 0x04, 0x43, 0xb5, 0x1a, 0xa3, 0x24, 0x72, 0xdc, 0x25, 0x6c, 0xa0, 0x1e,
+	0xb8, 0xf7, 0x89, 0x8b, 0x1d, 0x77, 0x19, 0x3b, 0x6e, 0x18, 0xd9, 0x94, 0x42, 0xed, 0xba, 0x5c,
+	0xfb, 0xcb, 0xc0, 0xc6, 0x04, 0x36, 0x51, 0x1f, 0xc0, 0xff, 0x32, 0xf0, 0x28, 0x9d, 0xd9, 0xf3,
+	0x15, 0x6c, 0x5d, 0xb7, 0xb6, 0xef, 0x07, 0xde, 0xab, 0x4d, 0xa1, 0x3e, 0xd3, 0x3f, 0x9a, 0xe7,
+	0xff, 0xda, 0x68, 0xe5, 0xc3, 0xbf, 0xfc, 0x0d, 0x00, 0x3f, 0xc0, 0x29, 0x26, 0xdd, 0x01, 0x00,
+	0x00,
 }
@@ -54,7 +54,7 @@ func (x Result_Status) String() string {
 	return proto.EnumName(Result_Status_name, int32(x))
 }
 func (Result_Status) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_rudder_dd8cdbe38a210d28, []int{0, 0}
+	return fileDescriptor_rudder_f18825b7a600bbc9, []int{0, 0}
 }
 
 type Result struct {
@@ -69,7 +69,7 @@ func (m *Result) Reset()         { *m = Result{} }
 func (m *Result) String() string { return proto.CompactTextString(m) }
 func (*Result) ProtoMessage()    {}
 func (*Result) Descriptor() ([]byte, []int) {
-	return fileDescriptor_rudder_dd8cdbe38a210d28, []int{0}
+	return fileDescriptor_rudder_f18825b7a600bbc9, []int{0}
 }
 func (m *Result) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Result.Unmarshal(m, b)
@@ -113,7 +113,7 @@ func (m *VersionReleaseRequest) Reset()         { *m = VersionReleaseRequest{} }
 func (m *VersionReleaseRequest) String() string { return proto.CompactTextString(m) }
 func (*VersionReleaseRequest) ProtoMessage()    {}
 func (*VersionReleaseRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_rudder_dd8cdbe38a210d28, []int{1}
+	return fileDescriptor_rudder_f18825b7a600bbc9, []int{1}
 }
 func (m *VersionReleaseRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_VersionReleaseRequest.Unmarshal(m, b)
@@ -145,7 +145,7 @@ func (m *VersionReleaseResponse) Reset()         { *m = VersionReleaseResponse{}
 func (m *VersionReleaseResponse) String() string { return proto.CompactTextString(m) }
 func (*VersionReleaseResponse) ProtoMessage()    {}
 func (*VersionReleaseResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_rudder_dd8cdbe38a210d28, []int{2}
+	return fileDescriptor_rudder_f18825b7a600bbc9, []int{2}
 }
 func (m *VersionReleaseResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_VersionReleaseResponse.Unmarshal(m, b)
@@ -190,7 +190,7 @@ func (m *InstallReleaseRequest) Reset()         { *m = InstallReleaseRequest{} }
 func (m *InstallReleaseRequest) String() string { return proto.CompactTextString(m) }
 func (*InstallReleaseRequest) ProtoMessage()    {}
 func (*InstallReleaseRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_rudder_dd8cdbe38a210d28, []int{3}
+	return fileDescriptor_rudder_f18825b7a600bbc9, []int{3}
 }
 func (m *InstallReleaseRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_InstallReleaseRequest.Unmarshal(m, b)
@@ -229,7 +229,7 @@ func (m *InstallReleaseResponse) Reset()         { *m = InstallReleaseResponse{}
 func (m *InstallReleaseResponse) String() string { return proto.CompactTextString(m) }
 func (*InstallReleaseResponse) ProtoMessage()    {}
 func (*InstallReleaseResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_rudder_dd8cdbe38a210d28, []int{4}
+	return fileDescriptor_rudder_f18825b7a600bbc9, []int{4}
 }
 func (m *InstallReleaseResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_InstallReleaseResponse.Unmarshal(m, b)
@@ -274,7 +274,7 @@ func (m *DeleteReleaseRequest) Reset()         { *m = DeleteReleaseRequest{} }
 func (m *DeleteReleaseRequest) String() string { return proto.CompactTextString(m) }
 func (*DeleteReleaseRequest) ProtoMessage()    {}
 func (*DeleteReleaseRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_rudder_dd8cdbe38a210d28, []int{5}
+	return fileDescriptor_rudder_f18825b7a600bbc9, []int{5}
 }
 func (m *DeleteReleaseRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DeleteReleaseRequest.Unmarshal(m, b)
@@ -302,18 +302,21 @@ func (m *DeleteReleaseRequest) GetRelease() *release.Release {
 }
 
 type DeleteReleaseResponse struct {
-	Release              *release.Release `protobuf:"bytes,1,opt,name=release,proto3" json:"release,omitempty"`
-	Result               *Result          `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
-	XXX_unrecognized     []byte           `json:"-"`
-	XXX_sizecache        int32            `json:"-"`
+	Release *release.Release `protobuf:"bytes,1,opt,name=release,proto3" json:"release,omitempty"`
+	Result  *Result          `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"`
+	// kept_resources are the resources left in place by their resource
+	// policy, as sorted "Kind/name".
+	KeptResources        []string `protobuf:"bytes,3,rep,name=kept_resources,json=keptResources,proto3" json:"kept_resources,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *DeleteReleaseResponse) Reset()         { *m = DeleteReleaseResponse{} }
 func (m *DeleteReleaseResponse) String() string { return proto.CompactTextString(m) }
 func (*DeleteReleaseResponse) ProtoMessage()    {}
 func (*DeleteReleaseResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_rudder_dd8cdbe38a210d28, []int{6}
+	return fileDescriptor_rudder_f18825b7a600bbc9, []int{6}
 }
 func (m *DeleteReleaseResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DeleteReleaseResponse.Unmarshal(m, b)
@@ -347,6 +350,13 @@ func (m *DeleteReleaseResponse) GetResult() *Result {
 	return nil
 }
 
+func (m *DeleteReleaseResponse) GetKeptResources() []string {
+	if m != nil {
+		return m.KeptResources
+	}
+	return nil
+}
+
 type UpgradeReleaseRequest struct {
 	Current              *release.Release `protobuf:"bytes,1,opt,name=current,proto3" json:"current,omitempty"`
 	Target               *release.Release `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`
@@ -364,7 +374,7 @@ func (m *UpgradeReleaseRequest) Reset()         { *m = UpgradeReleaseRequest{} }
 func (m *UpgradeReleaseRequest) String() string { return proto.CompactTextString(m) }
 func (*UpgradeReleaseRequest) ProtoMessage()    {}
 func (*UpgradeReleaseRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_rudder_dd8cdbe38a210d28, []int{7}
+	return fileDescriptor_rudder_f18825b7a600bbc9, []int{7}
 }
 func (m *UpgradeReleaseRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpgradeReleaseRequest.Unmarshal(m, b)
@@ -445,7 +455,7 @@ func (m *UpgradeReleaseResponse) Reset()         { *m = UpgradeReleaseResponse{}
 func (m *UpgradeReleaseResponse) String() string { return proto.CompactTextString(m) }
 func (*UpgradeReleaseResponse) ProtoMessage()    {}
 func (*UpgradeReleaseResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_rudder_dd8cdbe38a210d28, []int{8}
+	return fileDescriptor_rudder_f18825b7a600bbc9, []int{8}
 }
 func (m *UpgradeReleaseResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpgradeReleaseResponse.Unmarshal(m, b)
@@ -496,7 +506,7 @@ func (m *RollbackReleaseRequest) Reset()         { *m = RollbackReleaseRequest{}
 func (m *RollbackReleaseRequest) String() string { return proto.CompactTextString(m) }
 func (*RollbackReleaseRequest) ProtoMessage()    {}
 func (*RollbackReleaseRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_rudder_dd8cdbe38a210d28, []int{9}
+	return fileDescriptor_rudder_f18825b7a600bbc9, []int{9}
 }
 func (m *RollbackReleaseRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RollbackReleaseRequest.Unmarshal(m, b)
@@ -577,7 +587,7 @@ func (m *RollbackReleaseResponse) Reset()         { *m = RollbackReleaseResponse
 func (m *RollbackReleaseResponse) String() string { return proto.CompactTextString(m) }
 func (*RollbackReleaseResponse) ProtoMessage()    {}
 func (*RollbackReleaseResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_rudder_dd8cdbe38a210d28, []int{10}
+	return fileDescriptor_rudder_f18825b7a600bbc9, []int{10}
 }
 func (m *RollbackReleaseResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RollbackReleaseResponse.Unmarshal(m, b)
@@ -622,7 +632,7 @@ func (m *ReleaseStatusRequest) Reset()         { *m = ReleaseStatusRequest{} }
 func (m *ReleaseStatusRequest) String() string { return proto.CompactTextString(m) }
 func (*ReleaseStatusRequest) ProtoMessage()    {}
 func (*ReleaseStatusRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_rudder_dd8cdbe38a210d28, []int{11}
+	return fileDescriptor_rudder_f18825b7a600bbc9, []int{11}
 }
 func (m *ReleaseStatusRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ReleaseStatusRequest.Unmarshal(m, b)
@@ -661,7 +671,7 @@ func (m *ReleaseStatusResponse) Reset()         { *m = ReleaseStatusResponse{} }
 func (m *ReleaseStatusResponse) String() string { return proto.CompactTextString(m) }
 func (*ReleaseStatusResponse) ProtoMessage()    {}
 func (*ReleaseStatusResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_rudder_dd8cdbe38a210d28, []int{12}
+	return fileDescriptor_rudder_f18825b7a600bbc9, []int{12}
 }
 func (m *ReleaseStatusResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ReleaseStatusResponse.Unmarshal(m, b)
@@ -959,47 +969,48 @@ var _ReleaseModuleService_serviceDesc = grpc.ServiceDesc{
 	Metadata: "hapi/rudder/rudder.proto",
 }
 
-func init() { proto.RegisterFile("hapi/rudder/rudder.proto", fileDescriptor_rudder_dd8cdbe38a210d28) }
-
-var fileDescriptor_rudder_dd8cdbe38a210d28 = []byte{
-	// 615 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe4, 0x56, 0x4d, 0x6f, 0xd3, 0x40,
-	0x10, 0x8d, 0x9b, 0xc6, 0x69, 0xa6, 0x2a, 0x44, 0xab, 0xba, 0xb5, 0x2c, 0x0e, 0x91, 0x85, 0x50,
-	0x44, 0xa9, 0x2b, 0x15, 0x8e, 0x5c, 0x20, 0xfd, 0x14, 0x22, 0x95, 0x36, 0x84, 0x4a, 0xdc, 0xb6,
-	0xc9, 0x24, 0x18, 0x36, 0xb6, 0x59, 0xaf, 0x7b, 0x04, 0x7e, 0x0d, 0xff, 0x12, 0x84, 0xec, 0xb5,
-	0x23, 0x6c, 0xd6, 0x22, 0x14, 0x29, 0x17, 0x4e, 0xde, 0xd9, 0x79, 0x9d, 0x9d, 0xf7, 0x76, 0xf6,
-	0x35, 0x60, 0xbf, 0x67, 0x91, 0x7f, 0x24, 0x92, 0xe9, 0x14, 0x45, 0xfe, 0xf1, 0x22, 0x11, 0xca,
-	0x90, 0xec, 0xa6, 0x19, 0x2f, 0x46, 0x71, 0xeb, 0x4f, 0x30, 0xf6, 0x54, 0xce, 0xd9, 0x57, 0x78,
-	0xe4, 0xc8, 0x62, 0x3c, 0xf2, 0x83, 0x59, 0xa8, 0xe0, 0x8e, 0x53, 0x4a, 0xe4, 0x5f, 0x95, 0x73,
-	0x39, 0x98, 0x14, 0xe3, 0x84, 0x4b, 0x42, 0x60, 0x33, 0xfd, 0x1b, 0xdb, 0xe8, 0x19, 0xfd, 0x0e,
-	0xcd, 0xd6, 0xa4, 0x0b, 0x4d, 0x1e, 0xce, 0xed, 0x8d, 0x5e, 0xb3, 0xdf, 0xa1, 0xe9, 0xd2, 0x7d,
-	0x0e, 0xe6, 0x48, 0x32, 0x99, 0xc4, 0x64, 0x1b, 0xda, 0xe3, 0xe1, 0xab, 0xe1, 0xd5, 0xf5, 0xb0,
-	0xdb, 0x48, 0x83, 0xd1, 0x78, 0x30, 0x38, 0x1d, 0x8d, 0xba, 0x06, 0xd9, 0x81, 0xce, 0x78, 0x38,
-	0xb8, 0x78, 0x31, 0x3c, 0x3f, 0x3d, 0xe9, 0x6e, 0x90, 0x0e, 0xb4, 0x4e, 0x29, 0xbd, 0xa2, 0xdd,
-	0xa6, 0xbb, 0x0f, 0xd6, 0x5b, 0x14, 0xb1, 0x1f, 0x06, 0x54, 0x75, 0x41, 0xf1, 0x53, 0x82, 0xb1,
-	0x74, 0xcf, 0x60, 0xaf, 0x9a, 0x88, 0xa3, 0x30, 0x88, 0x31, 0x6d, 0x2b, 0x60, 0x0b, 0x2c, 0xda,
-	0x4a, 0xd7, 0xc4, 0x86, 0xf6, 0xad, 0x42, 0xdb, 0x1b, 0xd9, 0x76, 0x11, 0xba, 0x17, 0x60, 0x5d,
-	0x06, 0xb1, 0x64, 0x9c, 0x97, 0x0f, 0x20, 0x47, 0xd0, 0xce, 0x89, 0x67, 0x95, 0xb6, 0x8f, 0x2d,
-	0x2f, 0x13, 0xb1, 0x50, 0xa3, 0x80, 0x17, 0x28, 0xf7, 0x0b, 0xec, 0x55, 0x2b, 0xe5, 0x1d, 0xfd,
-	0x6d, 0x29, 0xf2, 0x0c, 0x4c, 0x91, 0x69, 0x9c, 0x75, 0xbb, 0x7d, 0xfc, 0xc0, 0xd3, 0xdd, 0x9f,
-	0xa7, 0xee, 0x81, 0xe6, 0x58, 0xf7, 0x1c, 0x76, 0x4f, 0x90, 0xa3, 0xc4, 0x7f, 0x65, 0xf2, 0x19,
-	0xac, 0x4a, 0xa1, 0xf5, 0x12, 0xf9, 0x6e, 0x80, 0x35, 0x8e, 0xe6, 0x82, 0x4d, 0x35, 0x54, 0x26,
-	0x89, 0x10, 0x18, 0xc8, 0x3f, 0x34, 0x90, 0xa3, 0xc8, 0x21, 0x98, 0x92, 0x89, 0x39, 0x16, 0x0d,
-	0xd4, 0xe0, 0x73, 0x50, 0x3a, 0x27, 0x6f, 0xfc, 0x05, 0x86, 0x89, 0xb4, 0x9b, 0x3d, 0xa3, 0xdf,
-	0xa4, 0x45, 0x98, 0x4e, 0xd5, 0x35, 0xf3, 0xa5, 0xbd, 0xd9, 0x33, 0xfa, 0x5b, 0x34, 0x5b, 0x13,
-	0x07, 0xb6, 0x28, 0x4e, 0x04, 0x32, 0x89, 0x76, 0x2b, 0xdb, 0x5f, 0xc6, 0x64, 0x17, 0x5a, 0x67,
-	0xa1, 0x98, 0xa0, 0x6d, 0x66, 0x09, 0x15, 0x90, 0x87, 0xb0, 0x33, 0xe0, 0xc8, 0x82, 0x24, 0xba,
-	0x0a, 0xce, 0x98, 0xcf, 0xed, 0x76, 0x96, 0x2d, 0x6f, 0xa6, 0x93, 0x54, 0xa5, 0xbf, 0xde, 0x0b,
-	0xf8, 0x61, 0xc0, 0x1e, 0x0d, 0x39, 0xbf, 0x61, 0x93, 0x8f, 0xff, 0xe5, 0x0d, 0x7c, 0x35, 0x60,
-	0xff, 0x37, 0x01, 0xd6, 0xfe, 0x9a, 0xf3, 0x4a, 0xca, 0x3e, 0xef, 0xfc, 0x9a, 0x23, 0xb0, 0x2a,
-	0x85, 0xee, 0x4a, 0xe4, 0x51, 0x6e, 0xf8, 0x8a, 0x06, 0x29, 0xa3, 0x2f, 0x83, 0x59, 0xa8, 0xfe,
-	0x09, 0x1c, 0x7f, 0x6b, 0x2d, 0x7b, 0x7f, 0x1d, 0x4e, 0x13, 0x8e, 0x23, 0x45, 0x95, 0xcc, 0xa0,
-	0x9d, 0x9b, 0x36, 0x39, 0xd0, 0x8b, 0xa0, 0x35, 0x7b, 0xe7, 0xc9, 0x6a, 0x60, 0xc5, 0xcb, 0x6d,
-	0x90, 0x05, 0xdc, 0x2b, 0x5b, 0x71, 0xdd, 0x71, 0x5a, 0xeb, 0xaf, 0x3b, 0x4e, 0xef, 0xee, 0x6e,
-	0x83, 0x7c, 0x80, 0x9d, 0x92, 0x5f, 0x92, 0xc7, 0xfa, 0x02, 0x3a, 0x77, 0x76, 0x0e, 0x56, 0xc2,
-	0x2e, 0xcf, 0x8a, 0xe0, 0x7e, 0x65, 0x30, 0x49, 0x4d, 0xbb, 0xfa, 0x07, 0xec, 0x1c, 0xae, 0x88,
-	0xfe, 0x55, 0xcc, 0xb2, 0x1b, 0xd5, 0x89, 0xa9, 0xb5, 0xec, 0x3a, 0x31, 0xf5, 0x06, 0xa7, 0xc4,
-	0x2c, 0x8d, 0x6b, 0x9d, 0x98, 0xba, 0xc7, 0x51, 0x27, 0xa6, 0x76, 0xfe, 0xdd, 0xc6, 0xcb, 0xad,
-	0x77, 0xa6, 0x42, 0xdc, 0x98, 0xd9, 0x8f, 0x9b, 0xa7, 0x3f, 0x03, 0x00, 0x00, 0xff, 0xff, 0xe2,
-	0x9e, 0x21, 0x0d, 0x43, 0x09, 0x00, 0x00,
+func init() { proto.RegisterFile("hapi/rudder/rudder.proto", fileDescriptor_rudder_f18825b7a600bbc9) }
+
+var fileDescriptor_rudder_f18825b7a600bbc9 = []byte{
+	// 636 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe4, 0x56, 0xdf, 0x6f, 0xd3, 0x30,
+	0x10, 0x5e, 0xd6, 0x35, 0x5d, 0x6f, 0xea, 0xa8, 0xac, 0x75, 0x8b, 0x22, 0x1e, 0xaa, 0x08, 0x50,
+	0xc5, 0xb6, 0x4c, 0x1a, 0x3c, 0xf2, 0x02, 0xdd, 0x4f, 0x21, 0x3a, 0xc9, 0xa5, 0x4c, 0xe2, 0x05,
+	0x65, 0xed, 0x6d, 0x84, 0x79, 0x71, 0xb0, 0x9d, 0xbd, 0xc2, 0x3f, 0x03, 0xff, 0x25, 0x08, 0xc5,
+	0x4e, 0xaa, 0xa5, 0xa4, 0x22, 0x0c, 0xa9, 0x2f, 0x3c, 0xc5, 0xbe, 0xfb, 0x7a, 0xf7, 0x7d, 0xe7,
+	0xf3, 0xb9, 0xe0, 0x7c, 0x0c, 0xe2, 0x70, 0x4f, 0x24, 0x93, 0x09, 0x8a, 0xec, 0xe3, 0xc7, 0x82,
+	0x2b, 0x4e, 0x36, 0x52, 0x8f, 0x2f, 0x51, 0xdc, 0x86, 0x63, 0x94, 0xbe, 0xf1, 0xb9, 0x5b, 0x06,
+	0x8f, 0x0c, 0x03, 0x89, 0x7b, 0x61, 0x74, 0xc9, 0x0d, 0xdc, 0x75, 0x0b, 0x8e, 0xec, 0x6b, 0x7c,
+	0x1e, 0x03, 0x9b, 0xa2, 0x4c, 0x98, 0x22, 0x04, 0x56, 0xd2, 0xdf, 0x38, 0x56, 0xd7, 0xea, 0x35,
+	0xa9, 0x5e, 0x93, 0x36, 0xd4, 0x18, 0xbf, 0x72, 0x96, 0xbb, 0xb5, 0x5e, 0x93, 0xa6, 0x4b, 0xef,
+	0x05, 0xd8, 0x43, 0x15, 0xa8, 0x44, 0x92, 0x35, 0x68, 0x8c, 0x06, 0xaf, 0x07, 0x67, 0xe7, 0x83,
+	0xf6, 0x52, 0xba, 0x19, 0x8e, 0xfa, 0xfd, 0xc3, 0xe1, 0xb0, 0x6d, 0x91, 0x16, 0x34, 0x47, 0x83,
+	0xfe, 0xc9, 0xcb, 0xc1, 0xf1, 0xe1, 0x41, 0x7b, 0x99, 0x34, 0xa1, 0x7e, 0x48, 0xe9, 0x19, 0x6d,
+	0xd7, 0xbc, 0x2d, 0xe8, 0xbc, 0x43, 0x21, 0x43, 0x1e, 0x51, 0xc3, 0x82, 0xe2, 0xe7, 0x04, 0xa5,
+	0xf2, 0x8e, 0x60, 0x73, 0xd6, 0x21, 0x63, 0x1e, 0x49, 0x4c, 0x69, 0x45, 0xc1, 0x0d, 0xe6, 0xb4,
+	0xd2, 0x35, 0x71, 0xa0, 0x71, 0x6b, 0xd0, 0xce, 0xb2, 0x36, 0xe7, 0x5b, 0xef, 0x04, 0x3a, 0xa7,
+	0x91, 0x54, 0x01, 0x63, 0xc5, 0x04, 0x64, 0x0f, 0x1a, 0x99, 0x70, 0x1d, 0x69, 0x6d, 0xbf, 0xe3,
+	0xeb, 0x22, 0x66, 0x46, 0x3f, 0x87, 0xe7, 0x28, 0xef, 0x0b, 0x6c, 0xce, 0x46, 0xca, 0x18, 0xfd,
+	0x6d, 0x28, 0xf2, 0x1c, 0x6c, 0xa1, 0x6b, 0xac, 0xd9, 0xae, 0xed, 0x3f, 0xf4, 0xcb, 0xce, 0xcf,
+	0x37, 0xe7, 0x40, 0x33, 0xac, 0x77, 0x0c, 0x1b, 0x07, 0xc8, 0x50, 0xe1, 0xbf, 0x2a, 0xf9, 0x66,
+	0x41, 0x67, 0x26, 0xd2, 0x42, 0x95, 0x90, 0xc7, 0xb0, 0x7e, 0x8d, 0xb1, 0xfa, 0x20, 0x50, 0xf2,
+	0x44, 0x8c, 0x51, 0x3a, 0x35, 0xdd, 0x50, 0xad, 0xd4, 0x4a, 0x73, 0xa3, 0xf7, 0xc3, 0x82, 0xce,
+	0x28, 0xbe, 0x12, 0xc1, 0xa4, 0x44, 0xf2, 0x38, 0x11, 0x02, 0x23, 0xf5, 0x07, 0x9e, 0x19, 0x8a,
+	0xec, 0x82, 0xad, 0x02, 0x71, 0x85, 0x39, 0xcf, 0x39, 0xf8, 0x0c, 0x94, 0xf6, 0xd3, 0xdb, 0xf0,
+	0x06, 0x79, 0xa2, 0x9c, 0x5a, 0xd7, 0xea, 0xd5, 0x68, 0xbe, 0x4d, 0xbb, 0xef, 0x3c, 0x08, 0x95,
+	0xb3, 0xd2, 0xb5, 0x7a, 0xab, 0x54, 0xaf, 0x89, 0x0b, 0xab, 0x14, 0xc7, 0x02, 0x03, 0x85, 0x4e,
+	0x5d, 0xdb, 0xa7, 0x7b, 0xb2, 0x01, 0xf5, 0x23, 0x2e, 0xc6, 0xe8, 0xd8, 0xda, 0x61, 0x36, 0xe4,
+	0x11, 0xb4, 0xfa, 0x0c, 0x83, 0x28, 0x89, 0xcf, 0xa2, 0xa3, 0x20, 0x64, 0x4e, 0x43, 0x7b, 0x8b,
+	0xc6, 0xb4, 0xe3, 0x66, 0xe5, 0x2f, 0xb6, 0xe3, 0x7e, 0x5a, 0xb0, 0x49, 0x39, 0x63, 0x17, 0xc1,
+	0xf8, 0xfa, 0xbf, 0x3c, 0x81, 0xaf, 0x16, 0x6c, 0xfd, 0x56, 0x80, 0x85, 0xdf, 0xfa, 0x2c, 0x92,
+	0x19, 0xb3, 0xf7, 0xbe, 0xf5, 0x31, 0x74, 0x66, 0x02, 0xdd, 0x57, 0xc8, 0x93, 0xec, 0x61, 0x30,
+	0x32, 0x48, 0x11, 0x7d, 0x1a, 0x5d, 0x72, 0xf3, 0x58, 0xec, 0x7f, 0xaf, 0x4f, 0xb9, 0xbf, 0xe1,
+	0x93, 0x84, 0xe1, 0xd0, 0x48, 0x25, 0x97, 0xd0, 0xc8, 0x86, 0x3b, 0xd9, 0x2e, 0x2f, 0x42, 0xe9,
+	0xa3, 0xe0, 0xee, 0x54, 0x03, 0x1b, 0x5d, 0xde, 0x12, 0xb9, 0x81, 0xf5, 0xe2, 0xc8, 0x9e, 0x97,
+	0xae, 0xf4, 0x89, 0x70, 0x77, 0xaa, 0x81, 0xa7, 0xe9, 0x3e, 0x41, 0xab, 0x30, 0x56, 0xc9, 0xd3,
+	0xf2, 0x00, 0x65, 0x53, 0xdc, 0xdd, 0xae, 0x84, 0x9d, 0xe6, 0x8a, 0xe1, 0xc1, 0x4c, 0x63, 0x92,
+	0x39, 0x74, 0xcb, 0x2f, 0xb0, 0xbb, 0x5b, 0x11, 0x7d, 0xb7, 0x98, 0xc5, 0x69, 0x34, 0xaf, 0x98,
+	0xa5, 0x23, 0xdb, 0xdd, 0xa9, 0x06, 0xbe, 0x5b, 0xcc, 0x42, 0xbb, 0xce, 0x2b, 0x66, 0xd9, 0xe5,
+	0x70, 0xb7, 0x2b, 0x61, 0xf3, 0x5c, 0xaf, 0x56, 0xdf, 0xdb, 0x06, 0x71, 0x61, 0xeb, 0x3f, 0x41,
+	0xcf, 0x7e, 0x0d, 0x00, 0x45, 0x8f, 0x83, 0x44, 0x6b, 0x09, 0x00, 0x00,
 }
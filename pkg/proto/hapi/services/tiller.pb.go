@@ -53,7 +53,7 @@ func (x ListSort_SortBy) String() string {
 	return proto.EnumName(ListSort_SortBy_name, int32(x))
 }
 func (ListSort_SortBy) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_96f0f3bb9e4ff674, []int{1, 0}
+	return fileDescriptor_tiller_7a0eb69344511777, []int{1, 0}
 }
 
 // SortOrder defines sort orders to augment sorting operations.
@@ -77,7 +77,7 @@ func (x ListSort_SortOrder) String() string {
 	return proto.EnumName(ListSort_SortOrder_name, int32(x))
 }
 func (ListSort_SortOrder) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_96f0f3bb9e4ff674, []int{1, 1}
+	return fileDescriptor_tiller_7a0eb69344511777, []int{1, 1}
 }
 
 // ListReleasesRequest requests a list of releases.
@@ -104,7 +104,11 @@ type ListReleasesRequest struct {
 	SortOrder   ListSort_SortOrder    `protobuf:"varint,5,opt,name=sort_order,json=sortOrder,proto3,enum=hapi.services.tiller.ListSort_SortOrder" json:"sort_order,omitempty"`
 	StatusCodes []release.Status_Code `protobuf:"varint,6,rep,packed,name=status_codes,json=statusCodes,proto3,enum=hapi.release.Status_Code" json:"status_codes,omitempty"`
 	// Namespace is the filter to select releases only from a specific namespace.
-	Namespace            string   `protobuf:"bytes,7,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Namespace string `protobuf:"bytes,7,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// ChartName is the filter to select releases only deployed from a chart of this name.
+	ChartName string `protobuf:"bytes,8,opt,name=chart_name,json=chartName,proto3" json:"chart_name,omitempty"`
+	// ChartVersion is the filter to select releases only deployed from this chart version.
+	ChartVersion         string   `protobuf:"bytes,9,opt,name=chart_version,json=chartVersion,proto3" json:"chart_version,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -114,7 +118,7 @@ func (m *ListReleasesRequest) Reset()         { *m = ListReleasesRequest{} }
 func (m *ListReleasesRequest) String() string { return proto.CompactTextString(m) }
 func (*ListReleasesRequest) ProtoMessage()    {}
 func (*ListReleasesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_96f0f3bb9e4ff674, []int{0}
+	return fileDescriptor_tiller_7a0eb69344511777, []int{0}
 }
 func (m *ListReleasesRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ListReleasesRequest.Unmarshal(m, b)
@@ -183,6 +187,20 @@ func (m *ListReleasesRequest) GetNamespace() string {
 	return ""
 }
 
+func (m *ListReleasesRequest) GetChartName() string {
+	if m != nil {
+		return m.ChartName
+	}
+	return ""
+}
+
+func (m *ListReleasesRequest) GetChartVersion() string {
+	if m != nil {
+		return m.ChartVersion
+	}
+	return ""
+}
+
 // ListSort defines sorting fields on a release list.
 type ListSort struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -194,7 +212,7 @@ func (m *ListSort) Reset()         { *m = ListSort{} }
 func (m *ListSort) String() string { return proto.CompactTextString(m) }
 func (*ListSort) ProtoMessage()    {}
 func (*ListSort) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_96f0f3bb9e4ff674, []int{1}
+	return fileDescriptor_tiller_7a0eb69344511777, []int{1}
 }
 func (m *ListSort) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ListSort.Unmarshal(m, b)
@@ -234,7 +252,7 @@ func (m *ListReleasesResponse) Reset()         { *m = ListReleasesResponse{} }
 func (m *ListReleasesResponse) String() string { return proto.CompactTextString(m) }
 func (*ListReleasesResponse) ProtoMessage()    {}
 func (*ListReleasesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_96f0f3bb9e4ff674, []int{2}
+	return fileDescriptor_tiller_7a0eb69344511777, []int{2}
 }
 func (m *ListReleasesResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ListReleasesResponse.Unmarshal(m, b)
@@ -297,7 +315,7 @@ func (m *GetReleaseStatusRequest) Reset()         { *m = GetReleaseStatusRequest
 func (m *GetReleaseStatusRequest) String() string { return proto.CompactTextString(m) }
 func (*GetReleaseStatusRequest) ProtoMessage()    {}
 func (*GetReleaseStatusRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_96f0f3bb9e4ff674, []int{3}
+	return fileDescriptor_tiller_7a0eb69344511777, []int{3}
 }
 func (m *GetReleaseStatusRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetReleaseStatusRequest.Unmarshal(m, b)
@@ -338,17 +356,19 @@ type GetReleaseStatusResponse struct {
 	// Info contains information about the release.
 	Info *release.Info `protobuf:"bytes,2,opt,name=info,proto3" json:"info,omitempty"`
 	// Namespace the release was released into
-	Namespace            string   `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Namespace string `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// RecordAnnotations are the annotations of the storage record holding the release.
+	RecordAnnotations    map[string]string `protobuf:"bytes,4,rep,name=record_annotations,json=recordAnnotations,proto3" json:"record_annotations,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *GetReleaseStatusResponse) Reset()         { *m = GetReleaseStatusResponse{} }
 func (m *GetReleaseStatusResponse) String() string { return proto.CompactTextString(m) }
 func (*GetReleaseStatusResponse) ProtoMessage()    {}
 func (*GetReleaseStatusResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_96f0f3bb9e4ff674, []int{4}
+	return fileDescriptor_tiller_7a0eb69344511777, []int{4}
 }
 func (m *GetReleaseStatusResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetReleaseStatusResponse.Unmarshal(m, b)
@@ -389,12 +409,25 @@ func (m *GetReleaseStatusResponse) GetNamespace() string {
 	return ""
 }
 
+func (m *GetReleaseStatusResponse) GetRecordAnnotations() map[string]string {
+	if m != nil {
+		return m.RecordAnnotations
+	}
+	return nil
+}
+
 // GetReleaseContentRequest is a request to get the contents of a release.
 type GetReleaseContentRequest struct {
 	// The name of the release
 	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	// Version is the version of the release
-	Version              int32    `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	Version int32 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	// ExcludeChart returns the release with its chart reduced to the chart
+	// metadata, which is cheaper when only the manifest or hooks are needed.
+	ExcludeChart bool `protobuf:"varint,3,opt,name=exclude_chart,json=excludeChart,proto3" json:"exclude_chart,omitempty"`
+	// SplitManifest also returns the manifest of the release split into the
+	// documents of its individual resources.
+	SplitManifest        bool     `protobuf:"varint,4,opt,name=split_manifest,json=splitManifest,proto3" json:"split_manifest,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -404,7 +437,7 @@ func (m *GetReleaseContentRequest) Reset()         { *m = GetReleaseContentReque
 func (m *GetReleaseContentRequest) String() string { return proto.CompactTextString(m) }
 func (*GetReleaseContentRequest) ProtoMessage()    {}
 func (*GetReleaseContentRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_96f0f3bb9e4ff674, []int{5}
+	return fileDescriptor_tiller_7a0eb69344511777, []int{5}
 }
 func (m *GetReleaseContentRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetReleaseContentRequest.Unmarshal(m, b)
@@ -438,20 +471,37 @@ func (m *GetReleaseContentRequest) GetVersion() int32 {
 	return 0
 }
 
+func (m *GetReleaseContentRequest) GetExcludeChart() bool {
+	if m != nil {
+		return m.ExcludeChart
+	}
+	return false
+}
+
+func (m *GetReleaseContentRequest) GetSplitManifest() bool {
+	if m != nil {
+		return m.SplitManifest
+	}
+	return false
+}
+
 // GetReleaseContentResponse is a response containing the contents of a release.
 type GetReleaseContentResponse struct {
 	// The release content
-	Release              *release.Release `protobuf:"bytes,1,opt,name=release,proto3" json:"release,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
-	XXX_unrecognized     []byte           `json:"-"`
-	XXX_sizecache        int32            `json:"-"`
+	Release *release.Release `protobuf:"bytes,1,opt,name=release,proto3" json:"release,omitempty"`
+	// Manifests are the documents of the release manifest, in order, when
+	// split_manifest was requested.
+	Manifests            []*ManifestDocument `protobuf:"bytes,2,rep,name=manifests,proto3" json:"manifests,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
 }
 
 func (m *GetReleaseContentResponse) Reset()         { *m = GetReleaseContentResponse{} }
 func (m *GetReleaseContentResponse) String() string { return proto.CompactTextString(m) }
 func (*GetReleaseContentResponse) ProtoMessage()    {}
 func (*GetReleaseContentResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_96f0f3bb9e4ff674, []int{6}
+	return fileDescriptor_tiller_7a0eb69344511777, []int{6}
 }
 func (m *GetReleaseContentResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetReleaseContentResponse.Unmarshal(m, b)
@@ -478,6 +528,87 @@ func (m *GetReleaseContentResponse) GetRelease() *release.Release {
 	return nil
 }
 
+func (m *GetReleaseContentResponse) GetManifests() []*ManifestDocument {
+	if m != nil {
+		return m.Manifests
+	}
+	return nil
+}
+
+// ManifestDocument is the manifest of a single resource of a release.
+type ManifestDocument struct {
+	ApiVersion string `protobuf:"bytes,1,opt,name=api_version,json=apiVersion,proto3" json:"api_version,omitempty"`
+	Kind       string `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Name       string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	// Namespace is the namespace set in the document, empty for resources
+	// installed into the namespace of the release.
+	Namespace string `protobuf:"bytes,4,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// Content is the YAML document of the resource.
+	Content              string   `protobuf:"bytes,5,opt,name=content,proto3" json:"content,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ManifestDocument) Reset()         { *m = ManifestDocument{} }
+func (m *ManifestDocument) String() string { return proto.CompactTextString(m) }
+func (*ManifestDocument) ProtoMessage()    {}
+func (*ManifestDocument) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_7a0eb69344511777, []int{7}
+}
+func (m *ManifestDocument) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ManifestDocument.Unmarshal(m, b)
+}
+func (m *ManifestDocument) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ManifestDocument.Marshal(b, m, deterministic)
+}
+func (dst *ManifestDocument) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ManifestDocument.Merge(dst, src)
+}
+func (m *ManifestDocument) XXX_Size() int {
+	return xxx_messageInfo_ManifestDocument.Size(m)
+}
+func (m *ManifestDocument) XXX_DiscardUnknown() {
+	xxx_messageInfo_ManifestDocument.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ManifestDocument proto.InternalMessageInfo
+
+func (m *ManifestDocument) GetApiVersion() string {
+	if m != nil {
+		return m.ApiVersion
+	}
+	return ""
+}
+
+func (m *ManifestDocument) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *ManifestDocument) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ManifestDocument) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *ManifestDocument) GetContent() string {
+	if m != nil {
+		return m.Content
+	}
+	return ""
+}
+
 // UpdateReleaseRequest updates a release.
 type UpdateReleaseRequest struct {
 	// The name of the release
@@ -509,7 +640,9 @@ type UpdateReleaseRequest struct {
 	// Render subchart notes if enabled
 	SubNotes bool `protobuf:"varint,13,opt,name=subNotes,proto3" json:"subNotes,omitempty"`
 	// Allow deletion of new resources created in this update when update failed
-	CleanupOnFail        bool     `protobuf:"varint,14,opt,name=cleanup_on_fail,json=cleanupOnFail,proto3" json:"cleanup_on_fail,omitempty"`
+	CleanupOnFail bool `protobuf:"varint,14,opt,name=cleanup_on_fail,json=cleanupOnFail,proto3" json:"cleanup_on_fail,omitempty"`
+	// restart rolls the Deployments and StatefulSets of the release as part of the update, before any wait
+	Restart              bool     `protobuf:"varint,15,opt,name=restart,proto3" json:"restart,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -519,7 +652,7 @@ func (m *UpdateReleaseRequest) Reset()         { *m = UpdateReleaseRequest{} }
 func (m *UpdateReleaseRequest) String() string { return proto.CompactTextString(m) }
 func (*UpdateReleaseRequest) ProtoMessage()    {}
 func (*UpdateReleaseRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_96f0f3bb9e4ff674, []int{7}
+	return fileDescriptor_tiller_7a0eb69344511777, []int{8}
 }
 func (m *UpdateReleaseRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpdateReleaseRequest.Unmarshal(m, b)
@@ -637,19 +770,29 @@ func (m *UpdateReleaseRequest) GetCleanupOnFail() bool {
 	return false
 }
 
+func (m *UpdateReleaseRequest) GetRestart() bool {
+	if m != nil {
+		return m.Restart
+	}
+	return false
+}
+
 // UpdateReleaseResponse is the response to an update request.
 type UpdateReleaseResponse struct {
-	Release              *release.Release `protobuf:"bytes,1,opt,name=release,proto3" json:"release,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
-	XXX_unrecognized     []byte           `json:"-"`
-	XXX_sizecache        int32            `json:"-"`
+	Release *release.Release `protobuf:"bytes,1,opt,name=release,proto3" json:"release,omitempty"`
+	// HookResults are the outcomes of the hooks run by the update, in the
+	// order they ran.
+	HookResults          []*release.HookResult `protobuf:"bytes,2,rep,name=hook_results,json=hookResults,proto3" json:"hook_results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
 }
 
 func (m *UpdateReleaseResponse) Reset()         { *m = UpdateReleaseResponse{} }
 func (m *UpdateReleaseResponse) String() string { return proto.CompactTextString(m) }
 func (*UpdateReleaseResponse) ProtoMessage()    {}
 func (*UpdateReleaseResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_96f0f3bb9e4ff674, []int{8}
+	return fileDescriptor_tiller_7a0eb69344511777, []int{9}
 }
 func (m *UpdateReleaseResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpdateReleaseResponse.Unmarshal(m, b)
@@ -676,6 +819,13 @@ func (m *UpdateReleaseResponse) GetRelease() *release.Release {
 	return nil
 }
 
+func (m *UpdateReleaseResponse) GetHookResults() []*release.HookResult {
+	if m != nil {
+		return m.HookResults
+	}
+	return nil
+}
+
 type RollbackReleaseRequest struct {
 	// The name of the release
 	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
@@ -697,7 +847,10 @@ type RollbackReleaseRequest struct {
 	// Description, if set, will set the description for the rollback
 	Description string `protobuf:"bytes,9,opt,name=description,proto3" json:"description,omitempty"`
 	// Allow deletion of new resources created in this rollback when rollback failed
-	CleanupOnFail        bool     `protobuf:"varint,10,opt,name=cleanup_on_fail,json=cleanupOnFail,proto3" json:"cleanup_on_fail,omitempty"`
+	CleanupOnFail bool `protobuf:"varint,10,opt,name=cleanup_on_fail,json=cleanupOnFail,proto3" json:"cleanup_on_fail,omitempty"`
+	// ReuseValues, if true, merges the current release's values over the target
+	// revision's values and renders the target revision's chart with them.
+	ReuseValues          bool     `protobuf:"varint,11,opt,name=reuse_values,json=reuseValues,proto3" json:"reuse_values,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -707,7 +860,7 @@ func (m *RollbackReleaseRequest) Reset()         { *m = RollbackReleaseRequest{}
 func (m *RollbackReleaseRequest) String() string { return proto.CompactTextString(m) }
 func (*RollbackReleaseRequest) ProtoMessage()    {}
 func (*RollbackReleaseRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_96f0f3bb9e4ff674, []int{9}
+	return fileDescriptor_tiller_7a0eb69344511777, []int{10}
 }
 func (m *RollbackReleaseRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RollbackReleaseRequest.Unmarshal(m, b)
@@ -797,6 +950,13 @@ func (m *RollbackReleaseRequest) GetCleanupOnFail() bool {
 	return false
 }
 
+func (m *RollbackReleaseRequest) GetReuseValues() bool {
+	if m != nil {
+		return m.ReuseValues
+	}
+	return false
+}
+
 // RollbackReleaseResponse is the response to an update request.
 type RollbackReleaseResponse struct {
 	Release              *release.Release `protobuf:"bytes,1,opt,name=release,proto3" json:"release,omitempty"`
@@ -809,7 +969,7 @@ func (m *RollbackReleaseResponse) Reset()         { *m = RollbackReleaseResponse
 func (m *RollbackReleaseResponse) String() string { return proto.CompactTextString(m) }
 func (*RollbackReleaseResponse) ProtoMessage()    {}
 func (*RollbackReleaseResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_96f0f3bb9e4ff674, []int{10}
+	return fileDescriptor_tiller_7a0eb69344511777, []int{11}
 }
 func (m *RollbackReleaseResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RollbackReleaseResponse.Unmarshal(m, b)
@@ -863,8 +1023,21 @@ type InstallReleaseRequest struct {
 	Wait           bool `protobuf:"varint,9,opt,name=wait,proto3" json:"wait,omitempty"`
 	DisableCrdHook bool `protobuf:"varint,10,opt,name=disable_crd_hook,json=disableCrdHook,proto3" json:"disable_crd_hook,omitempty"`
 	// Description, if set, will set the description for the installed release
-	Description          string   `protobuf:"bytes,11,opt,name=description,proto3" json:"description,omitempty"`
-	SubNotes             bool     `protobuf:"varint,12,opt,name=subNotes,proto3" json:"subNotes,omitempty"`
+	Description string `protobuf:"bytes,11,opt,name=description,proto3" json:"description,omitempty"`
+	SubNotes    bool   `protobuf:"varint,12,opt,name=subNotes,proto3" json:"subNotes,omitempty"`
+	// BaseRevision, if set, is the revision the release is first stored at
+	// instead of 1. It preserves revision numbers of migrated releases.
+	BaseRevision int32 `protobuf:"varint,13,opt,name=base_revision,json=baseRevision,proto3" json:"base_revision,omitempty"`
+	// ChartReference, if set and chart is empty, names a chart in a chart
+	// repository that Tiller downloads and installs.
+	ChartReference *ChartReference `protobuf:"bytes,14,opt,name=chart_reference,json=chartReference,proto3" json:"chart_reference,omitempty"`
+	// Atomic, if true, deletes the resources of the release and marks it
+	// FAILED when the install fails or its context is cancelled or expires.
+	Atomic bool `protobuf:"varint,15,opt,name=atomic,proto3" json:"atomic,omitempty"`
+	// SetValues are key=value overrides in the syntax of helm's --set flag,
+	// such as "image.tag=1.2" or "ports[0]=80". They are applied in order
+	// over values.
+	SetValues            []string `protobuf:"bytes,16,rep,name=set_values,json=setValues,proto3" json:"set_values,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -874,7 +1047,7 @@ func (m *InstallReleaseRequest) Reset()         { *m = InstallReleaseRequest{} }
 func (m *InstallReleaseRequest) String() string { return proto.CompactTextString(m) }
 func (*InstallReleaseRequest) ProtoMessage()    {}
 func (*InstallReleaseRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_96f0f3bb9e4ff674, []int{11}
+	return fileDescriptor_tiller_7a0eb69344511777, []int{12}
 }
 func (m *InstallReleaseRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_InstallReleaseRequest.Unmarshal(m, b)
@@ -978,19 +1151,110 @@ func (m *InstallReleaseRequest) GetSubNotes() bool {
 	return false
 }
 
+func (m *InstallReleaseRequest) GetBaseRevision() int32 {
+	if m != nil {
+		return m.BaseRevision
+	}
+	return 0
+}
+
+func (m *InstallReleaseRequest) GetChartReference() *ChartReference {
+	if m != nil {
+		return m.ChartReference
+	}
+	return nil
+}
+
+func (m *InstallReleaseRequest) GetAtomic() bool {
+	if m != nil {
+		return m.Atomic
+	}
+	return false
+}
+
+func (m *InstallReleaseRequest) GetSetValues() []string {
+	if m != nil {
+		return m.SetValues
+	}
+	return nil
+}
+
+// ChartReference identifies a chart archive in a chart repository.
+type ChartReference struct {
+	// RepoUrl is the base URL of the repository serving index.yaml. If empty,
+	// Tiller's configured default repository is used.
+	RepoUrl string `protobuf:"bytes,1,opt,name=repo_url,json=repoUrl,proto3" json:"repo_url,omitempty"`
+	// Name is the name of the chart in the repository index.
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Version is the chart version or SemVer constraint. If empty, the
+	// latest version is used.
+	Version              string   `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ChartReference) Reset()         { *m = ChartReference{} }
+func (m *ChartReference) String() string { return proto.CompactTextString(m) }
+func (*ChartReference) ProtoMessage()    {}
+func (*ChartReference) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_7a0eb69344511777, []int{13}
+}
+func (m *ChartReference) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChartReference.Unmarshal(m, b)
+}
+func (m *ChartReference) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChartReference.Marshal(b, m, deterministic)
+}
+func (dst *ChartReference) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChartReference.Merge(dst, src)
+}
+func (m *ChartReference) XXX_Size() int {
+	return xxx_messageInfo_ChartReference.Size(m)
+}
+func (m *ChartReference) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChartReference.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChartReference proto.InternalMessageInfo
+
+func (m *ChartReference) GetRepoUrl() string {
+	if m != nil {
+		return m.RepoUrl
+	}
+	return ""
+}
+
+func (m *ChartReference) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ChartReference) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
 // InstallReleaseResponse is the response from a release installation.
 type InstallReleaseResponse struct {
-	Release              *release.Release `protobuf:"bytes,1,opt,name=release,proto3" json:"release,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
-	XXX_unrecognized     []byte           `json:"-"`
-	XXX_sizecache        int32            `json:"-"`
+	Release *release.Release `protobuf:"bytes,1,opt,name=release,proto3" json:"release,omitempty"`
+	// HookResults are the outcomes of the hooks run by the install, in the
+	// order they ran.
+	HookResults          []*release.HookResult `protobuf:"bytes,2,rep,name=hook_results,json=hookResults,proto3" json:"hook_results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
 }
 
 func (m *InstallReleaseResponse) Reset()         { *m = InstallReleaseResponse{} }
 func (m *InstallReleaseResponse) String() string { return proto.CompactTextString(m) }
 func (*InstallReleaseResponse) ProtoMessage()    {}
 func (*InstallReleaseResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_96f0f3bb9e4ff674, []int{12}
+	return fileDescriptor_tiller_7a0eb69344511777, []int{14}
 }
 func (m *InstallReleaseResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_InstallReleaseResponse.Unmarshal(m, b)
@@ -1017,6 +1281,13 @@ func (m *InstallReleaseResponse) GetRelease() *release.Release {
 	return nil
 }
 
+func (m *InstallReleaseResponse) GetHookResults() []*release.HookResult {
+	if m != nil {
+		return m.HookResults
+	}
+	return nil
+}
+
 // UninstallReleaseRequest represents a request to uninstall a named release.
 type UninstallReleaseRequest struct {
 	// Name is the name of the release to delete.
@@ -1038,7 +1309,7 @@ func (m *UninstallReleaseRequest) Reset()         { *m = UninstallReleaseRequest
 func (m *UninstallReleaseRequest) String() string { return proto.CompactTextString(m) }
 func (*UninstallReleaseRequest) ProtoMessage()    {}
 func (*UninstallReleaseRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_96f0f3bb9e4ff674, []int{13}
+	return fileDescriptor_tiller_7a0eb69344511777, []int{15}
 }
 func (m *UninstallReleaseRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UninstallReleaseRequest.Unmarshal(m, b)
@@ -1098,7 +1369,10 @@ type UninstallReleaseResponse struct {
 	// Release is the release that was marked deleted.
 	Release *release.Release `protobuf:"bytes,1,opt,name=release,proto3" json:"release,omitempty"`
 	// Info is an uninstall message
-	Info                 string   `protobuf:"bytes,2,opt,name=info,proto3" json:"info,omitempty"`
+	Info string `protobuf:"bytes,2,opt,name=info,proto3" json:"info,omitempty"`
+	// KeptResources names, as "Kind/name", the resources left in place
+	// because of their helm.sh/resource-policy annotation.
+	KeptResources        []string `protobuf:"bytes,3,rep,name=kept_resources,json=keptResources,proto3" json:"kept_resources,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1108,7 +1382,7 @@ func (m *UninstallReleaseResponse) Reset()         { *m = UninstallReleaseRespon
 func (m *UninstallReleaseResponse) String() string { return proto.CompactTextString(m) }
 func (*UninstallReleaseResponse) ProtoMessage()    {}
 func (*UninstallReleaseResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_96f0f3bb9e4ff674, []int{14}
+	return fileDescriptor_tiller_7a0eb69344511777, []int{16}
 }
 func (m *UninstallReleaseResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UninstallReleaseResponse.Unmarshal(m, b)
@@ -1142,6 +1416,13 @@ func (m *UninstallReleaseResponse) GetInfo() string {
 	return ""
 }
 
+func (m *UninstallReleaseResponse) GetKeptResources() []string {
+	if m != nil {
+		return m.KeptResources
+	}
+	return nil
+}
+
 // GetVersionRequest requests for version information.
 type GetVersionRequest struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -1153,7 +1434,7 @@ func (m *GetVersionRequest) Reset()         { *m = GetVersionRequest{} }
 func (m *GetVersionRequest) String() string { return proto.CompactTextString(m) }
 func (*GetVersionRequest) ProtoMessage()    {}
 func (*GetVersionRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_96f0f3bb9e4ff674, []int{15}
+	return fileDescriptor_tiller_7a0eb69344511777, []int{17}
 }
 func (m *GetVersionRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetVersionRequest.Unmarshal(m, b)
@@ -1184,7 +1465,7 @@ func (m *GetVersionResponse) Reset()         { *m = GetVersionResponse{} }
 func (m *GetVersionResponse) String() string { return proto.CompactTextString(m) }
 func (*GetVersionResponse) ProtoMessage()    {}
 func (*GetVersionResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_96f0f3bb9e4ff674, []int{16}
+	return fileDescriptor_tiller_7a0eb69344511777, []int{18}
 }
 func (m *GetVersionResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetVersionResponse.Unmarshal(m, b)
@@ -1216,7 +1497,9 @@ type GetHistoryRequest struct {
 	// The name of the release.
 	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	// The maximum number of releases to include.
-	Max                  int32    `protobuf:"varint,2,opt,name=max,proto3" json:"max,omitempty"`
+	Max int32 `protobuf:"varint,2,opt,name=max,proto3" json:"max,omitempty"`
+	// Namespace, if set, limits the history to revisions in that namespace.
+	Namespace            string   `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1226,7 +1509,7 @@ func (m *GetHistoryRequest) Reset()         { *m = GetHistoryRequest{} }
 func (m *GetHistoryRequest) String() string { return proto.CompactTextString(m) }
 func (*GetHistoryRequest) ProtoMessage()    {}
 func (*GetHistoryRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_96f0f3bb9e4ff674, []int{17}
+	return fileDescriptor_tiller_7a0eb69344511777, []int{19}
 }
 func (m *GetHistoryRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetHistoryRequest.Unmarshal(m, b)
@@ -1260,6 +1543,13 @@ func (m *GetHistoryRequest) GetMax() int32 {
 	return 0
 }
 
+func (m *GetHistoryRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
 // GetHistoryResponse is received in response to a GetHistory rpc.
 type GetHistoryResponse struct {
 	Releases             []*release.Release `protobuf:"bytes,1,rep,name=releases,proto3" json:"releases,omitempty"`
@@ -1272,7 +1562,7 @@ func (m *GetHistoryResponse) Reset()         { *m = GetHistoryResponse{} }
 func (m *GetHistoryResponse) String() string { return proto.CompactTextString(m) }
 func (*GetHistoryResponse) ProtoMessage()    {}
 func (*GetHistoryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_96f0f3bb9e4ff674, []int{18}
+	return fileDescriptor_tiller_7a0eb69344511777, []int{20}
 }
 func (m *GetHistoryResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetHistoryResponse.Unmarshal(m, b)
@@ -1322,7 +1612,7 @@ func (m *TestReleaseRequest) Reset()         { *m = TestReleaseRequest{} }
 func (m *TestReleaseRequest) String() string { return proto.CompactTextString(m) }
 func (*TestReleaseRequest) ProtoMessage()    {}
 func (*TestReleaseRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_96f0f3bb9e4ff674, []int{19}
+	return fileDescriptor_tiller_7a0eb69344511777, []int{21}
 }
 func (m *TestReleaseRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TestReleaseRequest.Unmarshal(m, b)
@@ -1397,7 +1687,7 @@ func (m *TestReleaseResponse) Reset()         { *m = TestReleaseResponse{} }
 func (m *TestReleaseResponse) String() string { return proto.CompactTextString(m) }
 func (*TestReleaseResponse) ProtoMessage()    {}
 func (*TestReleaseResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tiller_96f0f3bb9e4ff674, []int{20}
+	return fileDescriptor_tiller_7a0eb69344511777, []int{22}
 }
 func (m *TestReleaseResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TestReleaseResponse.Unmarshal(m, b)
@@ -1431,19 +1721,497 @@ func (m *TestReleaseResponse) GetStatus() release.TestRun_Status {
 	return release.TestRun_UNKNOWN
 }
 
+// GetReleaseAuditRequest requests the audit trail of a release.
+type GetReleaseAuditRequest struct {
+	// The name of the release.
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetReleaseAuditRequest) Reset()         { *m = GetReleaseAuditRequest{} }
+func (m *GetReleaseAuditRequest) String() string { return proto.CompactTextString(m) }
+func (*GetReleaseAuditRequest) ProtoMessage()    {}
+func (*GetReleaseAuditRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_7a0eb69344511777, []int{23}
+}
+func (m *GetReleaseAuditRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetReleaseAuditRequest.Unmarshal(m, b)
+}
+func (m *GetReleaseAuditRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetReleaseAuditRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetReleaseAuditRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetReleaseAuditRequest.Merge(dst, src)
+}
+func (m *GetReleaseAuditRequest) XXX_Size() int {
+	return xxx_messageInfo_GetReleaseAuditRequest.Size(m)
+}
+func (m *GetReleaseAuditRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetReleaseAuditRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetReleaseAuditRequest proto.InternalMessageInfo
+
+func (m *GetReleaseAuditRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+// GetReleaseAuditResponse is received in response to a GetReleaseAudit rpc.
+type GetReleaseAuditResponse struct {
+	// Entries are ordered from oldest to newest.
+	Entries              []*release.AuditEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *GetReleaseAuditResponse) Reset()         { *m = GetReleaseAuditResponse{} }
+func (m *GetReleaseAuditResponse) String() string { return proto.CompactTextString(m) }
+func (*GetReleaseAuditResponse) ProtoMessage()    {}
+func (*GetReleaseAuditResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_7a0eb69344511777, []int{24}
+}
+func (m *GetReleaseAuditResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetReleaseAuditResponse.Unmarshal(m, b)
+}
+func (m *GetReleaseAuditResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetReleaseAuditResponse.Marshal(b, m, deterministic)
+}
+func (dst *GetReleaseAuditResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetReleaseAuditResponse.Merge(dst, src)
+}
+func (m *GetReleaseAuditResponse) XXX_Size() int {
+	return xxx_messageInfo_GetReleaseAuditResponse.Size(m)
+}
+func (m *GetReleaseAuditResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetReleaseAuditResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetReleaseAuditResponse proto.InternalMessageInfo
+
+func (m *GetReleaseAuditResponse) GetEntries() []*release.AuditEntry {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+// ReencryptReleasesRequest requests that stored releases be re-encrypted.
+type ReencryptReleasesRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReencryptReleasesRequest) Reset()         { *m = ReencryptReleasesRequest{} }
+func (m *ReencryptReleasesRequest) String() string { return proto.CompactTextString(m) }
+func (*ReencryptReleasesRequest) ProtoMessage()    {}
+func (*ReencryptReleasesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_7a0eb69344511777, []int{25}
+}
+func (m *ReencryptReleasesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReencryptReleasesRequest.Unmarshal(m, b)
+}
+func (m *ReencryptReleasesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReencryptReleasesRequest.Marshal(b, m, deterministic)
+}
+func (dst *ReencryptReleasesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReencryptReleasesRequest.Merge(dst, src)
+}
+func (m *ReencryptReleasesRequest) XXX_Size() int {
+	return xxx_messageInfo_ReencryptReleasesRequest.Size(m)
+}
+func (m *ReencryptReleasesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReencryptReleasesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReencryptReleasesRequest proto.InternalMessageInfo
+
+// ReencryptReleasesResponse is received in response to a ReencryptReleases rpc.
+type ReencryptReleasesResponse struct {
+	// The number of stored releases that were rewritten.
+	Reencrypted          int32    `protobuf:"varint,1,opt,name=reencrypted,proto3" json:"reencrypted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReencryptReleasesResponse) Reset()         { *m = ReencryptReleasesResponse{} }
+func (m *ReencryptReleasesResponse) String() string { return proto.CompactTextString(m) }
+func (*ReencryptReleasesResponse) ProtoMessage()    {}
+func (*ReencryptReleasesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_7a0eb69344511777, []int{26}
+}
+func (m *ReencryptReleasesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReencryptReleasesResponse.Unmarshal(m, b)
+}
+func (m *ReencryptReleasesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReencryptReleasesResponse.Marshal(b, m, deterministic)
+}
+func (dst *ReencryptReleasesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReencryptReleasesResponse.Merge(dst, src)
+}
+func (m *ReencryptReleasesResponse) XXX_Size() int {
+	return xxx_messageInfo_ReencryptReleasesResponse.Size(m)
+}
+func (m *ReencryptReleasesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReencryptReleasesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReencryptReleasesResponse proto.InternalMessageInfo
+
+func (m *ReencryptReleasesResponse) GetReencrypted() int32 {
+	if m != nil {
+		return m.Reencrypted
+	}
+	return 0
+}
+
+// ApproveReleaseRequest approves an upgrade in the PENDING_APPROVAL state.
+type ApproveReleaseRequest struct {
+	// The name of the release.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The version awaiting approval. 0 means the latest version.
+	Version int32 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	// timeout specifies the max amount of time any kubernetes client command can run.
+	Timeout              int64    `protobuf:"varint,3,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ApproveReleaseRequest) Reset()         { *m = ApproveReleaseRequest{} }
+func (m *ApproveReleaseRequest) String() string { return proto.CompactTextString(m) }
+func (*ApproveReleaseRequest) ProtoMessage()    {}
+func (*ApproveReleaseRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_7a0eb69344511777, []int{27}
+}
+func (m *ApproveReleaseRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ApproveReleaseRequest.Unmarshal(m, b)
+}
+func (m *ApproveReleaseRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ApproveReleaseRequest.Marshal(b, m, deterministic)
+}
+func (dst *ApproveReleaseRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ApproveReleaseRequest.Merge(dst, src)
+}
+func (m *ApproveReleaseRequest) XXX_Size() int {
+	return xxx_messageInfo_ApproveReleaseRequest.Size(m)
+}
+func (m *ApproveReleaseRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ApproveReleaseRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ApproveReleaseRequest proto.InternalMessageInfo
+
+func (m *ApproveReleaseRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ApproveReleaseRequest) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *ApproveReleaseRequest) GetTimeout() int64 {
+	if m != nil {
+		return m.Timeout
+	}
+	return 0
+}
+
+// ApproveReleaseResponse is received in response to an ApproveRelease rpc.
+type ApproveReleaseResponse struct {
+	Release              *release.Release `protobuf:"bytes,1,opt,name=release,proto3" json:"release,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *ApproveReleaseResponse) Reset()         { *m = ApproveReleaseResponse{} }
+func (m *ApproveReleaseResponse) String() string { return proto.CompactTextString(m) }
+func (*ApproveReleaseResponse) ProtoMessage()    {}
+func (*ApproveReleaseResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_7a0eb69344511777, []int{28}
+}
+func (m *ApproveReleaseResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ApproveReleaseResponse.Unmarshal(m, b)
+}
+func (m *ApproveReleaseResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ApproveReleaseResponse.Marshal(b, m, deterministic)
+}
+func (dst *ApproveReleaseResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ApproveReleaseResponse.Merge(dst, src)
+}
+func (m *ApproveReleaseResponse) XXX_Size() int {
+	return xxx_messageInfo_ApproveReleaseResponse.Size(m)
+}
+func (m *ApproveReleaseResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ApproveReleaseResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ApproveReleaseResponse proto.InternalMessageInfo
+
+func (m *ApproveReleaseResponse) GetRelease() *release.Release {
+	if m != nil {
+		return m.Release
+	}
+	return nil
+}
+
+// GetReleaseHooksRequest requests the hooks of a release.
+type GetReleaseHooksRequest struct {
+	// The name of the release.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The version of the release. 0 means the latest version.
+	Version              int32    `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetReleaseHooksRequest) Reset()         { *m = GetReleaseHooksRequest{} }
+func (m *GetReleaseHooksRequest) String() string { return proto.CompactTextString(m) }
+func (*GetReleaseHooksRequest) ProtoMessage()    {}
+func (*GetReleaseHooksRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_7a0eb69344511777, []int{29}
+}
+func (m *GetReleaseHooksRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetReleaseHooksRequest.Unmarshal(m, b)
+}
+func (m *GetReleaseHooksRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetReleaseHooksRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetReleaseHooksRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetReleaseHooksRequest.Merge(dst, src)
+}
+func (m *GetReleaseHooksRequest) XXX_Size() int {
+	return xxx_messageInfo_GetReleaseHooksRequest.Size(m)
+}
+func (m *GetReleaseHooksRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetReleaseHooksRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetReleaseHooksRequest proto.InternalMessageInfo
+
+func (m *GetReleaseHooksRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *GetReleaseHooksRequest) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+// GetReleaseHooksResponse is received in response to a GetReleaseHooks rpc.
+type GetReleaseHooksResponse struct {
+	// Hooks are in the order they were sorted in when the release was rendered.
+	Hooks                []*release.Hook `protobuf:"bytes,1,rep,name=hooks,proto3" json:"hooks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *GetReleaseHooksResponse) Reset()         { *m = GetReleaseHooksResponse{} }
+func (m *GetReleaseHooksResponse) String() string { return proto.CompactTextString(m) }
+func (*GetReleaseHooksResponse) ProtoMessage()    {}
+func (*GetReleaseHooksResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_7a0eb69344511777, []int{30}
+}
+func (m *GetReleaseHooksResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetReleaseHooksResponse.Unmarshal(m, b)
+}
+func (m *GetReleaseHooksResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetReleaseHooksResponse.Marshal(b, m, deterministic)
+}
+func (dst *GetReleaseHooksResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetReleaseHooksResponse.Merge(dst, src)
+}
+func (m *GetReleaseHooksResponse) XXX_Size() int {
+	return xxx_messageInfo_GetReleaseHooksResponse.Size(m)
+}
+func (m *GetReleaseHooksResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetReleaseHooksResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetReleaseHooksResponse proto.InternalMessageInfo
+
+func (m *GetReleaseHooksResponse) GetHooks() []*release.Hook {
+	if m != nil {
+		return m.Hooks
+	}
+	return nil
+}
+
+// GetReleaseContentsRequest requests the content of several releases.
+type GetReleaseContentsRequest struct {
+	// Releases are handled like individual GetReleaseContent requests.
+	Releases             []*GetReleaseContentRequest `protobuf:"bytes,1,rep,name=releases,proto3" json:"releases,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                    `json:"-"`
+	XXX_unrecognized     []byte                      `json:"-"`
+	XXX_sizecache        int32                       `json:"-"`
+}
+
+func (m *GetReleaseContentsRequest) Reset()         { *m = GetReleaseContentsRequest{} }
+func (m *GetReleaseContentsRequest) String() string { return proto.CompactTextString(m) }
+func (*GetReleaseContentsRequest) ProtoMessage()    {}
+func (*GetReleaseContentsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_7a0eb69344511777, []int{31}
+}
+func (m *GetReleaseContentsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetReleaseContentsRequest.Unmarshal(m, b)
+}
+func (m *GetReleaseContentsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetReleaseContentsRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetReleaseContentsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetReleaseContentsRequest.Merge(dst, src)
+}
+func (m *GetReleaseContentsRequest) XXX_Size() int {
+	return xxx_messageInfo_GetReleaseContentsRequest.Size(m)
+}
+func (m *GetReleaseContentsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetReleaseContentsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetReleaseContentsRequest proto.InternalMessageInfo
+
+func (m *GetReleaseContentsRequest) GetReleases() []*GetReleaseContentRequest {
+	if m != nil {
+		return m.Releases
+	}
+	return nil
+}
+
+// GetReleaseContentsResponse is received in response to a GetReleaseContents rpc.
+type GetReleaseContentsResponse struct {
+	// Results are in the order the releases were requested in.
+	Results              []*ReleaseContentResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
+}
+
+func (m *GetReleaseContentsResponse) Reset()         { *m = GetReleaseContentsResponse{} }
+func (m *GetReleaseContentsResponse) String() string { return proto.CompactTextString(m) }
+func (*GetReleaseContentsResponse) ProtoMessage()    {}
+func (*GetReleaseContentsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_7a0eb69344511777, []int{32}
+}
+func (m *GetReleaseContentsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetReleaseContentsResponse.Unmarshal(m, b)
+}
+func (m *GetReleaseContentsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetReleaseContentsResponse.Marshal(b, m, deterministic)
+}
+func (dst *GetReleaseContentsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetReleaseContentsResponse.Merge(dst, src)
+}
+func (m *GetReleaseContentsResponse) XXX_Size() int {
+	return xxx_messageInfo_GetReleaseContentsResponse.Size(m)
+}
+func (m *GetReleaseContentsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetReleaseContentsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetReleaseContentsResponse proto.InternalMessageInfo
+
+func (m *GetReleaseContentsResponse) GetResults() []*ReleaseContentResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+// ReleaseContentResult is the outcome of getting the content of one release.
+type ReleaseContentResult struct {
+	// The release content, if it could be retrieved.
+	Release *release.Release `protobuf:"bytes,1,opt,name=release,proto3" json:"release,omitempty"`
+	// Code is the gRPC status code of the failure, 0 meaning success.
+	Code int32 `protobuf:"varint,2,opt,name=code,proto3" json:"code,omitempty"`
+	// Error describes why the release could not be retrieved.
+	Error                string   `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReleaseContentResult) Reset()         { *m = ReleaseContentResult{} }
+func (m *ReleaseContentResult) String() string { return proto.CompactTextString(m) }
+func (*ReleaseContentResult) ProtoMessage()    {}
+func (*ReleaseContentResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_tiller_7a0eb69344511777, []int{33}
+}
+func (m *ReleaseContentResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReleaseContentResult.Unmarshal(m, b)
+}
+func (m *ReleaseContentResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReleaseContentResult.Marshal(b, m, deterministic)
+}
+func (dst *ReleaseContentResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReleaseContentResult.Merge(dst, src)
+}
+func (m *ReleaseContentResult) XXX_Size() int {
+	return xxx_messageInfo_ReleaseContentResult.Size(m)
+}
+func (m *ReleaseContentResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReleaseContentResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReleaseContentResult proto.InternalMessageInfo
+
+func (m *ReleaseContentResult) GetRelease() *release.Release {
+	if m != nil {
+		return m.Release
+	}
+	return nil
+}
+
+func (m *ReleaseContentResult) GetCode() int32 {
+	if m != nil {
+		return m.Code
+	}
+	return 0
+}
+
+func (m *ReleaseContentResult) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*ListReleasesRequest)(nil), "hapi.services.tiller.ListReleasesRequest")
 	proto.RegisterType((*ListSort)(nil), "hapi.services.tiller.ListSort")
 	proto.RegisterType((*ListReleasesResponse)(nil), "hapi.services.tiller.ListReleasesResponse")
 	proto.RegisterType((*GetReleaseStatusRequest)(nil), "hapi.services.tiller.GetReleaseStatusRequest")
 	proto.RegisterType((*GetReleaseStatusResponse)(nil), "hapi.services.tiller.GetReleaseStatusResponse")
+	proto.RegisterMapType((map[string]string)(nil), "hapi.services.tiller.GetReleaseStatusResponse.RecordAnnotationsEntry")
 	proto.RegisterType((*GetReleaseContentRequest)(nil), "hapi.services.tiller.GetReleaseContentRequest")
 	proto.RegisterType((*GetReleaseContentResponse)(nil), "hapi.services.tiller.GetReleaseContentResponse")
+	proto.RegisterType((*ManifestDocument)(nil), "hapi.services.tiller.ManifestDocument")
 	proto.RegisterType((*UpdateReleaseRequest)(nil), "hapi.services.tiller.UpdateReleaseRequest")
 	proto.RegisterType((*UpdateReleaseResponse)(nil), "hapi.services.tiller.UpdateReleaseResponse")
 	proto.RegisterType((*RollbackReleaseRequest)(nil), "hapi.services.tiller.RollbackReleaseRequest")
 	proto.RegisterType((*RollbackReleaseResponse)(nil), "hapi.services.tiller.RollbackReleaseResponse")
 	proto.RegisterType((*InstallReleaseRequest)(nil), "hapi.services.tiller.InstallReleaseRequest")
+	proto.RegisterType((*ChartReference)(nil), "hapi.services.tiller.ChartReference")
 	proto.RegisterType((*InstallReleaseResponse)(nil), "hapi.services.tiller.InstallReleaseResponse")
 	proto.RegisterType((*UninstallReleaseRequest)(nil), "hapi.services.tiller.UninstallReleaseRequest")
 	proto.RegisterType((*UninstallReleaseResponse)(nil), "hapi.services.tiller.UninstallReleaseResponse")
@@ -1453,6 +2221,17 @@ func init() {
 	proto.RegisterType((*GetHistoryResponse)(nil), "hapi.services.tiller.GetHistoryResponse")
 	proto.RegisterType((*TestReleaseRequest)(nil), "hapi.services.tiller.TestReleaseRequest")
 	proto.RegisterType((*TestReleaseResponse)(nil), "hapi.services.tiller.TestReleaseResponse")
+	proto.RegisterType((*GetReleaseAuditRequest)(nil), "hapi.services.tiller.GetReleaseAuditRequest")
+	proto.RegisterType((*GetReleaseAuditResponse)(nil), "hapi.services.tiller.GetReleaseAuditResponse")
+	proto.RegisterType((*ReencryptReleasesRequest)(nil), "hapi.services.tiller.ReencryptReleasesRequest")
+	proto.RegisterType((*ReencryptReleasesResponse)(nil), "hapi.services.tiller.ReencryptReleasesResponse")
+	proto.RegisterType((*ApproveReleaseRequest)(nil), "hapi.services.tiller.ApproveReleaseRequest")
+	proto.RegisterType((*ApproveReleaseResponse)(nil), "hapi.services.tiller.ApproveReleaseResponse")
+	proto.RegisterType((*GetReleaseHooksRequest)(nil), "hapi.services.tiller.GetReleaseHooksRequest")
+	proto.RegisterType((*GetReleaseHooksResponse)(nil), "hapi.services.tiller.GetReleaseHooksResponse")
+	proto.RegisterType((*GetReleaseContentsRequest)(nil), "hapi.services.tiller.GetReleaseContentsRequest")
+	proto.RegisterType((*GetReleaseContentsResponse)(nil), "hapi.services.tiller.GetReleaseContentsResponse")
+	proto.RegisterType((*ReleaseContentResult)(nil), "hapi.services.tiller.ReleaseContentResult")
 	proto.RegisterEnum("hapi.services.tiller.ListSort_SortBy", ListSort_SortBy_name, ListSort_SortBy_value)
 	proto.RegisterEnum("hapi.services.tiller.ListSort_SortOrder", ListSort_SortOrder_name, ListSort_SortOrder_value)
 }
@@ -1492,6 +2271,16 @@ type ReleaseServiceClient interface {
 	GetHistory(ctx context.Context, in *GetHistoryRequest, opts ...grpc.CallOption) (*GetHistoryResponse, error)
 	// RunReleaseTest executes the tests defined of a named release
 	RunReleaseTest(ctx context.Context, in *TestReleaseRequest, opts ...grpc.CallOption) (ReleaseService_RunReleaseTestClient, error)
+	// ReencryptReleases rewrites stored releases under the primary storage encryption key.
+	ReencryptReleases(ctx context.Context, in *ReencryptReleasesRequest, opts ...grpc.CallOption) (*ReencryptReleasesResponse, error)
+	// GetReleaseAudit retrieves the audit trail of a release across its history.
+	GetReleaseAudit(ctx context.Context, in *GetReleaseAuditRequest, opts ...grpc.CallOption) (*GetReleaseAuditResponse, error)
+	// ApproveRelease resumes an upgrade that is awaiting approval to run its post-upgrade hooks.
+	ApproveRelease(ctx context.Context, in *ApproveReleaseRequest, opts ...grpc.CallOption) (*ApproveReleaseResponse, error)
+	// GetReleaseHooks retrieves the hooks of a release, with their events, weights and delete policies.
+	GetReleaseHooks(ctx context.Context, in *GetReleaseHooksRequest, opts ...grpc.CallOption) (*GetReleaseHooksResponse, error)
+	// GetReleaseContents retrieves the content of several releases at once, reporting failures per release.
+	GetReleaseContents(ctx context.Context, in *GetReleaseContentsRequest, opts ...grpc.CallOption) (*GetReleaseContentsResponse, error)
 }
 
 type releaseServiceClient struct {
@@ -1638,6 +2427,51 @@ func (x *releaseServiceRunReleaseTestClient) Recv() (*TestReleaseResponse, error
 	return m, nil
 }
 
+func (c *releaseServiceClient) ReencryptReleases(ctx context.Context, in *ReencryptReleasesRequest, opts ...grpc.CallOption) (*ReencryptReleasesResponse, error) {
+	out := new(ReencryptReleasesResponse)
+	err := c.cc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/ReencryptReleases", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) GetReleaseAudit(ctx context.Context, in *GetReleaseAuditRequest, opts ...grpc.CallOption) (*GetReleaseAuditResponse, error) {
+	out := new(GetReleaseAuditResponse)
+	err := c.cc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/GetReleaseAudit", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) ApproveRelease(ctx context.Context, in *ApproveReleaseRequest, opts ...grpc.CallOption) (*ApproveReleaseResponse, error) {
+	out := new(ApproveReleaseResponse)
+	err := c.cc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/ApproveRelease", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) GetReleaseHooks(ctx context.Context, in *GetReleaseHooksRequest, opts ...grpc.CallOption) (*GetReleaseHooksResponse, error) {
+	out := new(GetReleaseHooksResponse)
+	err := c.cc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/GetReleaseHooks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) GetReleaseContents(ctx context.Context, in *GetReleaseContentsRequest, opts ...grpc.CallOption) (*GetReleaseContentsResponse, error) {
+	out := new(GetReleaseContentsResponse)
+	err := c.cc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/GetReleaseContents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ReleaseServiceServer is the server API for ReleaseService service.
 type ReleaseServiceServer interface {
 	// ListReleases retrieves release history.
@@ -1663,6 +2497,16 @@ type ReleaseServiceServer interface {
 	GetHistory(context.Context, *GetHistoryRequest) (*GetHistoryResponse, error)
 	// RunReleaseTest executes the tests defined of a named release
 	RunReleaseTest(*TestReleaseRequest, ReleaseService_RunReleaseTestServer) error
+	// ReencryptReleases rewrites stored releases under the primary storage encryption key.
+	ReencryptReleases(context.Context, *ReencryptReleasesRequest) (*ReencryptReleasesResponse, error)
+	// GetReleaseAudit retrieves the audit trail of a release across its history.
+	GetReleaseAudit(context.Context, *GetReleaseAuditRequest) (*GetReleaseAuditResponse, error)
+	// ApproveRelease resumes an upgrade that is awaiting approval to run its post-upgrade hooks.
+	ApproveRelease(context.Context, *ApproveReleaseRequest) (*ApproveReleaseResponse, error)
+	// GetReleaseHooks retrieves the hooks of a release, with their events, weights and delete policies.
+	GetReleaseHooks(context.Context, *GetReleaseHooksRequest) (*GetReleaseHooksResponse, error)
+	// GetReleaseContents retrieves the content of several releases at once, reporting failures per release.
+	GetReleaseContents(context.Context, *GetReleaseContentsRequest) (*GetReleaseContentsResponse, error)
 }
 
 func RegisterReleaseServiceServer(s *grpc.Server, srv ReleaseServiceServer) {
@@ -1855,6 +2699,96 @@ func (x *releaseServiceRunReleaseTestServer) Send(m *TestReleaseResponse) error
 	return x.ServerStream.SendMsg(m)
 }
 
+func _ReleaseService_ReencryptReleases_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReencryptReleasesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).ReencryptReleases(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hapi.services.tiller.ReleaseService/ReencryptReleases",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).ReencryptReleases(ctx, req.(*ReencryptReleasesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReleaseService_GetReleaseAudit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReleaseAuditRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).GetReleaseAudit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hapi.services.tiller.ReleaseService/GetReleaseAudit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).GetReleaseAudit(ctx, req.(*GetReleaseAuditRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReleaseService_ApproveRelease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApproveReleaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).ApproveRelease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hapi.services.tiller.ReleaseService/ApproveRelease",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).ApproveRelease(ctx, req.(*ApproveReleaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReleaseService_GetReleaseHooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReleaseHooksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).GetReleaseHooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hapi.services.tiller.ReleaseService/GetReleaseHooks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).GetReleaseHooks(ctx, req.(*GetReleaseHooksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReleaseService_GetReleaseContents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReleaseContentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).GetReleaseContents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hapi.services.tiller.ReleaseService/GetReleaseContents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).GetReleaseContents(ctx, req.(*GetReleaseContentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _ReleaseService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "hapi.services.tiller.ReleaseService",
 	HandlerType: (*ReleaseServiceServer)(nil),
@@ -1891,6 +2825,26 @@ var _ReleaseService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetHistory",
 			Handler:    _ReleaseService_GetHistory_Handler,
 		},
+		{
+			MethodName: "ReencryptReleases",
+			Handler:    _ReleaseService_ReencryptReleases_Handler,
+		},
+		{
+			MethodName: "GetReleaseAudit",
+			Handler:    _ReleaseService_GetReleaseAudit_Handler,
+		},
+		{
+			MethodName: "ApproveRelease",
+			Handler:    _ReleaseService_ApproveRelease_Handler,
+		},
+		{
+			MethodName: "GetReleaseHooks",
+			Handler:    _ReleaseService_GetReleaseHooks_Handler,
+		},
+		{
+			MethodName: "GetReleaseContents",
+			Handler:    _ReleaseService_GetReleaseContents_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -1907,94 +2861,136 @@ var _ReleaseService_serviceDesc = grpc.ServiceDesc{
 	Metadata: "hapi/services/tiller.proto",
 }
 
-func init() { proto.RegisterFile("hapi/services/tiller.proto", fileDescriptor_tiller_96f0f3bb9e4ff674) }
-
-var fileDescriptor_tiller_96f0f3bb9e4ff674 = []byte{
-	// 1369 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x58, 0xdd, 0x72, 0x1b, 0xc5,
-	0x12, 0xb6, 0xb4, 0xfa, 0x6d, 0xd9, 0x8a, 0x32, 0x71, 0xec, 0x8d, 0x4e, 0xce, 0x29, 0x9f, 0xa5,
-	0x48, 0x94, 0x40, 0x64, 0x30, 0xdc, 0x50, 0x45, 0x51, 0xe5, 0x28, 0xc6, 0x0e, 0x18, 0x87, 0x5a,
-	0x27, 0xa1, 0x8a, 0x2a, 0x4a, 0x35, 0x96, 0x46, 0xce, 0x92, 0xd5, 0x8e, 0xd8, 0x99, 0x35, 0xf6,
-	0x23, 0x70, 0xc9, 0x3b, 0x70, 0x0d, 0xaf, 0xc0, 0x2d, 0xcf, 0xc0, 0xcb, 0x50, 0xf3, 0xb7, 0xd6,
-	0xac, 0x76, 0x15, 0xe1, 0x1b, 0xed, 0xcc, 0x74, 0x4f, 0x77, 0xcf, 0xf7, 0x4d, 0xf7, 0xb4, 0x0d,
-	0xdd, 0x37, 0x78, 0x16, 0xec, 0x32, 0x12, 0x5f, 0x04, 0x23, 0xc2, 0x76, 0x79, 0x10, 0x86, 0x24,
-	0xee, 0xcf, 0x62, 0xca, 0x29, 0xda, 0x14, 0xb2, 0xbe, 0x91, 0xf5, 0x95, 0xac, 0xbb, 0x25, 0x77,
-	0x8c, 0xde, 0xe0, 0x98, 0xab, 0x5f, 0xa5, 0xdd, 0xdd, 0x9e, 0x5f, 0xa7, 0xd1, 0x24, 0x38, 0xd7,
-	0x02, 0xe5, 0x22, 0x26, 0x21, 0xc1, 0x8c, 0x98, 0xaf, 0xb5, 0xc9, 0xc8, 0x82, 0x68, 0x42, 0xb5,
-	0xe0, 0x3f, 0x96, 0x80, 0x13, 0xc6, 0x87, 0x71, 0x12, 0x69, 0xe1, 0x3d, 0x4b, 0xc8, 0x38, 0xe6,
-	0x09, 0xb3, 0x9c, 0x5d, 0x90, 0x98, 0x05, 0x34, 0x32, 0x5f, 0x25, 0xf3, 0xfe, 0x2c, 0xc3, 0x9d,
-	0xe3, 0x80, 0x71, 0x5f, 0x6d, 0x64, 0x3e, 0xf9, 0x29, 0x21, 0x8c, 0xa3, 0x4d, 0xa8, 0x86, 0xc1,
-	0x34, 0xe0, 0x6e, 0x69, 0xa7, 0xd4, 0x73, 0x7c, 0x35, 0x41, 0x5b, 0x50, 0xa3, 0x93, 0x09, 0x23,
-	0xdc, 0x2d, 0xef, 0x94, 0x7a, 0x4d, 0x5f, 0xcf, 0xd0, 0x17, 0x50, 0x67, 0x34, 0xe6, 0xc3, 0xb3,
-	0x2b, 0xd7, 0xd9, 0x29, 0xf5, 0xda, 0x7b, 0xef, 0xf7, 0xf3, 0x70, 0xea, 0x0b, 0x4f, 0xa7, 0x34,
-	0xe6, 0x7d, 0xf1, 0xf3, 0xf4, 0xca, 0xaf, 0x31, 0xf9, 0x15, 0x76, 0x27, 0x41, 0xc8, 0x49, 0xec,
-	0x56, 0x94, 0x5d, 0x35, 0x43, 0x87, 0x00, 0xd2, 0x2e, 0x8d, 0xc7, 0x24, 0x76, 0xab, 0xd2, 0x74,
-	0x6f, 0x05, 0xd3, 0x2f, 0x84, 0xbe, 0xdf, 0x64, 0x66, 0x88, 0x3e, 0x87, 0x75, 0x05, 0xc9, 0x70,
-	0x44, 0xc7, 0x84, 0xb9, 0xb5, 0x1d, 0xa7, 0xd7, 0xde, 0xbb, 0xa7, 0x4c, 0x19, 0xf8, 0x4f, 0x15,
-	0x68, 0x03, 0x3a, 0x26, 0x7e, 0x4b, 0xa9, 0x8b, 0x31, 0x43, 0xf7, 0xa1, 0x19, 0xe1, 0x29, 0x61,
-	0x33, 0x3c, 0x22, 0x6e, 0x5d, 0x46, 0x78, 0xbd, 0xe0, 0x45, 0xd0, 0x30, 0xce, 0xbd, 0xa7, 0x50,
-	0x53, 0x47, 0x43, 0x2d, 0xa8, 0xbf, 0x3a, 0xf9, 0xfa, 0xe4, 0xc5, 0x77, 0x27, 0x9d, 0x35, 0xd4,
-	0x80, 0xca, 0xc9, 0xfe, 0x37, 0x07, 0x9d, 0x12, 0xba, 0x0d, 0x1b, 0xc7, 0xfb, 0xa7, 0x2f, 0x87,
-	0xfe, 0xc1, 0xf1, 0xc1, 0xfe, 0xe9, 0xc1, 0xb3, 0x4e, 0x19, 0xb5, 0x01, 0x06, 0x47, 0xfb, 0xfe,
-	0xcb, 0xa1, 0x54, 0x71, 0xbc, 0xff, 0x41, 0x33, 0x3d, 0x03, 0xaa, 0x83, 0xb3, 0x7f, 0x3a, 0x50,
-	0x26, 0x9e, 0x1d, 0x9c, 0x0e, 0x3a, 0x25, 0xef, 0x97, 0x12, 0x6c, 0xda, 0x94, 0xb1, 0x19, 0x8d,
-	0x18, 0x11, 0x9c, 0x8d, 0x68, 0x12, 0xa5, 0x9c, 0xc9, 0x09, 0x42, 0x50, 0x89, 0xc8, 0xa5, 0x61,
-	0x4c, 0x8e, 0x85, 0x26, 0xa7, 0x1c, 0x87, 0x92, 0x2d, 0xc7, 0x57, 0x13, 0xf4, 0x31, 0x34, 0x34,
-	0x14, 0xcc, 0xad, 0xec, 0x38, 0xbd, 0xd6, 0xde, 0x5d, 0x1b, 0x20, 0xed, 0xd1, 0x4f, 0xd5, 0xbc,
-	0x43, 0xd8, 0x3e, 0x24, 0x26, 0x12, 0x85, 0x9f, 0xb9, 0x41, 0xc2, 0x2f, 0x9e, 0x12, 0x19, 0x8c,
-	0xf0, 0x8b, 0xa7, 0x04, 0xb9, 0x50, 0xd7, 0xd7, 0x4f, 0x86, 0x53, 0xf5, 0xcd, 0xd4, 0xe3, 0xe0,
-	0x2e, 0x1a, 0xd2, 0xe7, 0xca, 0xb3, 0xf4, 0x00, 0x2a, 0x22, 0x33, 0xa4, 0x99, 0xd6, 0x1e, 0xb2,
-	0xe3, 0x7c, 0x1e, 0x4d, 0xa8, 0x2f, 0xe5, 0x36, 0x75, 0x4e, 0x96, 0xba, 0xa3, 0x79, 0xaf, 0x03,
-	0x1a, 0x71, 0x12, 0xf1, 0x9b, 0xc5, 0x7f, 0x0c, 0xf7, 0x72, 0x2c, 0xe9, 0x03, 0xec, 0x42, 0x5d,
-	0x87, 0x26, 0xad, 0x15, 0xe2, 0x6a, 0xb4, 0xbc, 0xbf, 0x1c, 0xd8, 0x7c, 0x35, 0x1b, 0x63, 0x4e,
-	0x8c, 0x68, 0x49, 0x50, 0x0f, 0xa1, 0x2a, 0x2b, 0x8c, 0xc6, 0xe2, 0xb6, 0xb2, 0xad, 0xca, 0xd0,
-	0x40, 0xfc, 0xfa, 0x4a, 0x8e, 0x1e, 0x43, 0xed, 0x02, 0x87, 0x09, 0x61, 0x12, 0x88, 0x14, 0x35,
-	0xad, 0x29, 0xcb, 0x93, 0xaf, 0x35, 0xd0, 0x36, 0xd4, 0xc7, 0xf1, 0x95, 0xa8, 0x2f, 0x32, 0x25,
-	0x1b, 0x7e, 0x6d, 0x1c, 0x5f, 0xf9, 0x49, 0x84, 0xde, 0x83, 0x8d, 0x71, 0xc0, 0xf0, 0x59, 0x48,
-	0x86, 0x6f, 0x28, 0x7d, 0xcb, 0x64, 0x56, 0x36, 0xfc, 0x75, 0xbd, 0x78, 0x24, 0xd6, 0x50, 0x57,
-	0xdc, 0xa4, 0x51, 0x4c, 0x30, 0x27, 0x6e, 0x4d, 0xca, 0xd3, 0xb9, 0xc0, 0x90, 0x07, 0x53, 0x42,
-	0x13, 0x2e, 0x53, 0xc9, 0xf1, 0xcd, 0x14, 0xfd, 0x1f, 0xd6, 0x63, 0xc2, 0x08, 0x1f, 0xea, 0x28,
-	0x1b, 0x72, 0x67, 0x4b, 0xae, 0xbd, 0x56, 0x61, 0x21, 0xa8, 0xfc, 0x8c, 0x03, 0xee, 0x36, 0xa5,
-	0x48, 0x8e, 0xd5, 0xb6, 0x84, 0x11, 0xb3, 0x0d, 0xcc, 0xb6, 0x84, 0x11, 0xbd, 0x6d, 0x13, 0xaa,
-	0x13, 0x1a, 0x8f, 0x88, 0xdb, 0x92, 0x32, 0x35, 0x41, 0x3b, 0xd0, 0x1a, 0x13, 0x36, 0x8a, 0x83,
-	0x19, 0x17, 0x8c, 0xae, 0x4b, 0x4c, 0xe7, 0x97, 0xc4, 0x39, 0x58, 0x72, 0x76, 0x42, 0x39, 0x61,
-	0xee, 0x86, 0x3a, 0x87, 0x99, 0xa3, 0x07, 0x70, 0x6b, 0x14, 0x12, 0x1c, 0x25, 0xb3, 0x21, 0x8d,
-	0x86, 0x13, 0x1c, 0x84, 0x6e, 0x5b, 0xaa, 0x6c, 0xe8, 0xe5, 0x17, 0xd1, 0x97, 0x38, 0x08, 0xbd,
-	0x23, 0xb8, 0x9b, 0xa1, 0xf2, 0xa6, 0xb7, 0xe2, 0xf7, 0x32, 0x6c, 0xf9, 0x34, 0x0c, 0xcf, 0xf0,
-	0xe8, 0xed, 0x0a, 0xf7, 0x62, 0x8e, 0xc2, 0xf2, 0x72, 0x0a, 0x9d, 0x1c, 0x0a, 0xe7, 0xae, 0x7a,
-	0xc5, 0xba, 0xea, 0x16, 0xb9, 0xd5, 0x62, 0x72, 0x6b, 0x36, 0xb9, 0x86, 0xb9, 0xfa, 0x1c, 0x73,
-	0x29, 0x2d, 0x8d, 0x25, 0xb4, 0x34, 0x17, 0x69, 0xc9, 0x81, 0x1e, 0xf2, 0xa0, 0xff, 0x0a, 0xb6,
-	0x17, 0xf0, 0xba, 0x29, 0xf8, 0xbf, 0x3a, 0x70, 0xf7, 0x79, 0xc4, 0x38, 0x0e, 0xc3, 0x0c, 0xf6,
-	0x69, 0xfe, 0x95, 0x56, 0xce, 0xbf, 0xf2, 0xbf, 0xc9, 0x3f, 0xc7, 0x22, 0xcf, 0x30, 0x5d, 0x99,
-	0x63, 0x7a, 0xa5, 0x9c, 0xb4, 0x2a, 0x61, 0x2d, 0x53, 0x09, 0xd1, 0x7f, 0x01, 0x54, 0x12, 0x49,
-	0xe3, 0x8a, 0xa4, 0xa6, 0x5c, 0x39, 0xd1, 0x85, 0xcf, 0xf0, 0xda, 0xc8, 0xe7, 0x75, 0x3e, 0x23,
-	0x7b, 0xd0, 0x31, 0xf1, 0x8c, 0xe2, 0xb1, 0x8c, 0x49, 0x13, 0xd4, 0xd6, 0xeb, 0x83, 0x78, 0x2c,
-	0xa2, 0xca, 0x72, 0xdd, 0x5a, 0x9e, 0x82, 0xeb, 0x76, 0x0a, 0x7a, 0xcf, 0x61, 0x2b, 0x4b, 0xc9,
-	0x4d, 0xe9, 0xfd, 0xad, 0x04, 0xdb, 0xaf, 0xa2, 0x20, 0x97, 0xe0, 0xbc, 0xe4, 0x5a, 0x80, 0xbc,
-	0x9c, 0x03, 0xf9, 0x26, 0x54, 0x67, 0x49, 0x7c, 0x4e, 0x34, 0x85, 0x6a, 0x32, 0x8f, 0x65, 0xc5,
-	0xc6, 0x32, 0x83, 0x46, 0x75, 0x01, 0x0d, 0x6f, 0x08, 0xee, 0x62, 0x94, 0x37, 0x3c, 0xb3, 0x38,
-	0x57, 0xfa, 0x86, 0x36, 0xd5, 0x7b, 0xe9, 0xdd, 0x81, 0xdb, 0x87, 0x84, 0xbf, 0x56, 0xa9, 0xae,
-	0x01, 0xf0, 0x0e, 0x00, 0xcd, 0x2f, 0x5e, 0xfb, 0xd3, 0x4b, 0xb6, 0x3f, 0xd3, 0x60, 0x1a, 0x7d,
-	0xa3, 0xe5, 0x7d, 0x26, 0x6d, 0x1f, 0x05, 0x8c, 0xd3, 0xf8, 0x6a, 0x19, 0xb8, 0x1d, 0x70, 0xa6,
-	0xf8, 0x52, 0x3f, 0xb1, 0x62, 0xe8, 0x1d, 0xca, 0x08, 0xd2, 0xad, 0x3a, 0x82, 0xf9, 0x86, 0xa5,
-	0xb4, 0x5a, 0xc3, 0xf2, 0x47, 0x09, 0xd0, 0x4b, 0x92, 0x36, 0x4f, 0xef, 0x78, 0xec, 0x0d, 0x4f,
-	0x65, 0x9b, 0x27, 0x17, 0xea, 0xba, 0xd0, 0x68, 0x66, 0xcd, 0x54, 0xdc, 0xd6, 0x19, 0x8e, 0x71,
-	0x18, 0x92, 0x50, 0xbf, 0x9b, 0xe9, 0x5c, 0xbc, 0x53, 0x53, 0x7c, 0x39, 0x4c, 0xe5, 0x82, 0xde,
-	0x0d, 0xbf, 0x35, 0xc5, 0x97, 0xdf, 0x1a, 0x15, 0x04, 0x95, 0x90, 0x9e, 0x33, 0xfd, 0x66, 0xca,
-	0xb1, 0xf7, 0x03, 0xdc, 0xb1, 0x02, 0xd6, 0x67, 0x17, 0x18, 0xb1, 0x73, 0x1d, 0xb0, 0x18, 0xa2,
-	0x4f, 0xa1, 0xa6, 0x9a, 0x56, 0x19, 0x6e, 0x7b, 0xef, 0xbe, 0x8d, 0x85, 0x34, 0x92, 0x44, 0xba,
-	0xcb, 0xf5, 0xb5, 0xee, 0xde, 0xdf, 0x0d, 0x68, 0x9b, 0xb6, 0x4b, 0xb5, 0xd4, 0x28, 0x80, 0xf5,
-	0xf9, 0xfe, 0x12, 0x3d, 0x2a, 0xee, 0xb8, 0x33, 0x7f, 0x36, 0x74, 0x1f, 0xaf, 0xa2, 0xaa, 0x4e,
-	0xe0, 0xad, 0x7d, 0x54, 0x42, 0x0c, 0x3a, 0xd9, 0xb6, 0x0f, 0x3d, 0xc9, 0xb7, 0x51, 0xd0, 0x67,
-	0x76, 0xfb, 0xab, 0xaa, 0x1b, 0xb7, 0xe8, 0x42, 0xde, 0x43, 0xbb, 0x57, 0x43, 0xef, 0x34, 0x63,
-	0xb7, 0x87, 0xdd, 0xdd, 0x95, 0xf5, 0x53, 0xbf, 0x3f, 0xc2, 0x86, 0xd5, 0x09, 0xa0, 0x02, 0xb4,
-	0xf2, 0x3a, 0xbf, 0xee, 0x07, 0x2b, 0xe9, 0xa6, 0xbe, 0xa6, 0xd0, 0xb6, 0x4b, 0x23, 0x2a, 0x30,
-	0x90, 0xfb, 0xa6, 0x75, 0x3f, 0x5c, 0x4d, 0x39, 0x75, 0xc7, 0xa0, 0x93, 0xad, 0x4b, 0x45, 0x3c,
-	0x16, 0x54, 0xd9, 0x22, 0x1e, 0x8b, 0xca, 0x9d, 0xb7, 0x86, 0x30, 0xc0, 0x75, 0x59, 0x42, 0x0f,
-	0x0b, 0x09, 0xb1, 0xab, 0x59, 0xb7, 0xf7, 0x6e, 0xc5, 0xd4, 0xc5, 0x0c, 0x6e, 0x65, 0x3a, 0x08,
-	0x54, 0x00, 0x4d, 0x7e, 0x63, 0xd6, 0x7d, 0xb2, 0xa2, 0x76, 0xe6, 0x50, 0xba, 0xd2, 0x2d, 0x39,
-	0x94, 0x5d, 0x46, 0x97, 0x1c, 0x2a, 0x53, 0x34, 0xbd, 0x35, 0x14, 0x40, 0xdb, 0x4f, 0x22, 0xed,
-	0x5a, 0x94, 0x05, 0x54, 0xb0, 0x7b, 0xb1, 0x50, 0x76, 0x1f, 0xad, 0xa0, 0x79, 0x9d, 0xdf, 0x4f,
-	0xe1, 0xfb, 0x86, 0x51, 0x3d, 0xab, 0xc9, 0xff, 0x38, 0x7c, 0xf2, 0x4f, 0x00, 0x00, 0x00, 0xff,
-	0xff, 0x8a, 0x23, 0x76, 0x0d, 0x5f, 0x11, 0x00, 0x00,
+func init() { proto.RegisterFile("hapi/services/tiller.proto", fileDescriptor_tiller_7a0eb69344511777) }
+
+var fileDescriptor_tiller_7a0eb69344511777 = []byte{
+	// 2034 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x59, 0xdd, 0x72, 0xdc, 0x4a,
+	0xf1, 0x8f, 0x56, 0xfb, 0xd9, 0xfb, 0x91, 0xf5, 0xc4, 0xb1, 0x65, 0xfd, 0xcf, 0x1f, 0x8c, 0x0e,
+	0xc9, 0xd9, 0x13, 0x92, 0xf5, 0xc1, 0x70, 0x41, 0xf1, 0x55, 0xe5, 0xd8, 0x3e, 0x4e, 0x0e, 0x89,
+	0x43, 0xc9, 0x49, 0x4e, 0x41, 0x15, 0xb5, 0x25, 0x6b, 0x67, 0x1d, 0x61, 0xad, 0xb4, 0x67, 0x66,
+	0x64, 0xec, 0x7b, 0xa0, 0x8a, 0x5b, 0xe0, 0x05, 0xa8, 0xe2, 0x1e, 0x1e, 0x80, 0x97, 0xe0, 0x19,
+	0x78, 0x11, 0x6a, 0xbe, 0x64, 0x49, 0xab, 0xb5, 0x15, 0xdf, 0x70, 0xe3, 0x9d, 0xe9, 0xee, 0x99,
+	0xee, 0xe9, 0x5f, 0x77, 0x4f, 0x8f, 0x0c, 0xf6, 0x07, 0x6f, 0x11, 0xec, 0x50, 0x4c, 0x2e, 0x02,
+	0x1f, 0xd3, 0x1d, 0x16, 0x84, 0x21, 0x26, 0xe3, 0x05, 0x89, 0x59, 0x8c, 0xd6, 0x39, 0x6f, 0xac,
+	0x79, 0x63, 0xc9, 0xb3, 0x37, 0xc4, 0x0a, 0xff, 0x83, 0x47, 0x98, 0xfc, 0x2b, 0xa5, 0xed, 0xcd,
+	0x2c, 0x3d, 0x8e, 0x66, 0xc1, 0x99, 0x62, 0x58, 0x82, 0x41, 0x70, 0x88, 0x3d, 0x8a, 0x77, 0xbc,
+	0x64, 0x1a, 0xe4, 0x97, 0x68, 0xce, 0x87, 0x38, 0x3e, 0x57, 0x0c, 0x3b, 0xc7, 0x50, 0xbf, 0xa5,
+	0x8b, 0x82, 0x68, 0x16, 0x2b, 0xc6, 0xff, 0xe5, 0x18, 0x0c, 0x53, 0x36, 0x21, 0x49, 0xa4, 0x98,
+	0x5b, 0x39, 0x26, 0x65, 0x1e, 0x4b, 0x68, 0x4e, 0xd9, 0x05, 0x26, 0x34, 0x88, 0x23, 0xfd, 0x2b,
+	0x79, 0xce, 0xef, 0x4d, 0x78, 0xf0, 0x2a, 0xa0, 0xcc, 0x95, 0x0b, 0xa9, 0x8b, 0xbf, 0x49, 0x30,
+	0x65, 0x68, 0x1d, 0x1a, 0x61, 0x30, 0x0f, 0x98, 0x65, 0x6c, 0x1b, 0x23, 0xd3, 0x95, 0x13, 0xb4,
+	0x01, 0xcd, 0x78, 0x36, 0xa3, 0x98, 0x59, 0xb5, 0x6d, 0x63, 0xd4, 0x71, 0xd5, 0x0c, 0xfd, 0x1c,
+	0x5a, 0x34, 0x26, 0x6c, 0x72, 0x7a, 0x65, 0x99, 0xdb, 0xc6, 0x68, 0xb0, 0xfb, 0x68, 0x5c, 0xe6,
+	0xda, 0x31, 0xd7, 0x74, 0x12, 0x13, 0x36, 0xe6, 0x7f, 0x9e, 0x5f, 0xb9, 0x4d, 0x2a, 0x7e, 0xf9,
+	0xbe, 0xb3, 0x20, 0x64, 0x98, 0x58, 0x75, 0xb9, 0xaf, 0x9c, 0xa1, 0x23, 0x00, 0xb1, 0x6f, 0x4c,
+	0xa6, 0x98, 0x58, 0x0d, 0xb1, 0xf5, 0xa8, 0xc2, 0xd6, 0x6f, 0xb8, 0xbc, 0xdb, 0xa1, 0x7a, 0x88,
+	0x7e, 0x0a, 0x3d, 0xe9, 0x92, 0x89, 0x1f, 0x4f, 0x31, 0xb5, 0x9a, 0xdb, 0xe6, 0x68, 0xb0, 0xbb,
+	0x25, 0xb7, 0xd2, 0xee, 0x3f, 0x91, 0x4e, 0xdb, 0x8f, 0xa7, 0xd8, 0xed, 0x4a, 0x71, 0x3e, 0xa6,
+	0xe8, 0x13, 0xe8, 0x44, 0xde, 0x1c, 0xd3, 0x85, 0xe7, 0x63, 0xab, 0x25, 0x2c, 0xbc, 0x26, 0xa0,
+	0xff, 0x07, 0x10, 0x41, 0x31, 0xe1, 0x24, 0xab, 0x2d, 0xd9, 0x82, 0x72, 0xec, 0xcd, 0x31, 0xfa,
+	0x14, 0xfa, 0x92, 0xad, 0x1c, 0x6f, 0x75, 0x84, 0x44, 0x4f, 0x10, 0xdf, 0x4b, 0x9a, 0x13, 0x41,
+	0x5b, 0x1f, 0xc0, 0x79, 0x0e, 0x4d, 0xe9, 0x1e, 0xd4, 0x85, 0xd6, 0xbb, 0xe3, 0x5f, 0x1c, 0xbf,
+	0xf9, 0xfa, 0x78, 0x78, 0x0f, 0xb5, 0xa1, 0x7e, 0xbc, 0xf7, 0xfa, 0x70, 0x68, 0xa0, 0x35, 0xe8,
+	0xbf, 0xda, 0x3b, 0x79, 0x3b, 0x71, 0x0f, 0x5f, 0x1d, 0xee, 0x9d, 0x1c, 0x1e, 0x0c, 0x6b, 0x68,
+	0x00, 0xb0, 0xff, 0x62, 0xcf, 0x7d, 0x3b, 0x11, 0x22, 0xa6, 0xf3, 0x2d, 0xe8, 0xa4, 0x7e, 0x40,
+	0x2d, 0x30, 0xf7, 0x4e, 0xf6, 0xe5, 0x16, 0x07, 0x87, 0x27, 0xfb, 0x43, 0xc3, 0xf9, 0x93, 0x01,
+	0xeb, 0x79, 0xd8, 0xe9, 0x22, 0x8e, 0x28, 0xe6, 0xb8, 0xfb, 0x71, 0x12, 0xa5, 0xb8, 0x8b, 0x09,
+	0x42, 0x50, 0x8f, 0xf0, 0xa5, 0x46, 0x5d, 0x8c, 0xb9, 0x24, 0x8b, 0x99, 0x17, 0x0a, 0xc4, 0x4d,
+	0x57, 0x4e, 0xd0, 0xf7, 0xa1, 0xad, 0xdc, 0x49, 0xad, 0xfa, 0xb6, 0x39, 0xea, 0xee, 0x3e, 0xcc,
+	0x3b, 0x59, 0x69, 0x74, 0x53, 0x31, 0xe7, 0x08, 0x36, 0x8f, 0xb0, 0xb6, 0x44, 0x62, 0xa0, 0xa3,
+	0x90, 0xeb, 0xe5, 0x4e, 0x35, 0x94, 0x5e, 0xee, 0x4f, 0x0b, 0x5a, 0xda, 0x93, 0xdc, 0x9c, 0x86,
+	0xab, 0xa7, 0xce, 0x3f, 0x6b, 0x60, 0x2d, 0xef, 0xa4, 0x0e, 0x56, 0xb6, 0xd5, 0x63, 0xa8, 0xf3,
+	0xf4, 0x12, 0xfb, 0x74, 0x77, 0x51, 0xde, 0xd0, 0x97, 0xd1, 0x2c, 0x76, 0x05, 0x3f, 0x8f, 0xbf,
+	0x59, 0xc4, 0x9f, 0x01, 0x22, 0xd8, 0x8f, 0xc9, 0x74, 0xe2, 0x45, 0x51, 0xcc, 0x3c, 0x16, 0xc4,
+	0x91, 0x3e, 0xfc, 0x61, 0x79, 0xb0, 0xae, 0xb2, 0x72, 0xec, 0x8a, 0x8d, 0xf6, 0xae, 0xf7, 0x39,
+	0x8c, 0x18, 0xb9, 0x72, 0xd7, 0x48, 0x91, 0x6e, 0x1f, 0xc0, 0x46, 0xb9, 0x30, 0x1a, 0x82, 0x79,
+	0x8e, 0xaf, 0xd4, 0x41, 0xf9, 0x90, 0x43, 0x75, 0xe1, 0x85, 0x09, 0x56, 0xf8, 0xc9, 0xc9, 0x8f,
+	0x6b, 0x3f, 0x32, 0x9c, 0xbf, 0x1a, 0x59, 0x97, 0xed, 0xc7, 0x11, 0xc3, 0x11, 0xbb, 0x93, 0xf7,
+	0x79, 0x9c, 0xe3, 0x4b, 0x3f, 0x4c, 0xa6, 0x78, 0x22, 0x42, 0x5b, 0x38, 0xaa, 0xed, 0xf6, 0x14,
+	0x71, 0x9f, 0xd3, 0xd0, 0x23, 0x18, 0xd0, 0x45, 0x18, 0xb0, 0xc9, 0xdc, 0x8b, 0x82, 0x19, 0xa6,
+	0x4c, 0x24, 0x7c, 0xdb, 0xed, 0x0b, 0xea, 0x6b, 0x45, 0x74, 0xfe, 0x6c, 0xc0, 0x56, 0x89, 0x59,
+	0x0a, 0xca, 0x1d, 0x68, 0x29, 0x90, 0x84, 0x69, 0x2b, 0x43, 0x4c, 0x4b, 0xa1, 0x03, 0xe8, 0x68,
+	0x7d, 0xd4, 0xaa, 0x09, 0x60, 0x1e, 0x97, 0x03, 0xa3, 0x2d, 0x38, 0x88, 0xfd, 0x64, 0xce, 0x75,
+	0x5e, 0x2f, 0x74, 0xfe, 0x62, 0xc0, 0xb0, 0xc8, 0x47, 0xdf, 0x86, 0xae, 0xb7, 0x08, 0xd2, 0xdc,
+	0x96, 0xae, 0x02, 0x6f, 0x11, 0xa8, 0xcc, 0xe6, 0x4e, 0x3c, 0x0f, 0xa2, 0xa9, 0x4e, 0x1d, 0x3e,
+	0x4e, 0x1d, 0x6b, 0x66, 0x1c, 0x9b, 0x8b, 0xb1, 0x7a, 0x31, 0xc6, 0x2c, 0x68, 0xf9, 0xd2, 0x0b,
+	0xa2, 0x0a, 0x76, 0x5c, 0x3d, 0x75, 0xfe, 0x63, 0xc2, 0xfa, 0xbb, 0xc5, 0xd4, 0x63, 0x58, 0x1f,
+	0xfb, 0x06, 0xf4, 0x3e, 0x83, 0x86, 0xc4, 0x46, 0x46, 0xfc, 0x9a, 0x74, 0x82, 0x20, 0x8d, 0x05,
+	0x40, 0xae, 0xe4, 0xa3, 0x27, 0xd0, 0x14, 0x41, 0x42, 0x2d, 0x33, 0x9b, 0x1b, 0x4a, 0x52, 0x5c,
+	0x7e, 0xae, 0x92, 0x40, 0x9b, 0xd0, 0x9a, 0x92, 0x2b, 0x7e, 0x15, 0x29, 0x30, 0x9b, 0x53, 0x72,
+	0xe5, 0x26, 0x22, 0x22, 0xa6, 0x01, 0xf5, 0x4e, 0x43, 0x3c, 0xe1, 0x57, 0x1f, 0x15, 0xa6, 0xb7,
+	0xdd, 0x9e, 0x22, 0xbe, 0xe0, 0x34, 0x64, 0xf3, 0x82, 0xe1, 0x13, 0xec, 0x31, 0x6c, 0x35, 0x05,
+	0x3f, 0x9d, 0xf3, 0x53, 0xb3, 0x60, 0x8e, 0xe3, 0x84, 0x89, 0xaa, 0x6b, 0xba, 0x7a, 0x8a, 0xbe,
+	0x03, 0x3d, 0x82, 0x29, 0x66, 0x13, 0x65, 0x65, 0x5b, 0xac, 0xec, 0x0a, 0xda, 0x7b, 0x69, 0x16,
+	0x82, 0xfa, 0xef, 0xbc, 0x80, 0x89, 0x72, 0xdb, 0x76, 0xc5, 0x58, 0x2e, 0x4b, 0x28, 0xd6, 0xcb,
+	0x40, 0x2f, 0x4b, 0x28, 0x56, 0xcb, 0xd6, 0xa1, 0x31, 0x8b, 0x89, 0x8f, 0xad, 0xae, 0xe0, 0xc9,
+	0x09, 0xda, 0x86, 0xee, 0x14, 0x53, 0x9f, 0x04, 0x0b, 0x9e, 0x68, 0x56, 0x4f, 0xf8, 0x34, 0x4b,
+	0xe2, 0xe7, 0xa0, 0xc9, 0xe9, 0x71, 0xcc, 0x30, 0xb5, 0xfa, 0xf2, 0x1c, 0x7a, 0x8e, 0x1e, 0xc3,
+	0x7d, 0x3f, 0xc4, 0x5e, 0x94, 0x2c, 0x26, 0x71, 0x34, 0x99, 0x79, 0x41, 0x68, 0x0d, 0x64, 0xd8,
+	0x2b, 0xf2, 0x9b, 0xe8, 0x4b, 0x2f, 0x08, 0xf9, 0x79, 0x09, 0xa6, 0x8c, 0x03, 0x74, 0x5f, 0xf0,
+	0xf5, 0xd4, 0xf9, 0x83, 0x01, 0x0f, 0x0b, 0x28, 0xdf, 0x35, 0x19, 0x7e, 0x02, 0x3d, 0x8e, 0xc6,
+	0x84, 0x60, 0x9a, 0x84, 0x69, 0x3e, 0x58, 0xf9, 0x55, 0x1c, 0x1b, 0x57, 0x08, 0xb8, 0xdd, 0x0f,
+	0xe9, 0x98, 0x3a, 0xff, 0xae, 0xc1, 0x86, 0x1b, 0x87, 0xe1, 0xa9, 0xe7, 0x9f, 0x57, 0x88, 0xb7,
+	0x4c, 0x68, 0xd4, 0x6e, 0x0e, 0x0d, 0xb3, 0x24, 0x34, 0x32, 0xb5, 0xa6, 0x9e, 0xaf, 0x35, 0xd9,
+	0xa0, 0x69, 0xac, 0x0e, 0x9a, 0x66, 0x3e, 0x68, 0x74, 0x44, 0xb4, 0x32, 0x11, 0x91, 0xc2, 0xdd,
+	0xbe, 0x01, 0xee, 0xce, 0x32, 0xdc, 0x25, 0x90, 0x42, 0x19, 0xa4, 0xc5, 0x88, 0xeb, 0x2e, 0x45,
+	0x9c, 0xf3, 0x15, 0x6c, 0x2e, 0xb9, 0xf4, 0x8e, 0xe0, 0x3a, 0xff, 0xaa, 0xc3, 0xc3, 0x97, 0x11,
+	0x65, 0x5e, 0x18, 0x16, 0xe0, 0x49, 0x53, 0xdf, 0xa8, 0x9c, 0xfa, 0xb5, 0x8f, 0x49, 0x7d, 0x33,
+	0x87, 0xaf, 0x0e, 0x86, 0x7a, 0x26, 0x18, 0x2a, 0x95, 0x83, 0x5c, 0x19, 0x6c, 0x96, 0xb4, 0x5a,
+	0xd2, 0x9b, 0x62, 0x73, 0x89, 0x63, 0x47, 0x50, 0x8e, 0xd5, 0xe5, 0xa4, 0xa1, 0x6f, 0x97, 0x43,
+	0x9f, 0x2d, 0x06, 0x23, 0x18, 0x6a, 0x7b, 0x7c, 0x32, 0x15, 0x36, 0x29, 0x0c, 0x07, 0x8a, 0xbe,
+	0x4f, 0xa6, 0xdc, 0xaa, 0x62, 0x38, 0x74, 0x6f, 0xce, 0xfe, 0x5e, 0x21, 0xfb, 0x3f, 0x85, 0xfe,
+	0xa9, 0x47, 0xf1, 0x84, 0xe0, 0x8b, 0x40, 0x04, 0x73, 0x5f, 0x04, 0x73, 0xef, 0x54, 0xa0, 0x23,
+	0x69, 0xe8, 0x35, 0xdc, 0x97, 0x5d, 0x22, 0xc1, 0x33, 0x4c, 0x70, 0xe4, 0x63, 0x51, 0x22, 0xba,
+	0xbb, 0xdf, 0x2d, 0xbf, 0xa8, 0x24, 0x64, 0x5a, 0xd6, 0x1d, 0xf8, 0xb9, 0x39, 0x6f, 0xa8, 0x3d,
+	0x16, 0xcf, 0x03, 0x5f, 0x15, 0x12, 0x35, 0xe3, 0x0e, 0xcc, 0x54, 0xcd, 0xe1, 0xb6, 0xc9, 0xfd,
+	0x9b, 0xd6, 0x4c, 0xe7, 0x57, 0x30, 0xc8, 0x6f, 0x8c, 0xb6, 0x78, 0xa6, 0x2d, 0xe2, 0x49, 0x42,
+	0x42, 0x95, 0xd9, 0x2d, 0x3e, 0x7f, 0x47, 0xc2, 0x14, 0xe3, 0x5a, 0x79, 0x7b, 0x20, 0x2f, 0x37,
+	0x3d, 0x75, 0xfe, 0x68, 0xc0, 0x46, 0x31, 0x32, 0xff, 0x27, 0x25, 0xec, 0xef, 0x06, 0x6c, 0xbe,
+	0x8b, 0x82, 0xd2, 0x24, 0x29, 0xab, 0x61, 0x4b, 0x61, 0x5b, 0x2b, 0x09, 0xdb, 0x75, 0x68, 0x2c,
+	0x12, 0x72, 0x86, 0x55, 0x1a, 0xc8, 0x49, 0x36, 0x1e, 0xeb, 0xf9, 0x78, 0x2c, 0x44, 0x54, 0x63,
+	0x29, 0xa2, 0xb8, 0xbf, 0xac, 0x65, 0x33, 0xef, 0xea, 0x31, 0x94, 0xe9, 0x74, 0x3b, 0xaa, 0xab,
+	0x7d, 0x04, 0x83, 0x73, 0xbc, 0xe0, 0x11, 0x47, 0xe3, 0x84, 0xf8, 0xe2, 0xae, 0xe7, 0xf1, 0xd0,
+	0xe7, 0x54, 0x57, 0x13, 0x9d, 0x07, 0xb0, 0x76, 0x84, 0xf5, 0x43, 0x45, 0x39, 0xca, 0x39, 0x04,
+	0x94, 0x25, 0x5e, 0x9b, 0xf5, 0x3e, 0xd3, 0x08, 0xa5, 0x66, 0xe9, 0x27, 0xa7, 0x96, 0xd7, 0x52,
+	0xce, 0xd7, 0x62, 0xef, 0x17, 0x01, 0x65, 0x31, 0xb9, 0xba, 0x09, 0x84, 0x21, 0x98, 0x73, 0xef,
+	0x52, 0xb5, 0x9c, 0x7c, 0x78, 0x73, 0x4f, 0xee, 0x1c, 0x01, 0xca, 0x6e, 0xac, 0xec, 0xcb, 0x3e,
+	0x4e, 0x8c, 0x6a, 0x8f, 0x93, 0x7f, 0x18, 0x80, 0xde, 0xe2, 0xf4, 0xa1, 0x74, 0x4b, 0x6b, 0xac,
+	0xd1, 0xae, 0xe5, 0xd1, 0xe6, 0xdd, 0x9b, 0xbc, 0x15, 0x54, 0x7c, 0xe8, 0x29, 0xaf, 0x1b, 0x0b,
+	0x8f, 0x78, 0x61, 0x88, 0x43, 0xd5, 0x3c, 0xa5, 0x73, 0x7e, 0x75, 0xcc, 0xbd, 0xcb, 0x49, 0xca,
+	0xe7, 0x41, 0xd2, 0x77, 0xbb, 0x73, 0xef, 0xf2, 0x97, 0x5a, 0x04, 0x41, 0x3d, 0x8c, 0xcf, 0xa8,
+	0x6a, 0x9c, 0xc4, 0xd8, 0xf9, 0x0d, 0x3c, 0xc8, 0x19, 0xac, 0xce, 0xce, 0x3d, 0x48, 0xcf, 0xf4,
+	0xab, 0x60, 0x4e, 0xcf, 0xd0, 0x0f, 0xa1, 0x29, 0x1f, 0xb9, 0xc2, 0xdc, 0xc1, 0xee, 0x27, 0x79,
+	0x5f, 0x88, 0x4d, 0x92, 0x48, 0xbd, 0x8a, 0x5d, 0x25, 0xeb, 0x3c, 0x85, 0x8d, 0xeb, 0xce, 0x7c,
+	0x8f, 0x7f, 0xeb, 0xb8, 0xc1, 0x27, 0xce, 0x6b, 0xd8, 0x5c, 0x92, 0x56, 0x06, 0xed, 0x42, 0x0b,
+	0x47, 0x8c, 0x04, 0x29, 0x16, 0x85, 0xfc, 0x15, 0xd2, 0xf2, 0xf9, 0xa3, 0x05, 0x1d, 0x1b, 0x2c,
+	0x17, 0xe3, 0xc8, 0x27, 0x57, 0x8b, 0xe2, 0x17, 0x0b, 0xe7, 0x67, 0xb0, 0x55, 0xc2, 0x53, 0xca,
+	0xb6, 0xa1, 0x4b, 0x34, 0x13, 0x4f, 0x85, 0x89, 0x0d, 0x37, 0x4b, 0x72, 0x26, 0xf0, 0x70, 0x6f,
+	0xb1, 0x20, 0xf1, 0x05, 0xae, 0x06, 0xf5, 0x8a, 0x57, 0x50, 0x26, 0x08, 0xcc, 0x5c, 0x10, 0x38,
+	0x2f, 0x61, 0xa3, 0xa8, 0xe0, 0xae, 0xb7, 0xfc, 0x97, 0x59, 0x0c, 0x44, 0x01, 0xba, 0xdb, 0x83,
+	0x79, 0x1f, 0x36, 0x97, 0xf6, 0x51, 0x36, 0x8d, 0xa0, 0x21, 0xab, 0x9d, 0xc4, 0x06, 0x95, 0xd4,
+	0x56, 0x29, 0xe0, 0x9c, 0x95, 0x3c, 0xd5, 0x52, 0x7b, 0xbe, 0x5a, 0xca, 0xb8, 0xf1, 0x6d, 0x2f,
+	0xe2, 0xfc, 0x23, 0x34, 0x93, 0x8a, 0xa7, 0x60, 0x97, 0x29, 0x52, 0x06, 0x1f, 0x88, 0xde, 0x59,
+	0x5c, 0x07, 0x52, 0xd1, 0x93, 0x72, 0x45, 0x45, 0x2d, 0x7c, 0x89, 0xab, 0x97, 0x3a, 0xdf, 0xc0,
+	0x7a, 0x99, 0xc0, 0x9d, 0x0a, 0x2e, 0xff, 0xd2, 0xa4, 0x3c, 0x2e, 0xc6, 0xfc, 0x92, 0xc0, 0x84,
+	0xc4, 0x44, 0x95, 0x2b, 0x39, 0xd9, 0xfd, 0x5b, 0x1f, 0x06, 0xfa, 0x63, 0x80, 0xb4, 0x15, 0x05,
+	0xd0, 0xcb, 0x7e, 0x9c, 0x41, 0x9f, 0xaf, 0xfe, 0xe4, 0x55, 0xc8, 0x02, 0xfb, 0x49, 0x15, 0x51,
+	0xe9, 0x32, 0xe7, 0xde, 0x17, 0x06, 0xa2, 0x30, 0x2c, 0x7e, 0x8c, 0x40, 0xcf, 0xaa, 0x7e, 0xb4,
+	0x90, 0x2a, 0xc7, 0x1f, 0xf7, 0x8d, 0xc3, 0xb9, 0x87, 0x2e, 0x60, 0xed, 0x9a, 0xab, 0x1c, 0x8d,
+	0x3e, 0x32, 0x30, 0xec, 0x9d, 0xca, 0xf2, 0xa9, 0xde, 0xdf, 0x42, 0x3f, 0xf7, 0x88, 0x42, 0x2b,
+	0xbc, 0x55, 0xf6, 0x9e, 0xb6, 0xbf, 0x57, 0x49, 0x36, 0xd5, 0x35, 0x87, 0x41, 0xbe, 0xdd, 0x41,
+	0x2b, 0x36, 0x28, 0x6d, 0xd7, 0xed, 0xa7, 0xd5, 0x84, 0x53, 0x75, 0x14, 0x86, 0xc5, 0x6e, 0x61,
+	0x15, 0x8e, 0x2b, 0x9a, 0x1f, 0x7b, 0x5c, 0x55, 0x3c, 0x55, 0xea, 0x01, 0x5c, 0x77, 0x01, 0xe8,
+	0xb3, 0x95, 0x80, 0xe4, 0x9b, 0x07, 0x7b, 0x74, 0xbb, 0x60, 0xaa, 0x62, 0x01, 0xf7, 0x0b, 0x8f,
+	0x23, 0xb4, 0xc2, 0x35, 0xe5, 0xcf, 0x52, 0xfb, 0x59, 0x45, 0xe9, 0xc2, 0xa1, 0x54, 0xeb, 0x70,
+	0xc3, 0xa1, 0xf2, 0x5d, 0x8b, 0x3d, 0xba, 0x5d, 0x30, 0x55, 0x11, 0xc0, 0xc0, 0x4d, 0x22, 0xa5,
+	0x9a, 0xdf, 0xb3, 0x68, 0xc5, 0xea, 0xe5, 0xce, 0xc3, 0xfe, 0xbc, 0x82, 0x64, 0x26, 0xbf, 0x2f,
+	0x60, 0x6d, 0xe9, 0x56, 0x5c, 0x95, 0x6a, 0xab, 0xae, 0x56, 0x7b, 0xa7, 0xb2, 0x7c, 0x16, 0xb7,
+	0xc2, 0xc5, 0xbf, 0x0a, 0xb7, 0xf2, 0x6e, 0xc2, 0x7e, 0x56, 0x51, 0x3a, 0x9b, 0x70, 0xf9, 0xfb,
+	0x75, 0x55, 0xc2, 0x95, 0x5e, 0xf3, 0xf6, 0xd3, 0x6a, 0xc2, 0xe5, 0x07, 0x94, 0x8f, 0x80, 0x5b,
+	0x0f, 0x98, 0xbd, 0xaa, 0xed, 0x67, 0x15, 0xa5, 0x53, 0x8d, 0x57, 0x80, 0xae, 0x99, 0xfa, 0xfe,
+	0x43, 0x55, 0xcb, 0x60, 0xaa, 0xf7, 0x8b, 0xea, 0x0b, 0xb4, 0xea, 0xe7, 0xf0, 0xeb, 0xb6, 0x96,
+	0x3f, 0x6d, 0x8a, 0x7f, 0x1c, 0xfd, 0xe0, 0xbf, 0x03, 0x00, 0xe9, 0x42, 0x04, 0x8b, 0x59, 0x1b,
+	0x00, 0x00,
 }
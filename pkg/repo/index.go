@@ -17,6 +17,7 @@ limitations under the License.
 package repo
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -30,6 +31,7 @@ import (
 
 	"github.com/Masterminds/semver"
 	"github.com/ghodss/yaml"
+	"github.com/golang/protobuf/jsonpb"
 
 	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/proto/hapi/chart"
@@ -102,6 +104,11 @@ func LoadIndexFile(path string) (*IndexFile, error) {
 	return loadIndex(b)
 }
 
+// LoadIndex returns the IndexFile held in the contents of an index.yaml.
+func LoadIndex(data []byte) (*IndexFile, error) {
+	return loadIndex(data)
+}
+
 // Add adds a file to the index
 // This can leave the index in an unsorted state
 func (i IndexFile) Add(md *chart.Metadata, filename, baseURL, digest string) {
@@ -232,6 +239,30 @@ type ChartVersion struct {
 	Digest  string    `json:"digest,omitempty"`
 }
 
+// UnmarshalJSON decodes a ChartVersion. Without it the JSON decoding of the
+// embedded chart.Metadata would be promoted and called on a nil Metadata.
+func (c *ChartVersion) UnmarshalJSON(b []byte) error {
+	var fields struct {
+		URLs    []string  `json:"urls"`
+		Created time.Time `json:"created,omitempty"`
+		Removed bool      `json:"removed,omitempty"`
+		Digest  string    `json:"digest,omitempty"`
+	}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	md := &chart.Metadata{}
+	if err := (&jsonpb.Unmarshaler{AllowUnknownFields: true}).Unmarshal(bytes.NewReader(b), md); err != nil {
+		return err
+	}
+	c.Metadata = md
+	c.URLs = fields.URLs
+	c.Created = fields.Created
+	c.Removed = fields.Removed
+	c.Digest = fields.Digest
+	return nil
+}
+
 // IndexDirectory reads a (flat) directory and generates an index.
 //
 // It indexes only charts that have been packaged (*.tgz).
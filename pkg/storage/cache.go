@@ -0,0 +1,110 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage // import "k8s.io/helm/pkg/storage"
+
+import (
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/storage/driver"
+)
+
+// releaseCache holds every stored release so that listing does not have to
+// go to the driver. Writes through the owning Storage invalidate it.
+type releaseCache struct {
+	mu       sync.Mutex
+	releases []*rspb.Release
+	valid    bool
+	// gen is bumped by every invalidation, so that a list that raced with
+	// a write is not cached.
+	gen uint64
+}
+
+// list returns all releases, loading them from d unless they are cached
+// already or refresh is set.
+func (c *releaseCache) list(d driver.Driver, refresh bool) ([]*rspb.Release, error) {
+	c.mu.Lock()
+	if c.valid && !refresh {
+		releases := c.releases
+		c.mu.Unlock()
+		return releases, nil
+	}
+	gen := c.gen
+	c.mu.Unlock()
+
+	releases, err := d.List(func(_ *rspb.Release) bool { return true })
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if c.gen == gen {
+		c.releases, c.valid = releases, true
+	}
+	c.mu.Unlock()
+	return releases, nil
+}
+
+func (c *releaseCache) invalidate() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	c.releases, c.valid = nil, false
+	c.gen++
+	c.mu.Unlock()
+}
+
+// EnableCache makes the storage keep every release in memory after it was
+// first listed, until the next write through this Storage. It must only be
+// used when no other process writes to the same storage backend.
+func (s *Storage) EnableCache() {
+	if s.cache == nil {
+		s.cache = &releaseCache{}
+	}
+}
+
+// WarmCache loads every release into the cache, replacing what it holds.
+// It does nothing if the cache is not enabled.
+func (s *Storage) WarmCache() error {
+	if s.cache == nil {
+		return nil
+	}
+	_, err := s.cache.list(s.Driver, true)
+	return err
+}
+
+// list returns the releases for which filter returns true, from the cache
+// if it is enabled. Cached releases are copied so callers can modify them.
+func (s *Storage) list(filter func(*rspb.Release) bool) ([]*rspb.Release, error) {
+	if s.cache == nil {
+		return s.Driver.List(filter)
+	}
+	all, err := s.cache.list(s.Driver, false)
+	if err != nil {
+		return nil, err
+	}
+	var results []*rspb.Release
+	for _, rls := range all {
+		if filter(rls) {
+			results = append(results, proto.Clone(rls).(*rspb.Release))
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,63 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver // import "k8s.io/helm/pkg/storage/driver"
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// AnnotationGetter is implemented by drivers whose release records carry
+// annotations.
+type AnnotationGetter interface {
+	// GetAnnotations returns the annotations of the record holding the
+	// release named by key.
+	GetAnnotations(key string) (map[string]string, error)
+}
+
+// ParseRecordAnnotations parses comma-separated key=value pairs, such as
+// "team=payments,environment=prod", into annotations for release records.
+func ParseRecordAnnotations(s string) (map[string]string, error) {
+	annotations := map[string]string{}
+	for _, entry := range strings.Split(s, ",") {
+		parts := strings.SplitN(strings.TrimSpace(entry), "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("expected key=value, got %q", entry)
+		}
+		if errs := validation.IsQualifiedName(parts[0]); len(errs) != 0 {
+			return nil, fmt.Errorf("invalid annotation %q: %s", parts[0], errs[0])
+		}
+		annotations[parts[0]] = parts[1]
+	}
+	return annotations, nil
+}
+
+// setAnnotations merges annotations onto the metadata of a release record.
+func setAnnotations(meta *metav1.ObjectMeta, annotations map[string]string) {
+	if len(annotations) == 0 {
+		return
+	}
+	if meta.Annotations == nil {
+		meta.Annotations = make(map[string]string, len(annotations))
+	}
+	for k, v := range annotations {
+		meta.Annotations[k] = v
+	}
+}
@@ -0,0 +1,38 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseRecordAnnotations(t *testing.T) {
+	got, err := ParseRecordAnnotations("team=payments, example.com/owner=ops@example.com")
+	if err != nil {
+		t.Fatalf("Failed to parse annotations: %s", err)
+	}
+	expect := map[string]string{"team": "payments", "example.com/owner": "ops@example.com"}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("Expected %v, got %v", expect, got)
+	}
+	for _, s := range []string{"team", "not a key=value", "=value"} {
+		if _, err := ParseRecordAnnotations(s); err == nil {
+			t.Errorf("Expected %q to be rejected", s)
+		}
+	}
+}
@@ -34,6 +34,11 @@ import (
 )
 
 var _ Driver = (*ConfigMaps)(nil)
+var _ ContentGetter = (*ConfigMaps)(nil)
+var _ LabelLister = (*ConfigMaps)(nil)
+var _ Purger = (*ConfigMaps)(nil)
+var _ AnnotationGetter = (*ConfigMaps)(nil)
+var _ Pinger = (*ConfigMaps)(nil)
 
 // ConfigMapsDriverName is the string name of the driver.
 const ConfigMapsDriverName = "ConfigMap"
@@ -43,14 +48,28 @@ const ConfigMapsDriverName = "ConfigMap"
 type ConfigMaps struct {
 	impl corev1.ConfigMapInterface
 	Log  func(string, ...interface{})
+	// IndexedLabels names chart annotations that are copied to the labels
+	// of each release's ConfigMap, so Query can select releases by them.
+	IndexedLabels []string
+	// SeparateContent stores each release's content without its chart in a
+	// ConfigMap of its own, so GetContent can skip reading the chart.
+	SeparateContent bool
+	// Annotations are set on every ConfigMap holding a release.
+	Annotations map[string]string
+	// Encrypter, if set, encrypts release payloads at rest.
+	Encrypter Encrypter
+	// CompressionLevel is the gzip level release payloads are compressed
+	// with. NewConfigMaps sets it to DefaultCompressionLevel.
+	CompressionLevel int
 }
 
 // NewConfigMaps initializes a new ConfigMaps wrapping an implementation of
 // the kubernetes ConfigMapsInterface.
 func NewConfigMaps(impl corev1.ConfigMapInterface) *ConfigMaps {
 	return &ConfigMaps{
-		impl: impl,
-		Log:  func(_ string, _ ...interface{}) {},
+		impl:             impl,
+		Log:              func(_ string, _ ...interface{}) {},
+		CompressionLevel: DefaultCompressionLevel,
 	}
 }
 
@@ -73,7 +92,7 @@ func (cfgmaps *ConfigMaps) Get(key string) (*rspb.Release, error) {
 		return nil, err
 	}
 	// found the configmap, decode the base64 data string
-	r, err := decodeRelease(obj.Data["release"])
+	r, err := cfgmaps.decode(obj.Data["release"])
 	if err != nil {
 		cfgmaps.Log("get: failed to decode data %q: %s", key, err)
 		return nil, err
@@ -82,6 +101,20 @@ func (cfgmaps *ConfigMaps) Get(key string) (*rspb.Release, error) {
 	return r, nil
 }
 
+// GetAnnotations returns the annotations of the ConfigMap holding the release
+// named by key.
+func (cfgmaps *ConfigMaps) GetAnnotations(key string) (map[string]string, error) {
+	obj, err := cfgmaps.impl.Get(key, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, storageerrors.ErrReleaseNotFound(key)
+		}
+		cfgmaps.Log("get annotations: failed to get %q: %s", key, err)
+		return nil, err
+	}
+	return obj.Annotations, nil
+}
+
 // List fetches all releases and returns the list releases such
 // that filter(release) == true. An error is returned if the
 // configmap fails to retrieve the releases.
@@ -100,7 +133,7 @@ func (cfgmaps *ConfigMaps) List(filter func(*rspb.Release) bool) ([]*rspb.Releas
 	// iterate over the configmaps object list
 	// and decode each release
 	for _, item := range list.Items {
-		rls, err := decodeRelease(item.Data["release"])
+		rls, err := cfgmaps.decode(item.Data["release"])
 		if err != nil {
 			cfgmaps.Log("list: failed to decode release: %v: %s", item, err)
 			continue
@@ -112,6 +145,59 @@ func (cfgmaps *ConfigMaps) List(filter func(*rspb.Release) bool) ([]*rspb.Releas
 	return results, nil
 }
 
+// ListRecords fetches every release, along with the error for each configmap,
+// by name, whose release cannot be decoded.
+func (cfgmaps *ConfigMaps) ListRecords() ([]*rspb.Release, map[string]error, error) {
+	lsel := kblabels.Set{"OWNER": "TILLER"}.AsSelector()
+	opts := metav1.ListOptions{LabelSelector: lsel.String()}
+
+	list, err := cfgmaps.impl.List(opts)
+	if err != nil {
+		cfgmaps.Log("list records: failed to list: %s", err)
+		return nil, nil, err
+	}
+
+	var results []*rspb.Release
+	failed := map[string]error{}
+	for _, item := range list.Items {
+		rls, err := cfgmaps.decode(item.Data["release"])
+		if err != nil {
+			failed[item.Name] = err
+			continue
+		}
+		results = append(results, rls)
+	}
+	return results, failed, nil
+}
+
+// ListByLabels fetches the releases whose labels match selector, leaving
+// the selection to the API server. An error is returned if the configmap
+// fails to retrieve the releases.
+func (cfgmaps *ConfigMaps) ListByLabels(selector kblabels.Selector) ([]*rspb.Release, error) {
+	lsel, err := ownedSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	opts := metav1.ListOptions{LabelSelector: lsel}
+
+	list, err := cfgmaps.impl.List(opts)
+	if err != nil {
+		cfgmaps.Log("list by labels: failed to list: %s", err)
+		return nil, err
+	}
+
+	var results []*rspb.Release
+	for _, item := range list.Items {
+		rls, err := cfgmaps.decode(item.Data["release"])
+		if err != nil {
+			cfgmaps.Log("list by labels: failed to decode release: %s: %s", item.Name, err)
+			continue
+		}
+		results = append(results, rls)
+	}
+	return results, nil
+}
+
 // Query fetches all releases that match the provided map of labels.
 // An error is returned if the configmap fails to retrieve the releases.
 func (cfgmaps *ConfigMaps) Query(labels map[string]string) ([]*rspb.Release, error) {
@@ -137,7 +223,7 @@ func (cfgmaps *ConfigMaps) Query(labels map[string]string) ([]*rspb.Release, err
 
 	var results []*rspb.Release
 	for _, item := range list.Items {
-		rls, err := decodeRelease(item.Data["release"])
+		rls, err := cfgmaps.decode(item.Data["release"])
 		if err != nil {
 			cfgmaps.Log("query: failed to decode release: %s", err)
 			continue
@@ -147,6 +233,112 @@ func (cfgmaps *ConfigMaps) Query(labels map[string]string) ([]*rspb.Release, err
 	return results, nil
 }
 
+// GetContent fetches a revision of the named release without its chart,
+// from the ConfigMap holding its content. Revisions written without separate
+// content are read from their records, decoded in full and stripped.
+func (cfgmaps *ConfigMaps) GetContent(name string, version int32) (*rspb.Release, error) {
+	opts := metav1.ListOptions{LabelSelector: revisionSelector(contentOwner, name, version)}
+	contents, err := cfgmaps.impl.List(opts)
+	if err != nil {
+		cfgmaps.Log("get content: failed to list content of %q: %s", name, err)
+		return nil, err
+	}
+	if i := latestConfigMap(contents.Items); i >= 0 {
+		item := contents.Items[i]
+		if version <= 0 {
+			// Records newer than the latest content were written while
+			// separate content was disabled.
+			v, _ := strconv.Atoi(item.Labels["VERSION"])
+			newer, err := cfgmaps.impl.List(metav1.ListOptions{LabelSelector: newerSelector(name, int32(v))})
+			if err != nil {
+				cfgmaps.Log("get content: failed to list %q: %s", name, err)
+				return nil, err
+			}
+			if len(newer.Items) > 0 {
+				return cfgmaps.recordContent(name, newer.Items)
+			}
+		}
+		rls, err := cfgmaps.decode(item.Data[contentKey])
+		if err == nil {
+			return rls, nil
+		}
+		cfgmaps.Log("get content: failed to decode content %q, reading the release instead: %s", item.Name, err)
+	}
+
+	opts = metav1.ListOptions{LabelSelector: revisionSelector("TILLER", name, version)}
+	records, err := cfgmaps.impl.List(opts)
+	if err != nil {
+		cfgmaps.Log("get content: failed to list %q: %s", name, err)
+		return nil, err
+	}
+	return cfgmaps.recordContent(name, records.Items)
+}
+
+// recordContent decodes the latest of the release records in items and
+// strips its chart.
+func (cfgmaps *ConfigMaps) recordContent(name string, items []v1.ConfigMap) (*rspb.Release, error) {
+	i := latestConfigMap(items)
+	if i < 0 {
+		return nil, storageerrors.ErrReleaseNotFound(name)
+	}
+	rls, err := cfgmaps.decode(items[i].Data["release"])
+	if err != nil {
+		cfgmaps.Log("get content: failed to decode data %q: %s", items[i].Name, err)
+		return nil, err
+	}
+	return StripChart(rls), nil
+}
+
+// latestConfigMap returns the index of the ConfigMap holding the latest
+// revision, or -1 if there are none.
+func latestConfigMap(items []v1.ConfigMap) int {
+	lbs := make([]map[string]string, len(items))
+	for i, item := range items {
+		lbs[i] = item.Labels
+	}
+	return latestRevision(lbs)
+}
+
+// writeContent creates or updates the ConfigMap holding the content of the
+// release record named key.
+func (cfgmaps *ConfigMaps) writeContent(key string, rls *rspb.Release) error {
+	c, err := encodeReleaseContent(rls)
+	if err != nil {
+		return err
+	}
+	obj := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   contentName(key),
+			Labels: contentLabels(rls),
+		},
+		Data: map[string]string{contentKey: c},
+	}
+	setAnnotations(&obj.ObjectMeta, cfgmaps.Annotations)
+	if err := cfgmaps.encrypt(obj); err != nil {
+		return err
+	}
+	if _, err = cfgmaps.impl.Update(obj); apierrors.IsNotFound(err) {
+		_, err = cfgmaps.impl.Create(obj)
+	}
+	return err
+}
+
+// deleteContent deletes the ConfigMap holding the content of the release
+// record named key, if there is one.
+func (cfgmaps *ConfigMaps) deleteContent(key string) error {
+	if err := cfgmaps.impl.Delete(contentName(key), &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// Ping lists at most one release ConfigMap to check that the API server can
+// be reached.
+func (cfgmaps *ConfigMaps) Ping() error {
+	_, err := cfgmaps.impl.List(metav1.ListOptions{LabelSelector: "OWNER=TILLER", Limit: 1})
+	return err
+}
+
 // Create creates a new ConfigMap holding the release. If the
 // ConfigMap already exists, ErrReleaseExists is returned.
 func (cfgmaps *ConfigMaps) Create(key string, rls *rspb.Release) error {
@@ -155,13 +347,19 @@ func (cfgmaps *ConfigMaps) Create(key string, rls *rspb.Release) error {
 
 	lbs.init()
 	lbs.set("CREATED_AT", strconv.Itoa(int(time.Now().Unix())))
+	setIndexedLabels(lbs, rls, cfgmaps.IndexedLabels)
 
 	// create a new configmap to hold the release
-	obj, err := newConfigMapsObject(key, rls, lbs)
+	obj, err := newConfigMapsObject(key, rls, lbs, cfgmaps.CompressionLevel)
 	if err != nil {
 		cfgmaps.Log("create: failed to encode release %q: %s", rls.Name, err)
 		return err
 	}
+	setAnnotations(&obj.ObjectMeta, cfgmaps.Annotations)
+	if err := cfgmaps.encrypt(obj); err != nil {
+		cfgmaps.Log("create: failed to encrypt release %q: %s", rls.Name, err)
+		return err
+	}
 	// push the configmap object out into the kubiverse
 	if _, err := cfgmaps.impl.Create(obj); err != nil {
 		if apierrors.IsAlreadyExists(err) {
@@ -171,6 +369,16 @@ func (cfgmaps *ConfigMaps) Create(key string, rls *rspb.Release) error {
 		cfgmaps.Log("create: failed to create: %s", err)
 		return err
 	}
+	if cfgmaps.SeparateContent {
+		if err := cfgmaps.writeContent(key, rls); err != nil {
+			cfgmaps.Log("create: failed to create content of release %q: %s", rls.Name, err)
+			// Leave no record behind whose content would be missing.
+			if err := cfgmaps.impl.Delete(key, &metav1.DeleteOptions{}); err != nil {
+				cfgmaps.Log("create: failed to delete %q: %s", key, err)
+			}
+			return err
+		}
+	}
 	return nil
 }
 
@@ -182,19 +390,31 @@ func (cfgmaps *ConfigMaps) Update(key string, rls *rspb.Release) error {
 
 	lbs.init()
 	lbs.set("MODIFIED_AT", strconv.Itoa(int(time.Now().Unix())))
+	setIndexedLabels(lbs, rls, cfgmaps.IndexedLabels)
 
 	// create a new configmap object to hold the release
-	obj, err := newConfigMapsObject(key, rls, lbs)
+	obj, err := newConfigMapsObject(key, rls, lbs, cfgmaps.CompressionLevel)
 	if err != nil {
 		cfgmaps.Log("update: failed to encode release %q: %s", rls.Name, err)
 		return err
 	}
+	setAnnotations(&obj.ObjectMeta, cfgmaps.Annotations)
+	if err := cfgmaps.encrypt(obj); err != nil {
+		cfgmaps.Log("update: failed to encrypt release %q: %s", rls.Name, err)
+		return err
+	}
 	// push the configmap object out into the kubiverse
 	_, err = cfgmaps.impl.Update(obj)
 	if err != nil {
 		cfgmaps.Log("update: failed to update: %s", err)
 		return err
 	}
+	if cfgmaps.SeparateContent {
+		if err := cfgmaps.writeContent(key, rls); err != nil {
+			cfgmaps.Log("update: failed to update content of release %q: %s", rls.Name, err)
+			return err
+		}
+	}
 	return nil
 }
 
@@ -213,9 +433,79 @@ func (cfgmaps *ConfigMaps) Delete(key string) (rls *rspb.Release, err error) {
 	if err = cfgmaps.impl.Delete(key, &metav1.DeleteOptions{}); err != nil {
 		return rls, err
 	}
+	if err = cfgmaps.deleteContent(key); err != nil {
+		cfgmaps.Log("delete: failed to delete content of %q: %s", key, err)
+		return rls, err
+	}
 	return rls, nil
 }
 
+// DeleteAll deletes the configmaps holding every revision of the named
+// release, and those holding their content, with one collection deletion
+// each. Revisions that cannot be decoded are deleted as well, but are not
+// returned. The API server deletes the items of a collection one by one, so
+// a failed call may leave some revisions behind; as their content is deleted
+// first, retrying DeleteAll finishes the purge.
+func (cfgmaps *ConfigMaps) DeleteAll(name string) ([]*rspb.Release, error) {
+	opts := metav1.ListOptions{LabelSelector: revisionSelector("TILLER", name, 0)}
+
+	list, err := cfgmaps.impl.List(opts)
+	if err != nil {
+		cfgmaps.Log("delete all: failed to list %q: %s", name, err)
+		return nil, err
+	}
+	if len(list.Items) == 0 {
+		return nil, storageerrors.ErrReleaseNotFound(name)
+	}
+
+	var deleted []*rspb.Release
+	for _, item := range list.Items {
+		rls, err := cfgmaps.decode(item.Data["release"])
+		if err != nil {
+			cfgmaps.Log("delete all: failed to decode release %q: %s", item.Name, err)
+			continue
+		}
+		deleted = append(deleted, rls)
+	}
+
+	content := metav1.ListOptions{LabelSelector: revisionSelector(contentOwner, name, 0)}
+	if err := cfgmaps.impl.DeleteCollection(&metav1.DeleteOptions{}, content); err != nil {
+		cfgmaps.Log("delete all: failed to delete content of %q: %s", name, err)
+		return nil, err
+	}
+	if err := cfgmaps.impl.DeleteCollection(&metav1.DeleteOptions{}, opts); err != nil {
+		cfgmaps.Log("delete all: failed to delete %q: %s", name, err)
+		return nil, err
+	}
+	return deleted, nil
+}
+
+// decode decrypts a stored payload with the encrypter and decodes the
+// release.
+func (cfgmaps *ConfigMaps) decode(data string) (*rspb.Release, error) {
+	s, err := decrypt(cfgmaps.Encrypter, data)
+	if err != nil {
+		return nil, err
+	}
+	return decodeRelease(s)
+}
+
+// encrypt encrypts the payloads of obj in place when an encrypter is
+// configured.
+func (cfgmaps *ConfigMaps) encrypt(obj *v1.ConfigMap) error {
+	if cfgmaps.Encrypter == nil {
+		return nil
+	}
+	for k, data := range obj.Data {
+		s, err := cfgmaps.Encrypter.Encrypt(data)
+		if err != nil {
+			return err
+		}
+		obj.Data[k] = s
+	}
+	return nil
+}
+
 // newConfigMapsObject constructs a kubernetes ConfigMap object
 // to store a release. Each configmap data entry is the base64
 // encoded string of a release's binary protobuf encoding.
@@ -229,11 +519,11 @@ func (cfgmaps *ConfigMaps) Delete(key string) (rls *rspb.Release, err error) {
 //    "OWNER"          - owner of the configmap, currently "TILLER".
 //    "NAME"           - name of the release.
 //
-func newConfigMapsObject(key string, rls *rspb.Release, lbs labels) (*v1.ConfigMap, error) {
+func newConfigMapsObject(key string, rls *rspb.Release, lbs labels, level int) (*v1.ConfigMap, error) {
 	const owner = "TILLER"
 
 	// encode the release
-	s, err := encodeRelease(rls)
+	s, err := encodeRelease(rls, level)
 	if err != nil {
 		return nil, err
 	}
@@ -15,11 +15,14 @@ package driver
 
 import (
 	"encoding/base64"
+	"strings"
 	"testing"
 
 	"github.com/gogo/protobuf/proto"
 	"k8s.io/api/core/v1"
+	kblabels "k8s.io/apimachinery/pkg/labels"
 
+	"k8s.io/helm/pkg/proto/hapi/chart"
 	rspb "k8s.io/helm/pkg/proto/hapi/release"
 )
 
@@ -58,7 +61,7 @@ func TestUNcompressedConfigMapGet(t *testing.T) {
 	rel := releaseStub(name, vers, namespace, rspb.Status_DEPLOYED)
 
 	// Create a test fixture which contains an uncompressed release
-	cfgmap, err := newConfigMapsObject(key, rel, nil)
+	cfgmap, err := newConfigMapsObject(key, rel, nil, DefaultCompressionLevel)
 	if err != nil {
 		t.Fatalf("Failed to create configmap: %s", err)
 	}
@@ -129,6 +132,39 @@ func TestConfigMapList(t *testing.T) {
 	}
 }
 
+func TestConfigMapListByLabels(t *testing.T) {
+	cfgmaps := newTestFixtureCfgMaps(t, []*rspb.Release{
+		releaseStub("key-1", 1, "default", rspb.Status_DELETED),
+		releaseStub("key-2", 1, "default", rspb.Status_FAILED),
+		releaseStub("key-3", 1, "default", rspb.Status_DEPLOYED),
+		releaseStub("key-4", 2, "default", rspb.Status_SUPERSEDED),
+	}...)
+
+	selector, err := kblabels.Parse("STATUS in (DEPLOYED,FAILED)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rels, err := cfgmaps.ListByLabels(selector)
+	if err != nil {
+		t.Fatalf("Failed to list by labels: %s", err)
+	}
+	got := map[string]bool{}
+	for _, rel := range rels {
+		got[rel.Name] = true
+	}
+	if len(rels) != 2 || !got["key-2"] || !got["key-3"] {
+		t.Errorf("Expected key-2 and key-3, got %v", got)
+	}
+
+	rels, err = cfgmaps.ListByLabels(kblabels.Everything())
+	if err != nil {
+		t.Fatalf("Failed to list by labels: %s", err)
+	}
+	if len(rels) != 4 {
+		t.Errorf("Expected 4 releases for an empty selector, got %d", len(rels))
+	}
+}
+
 func TestConfigMapCreate(t *testing.T) {
 	cfgmaps := newTestFixtureCfgMaps(t)
 
@@ -155,6 +191,63 @@ func TestConfigMapCreate(t *testing.T) {
 	}
 }
 
+func TestConfigMapDeleteAll(t *testing.T) {
+	cfgmaps := newTestFixtureCfgMaps(t, []*rspb.Release{
+		releaseStub("smug-pigeon", 1, "default", rspb.Status_SUPERSEDED),
+		releaseStub("smug-pigeon", 2, "default", rspb.Status_DELETED),
+		releaseStub("angry-beaver", 1, "default", rspb.Status_DEPLOYED),
+	}...)
+
+	deleted, err := cfgmaps.DeleteAll("smug-pigeon")
+	if err != nil {
+		t.Fatalf("Failed to delete all revisions: %s", err)
+	}
+	if len(deleted) != 2 {
+		t.Errorf("Expected 2 deleted revisions, got %d", len(deleted))
+	}
+	if _, err := cfgmaps.Query(map[string]string{"NAME": "smug-pigeon", "OWNER": "TILLER"}); err == nil {
+		t.Error("Expected no revisions of smug-pigeon to remain")
+	}
+	if _, err := cfgmaps.Get(testKey("angry-beaver", 1)); err != nil {
+		t.Errorf("Expected other releases to be kept: %s", err)
+	}
+
+	if _, err := cfgmaps.DeleteAll("smug-pigeon"); err == nil {
+		t.Error("Expected an error deleting a release without revisions")
+	}
+}
+
+func TestConfigMapDeleteAllContent(t *testing.T) {
+	cfgmaps := newTestFixtureCfgMaps(t)
+	cfgmaps.SeparateContent = true
+	for _, rls := range []*rspb.Release{
+		releaseStub("smug-pigeon", 1, "default", rspb.Status_SUPERSEDED),
+		releaseStub("smug-pigeon", 2, "default", rspb.Status_DEPLOYED),
+		releaseStub("angry-beaver", 1, "default", rspb.Status_DEPLOYED),
+	} {
+		if err := cfgmaps.Create(testKey(rls.Name, rls.Version), rls); err != nil {
+			t.Fatalf("Failed to create release: %s", err)
+		}
+	}
+
+	deleted, err := cfgmaps.DeleteAll("smug-pigeon")
+	if err != nil {
+		t.Fatalf("Failed to delete all revisions: %s", err)
+	}
+	if len(deleted) != 2 {
+		t.Errorf("Expected 2 deleted revisions, got %d", len(deleted))
+	}
+	mock := cfgmaps.impl.(*MockConfigMapsInterface)
+	for name := range mock.objects {
+		if strings.HasPrefix(name, "smug-pigeon") {
+			t.Errorf("Expected %s to be deleted", name)
+		}
+	}
+	if _, ok := mock.objects[contentName(testKey("angry-beaver", 1))]; !ok {
+		t.Error("Expected the content of other releases to be kept")
+	}
+}
+
 func TestConfigMapUpdate(t *testing.T) {
 	vers := int32(1)
 	name := "smug-pigeon"
@@ -183,3 +276,125 @@ func TestConfigMapUpdate(t *testing.T) {
 		t.Errorf("Expected status %s, got status %s", rel.Info.Status.Code, got.Info.Status.Code)
 	}
 }
+
+func TestConfigMapQueryIndexedLabels(t *testing.T) {
+	stamped := func(name string, vers int32, annotations map[string]string) *rspb.Release {
+		rls := releaseStub(name, vers, "default", rspb.Status_DEPLOYED)
+		rls.Chart = &chart.Chart{Metadata: &chart.Metadata{Name: "app", Annotations: annotations}}
+		return rls
+	}
+
+	cfgmaps := newTestFixtureCfgMaps(t)
+	cfgmaps.IndexedLabels = []string{"team", "cost-center"}
+
+	for _, rls := range []*rspb.Release{
+		stamped("payments-api", 1, map[string]string{"team": "payments", "cost-center": "cc-42", "owner-email": "a@example.com"}),
+		stamped("payments-db", 1, map[string]string{"team": "payments", "cost-center": "not a label value!"}),
+		stamped("search", 1, map[string]string{"team": "search"}),
+	} {
+		if err := cfgmaps.Create(testKey(rls.Name, rls.Version), rls); err != nil {
+			t.Fatalf("Failed to create release %s: %s", rls.Name, err)
+		}
+	}
+	// Labels follow the release's chart when it is updated.
+	updated := stamped("search", 1, map[string]string{"team": "payments"})
+	if err := cfgmaps.Update(testKey(updated.Name, updated.Version), updated); err != nil {
+		t.Fatalf("Failed to update release: %s", err)
+	}
+
+	results, err := cfgmaps.Query(map[string]string{"team": "payments"})
+	if err != nil {
+		t.Fatalf("Failed to query: %s", err)
+	}
+	if len(results) != 3 {
+		t.Errorf("Expected 3 releases owned by payments, got %d", len(results))
+	}
+
+	results, err = cfgmaps.Query(map[string]string{"cost-center": "cc-42"})
+	if err != nil {
+		t.Fatalf("Failed to query: %s", err)
+	}
+	if len(results) != 1 || results[0].Name != "payments-api" {
+		t.Errorf("Expected only payments-api in cost center cc-42, got %v", results)
+	}
+
+	if _, err := cfgmaps.Query(map[string]string{"owner-email": "a@example.com"}); err == nil {
+		t.Error("Expected annotations that are not indexed not to be queryable")
+	}
+}
+
+func TestConfigMapGetContent(t *testing.T) {
+	withChart := func(name string, vers int32, manifest string) *rspb.Release {
+		rls := releaseStub(name, vers, "default", rspb.Status_DEPLOYED)
+		rls.Manifest = manifest
+		rls.Chart = &chart.Chart{
+			Metadata:  &chart.Metadata{Name: "app"},
+			Templates: []*chart.Template{{Name: "templates/cm.yaml", Data: []byte(manifest)}},
+		}
+		return rls
+	}
+
+	// rls-old was written before separate content was enabled.
+	cfgmaps := newTestFixtureCfgMaps(t, withChart("rls-old", 1, "kind: Old"))
+	cfgmaps.SeparateContent = true
+	for _, rls := range []*rspb.Release{withChart("rls-a", 1, "kind: A1"), withChart("rls-a", 2, "kind: A2"), withChart("rls-b", 1, "kind: B1")} {
+		if err := cfgmaps.Create(testKey(rls.Name, rls.Version), rls); err != nil {
+			t.Fatalf("Failed to create release: %s", err)
+		}
+	}
+	// rls-b v2 was written after separate content was disabled.
+	cfgmaps.SeparateContent = false
+	if err := cfgmaps.Create(testKey("rls-b", 2), withChart("rls-b", 2, "kind: B2")); err != nil {
+		t.Fatalf("Failed to create release: %s", err)
+	}
+
+	mock := cfgmaps.impl.(*MockConfigMapsInterface)
+	if _, ok := mock.objects[contentName(testKey("rls-a", 2))]; !ok {
+		t.Fatal("Expected the content of rls-a v2 in a ConfigMap of its own")
+	}
+	if _, ok := mock.objects[testKey("rls-a", 2)].Data[contentKey]; ok {
+		t.Error("Expected the record of rls-a v2 to hold no content")
+	}
+
+	for _, tt := range []struct {
+		name     string
+		version  int32
+		manifest string
+		read     string
+	}{
+		{"rls-a", 0, "kind: A2", ""},
+		{"rls-a", 1, "kind: A1", ""},
+		{"rls-b", 0, "kind: B2", testKey("rls-b", 2)},
+		{"rls-old", 0, "kind: Old", testKey("rls-old", 1)},
+	} {
+		mock.read = nil
+		rls, err := cfgmaps.GetContent(tt.name, tt.version)
+		if err != nil {
+			t.Fatalf("Failed to get content of %s v%d: %s", tt.name, tt.version, err)
+		}
+		if rls.Manifest != tt.manifest {
+			t.Errorf("Expected manifest %q for %s v%d, got %q", tt.manifest, tt.name, tt.version, rls.Manifest)
+		}
+		if rls.Chart.Metadata.Name != "app" || len(rls.Chart.Templates) != 0 {
+			t.Errorf("Expected chart metadata only for %s v%d, got %v", tt.name, tt.version, rls.Chart)
+		}
+		// Fetching content must not read the records holding the chart,
+		// unless they were written without separate content.
+		for name := range mock.read {
+			if _, isRecord := mock.objects[name].Data["release"]; isRecord && name != tt.read {
+				t.Errorf("Expected getting the content of %s v%d not to read %s", tt.name, tt.version, name)
+			}
+		}
+	}
+
+	if _, err := cfgmaps.GetContent("rls-a", 3); err == nil {
+		t.Error("Expected an error getting content of a missing revision")
+	}
+
+	if _, err := cfgmaps.Delete(testKey("rls-a", 2)); err != nil {
+		t.Fatalf("Failed to delete release: %s", err)
+	}
+	if _, ok := mock.objects[contentName(testKey("rls-a", 2))]; ok {
+		t.Error("Expected the content of rls-a v2 to be deleted with its record")
+	}
+}
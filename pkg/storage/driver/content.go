@@ -0,0 +1,123 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver // import "k8s.io/helm/pkg/storage/driver"
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+	kblabels "k8s.io/apimachinery/pkg/labels"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+)
+
+const (
+	// contentKey is the data key holding a release's content in the object
+	// written next to the release's record when separate content is enabled.
+	contentKey = "content"
+
+	// contentOwner is the OWNER label of the objects holding release content.
+	// It keeps them out of the selections of release records, which are
+	// owned by "TILLER".
+	contentOwner = "TILLER_CONTENT"
+)
+
+// ContentGetter is implemented by drivers that can fetch a release's
+// rendered content without decoding its chart.
+type ContentGetter interface {
+	// GetContent returns the given revision of the named release, or its
+	// latest revision if version is 0, with the chart reduced to its
+	// metadata.
+	GetContent(name string, version int32) (*rspb.Release, error)
+}
+
+// StripChart returns a shallow copy of rls whose chart carries only its
+// metadata, dropping templates, values, files and dependencies.
+func StripChart(rls *rspb.Release) *rspb.Release {
+	c := *rls
+	if rls.Chart != nil {
+		c.Chart = &chart.Chart{Metadata: rls.Chart.Metadata}
+	}
+	return &c
+}
+
+// encodeReleaseContent encodes rls with its chart stripped. Content is read
+// far more often than it is written, so it favours speed over size.
+func encodeReleaseContent(rls *rspb.Release) (string, error) {
+	b, err := proto.Marshal(StripChart(rls))
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	w, err := gzip.NewWriterLevel(&buf, gzip.BestSpeed)
+	if err != nil {
+		return "", err
+	}
+	if _, err = w.Write(b); err != nil {
+		return "", err
+	}
+	w.Close()
+
+	return headerGzip + b64.EncodeToString(buf.Bytes()), nil
+}
+
+// contentName returns the name of the object holding the content of the
+// release record named key.
+func contentName(key string) string {
+	return key + "." + contentKey
+}
+
+// contentLabels returns the labels of the object holding the content of rls.
+func contentLabels(rls *rspb.Release) map[string]string {
+	return map[string]string{
+		"NAME":    rls.Name,
+		"OWNER":   contentOwner,
+		"VERSION": strconv.Itoa(int(rls.Version)),
+	}
+}
+
+// revisionSelector selects the objects of the given owner holding a release
+// revision, or every revision if version is 0.
+func revisionSelector(owner, name string, version int32) string {
+	ls := kblabels.Set{"NAME": name, "OWNER": owner}
+	if version > 0 {
+		ls["VERSION"] = strconv.Itoa(int(version))
+	}
+	return ls.AsSelector().String()
+}
+
+// newerSelector selects the records of the revisions of a release above
+// version.
+func newerSelector(name string, version int32) string {
+	return fmt.Sprintf("%s,VERSION>%d", revisionSelector("TILLER", name, 0), version)
+}
+
+// latestRevision returns the index of the labels with the highest VERSION,
+// or -1 if there are none.
+func latestRevision(lbs []map[string]string) int {
+	latest, max := -1, -1
+	for i, l := range lbs {
+		if v, err := strconv.Atoi(l["VERSION"]); err == nil && v > max {
+			latest, max = i, v
+		}
+	}
+	return latest
+}
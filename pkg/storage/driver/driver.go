@@ -17,6 +17,8 @@ limitations under the License.
 package driver // import "k8s.io/helm/pkg/storage/driver"
 
 import (
+	kblabels "k8s.io/apimachinery/pkg/labels"
+
 	rspb "k8s.io/helm/pkg/proto/hapi/release"
 	storageerrors "k8s.io/helm/pkg/storage/errors"
 )
@@ -68,6 +70,39 @@ type Queryor interface {
 	Query(labels map[string]string) ([]*rspb.Release, error)
 }
 
+// LabelLister is implemented by drivers that can select releases by their
+// labels in the backend, rather than loading every release and filtering.
+//
+// ListByLabels returns the releases whose labels match selector.
+type LabelLister interface {
+	ListByLabels(selector kblabels.Selector) ([]*rspb.Release, error)
+}
+
+// Purger is implemented by drivers that can delete every revision of a
+// release at once.
+//
+// DeleteAll deletes all revisions of the named release and returns them, or
+// returns ErrReleaseNotFound if there are none.
+type Purger interface {
+	DeleteAll(name string) ([]*rspb.Release, error)
+}
+
+// RecordLister is implemented by drivers that can report the records they
+// fail to decode, which List skips.
+//
+// ListRecords returns every release that could be decoded, and the error for
+// each record, by key, that could not.
+type RecordLister interface {
+	ListRecords() ([]*rspb.Release, map[string]error, error)
+}
+
+// Pinger is implemented by drivers whose backend can become unreachable.
+//
+// Ping returns an error if the backend cannot be reached.
+type Pinger interface {
+	Ping() error
+}
+
 // Driver is the interface composed of Creator, Updator, Deletor, and Queryor
 // interfaces. It defines the behavior for storing, updating, deleted,
 // and retrieving Tiller releases from some underlying storage mechanism,
@@ -0,0 +1,146 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver // import "k8s.io/helm/pkg/storage/driver"
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// encryptedPrefix marks a stored release payload that has been sealed by a
+// Keyring. The full layout is "enc:<key-id>:<base64 nonce+ciphertext>".
+const encryptedPrefix = "enc:"
+
+// Reencrypter is implemented by drivers that can rewrite stored releases
+// under the current primary encryption key.
+type Reencrypter interface {
+	// StaleReleases returns the names of the releases with records that are
+	// not sealed with the primary key.
+	StaleReleases() ([]string, error)
+	// Reencrypt rewrites the records of the named release that are not
+	// sealed with the primary key and returns the number of records
+	// rewritten.
+	Reencrypt(name string) (int, error)
+}
+
+// Keyring holds the AES keys used to encrypt release payloads at rest.
+//
+// Writes are always sealed with the primary key. Reads pick the key named by
+// the key-id prefix of the payload, so records written under a previous
+// primary stay readable until they are re-encrypted.
+type Keyring struct {
+	primary string
+	keys    map[string]cipher.AEAD
+}
+
+// NewKeyring creates a Keyring from a set of AES keys indexed by key id.
+// Keys must be 16, 24 or 32 bytes long and primary must name one of them.
+func NewKeyring(primary string, keys map[string][]byte) (*Keyring, error) {
+	if _, ok := keys[primary]; !ok {
+		return nil, fmt.Errorf("primary key %q is not in the keyring", primary)
+	}
+	k := &Keyring{primary: primary, keys: make(map[string]cipher.AEAD, len(keys))}
+	for id, key := range keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("invalid key id %q", id)
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %s", id, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %s", id, err)
+		}
+		k.keys[id] = aead
+	}
+	return k, nil
+}
+
+// Primary returns the id of the key used for writes.
+func (k *Keyring) Primary() string {
+	return k.primary
+}
+
+// seal encrypts an encoded release under the primary key. A nil Keyring
+// returns data unchanged.
+func (k *Keyring) seal(data string) (string, error) {
+	if k == nil {
+		return data, nil
+	}
+	aead := k.keys[k.primary]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	ct := aead.Seal(nonce, nonce, []byte(data), []byte(k.primary))
+	return encryptedPrefix + k.primary + ":" + b64.EncodeToString(ct), nil
+}
+
+// open decrypts a payload produced by seal. Payloads without the encrypted
+// prefix are returned as-is so that plaintext records remain readable.
+func (k *Keyring) open(data string) (string, error) {
+	id, payload, ok := splitEncrypted(data)
+	if !ok {
+		return data, nil
+	}
+	if k == nil {
+		return "", fmt.Errorf("release is encrypted with key %q but no keyring is configured", id)
+	}
+	aead, found := k.keys[id]
+	if !found {
+		return "", fmt.Errorf("release is encrypted with unknown key %q", id)
+	}
+	b, err := b64.DecodeString(payload)
+	if err != nil {
+		return "", err
+	}
+	if len(b) < aead.NonceSize() {
+		return "", fmt.Errorf("encrypted release payload is truncated")
+	}
+	nonce, ct := b[:aead.NonceSize()], b[aead.NonceSize():]
+	pt, err := aead.Open(nil, nonce, ct, []byte(id))
+	if err != nil {
+		return "", fmt.Errorf("decrypting release with key %q: %s", id, err)
+	}
+	return string(pt), nil
+}
+
+// current reports whether data is already sealed with the primary key.
+func (k *Keyring) current(data string) bool {
+	id, _, ok := splitEncrypted(data)
+	if k == nil {
+		return !ok
+	}
+	return ok && id == k.primary
+}
+
+// splitEncrypted splits a sealed payload into its key id and ciphertext.
+func splitEncrypted(data string) (id, payload string, ok bool) {
+	if !strings.HasPrefix(data, encryptedPrefix) {
+		return "", "", false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(data, encryptedPrefix), ":", 2)
+	if len(parts) != 2 {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
@@ -16,6 +16,76 @@ limitations under the License.
 
 package driver
 
+import (
+	"fmt"
+
+	kblabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+)
+
+// reservedLabels are the labels the drivers set on every release record.
+var reservedLabels = map[string]bool{
+	"NAME":        true,
+	"OWNER":       true,
+	"STATUS":      true,
+	"VERSION":     true,
+	"CREATED_AT":  true,
+	"MODIFIED_AT": true,
+}
+
+// ownedSelector narrows selector to the records owned by Tiller.
+func ownedSelector(selector kblabels.Selector) (string, error) {
+	owner, err := kblabels.NewRequirement("OWNER", selection.Equals, []string{"TILLER"})
+	if err != nil {
+		return "", err
+	}
+	return selector.Add(*owner).String(), nil
+}
+
+// ValidateIndexedLabels checks that keys can be used as IndexedLabels: each
+// must be a valid label key that is not already set by the drivers.
+func ValidateIndexedLabels(keys []string) error {
+	for _, k := range keys {
+		if reservedLabels[k] {
+			return fmt.Errorf("label %q is reserved", k)
+		}
+		if errs := validation.IsQualifiedName(k); len(errs) != 0 {
+			return fmt.Errorf("invalid label %q: %s", k, errs[0])
+		}
+	}
+	return nil
+}
+
+// setIndexedLabels copies the annotations named by keys from the release's
+// chart into lbs, so that releases can be queried by them.
+func setIndexedLabels(lbs labels, rls *rspb.Release, keys []string) {
+	for k, v := range IndexedLabels(rls, keys) {
+		lbs.set(k, v)
+	}
+}
+
+// IndexedLabels returns the annotations named by keys from the release's
+// chart, as the drivers label its records with them. Annotations that are
+// missing or are not valid label values are left out.
+func IndexedLabels(rls *rspb.Release, keys []string) map[string]string {
+	lbs := map[string]string{}
+	md := rls.GetChart().GetMetadata()
+	if md == nil {
+		return lbs
+	}
+	for _, k := range keys {
+		v, ok := md.Annotations[k]
+		if !ok || len(validation.IsValidLabelValue(v)) != 0 {
+			continue
+		}
+		lbs[k] = v
+	}
+	return lbs
+}
+
 // labels is a map of key value pairs to be included as metadata in a configmap object.
 type labels map[string]string
 
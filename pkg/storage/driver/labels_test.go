@@ -47,3 +47,14 @@ func TestLabelsMatch(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateIndexedLabels(t *testing.T) {
+	if err := ValidateIndexedLabels([]string{"team", "example.com/cost-center"}); err != nil {
+		t.Errorf("Expected labels to be valid, got %s", err)
+	}
+	for _, key := range []string{"STATUS", "not a key", ""} {
+		if err := ValidateIndexedLabels([]string{key}); err == nil {
+			t.Errorf("Expected label %q to be rejected", key)
+		}
+	}
+}
@@ -21,11 +21,15 @@ import (
 	"strings"
 	"sync"
 
+	kblabels "k8s.io/apimachinery/pkg/labels"
+
 	rspb "k8s.io/helm/pkg/proto/hapi/release"
 	storageerrors "k8s.io/helm/pkg/storage/errors"
 )
 
 var _ Driver = (*Memory)(nil)
+var _ LabelLister = (*Memory)(nil)
+var _ Purger = (*Memory)(nil)
 
 // MemoryDriverName is the string name of this driver.
 const MemoryDriverName = "Memory"
@@ -109,6 +113,25 @@ func (mem *Memory) Query(keyvals map[string]string) ([]*rspb.Release, error) {
 	return ls, nil
 }
 
+// ListByLabels returns the set of releases whose labels match selector.
+func (mem *Memory) ListByLabels(selector kblabels.Selector) ([]*rspb.Release, error) {
+	defer unlock(mem.rlock())
+
+	var ls []*rspb.Release
+	for _, recs := range mem.cache {
+		recs.Iter(func(_ int, rec *record) bool {
+			if rec == nil {
+				return false
+			}
+			if selector.Matches(kblabels.Set(rec.lbs.toMap())) {
+				ls = append(ls, rec.rls)
+			}
+			return true
+		})
+	}
+	return ls, nil
+}
+
 // Create creates a new release or returns ErrReleaseExists.
 func (mem *Memory) Create(key string, rls *rspb.Release) error {
 	defer unlock(mem.wlock())
@@ -159,6 +182,22 @@ func (mem *Memory) Delete(key string) (*rspb.Release, error) {
 	return nil, storageerrors.ErrReleaseNotFound(key)
 }
 
+// DeleteAll deletes every revision of the named release.
+func (mem *Memory) DeleteAll(name string) ([]*rspb.Release, error) {
+	defer unlock(mem.wlock())
+
+	recs, ok := mem.cache[name]
+	if !ok || len(recs) == 0 {
+		return nil, storageerrors.ErrReleaseNotFound(name)
+	}
+	deleted := make([]*rspb.Release, 0, len(recs))
+	for _, rec := range recs {
+		deleted = append(deleted, rec.rls)
+	}
+	delete(mem.cache, name)
+	return deleted, nil
+}
+
 // wlock locks mem for writing
 func (mem *Memory) wlock() func() {
 	mem.Lock()
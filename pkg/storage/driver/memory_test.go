@@ -19,6 +19,7 @@ package driver
 import (
 	"fmt"
 	"reflect"
+	"sync"
 	"testing"
 
 	rspb "k8s.io/helm/pkg/proto/hapi/release"
@@ -194,3 +195,52 @@ func TestMemoryDelete(t *testing.T) {
 	}
 
 }
+
+func TestMemoryConcurrentAccess(t *testing.T) {
+	ts := NewMemory()
+
+	const workers, versions = 8, 20
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			name := fmt.Sprintf("rls-%d", w)
+			for v := int32(1); v <= versions; v++ {
+				key := testKey(name, v)
+				rls := releaseStub(name, v, "default", rspb.Status_DEPLOYED)
+				if err := ts.Create(key, rls); err != nil {
+					t.Errorf("failed to create %s: %s", key, err)
+					return
+				}
+				if _, err := ts.Get(key); err != nil {
+					t.Errorf("failed to get %s: %s", key, err)
+				}
+				if _, err := ts.Query(map[string]string{"NAME": name, "OWNER": "TILLER"}); err != nil {
+					t.Errorf("failed to query %s: %s", name, err)
+				}
+				if _, err := ts.List(func(*rspb.Release) bool { return true }); err != nil {
+					t.Errorf("failed to list: %s", err)
+				}
+				rls = releaseStub(name, v, "default", rspb.Status_SUPERSEDED)
+				if err := ts.Update(key, rls); err != nil {
+					t.Errorf("failed to update %s: %s", key, err)
+				}
+				if v%2 == 0 {
+					if _, err := ts.Delete(key); err != nil {
+						t.Errorf("failed to delete %s: %s", key, err)
+					}
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	ls, err := ts.List(func(*rspb.Release) bool { return true })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := workers * versions / 2; len(ls) != expect {
+		t.Errorf("expected %d releases, got %d", expect, len(ls))
+	}
+}
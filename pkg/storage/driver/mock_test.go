@@ -25,6 +25,7 @@ import (
 	"k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kblabels "k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 
@@ -92,6 +93,8 @@ type MockConfigMapsInterface struct {
 	corev1.ConfigMapInterface
 
 	objects map[string]*v1.ConfigMap
+	// read records the names of the objects returned by Get and List.
+	read map[string]bool
 }
 
 // Init initializes the MockConfigMapsInterface with the set of releases.
@@ -101,7 +104,7 @@ func (mock *MockConfigMapsInterface) Init(t *testing.T, releases ...*rspb.Releas
 	for _, rls := range releases {
 		objkey := testKey(rls.Name, rls.Version)
 
-		cfgmap, err := newConfigMapsObject(objkey, rls, nil)
+		cfgmap, err := newConfigMapsObject(objkey, rls, nil, DefaultCompressionLevel)
 		if err != nil {
 			t.Fatalf("Failed to create configmap: %s", err)
 		}
@@ -115,18 +118,33 @@ func (mock *MockConfigMapsInterface) Get(name string, options metav1.GetOptions)
 	if !ok {
 		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "tests"}, name)
 	}
+	mock.markRead(name)
 	return object, nil
 }
 
 // List returns the a of ConfigMaps.
 func (mock *MockConfigMapsInterface) List(opts metav1.ListOptions) (*v1.ConfigMapList, error) {
 	var list v1.ConfigMapList
+	selector, err := kblabels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
 	for _, cfgmap := range mock.objects {
-		list.Items = append(list.Items, *cfgmap)
+		if selector.Matches(kblabels.Set(cfgmap.Labels)) {
+			list.Items = append(list.Items, *cfgmap)
+			mock.markRead(cfgmap.Name)
+		}
 	}
 	return &list, nil
 }
 
+func (mock *MockConfigMapsInterface) markRead(name string) {
+	if mock.read == nil {
+		mock.read = map[string]bool{}
+	}
+	mock.read[name] = true
+}
+
 // Create creates a new ConfigMap.
 func (mock *MockConfigMapsInterface) Create(cfgmap *v1.ConfigMap) (*v1.ConfigMap, error) {
 	name := cfgmap.ObjectMeta.Name
@@ -156,6 +174,20 @@ func (mock *MockConfigMapsInterface) Delete(name string, opts *metav1.DeleteOpti
 	return nil
 }
 
+// DeleteCollection deletes the ConfigMaps matching the label selector.
+func (mock *MockConfigMapsInterface) DeleteCollection(opts *metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	selector, err := kblabels.Parse(listOpts.LabelSelector)
+	if err != nil {
+		return err
+	}
+	for name, object := range mock.objects {
+		if selector.Matches(kblabels.Set(object.Labels)) {
+			delete(mock.objects, name)
+		}
+	}
+	return nil
+}
+
 // newTestFixture initializes a MockSecretsInterface.
 // Secrets are created for each release provided.
 func newTestFixtureSecrets(t *testing.T, releases ...*rspb.Release) *Secrets {
@@ -170,6 +202,8 @@ type MockSecretsInterface struct {
 	corev1.SecretInterface
 
 	objects map[string]*v1.Secret
+	// read records the names of the objects returned by Get and List.
+	read map[string]bool
 }
 
 // Init initializes the MockSecretsInterface with the set of releases.
@@ -179,7 +213,7 @@ func (mock *MockSecretsInterface) Init(t *testing.T, releases ...*rspb.Release)
 	for _, rls := range releases {
 		objkey := testKey(rls.Name, rls.Version)
 
-		secret, err := newSecretsObject(objkey, rls, nil)
+		secret, err := newSecretsObject(objkey, rls, nil, DefaultCompressionLevel)
 		if err != nil {
 			t.Fatalf("Failed to create secret: %s", err)
 		}
@@ -193,18 +227,33 @@ func (mock *MockSecretsInterface) Get(name string, options metav1.GetOptions) (*
 	if !ok {
 		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "tests"}, name)
 	}
+	mock.markRead(name)
 	return object, nil
 }
 
 // List returns the a of Secret.
 func (mock *MockSecretsInterface) List(opts metav1.ListOptions) (*v1.SecretList, error) {
 	var list v1.SecretList
+	selector, err := kblabels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
 	for _, secret := range mock.objects {
-		list.Items = append(list.Items, *secret)
+		if selector.Matches(kblabels.Set(secret.Labels)) {
+			list.Items = append(list.Items, *secret)
+			mock.markRead(secret.Name)
+		}
 	}
 	return &list, nil
 }
 
+func (mock *MockSecretsInterface) markRead(name string) {
+	if mock.read == nil {
+		mock.read = map[string]bool{}
+	}
+	mock.read[name] = true
+}
+
 // Create creates a new Secret.
 func (mock *MockSecretsInterface) Create(secret *v1.Secret) (*v1.Secret, error) {
 	name := secret.ObjectMeta.Name
@@ -234,6 +283,20 @@ func (mock *MockSecretsInterface) Delete(name string, opts *metav1.DeleteOptions
 	return nil
 }
 
+// DeleteCollection deletes the Secrets matching the label selector.
+func (mock *MockSecretsInterface) DeleteCollection(opts *metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	selector, err := kblabels.Parse(listOpts.LabelSelector)
+	if err != nil {
+		return err
+	}
+	for name, object := range mock.objects {
+		if selector.Matches(kblabels.Set(object.Labels)) {
+			delete(mock.objects, name)
+		}
+	}
+	return nil
+}
+
 // newTestFixtureSQL mocks the SQL database (for testing purposes)
 func newTestFixtureSQL(t *testing.T, releases ...*rspb.Release) (*SQL, sqlmock.Sqlmock) {
 	sqlDB, mock, err := sqlmock.New()
@@ -243,7 +306,8 @@ func newTestFixtureSQL(t *testing.T, releases ...*rspb.Release) (*SQL, sqlmock.S
 
 	sqlxDB := sqlx.NewDb(sqlDB, "sqlmock")
 	return &SQL{
-		db:  sqlxDB,
-		Log: func(_ string, _ ...interface{}) {},
+		db:               sqlxDB,
+		Log:              func(_ string, _ ...interface{}) {},
+		CompressionLevel: DefaultCompressionLevel,
 	}, mock
 }
@@ -0,0 +1,336 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver // import "k8s.io/helm/pkg/storage/driver"
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+	storageerrors "k8s.io/helm/pkg/storage/errors"
+)
+
+var _ Driver = (*Redis)(nil)
+var _ Pinger = (*Redis)(nil)
+
+// RedisDriverName is the string name of the driver.
+const RedisDriverName = "Redis"
+
+// Redis is a wrapper around an implementation of a redis client that stores
+// releases under a key prefix.
+//
+// Each release is stored, encoded like the other drivers do, under
+// "<prefix>:<name>.v<version>". The keys of the versions of a release are
+// kept in the set "<prefix>:versions:<name>", and the names of the releases
+// in the set "<prefix>:releases".
+type Redis struct {
+	client *redis.Client
+	prefix string
+
+	// TTL, if positive, is how long superseded, deleted and failed release
+	// records are kept after they were last written. Other records, such as
+	// the deployed version of a release, never expire.
+	TTL time.Duration
+
+	// CompressionLevel is the gzip level release payloads are compressed
+	// with. NewRedis sets it to DefaultCompressionLevel.
+	CompressionLevel int
+
+	// Encrypter, if set, encrypts release payloads at rest.
+	Encrypter Encrypter
+
+	Log func(string, ...interface{})
+}
+
+// NewRedis initializes a new Redis driver storing its records under prefix.
+func NewRedis(client *redis.Client, prefix string) *Redis {
+	return &Redis{
+		client:           client,
+		prefix:           prefix,
+		CompressionLevel: DefaultCompressionLevel,
+		Log:              func(_ string, _ ...interface{}) {},
+	}
+}
+
+// Name returns the name of the driver.
+func (r *Redis) Name() string {
+	return RedisDriverName
+}
+
+// Get fetches the release named by key. The corresponding release is returned
+// or error if not found.
+func (r *Redis) Get(key string) (*rspb.Release, error) {
+	data, err := r.client.Get(r.recordKey(key)).Result()
+	if err == redis.Nil {
+		return nil, storageerrors.ErrReleaseNotFound(key)
+	}
+	if err != nil {
+		r.Log("get: failed to get %q: %s", key, err)
+		return nil, err
+	}
+	rls, err := r.decode(data)
+	if err != nil {
+		r.Log("get: failed to decode data %q: %s", key, err)
+		return nil, err
+	}
+	return rls, nil
+}
+
+// List fetches all releases and returns the list releases such
+// that filter(release) == true. An error is returned if redis
+// fails to retrieve the releases.
+func (r *Redis) List(filter func(*rspb.Release) bool) ([]*rspb.Release, error) {
+	names, err := r.client.SMembers(r.releasesKey()).Result()
+	if err != nil {
+		r.Log("list: failed to list releases: %s", err)
+		return nil, err
+	}
+
+	var results []*rspb.Release
+	for _, name := range names {
+		rels, err := r.history(name)
+		if err != nil {
+			return nil, err
+		}
+		for _, rls := range rels {
+			if filter(rls) {
+				results = append(results, rls)
+			}
+		}
+	}
+	return results, nil
+}
+
+// Query fetches all releases that match the provided map of labels. Releases
+// carry the NAME, OWNER, STATUS and VERSION labels. An error is returned if
+// redis fails to retrieve the releases.
+func (r *Redis) Query(labels map[string]string) ([]*rspb.Release, error) {
+	var (
+		candidates []*rspb.Release
+		err        error
+	)
+	if name, ok := labels["NAME"]; ok {
+		candidates, err = r.history(name)
+	} else {
+		candidates, err = r.List(func(*rspb.Release) bool { return true })
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*rspb.Release
+	for _, rls := range candidates {
+		if matchesLabels(rls, labels) {
+			results = append(results, rls)
+		}
+	}
+	if len(results) == 0 {
+		return nil, storageerrors.ErrReleaseNotFound(labels["NAME"])
+	}
+	return results, nil
+}
+
+// Ping checks that the Redis server can be reached.
+func (r *Redis) Ping() error {
+	return r.client.Ping().Err()
+}
+
+// Create creates a new release record or returns ErrReleaseExists if one is
+// already stored under key.
+func (r *Redis) Create(key string, rls *rspb.Release) error {
+	data, err := r.encode(rls)
+	if err != nil {
+		r.Log("create: failed to encode release %q: %s", rls.Name, err)
+		return err
+	}
+	created, err := r.client.SetNX(r.recordKey(key), data, r.ttl(rls)).Result()
+	if err != nil {
+		r.Log("create: failed to create: %s", err)
+		return err
+	}
+	if !created {
+		return storageerrors.ErrReleaseExists(key)
+	}
+	return r.index(key, rls.Name)
+}
+
+// Update updates the release record stored under key, or returns
+// ErrReleaseNotFound if there is none.
+func (r *Redis) Update(key string, rls *rspb.Release) error {
+	data, err := r.encode(rls)
+	if err != nil {
+		r.Log("update: failed to encode release %q: %s", rls.Name, err)
+		return err
+	}
+	updated, err := r.client.SetXX(r.recordKey(key), data, r.ttl(rls)).Result()
+	if err != nil {
+		r.Log("update: failed to update: %s", err)
+		return err
+	}
+	if !updated {
+		return storageerrors.ErrReleaseNotFound(key)
+	}
+	return r.index(key, rls.Name)
+}
+
+// Delete deletes the release record stored under key and returns it, or
+// returns ErrReleaseNotFound if there is none.
+func (r *Redis) Delete(key string) (*rspb.Release, error) {
+	rls, err := r.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	_, err = r.client.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.Del(r.recordKey(key))
+		pipe.SRem(r.versionsKey(rls.Name), key)
+		return nil
+	})
+	if err != nil {
+		r.Log("delete: failed to delete %q: %s", key, err)
+		return nil, err
+	}
+	r.forgetIfEmpty(rls.Name)
+	return rls, nil
+}
+
+// history returns the stored versions of the named release, dropping the
+// keys of records that have expired from its versions set.
+func (r *Redis) history(name string) ([]*rspb.Release, error) {
+	keys, err := r.client.SMembers(r.versionsKey(name)).Result()
+	if err != nil {
+		r.Log("history: failed to list versions of %q: %s", name, err)
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	recordKeys := make([]string, len(keys))
+	for i, key := range keys {
+		recordKeys[i] = r.recordKey(key)
+	}
+	values, err := r.client.MGet(recordKeys...).Result()
+	if err != nil {
+		r.Log("history: failed to get versions of %q: %s", name, err)
+		return nil, err
+	}
+
+	var (
+		rels    []*rspb.Release
+		expired []interface{}
+	)
+	for i, v := range values {
+		data, ok := v.(string)
+		if !ok {
+			expired = append(expired, keys[i])
+			continue
+		}
+		rls, err := r.decode(data)
+		if err != nil {
+			r.Log("history: failed to decode release %q: %s", keys[i], err)
+			continue
+		}
+		rels = append(rels, rls)
+	}
+	if len(expired) > 0 {
+		r.client.SRem(r.versionsKey(name), expired...)
+		r.forgetIfEmpty(name)
+	}
+	return rels, nil
+}
+
+// ttl returns how long the record of rls is kept. Writing a record with no
+// expiry also clears any expiry it had before.
+func (r *Redis) ttl(rls *rspb.Release) time.Duration {
+	if r.TTL <= 0 {
+		return 0
+	}
+	switch rls.Info.GetStatus().GetCode() {
+	case rspb.Status_SUPERSEDED, rspb.Status_DELETED, rspb.Status_FAILED:
+		return r.TTL
+	}
+	return 0
+}
+
+// index records key as a version of the named release. The sets never
+// expire; keys of expired records are dropped from them as they are read.
+func (r *Redis) index(key, name string) error {
+	_, err := r.client.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.SAdd(r.versionsKey(name), key)
+		pipe.SAdd(r.releasesKey(), name)
+		return nil
+	})
+	if err != nil {
+		r.Log("failed to index release %q: %s", key, err)
+	}
+	return err
+}
+
+// forgetIfEmpty removes name from the set of releases once it has no
+// versions left.
+func (r *Redis) forgetIfEmpty(name string) {
+	if n, err := r.client.SCard(r.versionsKey(name)).Result(); err == nil && n == 0 {
+		r.client.SRem(r.releasesKey(), name)
+	}
+}
+
+// encode encodes rls and encrypts it with the encrypter, if one is set.
+func (r *Redis) encode(rls *rspb.Release) (string, error) {
+	data, err := encodeRelease(rls, r.CompressionLevel)
+	if err != nil {
+		return "", err
+	}
+	return encrypt(r.Encrypter, data)
+}
+
+// decode decrypts a stored payload with the encrypter and decodes the
+// release.
+func (r *Redis) decode(data string) (*rspb.Release, error) {
+	data, err := decrypt(r.Encrypter, data)
+	if err != nil {
+		return nil, err
+	}
+	return decodeRelease(data)
+}
+
+func (r *Redis) recordKey(key string) string    { return r.prefix + ":" + key }
+func (r *Redis) versionsKey(name string) string { return r.prefix + ":versions:" + name }
+func (r *Redis) releasesKey() string            { return r.prefix + ":releases" }
+
+// matchesLabels reports whether rls has every one of labels.
+func matchesLabels(rls *rspb.Release, labels map[string]string) bool {
+	for k, v := range labels {
+		var actual string
+		switch k {
+		case "NAME":
+			actual = rls.Name
+		case "OWNER":
+			actual = "TILLER"
+		case "STATUS":
+			actual = rls.Info.Status.Code.String()
+		case "VERSION":
+			actual = strconv.Itoa(int(rls.Version))
+		default:
+			return false
+		}
+		if actual != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,209 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"bytes"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis"
+	"github.com/go-redis/redis"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+)
+
+func newTestFixtureRedis(t *testing.T, rels ...*rspb.Release) (*Redis, *miniredis.Miniredis) {
+	server, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("Failed to start redis server: %s", err)
+	}
+	r := NewRedis(redis.NewClient(&redis.Options{Addr: server.Addr()}), "helm")
+	for _, rel := range rels {
+		if err := r.Create(testKey(rel.Name, rel.Version), rel); err != nil {
+			t.Fatalf("Failed to create release: %s", err)
+		}
+	}
+	return r, server
+}
+
+func TestRedisName(t *testing.T) {
+	r, server := newTestFixtureRedis(t)
+	defer server.Close()
+	if r.Name() != RedisDriverName {
+		t.Errorf("Expected name to be %q, got %q", RedisDriverName, r.Name())
+	}
+}
+
+func TestRedisPing(t *testing.T) {
+	r, server := newTestFixtureRedis(t)
+	if err := r.Ping(); err != nil {
+		t.Fatalf("Failed to ping redis: %s", err)
+	}
+	server.Close()
+	if err := r.Ping(); err == nil {
+		t.Error("Expected an error pinging a stopped redis server")
+	}
+}
+
+func TestRedisCreateGet(t *testing.T) {
+	rel := releaseStub("smug-pigeon", 1, "default", rspb.Status_DEPLOYED)
+	key := testKey(rel.Name, rel.Version)
+	r, server := newTestFixtureRedis(t, rel)
+	defer server.Close()
+
+	data, err := server.Get("helm:" + key)
+	if err != nil {
+		t.Fatalf("Expected the release to be stored under helm:%s: %s", key, err)
+	}
+	if b, err := b64.DecodeString(data); err != nil || !bytes.HasPrefix(b, magicGzip) {
+		t.Errorf("Expected a gzipped record, got %q", data)
+	}
+	if members, _ := server.Members("helm:versions:smug-pigeon"); !reflect.DeepEqual(members, []string{key}) {
+		t.Errorf("Expected the version set to hold %s, got %v", key, members)
+	}
+
+	got, err := r.Get(key)
+	if err != nil {
+		t.Fatalf("Failed to get release: %s", err)
+	}
+	if !shallowReleaseEqual(rel, got) {
+		t.Errorf("Expected {%q}, got {%q}", rel, got)
+	}
+
+	if err := r.Create(key, rel); err == nil {
+		t.Error("Expected creating the release again to fail")
+	}
+	if _, err := r.Get(testKey("smug-pigeon", 2)); err == nil {
+		t.Error("Expected a missing release to be reported")
+	}
+}
+
+func TestRedisUpdate(t *testing.T) {
+	rel := releaseStub("smug-pigeon", 1, "default", rspb.Status_DEPLOYED)
+	key := testKey(rel.Name, rel.Version)
+	r, server := newTestFixtureRedis(t, rel)
+	defer server.Close()
+
+	rel.Info.Status.Code = rspb.Status_SUPERSEDED
+	if err := r.Update(key, rel); err != nil {
+		t.Fatalf("Failed to update release: %s", err)
+	}
+	got, err := r.Get(key)
+	if err != nil {
+		t.Fatalf("Failed to get release: %s", err)
+	}
+	if got.Info.Status.Code != rspb.Status_SUPERSEDED {
+		t.Errorf("Expected status %s, got %s", rspb.Status_SUPERSEDED, got.Info.Status.Code)
+	}
+
+	if err := r.Update(testKey("smug-pigeon", 2), rel); err == nil {
+		t.Error("Expected updating a missing release to fail")
+	}
+}
+
+func TestRedisListQueryDelete(t *testing.T) {
+	r, server := newTestFixtureRedis(t,
+		releaseStub("rls-a", 1, "default", rspb.Status_SUPERSEDED),
+		releaseStub("rls-a", 2, "default", rspb.Status_DEPLOYED),
+		releaseStub("rls-b", 1, "default", rspb.Status_DEPLOYED),
+		releaseStub("rls-c", 1, "default", rspb.Status_DELETED),
+	)
+	defer server.Close()
+
+	deployed, err := r.List(func(rel *rspb.Release) bool { return rel.Info.Status.Code == rspb.Status_DEPLOYED })
+	if err != nil {
+		t.Fatalf("Failed to list releases: %s", err)
+	}
+	if len(deployed) != 2 {
+		t.Errorf("Expected 2 deployed releases, got %d", len(deployed))
+	}
+
+	history, err := r.Query(map[string]string{"NAME": "rls-a", "OWNER": "TILLER"})
+	if err != nil {
+		t.Fatalf("Failed to query releases: %s", err)
+	}
+	var versions []int
+	for _, rel := range history {
+		versions = append(versions, int(rel.Version))
+	}
+	sort.Ints(versions)
+	if !reflect.DeepEqual(versions, []int{1, 2}) {
+		t.Errorf("Expected versions [1 2] of rls-a, got %v", versions)
+	}
+	if _, err := r.Query(map[string]string{"NAME": "rls-a", "STATUS": "DELETED"}); err == nil {
+		t.Error("Expected a query matching nothing to fail")
+	}
+
+	if _, err := r.Delete(testKey("rls-b", 1)); err != nil {
+		t.Fatalf("Failed to delete release: %s", err)
+	}
+	if _, err := r.Get(testKey("rls-b", 1)); err == nil {
+		t.Error("Expected the release to be deleted")
+	}
+	if names, _ := server.Members("helm:releases"); !reflect.DeepEqual(names, []string{"rls-a", "rls-c"}) {
+		t.Errorf("Expected rls-b to be dropped from the set of releases, got %v", names)
+	}
+}
+
+func TestRedisTTL(t *testing.T) {
+	r, server := newTestFixtureRedis(t)
+	defer server.Close()
+	r.TTL = time.Hour
+
+	old := releaseStub("smug-pigeon", 1, "default", rspb.Status_DEPLOYED)
+	if err := r.Create(testKey(old.Name, old.Version), old); err != nil {
+		t.Fatalf("Failed to create release: %s", err)
+	}
+	if ttl := server.TTL("helm:smug-pigeon.v1"); ttl != 0 {
+		t.Errorf("Expected the deployed record never to expire, got %s", ttl)
+	}
+
+	old.Info.Status.Code = rspb.Status_SUPERSEDED
+	if err := r.Update(testKey(old.Name, old.Version), old); err != nil {
+		t.Fatalf("Failed to update release: %s", err)
+	}
+	if ttl := server.TTL("helm:smug-pigeon.v1"); ttl != time.Hour {
+		t.Errorf("Expected the superseded record to expire in an hour, got %s", ttl)
+	}
+	rel := releaseStub("smug-pigeon", 2, "default", rspb.Status_DEPLOYED)
+	if err := r.Create(testKey(rel.Name, rel.Version), rel); err != nil {
+		t.Fatalf("Failed to create release: %s", err)
+	}
+	for _, key := range []string{"helm:releases", "helm:versions:smug-pigeon"} {
+		if ttl := server.TTL(key); ttl != 0 {
+			t.Errorf("Expected %s never to expire, got %s", key, ttl)
+		}
+	}
+
+	server.FastForward(2 * time.Hour)
+	if _, err := r.Get(testKey(old.Name, old.Version)); err == nil {
+		t.Error("Expected the superseded release to have expired")
+	}
+	rels, err := r.List(func(*rspb.Release) bool { return true })
+	if err != nil {
+		t.Fatalf("Failed to list releases: %s", err)
+	}
+	if len(rels) != 1 || rels[0].Version != 2 {
+		t.Errorf("Expected only the deployed release after expiry, got %v", rels)
+	}
+	if keys, _ := server.Members("helm:versions:smug-pigeon"); !reflect.DeepEqual(keys, []string{"smug-pigeon.v2"}) {
+		t.Errorf("Expected the expired version to be dropped from the versions set, got %v", keys)
+	}
+}
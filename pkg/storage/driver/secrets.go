@@ -18,6 +18,7 @@ package driver // import "k8s.io/helm/pkg/storage/driver"
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -34,6 +35,12 @@ import (
 )
 
 var _ Driver = (*Secrets)(nil)
+var _ Reencrypter = (*Secrets)(nil)
+var _ ContentGetter = (*Secrets)(nil)
+var _ LabelLister = (*Secrets)(nil)
+var _ Purger = (*Secrets)(nil)
+var _ AnnotationGetter = (*Secrets)(nil)
+var _ Pinger = (*Secrets)(nil)
 
 // SecretsDriverName is the string name of the driver.
 const SecretsDriverName = "Secret"
@@ -43,14 +50,31 @@ const SecretsDriverName = "Secret"
 type Secrets struct {
 	impl corev1.SecretInterface
 	Log  func(string, ...interface{})
+	// Keyring, if set, encrypts release payloads at rest.
+	Keyring *Keyring
+	// IndexedLabels names chart annotations that are copied to the labels
+	// of each release's Secret, so Query can select releases by them.
+	IndexedLabels []string
+	// SeparateContent stores each release's content without its chart in a
+	// Secret of its own, so GetContent can skip reading the chart.
+	SeparateContent bool
+	// Annotations are set on every Secret holding a release.
+	Annotations map[string]string
+	// Encrypter, if set, encrypts release payloads before the keyring
+	// seals them.
+	Encrypter Encrypter
+	// CompressionLevel is the gzip level release payloads are compressed
+	// with. NewSecrets sets it to DefaultCompressionLevel.
+	CompressionLevel int
 }
 
 // NewSecrets initializes a new Secrets wrapping an implementation of
 // the kubernetes SecretsInterface.
 func NewSecrets(impl corev1.SecretInterface) *Secrets {
 	return &Secrets{
-		impl: impl,
-		Log:  func(_ string, _ ...interface{}) {},
+		impl:             impl,
+		Log:              func(_ string, _ ...interface{}) {},
+		CompressionLevel: DefaultCompressionLevel,
 	}
 }
 
@@ -73,7 +97,7 @@ func (secrets *Secrets) Get(key string) (*rspb.Release, error) {
 		return nil, err
 	}
 	// found the secret, decode the base64 data string
-	r, err := decodeRelease(string(obj.Data["release"]))
+	r, err := secrets.decode(string(obj.Data["release"]))
 	if err != nil {
 		secrets.Log("get: failed to decode data %q: %s", key, err)
 		return nil, err
@@ -82,6 +106,20 @@ func (secrets *Secrets) Get(key string) (*rspb.Release, error) {
 	return r, nil
 }
 
+// GetAnnotations returns the annotations of the Secret holding the release
+// named by key.
+func (secrets *Secrets) GetAnnotations(key string) (map[string]string, error) {
+	obj, err := secrets.impl.Get(key, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, storageerrors.ErrReleaseNotFound(key)
+		}
+		secrets.Log("get annotations: failed to get %q: %s", key, err)
+		return nil, err
+	}
+	return obj.Annotations, nil
+}
+
 // List fetches all releases and returns the list releases such
 // that filter(release) == true. An error is returned if the
 // secret fails to retrieve the releases.
@@ -100,7 +138,7 @@ func (secrets *Secrets) List(filter func(*rspb.Release) bool) ([]*rspb.Release,
 	// iterate over the secrets object list
 	// and decode each release
 	for _, item := range list.Items {
-		rls, err := decodeRelease(string(item.Data["release"]))
+		rls, err := secrets.decode(string(item.Data["release"]))
 		if err != nil {
 			secrets.Log("list: failed to decode release: %v: %s", item, err)
 			continue
@@ -112,6 +150,59 @@ func (secrets *Secrets) List(filter func(*rspb.Release) bool) ([]*rspb.Release,
 	return results, nil
 }
 
+// ListRecords fetches every release, along with the error for each secret,
+// by name, whose release cannot be decoded.
+func (secrets *Secrets) ListRecords() ([]*rspb.Release, map[string]error, error) {
+	lsel := kblabels.Set{"OWNER": "TILLER"}.AsSelector()
+	opts := metav1.ListOptions{LabelSelector: lsel.String()}
+
+	list, err := secrets.impl.List(opts)
+	if err != nil {
+		secrets.Log("list records: failed to list: %s", err)
+		return nil, nil, err
+	}
+
+	var results []*rspb.Release
+	failed := map[string]error{}
+	for _, item := range list.Items {
+		rls, err := secrets.decode(string(item.Data["release"]))
+		if err != nil {
+			failed[item.Name] = err
+			continue
+		}
+		results = append(results, rls)
+	}
+	return results, failed, nil
+}
+
+// ListByLabels fetches the releases whose labels match selector, leaving
+// the selection to the API server. An error is returned if the secret
+// fails to retrieve the releases.
+func (secrets *Secrets) ListByLabels(selector kblabels.Selector) ([]*rspb.Release, error) {
+	lsel, err := ownedSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	opts := metav1.ListOptions{LabelSelector: lsel}
+
+	list, err := secrets.impl.List(opts)
+	if err != nil {
+		secrets.Log("list by labels: failed to list: %s", err)
+		return nil, err
+	}
+
+	var results []*rspb.Release
+	for _, item := range list.Items {
+		rls, err := secrets.decode(string(item.Data["release"]))
+		if err != nil {
+			secrets.Log("list by labels: failed to decode release: %s: %s", item.Name, err)
+			continue
+		}
+		results = append(results, rls)
+	}
+	return results, nil
+}
+
 // Query fetches all releases that match the provided map of labels.
 // An error is returned if the secret fails to retrieve the releases.
 func (secrets *Secrets) Query(labels map[string]string) ([]*rspb.Release, error) {
@@ -137,7 +228,7 @@ func (secrets *Secrets) Query(labels map[string]string) ([]*rspb.Release, error)
 
 	var results []*rspb.Release
 	for _, item := range list.Items {
-		rls, err := decodeRelease(string(item.Data["release"]))
+		rls, err := secrets.decode(string(item.Data["release"]))
 		if err != nil {
 			secrets.Log("query: failed to decode release: %s", err)
 			continue
@@ -147,6 +238,112 @@ func (secrets *Secrets) Query(labels map[string]string) ([]*rspb.Release, error)
 	return results, nil
 }
 
+// GetContent fetches a revision of the named release without its chart,
+// from the Secret holding its content. Revisions written without separate
+// content are read from their records, decoded in full and stripped.
+func (secrets *Secrets) GetContent(name string, version int32) (*rspb.Release, error) {
+	opts := metav1.ListOptions{LabelSelector: revisionSelector(contentOwner, name, version)}
+	contents, err := secrets.impl.List(opts)
+	if err != nil {
+		secrets.Log("get content: failed to list content of %q: %s", name, err)
+		return nil, err
+	}
+	if i := latestSecret(contents.Items); i >= 0 {
+		item := contents.Items[i]
+		if version <= 0 {
+			// Records newer than the latest content were written while
+			// separate content was disabled.
+			v, _ := strconv.Atoi(item.Labels["VERSION"])
+			newer, err := secrets.impl.List(metav1.ListOptions{LabelSelector: newerSelector(name, int32(v))})
+			if err != nil {
+				secrets.Log("get content: failed to list %q: %s", name, err)
+				return nil, err
+			}
+			if len(newer.Items) > 0 {
+				return secrets.recordContent(name, newer.Items)
+			}
+		}
+		rls, err := secrets.decode(string(item.Data[contentKey]))
+		if err == nil {
+			return rls, nil
+		}
+		secrets.Log("get content: failed to decode content %q, reading the release instead: %s", item.Name, err)
+	}
+
+	opts = metav1.ListOptions{LabelSelector: revisionSelector("TILLER", name, version)}
+	records, err := secrets.impl.List(opts)
+	if err != nil {
+		secrets.Log("get content: failed to list %q: %s", name, err)
+		return nil, err
+	}
+	return secrets.recordContent(name, records.Items)
+}
+
+// recordContent decodes the latest of the release records in items and
+// strips its chart.
+func (secrets *Secrets) recordContent(name string, items []v1.Secret) (*rspb.Release, error) {
+	i := latestSecret(items)
+	if i < 0 {
+		return nil, storageerrors.ErrReleaseNotFound(name)
+	}
+	rls, err := secrets.decode(string(items[i].Data["release"]))
+	if err != nil {
+		secrets.Log("get content: failed to decode data %q: %s", items[i].Name, err)
+		return nil, err
+	}
+	return StripChart(rls), nil
+}
+
+// latestSecret returns the index of the Secret holding the latest revision,
+// or -1 if there are none.
+func latestSecret(items []v1.Secret) int {
+	lbs := make([]map[string]string, len(items))
+	for i, item := range items {
+		lbs[i] = item.Labels
+	}
+	return latestRevision(lbs)
+}
+
+// writeContent creates or updates the Secret holding the content of the
+// release record named key.
+func (secrets *Secrets) writeContent(key string, rls *rspb.Release) error {
+	c, err := encodeReleaseContent(rls)
+	if err != nil {
+		return err
+	}
+	if c, err = secrets.seal(c); err != nil {
+		return err
+	}
+	obj := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   contentName(key),
+			Labels: contentLabels(rls),
+		},
+		Data: map[string][]byte{contentKey: []byte(c)},
+	}
+	setAnnotations(&obj.ObjectMeta, secrets.Annotations)
+	if _, err = secrets.impl.Update(obj); apierrors.IsNotFound(err) {
+		_, err = secrets.impl.Create(obj)
+	}
+	return err
+}
+
+// deleteContent deletes the Secret holding the content of the release
+// record named key, if there is one.
+func (secrets *Secrets) deleteContent(key string) error {
+	if err := secrets.impl.Delete(contentName(key), &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// Ping lists at most one release Secret to check that the API server can be
+// reached.
+func (secrets *Secrets) Ping() error {
+	_, err := secrets.impl.List(metav1.ListOptions{LabelSelector: "OWNER=TILLER", Limit: 1})
+	return err
+}
+
 // Create creates a new Secret holding the release. If the
 // Secret already exists, ErrReleaseExists is returned.
 func (secrets *Secrets) Create(key string, rls *rspb.Release) error {
@@ -155,13 +352,15 @@ func (secrets *Secrets) Create(key string, rls *rspb.Release) error {
 
 	lbs.init()
 	lbs.set("CREATED_AT", strconv.Itoa(int(time.Now().Unix())))
+	setIndexedLabels(lbs, rls, secrets.IndexedLabels)
 
 	// create a new secret to hold the release
-	obj, err := newSecretsObject(key, rls, lbs)
+	obj, err := secrets.newObject(key, rls, lbs)
 	if err != nil {
 		secrets.Log("create: failed to encode release %q: %s", rls.Name, err)
 		return err
 	}
+	setAnnotations(&obj.ObjectMeta, secrets.Annotations)
 	// push the secret object out into the kubiverse
 	if _, err := secrets.impl.Create(obj); err != nil {
 		if apierrors.IsAlreadyExists(err) {
@@ -171,6 +370,16 @@ func (secrets *Secrets) Create(key string, rls *rspb.Release) error {
 		secrets.Log("create: failed to create: %s", err)
 		return err
 	}
+	if secrets.SeparateContent {
+		if err := secrets.writeContent(key, rls); err != nil {
+			secrets.Log("create: failed to create content of release %q: %s", rls.Name, err)
+			// Leave no record behind whose content would be missing.
+			if err := secrets.impl.Delete(key, &metav1.DeleteOptions{}); err != nil {
+				secrets.Log("create: failed to delete %q: %s", key, err)
+			}
+			return err
+		}
+	}
 	return nil
 }
 
@@ -182,19 +391,27 @@ func (secrets *Secrets) Update(key string, rls *rspb.Release) error {
 
 	lbs.init()
 	lbs.set("MODIFIED_AT", strconv.Itoa(int(time.Now().Unix())))
+	setIndexedLabels(lbs, rls, secrets.IndexedLabels)
 
 	// create a new secret object to hold the release
-	obj, err := newSecretsObject(key, rls, lbs)
+	obj, err := secrets.newObject(key, rls, lbs)
 	if err != nil {
 		secrets.Log("update: failed to encode release %q: %s", rls.Name, err)
 		return err
 	}
+	setAnnotations(&obj.ObjectMeta, secrets.Annotations)
 	// push the secret object out into the kubiverse
 	_, err = secrets.impl.Update(obj)
 	if err != nil {
 		secrets.Log("update: failed to update: %s", err)
 		return err
 	}
+	if secrets.SeparateContent {
+		if err := secrets.writeContent(key, rls); err != nil {
+			secrets.Log("update: failed to update content of release %q: %s", rls.Name, err)
+			return err
+		}
+	}
 	return nil
 }
 
@@ -213,9 +430,184 @@ func (secrets *Secrets) Delete(key string) (rls *rspb.Release, err error) {
 	if err = secrets.impl.Delete(key, &metav1.DeleteOptions{}); err != nil {
 		return rls, err
 	}
+	if err = secrets.deleteContent(key); err != nil {
+		secrets.Log("delete: failed to delete content of %q: %s", key, err)
+		return rls, err
+	}
 	return rls, nil
 }
 
+// DeleteAll deletes the secrets holding every revision of the named
+// release, and those holding their content, with one collection deletion
+// each. Revisions that cannot be decoded are deleted as well, but are not
+// returned. The API server deletes the items of a collection one by one, so
+// a failed call may leave some revisions behind; as their content is deleted
+// first, retrying DeleteAll finishes the purge.
+func (secrets *Secrets) DeleteAll(name string) ([]*rspb.Release, error) {
+	opts := metav1.ListOptions{LabelSelector: revisionSelector("TILLER", name, 0)}
+
+	list, err := secrets.impl.List(opts)
+	if err != nil {
+		secrets.Log("delete all: failed to list %q: %s", name, err)
+		return nil, err
+	}
+	if len(list.Items) == 0 {
+		return nil, storageerrors.ErrReleaseNotFound(name)
+	}
+
+	var deleted []*rspb.Release
+	for _, item := range list.Items {
+		rls, err := secrets.decode(string(item.Data["release"]))
+		if err != nil {
+			secrets.Log("delete all: failed to decode release %q: %s", item.Name, err)
+			continue
+		}
+		deleted = append(deleted, rls)
+	}
+
+	content := metav1.ListOptions{LabelSelector: revisionSelector(contentOwner, name, 0)}
+	if err := secrets.impl.DeleteCollection(&metav1.DeleteOptions{}, content); err != nil {
+		secrets.Log("delete all: failed to delete content of %q: %s", name, err)
+		return nil, err
+	}
+	if err := secrets.impl.DeleteCollection(&metav1.DeleteOptions{}, opts); err != nil {
+		secrets.Log("delete all: failed to delete %q: %s", name, err)
+		return nil, err
+	}
+	return deleted, nil
+}
+
+// StaleReleases returns the names of the releases with records, or content,
+// that are not sealed with the primary key of the keyring, sorted.
+func (secrets *Secrets) StaleReleases() ([]string, error) {
+	opts := metav1.ListOptions{LabelSelector: sealedSelector("")}
+
+	list, err := secrets.impl.List(opts)
+	if err != nil {
+		secrets.Log("stale releases: failed to list: %s", err)
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	var names []string
+	for _, item := range list.Items {
+		name := item.Labels["NAME"]
+		if seen[name] || secrets.Keyring.current(string(item.Data[sealedKey(item.Labels)])) {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Reencrypt rewrites the records, and content, of the named release that are
+// not sealed with the primary key of the keyring. Records that fail to decode
+// are logged and skipped.
+func (secrets *Secrets) Reencrypt(name string) (int, error) {
+	opts := metav1.ListOptions{LabelSelector: sealedSelector(name)}
+
+	list, err := secrets.impl.List(opts)
+	if err != nil {
+		secrets.Log("reencrypt: failed to list %q: %s", name, err)
+		return 0, err
+	}
+
+	var n int
+	for i := range list.Items {
+		item := &list.Items[i]
+		key := sealedKey(item.Labels)
+		data := string(item.Data[key])
+		if secrets.Keyring.current(data) {
+			continue
+		}
+		rls, err := secrets.decode(data)
+		if err != nil {
+			secrets.Log("reencrypt: failed to decode release %q: %s", item.Name, err)
+			continue
+		}
+		var s string
+		if key == contentKey {
+			s, err = encodeReleaseContent(rls)
+		} else {
+			s, err = encodeRelease(rls, secrets.CompressionLevel)
+		}
+		if err != nil {
+			return n, err
+		}
+		if s, err = secrets.seal(s); err != nil {
+			return n, err
+		}
+		item.Data[key] = []byte(s)
+		if _, err := secrets.impl.Update(item); err != nil {
+			secrets.Log("reencrypt: failed to update %q: %s", item.Name, err)
+			return n, err
+		}
+		if key != contentKey {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// sealedSelector selects the records and content of the named release, or of
+// every release if name is empty.
+func sealedSelector(name string) string {
+	s := fmt.Sprintf("OWNER in (TILLER,%s)", contentOwner)
+	if name != "" {
+		s = "NAME=" + name + "," + s
+	}
+	return s
+}
+
+// sealedKey returns the data key of the sealed payload of a Secret with the
+// given labels.
+func sealedKey(lbs map[string]string) string {
+	if lbs["OWNER"] == contentOwner {
+		return contentKey
+	}
+	return "release"
+}
+
+// decode opens a stored payload with the keyring, decrypts it with the
+// encrypter and decodes the release.
+func (secrets *Secrets) decode(data string) (*rspb.Release, error) {
+	s, err := secrets.Keyring.open(data)
+	if err != nil {
+		return nil, err
+	}
+	if s, err = decrypt(secrets.Encrypter, s); err != nil {
+		return nil, err
+	}
+	return decodeRelease(s)
+}
+
+// seal encrypts an encoded payload with the encrypter and the keyring, with
+// whichever of them are configured.
+func (secrets *Secrets) seal(data string) (string, error) {
+	s, err := encrypt(secrets.Encrypter, data)
+	if err != nil {
+		return "", err
+	}
+	return secrets.Keyring.seal(s)
+}
+
+// newObject builds the secret for a release, sealing the payload when
+// encryption is configured.
+func (secrets *Secrets) newObject(key string, rls *rspb.Release, lbs labels) (*v1.Secret, error) {
+	obj, err := newSecretsObject(key, rls, lbs, secrets.CompressionLevel)
+	if err != nil {
+		return nil, err
+	}
+	s, err := secrets.seal(string(obj.Data["release"]))
+	if err != nil {
+		return nil, err
+	}
+	obj.Data["release"] = []byte(s)
+	return obj, nil
+}
+
 // newSecretsObject constructs a kubernetes Secret object
 // to store a release. Each secret data entry is the base64
 // encoded string of a release's binary protobuf encoding.
@@ -229,11 +621,11 @@ func (secrets *Secrets) Delete(key string) (rls *rspb.Release, err error) {
 //    "OWNER"          - owner of the secret, currently "TILLER".
 //    "NAME"           - name of the release.
 //
-func newSecretsObject(key string, rls *rspb.Release, lbs labels) (*v1.Secret, error) {
+func newSecretsObject(key string, rls *rspb.Release, lbs labels, level int) (*v1.Secret, error) {
 	const owner = "TILLER"
 
 	// encode the release
-	s, err := encodeRelease(rls)
+	s, err := encodeRelease(rls, level)
 	if err != nil {
 		return nil, err
 	}
@@ -15,11 +15,14 @@ package driver
 
 import (
 	"encoding/base64"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/gogo/protobuf/proto"
 	"k8s.io/api/core/v1"
 
+	"k8s.io/helm/pkg/proto/hapi/chart"
 	rspb "k8s.io/helm/pkg/proto/hapi/release"
 )
 
@@ -58,7 +61,7 @@ func TestUNcompressedSecretGet(t *testing.T) {
 	rel := releaseStub(name, vers, namespace, rspb.Status_DEPLOYED)
 
 	// Create a test fixture which contains an uncompressed release
-	secret, err := newSecretsObject(key, rel, nil)
+	secret, err := newSecretsObject(key, rel, nil, DefaultCompressionLevel)
 	if err != nil {
 		t.Fatalf("Failed to create secret: %s", err)
 	}
@@ -155,6 +158,40 @@ func TestSecretCreate(t *testing.T) {
 	}
 }
 
+func TestSecretDeleteAll(t *testing.T) {
+	secrets := newTestFixtureSecrets(t, releaseStub("smug-pigeon", 1, "default", rspb.Status_SUPERSEDED))
+	secrets.SeparateContent = true
+	for _, rls := range []*rspb.Release{
+		releaseStub("smug-pigeon", 2, "default", rspb.Status_DEPLOYED),
+		releaseStub("angry-beaver", 1, "default", rspb.Status_DEPLOYED),
+	} {
+		if err := secrets.Create(testKey(rls.Name, rls.Version), rls); err != nil {
+			t.Fatalf("Failed to create release: %s", err)
+		}
+	}
+
+	deleted, err := secrets.DeleteAll("smug-pigeon")
+	if err != nil {
+		t.Fatalf("Failed to delete all revisions: %s", err)
+	}
+	if len(deleted) != 2 {
+		t.Errorf("Expected 2 deleted revisions, got %d", len(deleted))
+	}
+	mock := secrets.impl.(*MockSecretsInterface)
+	for name := range mock.objects {
+		if strings.HasPrefix(name, "smug-pigeon") {
+			t.Errorf("Expected %s to be deleted", name)
+		}
+	}
+	if _, err := secrets.Get(testKey("angry-beaver", 1)); err != nil {
+		t.Errorf("Expected other releases to be kept: %s", err)
+	}
+
+	if _, err := secrets.DeleteAll("smug-pigeon"); err == nil {
+		t.Error("Expected an error deleting a release without revisions")
+	}
+}
+
 func TestSecretUpdate(t *testing.T) {
 	vers := int32(1)
 	name := "smug-pigeon"
@@ -183,3 +220,121 @@ func TestSecretUpdate(t *testing.T) {
 		t.Errorf("Expected status %s, got status %s", rel.Info.Status.Code, got.Info.Status.Code)
 	}
 }
+
+func TestSecretKeyringRotation(t *testing.T) {
+	oldKey := []byte("0123456789abcdef0123456789abcdef")
+	newKey := []byte("fedcba9876543210fedcba9876543210")
+
+	oldRing, err := NewKeyring("k1", map[string][]byte{"k1": oldKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mock MockSecretsInterface
+	mock.objects = map[string]*v1.Secret{}
+	secrets := NewSecrets(&mock)
+	secrets.Keyring = oldRing
+
+	rel := releaseStub("smug-pigeon", 1, "default", rspb.Status_DEPLOYED)
+	key := testKey(rel.Name, rel.Version)
+	if err := secrets.Create(key, rel); err != nil {
+		t.Fatalf("Failed to create release: %s", err)
+	}
+	if data := string(mock.objects[key].Data["release"]); !strings.HasPrefix(data, "enc:k1:") {
+		t.Fatalf("Expected payload sealed with k1, got %q", data)
+	}
+
+	// rotate: k2 becomes primary, k1 is kept for reads
+	secrets.Keyring, err = NewKeyring("k2", map[string][]byte{"k1": oldKey, "k2": newKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := secrets.Get(key)
+	if err != nil {
+		t.Fatalf("Failed to get release sealed with old key: %s", err)
+	}
+	if !shallowReleaseEqual(rel, got) {
+		t.Errorf("Expected {%q}, got {%q}", rel, got)
+	}
+
+	if names, err := secrets.StaleReleases(); err != nil || !reflect.DeepEqual(names, []string{"smug-pigeon"}) {
+		t.Errorf("Expected smug-pigeon to need re-encrypting, got %v, %v", names, err)
+	}
+	n, err := secrets.Reencrypt("smug-pigeon")
+	if err != nil {
+		t.Fatalf("Failed to re-encrypt: %s", err)
+	}
+	if n != 1 {
+		t.Errorf("Expected 1 release re-encrypted, got %d", n)
+	}
+	if data := string(mock.objects[key].Data["release"]); !strings.HasPrefix(data, "enc:k2:") {
+		t.Fatalf("Expected payload sealed with k2, got %q", data)
+	}
+	if n, _ := secrets.Reencrypt("smug-pigeon"); n != 0 {
+		t.Errorf("Expected nothing left to re-encrypt, got %d", n)
+	}
+	if names, _ := secrets.StaleReleases(); len(names) != 0 {
+		t.Errorf("Expected no releases left to re-encrypt, got %v", names)
+	}
+
+	// the old key can now be dropped
+	secrets.Keyring, err = NewKeyring("k2", map[string][]byte{"k2": newKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := secrets.Get(key); err != nil {
+		t.Fatalf("Failed to get re-encrypted release: %s", err)
+	}
+}
+
+func TestSecretGetContent(t *testing.T) {
+	ring, err := NewKeyring("k1", map[string][]byte{"k1": []byte("0123456789abcdef0123456789abcdef")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mock MockSecretsInterface
+	mock.objects = map[string]*v1.Secret{}
+	secrets := NewSecrets(&mock)
+	secrets.Keyring = ring
+	secrets.SeparateContent = true
+
+	rel := releaseStub("smug-pigeon", 1, "default", rspb.Status_DEPLOYED)
+	rel.Manifest = "kind: ConfigMap"
+	rel.Chart = &chart.Chart{
+		Metadata:  &chart.Metadata{Name: "app"},
+		Templates: []*chart.Template{{Name: "templates/cm.yaml", Data: []byte(rel.Manifest)}},
+	}
+	key := testKey(rel.Name, rel.Version)
+	if err := secrets.Create(key, rel); err != nil {
+		t.Fatalf("Failed to create release: %s", err)
+	}
+	if data := string(mock.objects[contentName(key)].Data[contentKey]); !strings.HasPrefix(data, "enc:k1:") {
+		t.Fatalf("Expected content sealed with k1, got %q", data)
+	}
+
+	mock.read = nil
+	got, err := secrets.GetContent(rel.Name, 0)
+	if err != nil {
+		t.Fatalf("Failed to get content: %s", err)
+	}
+	if got.Manifest != rel.Manifest {
+		t.Errorf("Expected manifest %q, got %q", rel.Manifest, got.Manifest)
+	}
+	if got.Chart.Metadata.Name != "app" || len(got.Chart.Templates) != 0 {
+		t.Errorf("Expected chart metadata only, got %v", got.Chart)
+	}
+	if mock.read[key] {
+		t.Errorf("Expected getting the content not to read %s, which holds the chart", key)
+	}
+
+	// Content sealed with a rotated key is re-encrypted with the release.
+	secrets.Keyring, err = NewKeyring("k2", map[string][]byte{"k1": []byte("0123456789abcdef0123456789abcdef"), "k2": []byte("fedcba9876543210fedcba9876543210")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := secrets.Reencrypt(rel.Name); err != nil || n != 1 {
+		t.Fatalf("Expected 1 release re-encrypted, got %d, %v", n, err)
+	}
+	if data := string(mock.objects[contentName(key)].Data[contentKey]); !strings.HasPrefix(data, "enc:k2:") {
+		t.Errorf("Expected content sealed with k2, got %q", data)
+	}
+}
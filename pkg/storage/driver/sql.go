@@ -33,6 +33,8 @@ import (
 )
 
 var _ Driver = (*SQL)(nil)
+var _ Purger = (*SQL)(nil)
+var _ Pinger = (*SQL)(nil)
 
 var labelMap = map[string]string{
 	"MODIFIED_AT": "modified_at",
@@ -54,6 +56,11 @@ const SQLDriverName = "SQL"
 type SQL struct {
 	db  *sqlx.DB
 	Log func(string, ...interface{})
+	// CompressionLevel is the gzip level release payloads are compressed
+	// with. NewSQL sets it to DefaultCompressionLevel.
+	CompressionLevel int
+	// Encrypter, if set, encrypts release payloads at rest.
+	Encrypter Encrypter
 }
 
 // Name returns the name of the driver.
@@ -133,8 +140,9 @@ func NewSQL(dialect, connectionString string, logger func(string, ...interface{}
 	}
 
 	driver := &SQL{
-		db:  db,
-		Log: logger,
+		db:               db,
+		Log:              logger,
+		CompressionLevel: DefaultCompressionLevel,
 	}
 
 	if err := driver.ensureDBSetup(); err != nil {
@@ -154,7 +162,7 @@ func (s *SQL) Get(key string) (*rspb.Release, error) {
 		return nil, storageerrors.ErrReleaseNotFound(key)
 	}
 
-	release, err := decodeRelease(record.Body)
+	release, err := s.decode(record.Body)
 	if err != nil {
 		s.Log("get: failed to decode data %q: %v", key, err)
 		return nil, err
@@ -173,7 +181,7 @@ func (s *SQL) List(filter func(*rspb.Release) bool) ([]*rspb.Release, error) {
 
 	var releases []*rspb.Release
 	for _, record := range records {
-		release, err := decodeRelease(record.Body)
+		release, err := s.decode(record.Body)
 		if err != nil {
 			s.Log("list: failed to decode release: %v: %v", record, err)
 			continue
@@ -225,7 +233,7 @@ func (s *SQL) Query(labels map[string]string) ([]*rspb.Release, error) {
 			return nil, err
 		}
 
-		release, err := decodeRelease(record.Body)
+		release, err := s.decode(record.Body)
 		if err != nil {
 			s.Log("failed to decode release: %v", err)
 			continue
@@ -240,9 +248,14 @@ func (s *SQL) Query(labels map[string]string) ([]*rspb.Release, error) {
 	return releases, nil
 }
 
+// Ping checks that the database can be reached.
+func (s *SQL) Ping() error {
+	return s.db.Ping()
+}
+
 // Create creates a new release.
 func (s *SQL) Create(key string, rls *rspb.Release) error {
-	body, err := encodeRelease(rls)
+	body, err := s.encode(rls)
 	if err != nil {
 		s.Log("failed to encode release: %v", err)
 		return err
@@ -283,7 +296,7 @@ func (s *SQL) Create(key string, rls *rspb.Release) error {
 
 // Update updates a release.
 func (s *SQL) Update(key string, rls *rspb.Release) error {
-	body, err := encodeRelease(rls)
+	body, err := s.encode(rls)
 	if err != nil {
 		s.Log("failed to encode release: %v", err)
 		return err
@@ -323,7 +336,7 @@ func (s *SQL) Delete(key string) (*rspb.Release, error) {
 		return nil, storageerrors.ErrReleaseNotFound(key)
 	}
 
-	release, err := decodeRelease(record.Body)
+	release, err := s.decode(record.Body)
 	if err != nil {
 		s.Log("failed to decode release %s: %v", key, err)
 		transaction.Rollback()
@@ -334,3 +347,63 @@ func (s *SQL) Delete(key string) (*rspb.Release, error) {
 	_, err = transaction.Exec("DELETE FROM releases WHERE key = $1", key)
 	return release, err
 }
+
+// DeleteAll deletes every revision of the named release in a single
+// transaction, or returns ErrReleaseNotFound if there are none.
+func (s *SQL) DeleteAll(name string) ([]*rspb.Release, error) {
+	transaction, err := s.db.Beginx()
+	if err != nil {
+		s.Log("failed to start SQL transaction: %v", err)
+		return nil, fmt.Errorf("error beginning transaction: %v", err)
+	}
+
+	var records []SQLReleaseWrapper
+	if err := transaction.Select(&records, "SELECT body FROM releases WHERE name = $1 AND owner = $2", name, "TILLER"); err != nil {
+		s.Log("failed to query release %s: %v", name, err)
+		transaction.Rollback()
+		return nil, err
+	}
+	if len(records) == 0 {
+		transaction.Rollback()
+		return nil, storageerrors.ErrReleaseNotFound(name)
+	}
+
+	deleted := make([]*rspb.Release, 0, len(records))
+	for _, record := range records {
+		release, err := s.decode(record.Body)
+		if err != nil {
+			s.Log("failed to decode release %s: %v", name, err)
+			continue
+		}
+		deleted = append(deleted, release)
+	}
+
+	if _, err := transaction.Exec("DELETE FROM releases WHERE name = $1 AND owner = $2", name, "TILLER"); err != nil {
+		s.Log("failed to delete release %s: %v", name, err)
+		transaction.Rollback()
+		return nil, err
+	}
+	if err := transaction.Commit(); err != nil {
+		return nil, err
+	}
+	return deleted, nil
+}
+
+// encode encodes rls and encrypts it with the encrypter, if one is set.
+func (s *SQL) encode(rls *rspb.Release) (string, error) {
+	body, err := encodeRelease(rls, s.CompressionLevel)
+	if err != nil {
+		return "", err
+	}
+	return encrypt(s.Encrypter, body)
+}
+
+// decode decrypts a stored payload with the encrypter and decodes the
+// release.
+func (s *SQL) decode(body string) (*rspb.Release, error) {
+	body, err := decrypt(s.Encrypter, body)
+	if err != nil {
+		return nil, err
+	}
+	return decodeRelease(body)
+}
@@ -40,7 +40,7 @@ func TestSQLGet(t *testing.T) {
 	key := testKey(name, vers)
 	rel := releaseStub(name, vers, namespace, rspb.Status_DEPLOYED)
 
-	body, err := encodeRelease(rel)
+	body, err := encodeRelease(rel, DefaultCompressionLevel)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -72,12 +72,12 @@ func TestSQLGet(t *testing.T) {
 }
 
 func TestSQLList(t *testing.T) {
-	body1, _ := encodeRelease(releaseStub("key-1", 1, "default", rspb.Status_DELETED))
-	body2, _ := encodeRelease(releaseStub("key-2", 1, "default", rspb.Status_DELETED))
-	body3, _ := encodeRelease(releaseStub("key-3", 1, "default", rspb.Status_DEPLOYED))
-	body4, _ := encodeRelease(releaseStub("key-4", 1, "default", rspb.Status_DEPLOYED))
-	body5, _ := encodeRelease(releaseStub("key-5", 1, "default", rspb.Status_SUPERSEDED))
-	body6, _ := encodeRelease(releaseStub("key-6", 1, "default", rspb.Status_SUPERSEDED))
+	body1, _ := encodeRelease(releaseStub("key-1", 1, "default", rspb.Status_DELETED), DefaultCompressionLevel)
+	body2, _ := encodeRelease(releaseStub("key-2", 1, "default", rspb.Status_DELETED), DefaultCompressionLevel)
+	body3, _ := encodeRelease(releaseStub("key-3", 1, "default", rspb.Status_DEPLOYED), DefaultCompressionLevel)
+	body4, _ := encodeRelease(releaseStub("key-4", 1, "default", rspb.Status_DEPLOYED), DefaultCompressionLevel)
+	body5, _ := encodeRelease(releaseStub("key-5", 1, "default", rspb.Status_SUPERSEDED), DefaultCompressionLevel)
+	body6, _ := encodeRelease(releaseStub("key-6", 1, "default", rspb.Status_SUPERSEDED), DefaultCompressionLevel)
 
 	sqlDriver, mock := newTestFixtureSQL(t)
 
@@ -146,7 +146,7 @@ func TestSqlCreate(t *testing.T) {
 	rel := releaseStub(name, vers, namespace, rspb.Status_DEPLOYED)
 
 	sqlDriver, mock := newTestFixtureSQL(t)
-	body, _ := encodeRelease(rel)
+	body, _ := encodeRelease(rel, DefaultCompressionLevel)
 
 	mock.ExpectBegin()
 	mock.
@@ -172,7 +172,7 @@ func TestSqlCreateAlreadyExists(t *testing.T) {
 	rel := releaseStub(name, vers, namespace, rspb.Status_DEPLOYED)
 
 	sqlDriver, mock := newTestFixtureSQL(t)
-	body, _ := encodeRelease(rel)
+	body, _ := encodeRelease(rel, DefaultCompressionLevel)
 
 	// Insert fails (primary key already exists)
 	mock.ExpectBegin()
@@ -211,7 +211,7 @@ func TestSqlUpdate(t *testing.T) {
 	rel := releaseStub(name, vers, namespace, rspb.Status_DEPLOYED)
 
 	sqlDriver, mock := newTestFixtureSQL(t)
-	body, _ := encodeRelease(rel)
+	body, _ := encodeRelease(rel, DefaultCompressionLevel)
 
 	mock.
 		ExpectExec(regexp.QuoteMeta("UPDATE releases SET body=?, name=?, version=?, status=?, owner=?, modified_at=? WHERE key=?")).
@@ -240,9 +240,9 @@ func TestSqlQuery(t *testing.T) {
 	}
 
 	supersededRelease := releaseStub("smug-pigeon", 1, "default", rspb.Status_SUPERSEDED)
-	supersededReleaseBody, _ := encodeRelease(supersededRelease)
+	supersededReleaseBody, _ := encodeRelease(supersededRelease, DefaultCompressionLevel)
 	deployedRelease := releaseStub("smug-pigeon", 2, "default", rspb.Status_DEPLOYED)
-	deployedReleaseBody, _ := encodeRelease(deployedRelease)
+	deployedReleaseBody, _ := encodeRelease(deployedRelease, DefaultCompressionLevel)
 
 	// Let's actually start our test
 	sqlDriver, mock := newTestFixtureSQL(t)
@@ -309,7 +309,7 @@ func TestSqlDelete(t *testing.T) {
 	key := testKey(name, vers)
 	rel := releaseStub(name, vers, namespace, rspb.Status_DEPLOYED)
 
-	body, _ := encodeRelease(rel)
+	body, _ := encodeRelease(rel, DefaultCompressionLevel)
 
 	sqlDriver, mock := newTestFixtureSQL(t)
 
@@ -344,3 +344,44 @@ func TestSqlDelete(t *testing.T) {
 		t.Errorf("sql expectations weren't met: %v", err)
 	}
 }
+
+func TestSqlDeleteAll(t *testing.T) {
+	name := "smug-pigeon"
+	rel1 := releaseStub(name, 1, "default", rspb.Status_SUPERSEDED)
+	rel2 := releaseStub(name, 2, "default", rspb.Status_DEPLOYED)
+	body1, _ := encodeRelease(rel1, DefaultCompressionLevel)
+	body2, _ := encodeRelease(rel2, DefaultCompressionLevel)
+
+	sqlDriver, mock := newTestFixtureSQL(t)
+
+	mock.ExpectBegin()
+	mock.
+		ExpectQuery(regexp.QuoteMeta("SELECT body FROM releases WHERE name = $1 AND owner = $2")).
+		WithArgs(name, "TILLER").
+		WillReturnRows(
+			mock.NewRows([]string{
+				"body",
+			}).AddRow(
+				body1,
+			).AddRow(
+				body2,
+			),
+		).RowsWillBeClosed()
+	mock.
+		ExpectExec(regexp.QuoteMeta("DELETE FROM releases WHERE name = $1 AND owner = $2")).
+		WithArgs(name, "TILLER").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	deleted, err := sqlDriver.DeleteAll(name)
+	if err != nil {
+		t.Fatalf("failed to delete all revisions of %q: %v", name, err)
+	}
+	if len(deleted) != 2 || !shallowReleaseEqual(rel1, deleted[0]) || !shallowReleaseEqual(rel2, deleted[1]) {
+		t.Errorf("Expected revisions 1 and 2 of %q, got %v", name, deleted)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("sql expectations weren't met: %v", err)
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"compress/gzip"
 	"encoding/base64"
 	"io/ioutil"
+	"strings"
 
 	"github.com/golang/protobuf/proto"
 	rspb "k8s.io/helm/pkg/proto/hapi/release"
@@ -30,15 +31,38 @@ var b64 = base64.StdEncoding
 
 var magicGzip = []byte{0x1f, 0x8b, 0x08}
 
-// encodeRelease encodes a release returning a base64 encoded
-// gzipped binary protobuf encoding representation, or error.
-func encodeRelease(rls *rspb.Release) (string, error) {
+// Encoded releases start with a header naming the format of the base64
+// payload that follows. The headers contain a character outside the base64
+// alphabet, so they cannot be confused with records written before headers
+// were introduced.
+const (
+	// headerGzip marks a gzipped binary protobuf encoding.
+	headerGzip = "gz:"
+	// headerProto marks an uncompressed binary protobuf encoding.
+	headerProto = "pb:"
+)
+
+// WriteFormatHeaders makes encodeRelease prefix the releases it encodes with
+// a format header. Tiller versions from before the headers cannot read such
+// records, so this is off by default; turning it on is a one-way migration
+// unless the records are rewritten before downgrading Tiller.
+var WriteFormatHeaders = false
+
+// DefaultCompressionLevel is the gzip level release payloads are compressed
+// with unless a driver is configured otherwise.
+const DefaultCompressionLevel = gzip.BestCompression
+
+// encodeRelease encodes a release returning a base64 encoded gzipped binary
+// protobuf encoding representation, preceded by a format header if
+// WriteFormatHeaders is set, or error. level is a compress/gzip compression
+// level.
+func encodeRelease(rls *rspb.Release, level int) (string, error) {
 	b, err := proto.Marshal(rls)
 	if err != nil {
 		return "", err
 	}
 	var buf bytes.Buffer
-	w, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	w, err := gzip.NewWriterLevel(&buf, level)
 	if err != nil {
 		return "", err
 	}
@@ -47,35 +71,62 @@ func encodeRelease(rls *rspb.Release) (string, error) {
 	}
 	w.Close()
 
-	return b64.EncodeToString(buf.Bytes()), nil
+	if !WriteFormatHeaders {
+		return b64.EncodeToString(buf.Bytes()), nil
+	}
+	return headerGzip + b64.EncodeToString(buf.Bytes()), nil
 }
 
 // decodeRelease decodes the bytes in data into a release
 // type. Data must contain a base64 encoded string of a
-// valid protobuf encoding of a release, otherwise
-// an error is returned.
+// valid protobuf encoding of a release, optionally preceded
+// by a format header, otherwise an error is returned.
 func decodeRelease(data string) (*rspb.Release, error) {
-	// base64 decode string
-	b, err := b64.DecodeString(data)
-	if err != nil {
-		return nil, err
-	}
-
-	// For backwards compatibility with releases that were stored before
-	// compression was introduced we skip decompression if the
-	// gzip magic header is not found
-	if bytes.Equal(b[0:3], magicGzip) {
-		r, err := gzip.NewReader(bytes.NewReader(b))
+	switch {
+	case strings.HasPrefix(data, headerGzip):
+		b, err := b64.DecodeString(strings.TrimPrefix(data, headerGzip))
 		if err != nil {
 			return nil, err
 		}
-		b2, err := ioutil.ReadAll(r)
+		if b, err = gunzip(b); err != nil {
+			return nil, err
+		}
+		return unmarshalRelease(b)
+	case strings.HasPrefix(data, headerProto):
+		b, err := b64.DecodeString(strings.TrimPrefix(data, headerProto))
 		if err != nil {
 			return nil, err
 		}
-		b = b2
+		return unmarshalRelease(b)
 	}
 
+	// Records written before format headers were introduced are either
+	// gzipped or, if older still, plain protobuf. Those that look gzipped
+	// but fail to decompress or decode are retried as plain protobuf.
+	b, err := b64.DecodeString(data)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(b, magicGzip) {
+		if b2, err := gunzip(b); err == nil {
+			if rls, err := unmarshalRelease(b2); err == nil {
+				return rls, nil
+			}
+		}
+	}
+	return unmarshalRelease(b)
+}
+
+func gunzip(b []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+func unmarshalRelease(b []byte) (*rspb.Release, error) {
 	var rls rspb.Release
 	// unmarshal protobuf bytes
 	if err := proto.Unmarshal(b, &rls); err != nil {
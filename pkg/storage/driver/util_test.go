@@ -0,0 +1,140 @@
+/*
+Copyright The Helm Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+)
+
+func TestEncodeReleaseHeader(t *testing.T) {
+	rel := releaseStub("smug-pigeon", 1, "default", rspb.Status_DEPLOYED)
+
+	// older Tillers must be able to read what is written by default
+	data, err := encodeRelease(rel, DefaultCompressionLevel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(data, ":") {
+		t.Fatalf("Expected a legacy encoded release without a header, got %q", data[:10])
+	}
+	if b, err := base64.StdEncoding.DecodeString(data); err != nil || !bytes.HasPrefix(b, magicGzip) {
+		t.Fatalf("Expected base64 encoded gzip, got %q", data[:10])
+	}
+
+	defer func() { WriteFormatHeaders = false }()
+	WriteFormatHeaders = true
+	data, err = encodeRelease(rel, DefaultCompressionLevel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(data, headerGzip) {
+		t.Fatalf("Expected encoded release to start with %q, got %q", headerGzip, data[:10])
+	}
+	got, err := decodeRelease(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !shallowReleaseEqual(rel, got) {
+		t.Errorf("Expected {%q}, got {%q}", rel, got)
+	}
+}
+
+func TestEncodeReleaseCompressionLevel(t *testing.T) {
+	rel := releaseStub("smug-pigeon", 1, "default", rspb.Status_DEPLOYED)
+	for _, level := range []int{gzip.HuffmanOnly, gzip.DefaultCompression, gzip.NoCompression, gzip.BestSpeed, gzip.BestCompression} {
+		data, err := encodeRelease(rel, level)
+		if err != nil {
+			t.Fatalf("level %d: %s", level, err)
+		}
+		got, err := decodeRelease(data)
+		if err != nil {
+			t.Fatalf("level %d: %s", level, err)
+		}
+		if !shallowReleaseEqual(rel, got) {
+			t.Errorf("level %d: expected {%q}, got {%q}", level, rel, got)
+		}
+	}
+	if _, err := encodeRelease(rel, gzip.BestCompression+1); err == nil {
+		t.Error("Expected an error for an invalid compression level")
+	}
+}
+
+func TestDecodeReleaseFormats(t *testing.T) {
+	gz := func(b []byte) []byte {
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		w.Write(b)
+		w.Close()
+		return buf.Bytes()
+	}
+	marshal := func(rls *rspb.Release) []byte {
+		b, err := proto.Marshal(rls)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	b64 := base64.StdEncoding.EncodeToString
+
+	stub := releaseStub("smug-pigeon", 3, "default", rspb.Status_DEPLOYED)
+	tiny := &rspb.Release{Version: 7}
+
+	tests := []struct {
+		name    string
+		data    string
+		version int32
+		wantErr bool
+	}{
+		{"gzip header", headerGzip + b64(gz(marshal(stub))), 3, false},
+		{"proto header", headerProto + b64(marshal(stub)), 3, false},
+		{"legacy gzip", b64(gz(marshal(stub))), 3, false},
+		{"legacy uncompressed", b64(marshal(stub)), 3, false},
+		// shorter than the gzip magic, which used to panic the autodetect
+		{"legacy two byte release", b64(marshal(tiny)), 7, false},
+		{"legacy empty release", "", 0, false},
+		// an unknown fixed32 field whose tag encodes as "pbAB", which
+		// resembles a header but has no separator
+		{"legacy uncompressed starting with pb", b64(append([]byte{0xa5, 0xb0, 0x01, 0, 0, 0, 0}, marshal(stub)...)), 3, false},
+		// an explicit header is never second guessed
+		{"gzip header on plain proto", headerGzip + b64(marshal(stub)), 0, true},
+		{"proto header on gzip", headerProto + b64(gz(marshal(stub))), 0, true},
+		{"legacy gzip magic without gzip stream", b64(append([]byte{0x1f, 0x8b, 0x08}, marshal(stub)...)), 0, true},
+		{"not base64", "%%%", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rls, err := decodeRelease(tt.data)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, got release %v", rls)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if rls.Version != tt.version {
+				t.Errorf("Expected version %d, got %d", tt.version, rls.Version)
+			}
+		})
+	}
+}
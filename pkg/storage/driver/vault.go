@@ -0,0 +1,146 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver // import "k8s.io/helm/pkg/storage/driver"
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// vaultPrefix starts every ciphertext produced by Vault's transit engine,
+// which is stored as is: "vault:v<key-version>:<base64 ciphertext>".
+const vaultPrefix = "vault:"
+
+// Encrypter encrypts encoded release payloads before a driver stores them,
+// and decrypts them when they are read back.
+type Encrypter interface {
+	Encrypt(data string) (string, error)
+	Decrypt(data string) (string, error)
+}
+
+var _ Encrypter = (*VaultTransit)(nil)
+
+// VaultTransit is an Encrypter that has HashiCorp Vault's transit secrets
+// engine encrypt and decrypt release payloads, so the encryption key never
+// leaves Vault.
+type VaultTransit struct {
+	addr   string
+	key    string
+	token  string
+	client *http.Client
+}
+
+// NewVaultTransit creates an Encrypter using the transit key named key of the
+// Vault server at addr, authenticating with token.
+func NewVaultTransit(addr, key, token string) *VaultTransit {
+	return &VaultTransit{
+		addr:   strings.TrimSuffix(addr, "/"),
+		key:    key,
+		token:  token,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Encrypt encrypts data with the transit key.
+func (v *VaultTransit) Encrypt(data string) (string, error) {
+	var resp struct {
+		Ciphertext string `json:"ciphertext"`
+	}
+	err := v.call("encrypt", map[string]string{"plaintext": b64.EncodeToString([]byte(data))}, &resp)
+	if err != nil {
+		return "", err
+	}
+	return resp.Ciphertext, nil
+}
+
+// Decrypt decrypts a payload produced by Encrypt. Payloads Vault did not
+// encrypt are returned as-is so that plaintext records remain readable.
+func (v *VaultTransit) Decrypt(data string) (string, error) {
+	if !strings.HasPrefix(data, vaultPrefix) {
+		return data, nil
+	}
+	var resp struct {
+		Plaintext string `json:"plaintext"`
+	}
+	if err := v.call("decrypt", map[string]string{"ciphertext": data}, &resp); err != nil {
+		return "", err
+	}
+	b, err := b64.DecodeString(resp.Plaintext)
+	if err != nil {
+		return "", fmt.Errorf("vault returned an invalid plaintext: %s", err)
+	}
+	return string(b), nil
+}
+
+// call posts req to the transit endpoint for op and decodes the data of the
+// response into out.
+func (v *VaultTransit) call(op string, req interface{}, out interface{}) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	u := fmt.Sprintf("%s/v1/transit/%s/%s", v.addr, op, url.PathEscape(v.key))
+	r, err := http.NewRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("X-Vault-Token", v.token)
+
+	resp, err := v.client.Do(r)
+	if err != nil {
+		return fmt.Errorf("vault transit %s: %s", op, err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []string        `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("vault transit %s: %s: %s", op, resp.Status, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("vault transit %s: %s: %s", op, resp.Status, strings.Join(result.Errors, "; "))
+	}
+	return json.Unmarshal(result.Data, out)
+}
+
+// encrypt encrypts data with e. A nil Encrypter returns data unchanged.
+func encrypt(e Encrypter, data string) (string, error) {
+	if e == nil {
+		return data, nil
+	}
+	return e.Encrypt(data)
+}
+
+// decrypt decrypts data with e, failing on payloads Vault encrypted if no
+// Encrypter is configured.
+func decrypt(e Encrypter, data string) (string, error) {
+	if e == nil {
+		if strings.HasPrefix(data, vaultPrefix) {
+			return "", fmt.Errorf("release is encrypted with Vault transit but no encrypter is configured")
+		}
+		return data, nil
+	}
+	return e.Decrypt(data)
+}
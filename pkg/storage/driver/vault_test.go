@@ -0,0 +1,214 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"k8s.io/api/core/v1"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+)
+
+// newTransitServer fakes the encrypt and decrypt endpoints of Vault's transit
+// engine for the key "helm", "encrypting" by reversing the plaintext.
+func newTransitServer(t *testing.T) *httptest.Server {
+	reverse := func(s string) string {
+		b := []byte(s)
+		for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+			b[i], b[j] = b[j], b[i]
+		}
+		return string(b)
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "s.token" {
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(map[string][]string{"errors": {"permission denied"}})
+			return
+		}
+		var req map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Failed to decode request: %s", err)
+		}
+		var data map[string]string
+		switch r.URL.Path {
+		case "/v1/transit/encrypt/helm":
+			data = map[string]string{"ciphertext": "vault:v1:" + reverse(req["plaintext"])}
+		case "/v1/transit/decrypt/helm":
+			data = map[string]string{"plaintext": reverse(strings.TrimPrefix(req["ciphertext"], "vault:v1:"))}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string][]string{"errors": {}})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}))
+}
+
+func TestConfigMapVaultTransit(t *testing.T) {
+	srv := newTransitServer(t)
+	defer srv.Close()
+
+	var mock MockConfigMapsInterface
+	mock.objects = map[string]*v1.ConfigMap{}
+	cfgmaps := NewConfigMaps(&mock)
+	cfgmaps.SeparateContent = true
+	cfgmaps.Encrypter = NewVaultTransit(srv.URL+"/", "helm", "s.token")
+
+	rel := releaseStub("smug-pigeon", 1, "default", rspb.Status_DEPLOYED)
+	key := testKey(rel.Name, rel.Version)
+	if err := cfgmaps.Create(key, rel); err != nil {
+		t.Fatalf("Failed to create release: %s", err)
+	}
+	for _, name := range []string{key, contentName(key)} {
+		for k, data := range mock.objects[name].Data {
+			if !strings.HasPrefix(data, "vault:v1:") {
+				t.Errorf("Expected %s payload of %s encrypted by vault, got %q", k, name, data)
+			}
+		}
+	}
+
+	got, err := cfgmaps.Get(key)
+	if err != nil {
+		t.Fatalf("Failed to get release: %s", err)
+	}
+	if !shallowReleaseEqual(rel, got) {
+		t.Errorf("Expected {%q}, got {%q}", rel, got)
+	}
+	if _, err := cfgmaps.GetContent(rel.Name, rel.Version); err != nil {
+		t.Errorf("Failed to get release content: %s", err)
+	}
+
+	cfgmaps.Encrypter = nil
+	if _, err := cfgmaps.Get(key); err == nil || !strings.Contains(err.Error(), "no encrypter is configured") {
+		t.Errorf("Expected an error reading an encrypted release without an encrypter, got %v", err)
+	}
+
+	cfgmaps.Encrypter = NewVaultTransit(srv.URL, "helm", "s.wrong")
+	if _, err := cfgmaps.Get(key); err == nil || !strings.Contains(err.Error(), "permission denied") {
+		t.Errorf("Expected the error returned by vault, got %v", err)
+	}
+}
+
+func TestRedisVaultTransit(t *testing.T) {
+	srv := newTransitServer(t)
+	defer srv.Close()
+
+	r, server := newTestFixtureRedis(t)
+	defer server.Close()
+	r.Encrypter = NewVaultTransit(srv.URL, "helm", "s.token")
+
+	rel := releaseStub("smug-pigeon", 1, "default", rspb.Status_DEPLOYED)
+	key := testKey(rel.Name, rel.Version)
+	if err := r.Create(key, rel); err != nil {
+		t.Fatalf("Failed to create release: %s", err)
+	}
+	if data, _ := server.Get("helm:" + key); !strings.HasPrefix(data, "vault:v1:") {
+		t.Errorf("Expected the payload encrypted by vault, got %q", data)
+	}
+
+	got, err := r.Get(key)
+	if err != nil {
+		t.Fatalf("Failed to get release: %s", err)
+	}
+	if !shallowReleaseEqual(rel, got) {
+		t.Errorf("Expected {%q}, got {%q}", rel, got)
+	}
+
+	r.Encrypter = nil
+	if _, err := r.Get(key); err == nil || !strings.Contains(err.Error(), "no encrypter is configured") {
+		t.Errorf("Expected an error reading an encrypted release without an encrypter, got %v", err)
+	}
+}
+
+func TestSQLVaultTransit(t *testing.T) {
+	srv := newTransitServer(t)
+	defer srv.Close()
+
+	rel := releaseStub("smug-pigeon", 1, "default", rspb.Status_DEPLOYED)
+	key := testKey(rel.Name, rel.Version)
+	sqlDriver, mock := newTestFixtureSQL(t)
+	sqlDriver.Encrypter = NewVaultTransit(srv.URL, "helm", "s.token")
+
+	body, err := sqlDriver.encode(rel)
+	if err != nil {
+		t.Fatalf("Failed to encode release: %s", err)
+	}
+	if !strings.HasPrefix(body, "vault:v1:") {
+		t.Errorf("Expected the payload encrypted by vault, got %q", body)
+	}
+	mock.
+		ExpectQuery("SELECT body FROM releases WHERE key = ?").
+		WithArgs(key).
+		WillReturnRows(mock.NewRows([]string{"body"}).AddRow(body)).
+		RowsWillBeClosed()
+
+	got, err := sqlDriver.Get(key)
+	if err != nil {
+		t.Fatalf("Failed to get release: %s", err)
+	}
+	if !shallowReleaseEqual(rel, got) {
+		t.Errorf("Expected {%q}, got {%q}", rel, got)
+	}
+}
+
+func TestSecretVaultTransitWithKeyring(t *testing.T) {
+	srv := newTransitServer(t)
+	defer srv.Close()
+
+	ring, err := NewKeyring("k1", map[string][]byte{"k1": []byte("0123456789abcdef0123456789abcdef")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mock MockSecretsInterface
+	mock.objects = map[string]*v1.Secret{}
+	secrets := NewSecrets(&mock)
+	secrets.Encrypter = NewVaultTransit(srv.URL, "helm", "s.token")
+
+	rel := releaseStub("smug-pigeon", 1, "default", rspb.Status_DEPLOYED)
+	key := testKey(rel.Name, rel.Version)
+	if err := secrets.Create(key, rel); err != nil {
+		t.Fatalf("Failed to create release: %s", err)
+	}
+	if data := string(mock.objects[key].Data["release"]); !strings.HasPrefix(data, "vault:v1:") {
+		t.Fatalf("Expected payload encrypted by vault, got %q", data)
+	}
+
+	// re-encrypting under a keyring keeps the vault encryption underneath
+	secrets.Keyring = ring
+	if n, err := secrets.Reencrypt("smug-pigeon"); err != nil || n != 1 {
+		t.Fatalf("Expected 1 release re-encrypted, got %d, %v", n, err)
+	}
+	inner, err := ring.open(string(mock.objects[key].Data["release"]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(inner, "vault:v1:") {
+		t.Errorf("Expected sealed payload encrypted by vault, got %q", inner)
+	}
+	got, err := secrets.Get(key)
+	if err != nil {
+		t.Fatalf("Failed to get release: %s", err)
+	}
+	if !shallowReleaseEqual(rel, got) {
+		t.Errorf("Expected {%q}, got {%q}", rel, got)
+	}
+}
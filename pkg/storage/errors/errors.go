@@ -19,9 +19,22 @@ import "fmt"
 
 var (
 	// ErrReleaseNotFound indicates that a release is not found.
-	ErrReleaseNotFound = func(release string) error { return fmt.Errorf("release: %q not found", release) }
+	ErrReleaseNotFound = func(release string) error { return releaseNotFoundError(release) }
 	// ErrReleaseExists indicates that a release already exists.
 	ErrReleaseExists = func(release string) error { return fmt.Errorf("release: %q already exists", release) }
 	// ErrInvalidKey indicates that a release key could not be parsed.
 	ErrInvalidKey = func(release string) error { return fmt.Errorf("release: %q invalid key", release) }
 )
+
+// releaseNotFoundError is the error returned by ErrReleaseNotFound.
+type releaseNotFoundError string
+
+func (e releaseNotFoundError) Error() string {
+	return fmt.Sprintf("release: %q not found", string(e))
+}
+
+// IsReleaseNotFound reports whether err was returned by ErrReleaseNotFound.
+func IsReleaseNotFound(err error) bool {
+	_, ok := err.(releaseNotFoundError)
+	return ok
+}
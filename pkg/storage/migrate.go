@@ -0,0 +1,86 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage // import "k8s.io/helm/pkg/storage"
+
+import (
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+	relutil "k8s.io/helm/pkg/releaseutil"
+	"k8s.io/helm/pkg/storage/driver"
+	storageerrors "k8s.io/helm/pkg/storage/errors"
+)
+
+// MigrationResult counts the release records handled by MigrateFrom.
+type MigrationResult struct {
+	// Migrated is the number of records written to the storage.
+	Migrated int
+	// Skipped is the number of records the storage already held.
+	Skipped int
+	// Failed is the number of records that could not be written.
+	Failed int
+}
+
+// MigrateFrom copies every release record stored by src into the storage's
+// driver, keeping each record as is so that names, versions, statuses and
+// timestamps are preserved. Records already present in the storage are
+// skipped, so an interrupted migration can simply be run again. An error is
+// only returned if the releases of src cannot be listed; records that cannot
+// be decoded, or fail to be written, are logged and counted as failed.
+func (s *Storage) MigrateFrom(src driver.Driver) (MigrationResult, error) {
+	var res MigrationResult
+
+	var rels []*rspb.Release
+	if rl, ok := src.(driver.RecordLister); ok {
+		var (
+			failed map[string]error
+			err    error
+		)
+		if rels, failed, err = rl.ListRecords(); err != nil {
+			return res, err
+		}
+		for key, err := range failed {
+			s.Log("migrate: failed to read release %q: %s", key, err)
+			res.Failed++
+		}
+	} else {
+		var err error
+		if rels, err = src.List(func(*rspb.Release) bool { return true }); err != nil {
+			return res, err
+		}
+	}
+	relutil.SortByRevision(rels)
+
+	for _, rls := range rels {
+		key := makeKey(rls.Name, rls.Version)
+		_, err := s.Driver.Get(key)
+		if err == nil {
+			res.Skipped++
+			continue
+		}
+		if !storageerrors.IsReleaseNotFound(err) {
+			s.Log("migrate: failed to check for release %q: %s", key, err)
+			res.Failed++
+			continue
+		}
+		if err := s.Driver.Create(key, rls); err != nil {
+			s.Log("migrate: failed to write release %q: %s", key, err)
+			res.Failed++
+			continue
+		}
+		res.Migrated++
+	}
+	return res, nil
+}
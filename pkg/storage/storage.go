@@ -18,11 +18,15 @@ package storage // import "k8s.io/helm/pkg/storage"
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
+	kblabels "k8s.io/apimachinery/pkg/labels"
+
 	rspb "k8s.io/helm/pkg/proto/hapi/release"
 	relutil "k8s.io/helm/pkg/releaseutil"
 	"k8s.io/helm/pkg/storage/driver"
+	storageerrors "k8s.io/helm/pkg/storage/errors"
 )
 
 // NoReleasesErr indicates that a given release cannot be found
@@ -37,7 +41,14 @@ type Storage struct {
 	// ignored (meaning no limits are imposed).
 	MaxHistory int
 
+	// IndexedLabels are the chart annotations the driver copies to the labels
+	// of release records. ListByLabels matches them too when it selects
+	// releases in memory.
+	IndexedLabels []string
+
 	Log func(string, ...interface{})
+
+	cache *releaseCache
 }
 
 // Get retrieves the release from storage. An error is returned
@@ -48,6 +59,48 @@ func (s *Storage) Get(name string, version int32) (*rspb.Release, error) {
 	return s.Driver.Get(makeKey(name, version))
 }
 
+// GetContent retrieves a revision of the release, or its latest revision if
+// version is 0, with the chart reduced to its metadata. Drivers that store
+// content separately serve it without decoding the chart.
+func (s *Storage) GetContent(name string, version int32) (*rspb.Release, error) {
+	s.Log("getting content of release %q", makeKey(name, version))
+	if cg, ok := s.Driver.(driver.ContentGetter); ok {
+		return cg.GetContent(name, version)
+	}
+
+	var (
+		rls *rspb.Release
+		err error
+	)
+	if version <= 0 {
+		rls, err = s.Last(name)
+	} else {
+		rls, err = s.Get(name, version)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return driver.StripChart(rls), nil
+}
+
+// GetAnnotations returns the annotations of the record holding a revision of
+// the release. Drivers whose records carry no annotations return none.
+func (s *Storage) GetAnnotations(name string, version int32) (map[string]string, error) {
+	if ag, ok := s.Driver.(driver.AnnotationGetter); ok {
+		return ag.GetAnnotations(makeKey(name, version))
+	}
+	return nil, nil
+}
+
+// Ping checks that the storage backend can be reached. Drivers that keep
+// releases in process, and so are always reachable, succeed.
+func (s *Storage) Ping() error {
+	if p, ok := s.Driver.(driver.Pinger); ok {
+		return p.Ping()
+	}
+	return nil
+}
+
 // Create creates a new storage entry holding the release. An
 // error is returned if the storage driver failed to store the
 // release, or a release with identical key already exists.
@@ -57,6 +110,7 @@ func (s *Storage) Create(rls *rspb.Release) error {
 		// Want to make space for one more release.
 		s.removeLeastRecent(rls.Name, s.MaxHistory-1)
 	}
+	defer s.cache.invalidate()
 	return s.Driver.Create(makeKey(rls.Name, rls.Version), rls)
 }
 
@@ -65,6 +119,7 @@ func (s *Storage) Create(rls *rspb.Release) error {
 // does not exist.
 func (s *Storage) Update(rls *rspb.Release) error {
 	s.Log("updating release %q", makeKey(rls.Name, rls.Version))
+	defer s.cache.invalidate()
 	return s.Driver.Update(makeKey(rls.Name, rls.Version), rls)
 }
 
@@ -73,21 +128,49 @@ func (s *Storage) Update(rls *rspb.Release) error {
 // does not exist.
 func (s *Storage) Delete(name string, version int32) (*rspb.Release, error) {
 	s.Log("deleting release %q", makeKey(name, version))
+	defer s.cache.invalidate()
 	return s.Driver.Delete(makeKey(name, version))
 }
 
+// DeleteAll deletes every revision of the named release and returns the
+// deleted revisions. Drivers that cannot do so in one operation have the
+// revisions deleted one by one, stopping at the first failure.
+func (s *Storage) DeleteAll(name string) ([]*rspb.Release, error) {
+	s.Log("deleting all revisions of release %q", name)
+	defer s.cache.invalidate()
+	if p, ok := s.Driver.(driver.Purger); ok {
+		return p.DeleteAll(name)
+	}
+
+	h, err := s.History(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(h) == 0 {
+		return nil, storageerrors.ErrReleaseNotFound(name)
+	}
+	deleted := make([]*rspb.Release, 0, len(h))
+	for _, rls := range h {
+		if _, err := s.Driver.Delete(makeKey(rls.Name, rls.Version)); err != nil {
+			return deleted, err
+		}
+		deleted = append(deleted, rls)
+	}
+	return deleted, nil
+}
+
 // ListReleases returns all releases from storage. An error is returned if the
 // storage backend fails to retrieve the releases.
 func (s *Storage) ListReleases() ([]*rspb.Release, error) {
 	s.Log("listing all releases in storage")
-	return s.Driver.List(func(_ *rspb.Release) bool { return true })
+	return s.list(func(_ *rspb.Release) bool { return true })
 }
 
 // ListDeleted returns all releases with Status == DELETED. An error is returned
 // if the storage backend fails to retrieve the releases.
 func (s *Storage) ListDeleted() ([]*rspb.Release, error) {
 	s.Log("listing deleted releases in storage")
-	return s.Driver.List(func(rls *rspb.Release) bool {
+	return s.list(func(rls *rspb.Release) bool {
 		return relutil.StatusFilter(rspb.Status_DELETED).Check(rls)
 	})
 }
@@ -96,17 +179,43 @@ func (s *Storage) ListDeleted() ([]*rspb.Release, error) {
 // if the storage backend fails to retrieve the releases.
 func (s *Storage) ListDeployed() ([]*rspb.Release, error) {
 	s.Log("listing all deployed releases in storage")
-	return s.Driver.List(func(rls *rspb.Release) bool {
+	return s.list(func(rls *rspb.Release) bool {
 		return relutil.StatusFilter(rspb.Status_DEPLOYED).Check(rls)
 	})
 }
 
+// ListByLabels returns the releases whose NAME, OWNER, STATUS and VERSION
+// labels match selector. Drivers that can select by label do so in the
+// backend; for the others, and when the cache is enabled, releases are
+// matched in memory.
+func (s *Storage) ListByLabels(selector kblabels.Selector) ([]*rspb.Release, error) {
+	s.Log("listing releases matching %q", selector)
+	if ll, ok := s.Driver.(driver.LabelLister); ok && s.cache == nil {
+		return ll.ListByLabels(selector)
+	}
+	return s.list(func(rls *rspb.Release) bool {
+		return selector.Matches(s.releaseLabels(rls))
+	})
+}
+
+// releaseLabels returns the labels the drivers record for rls.
+func (s *Storage) releaseLabels(rls *rspb.Release) kblabels.Set {
+	ls := kblabels.Set(driver.IndexedLabels(rls, s.IndexedLabels))
+	ls["NAME"] = rls.Name
+	ls["OWNER"] = "TILLER"
+	ls["VERSION"] = strconv.Itoa(int(rls.Version))
+	if rls.Info != nil && rls.Info.Status != nil {
+		ls["STATUS"] = rls.Info.Status.Code.String()
+	}
+	return ls
+}
+
 // ListFilterAll returns the set of releases satisfying the predicate
 // (filter0 && filter1 && ... && filterN), i.e. a Release is included in the results
 // if and only if all filters return true.
 func (s *Storage) ListFilterAll(fns ...relutil.FilterFunc) ([]*rspb.Release, error) {
 	s.Log("listing all releases with filter")
-	return s.Driver.List(func(rls *rspb.Release) bool {
+	return s.list(func(rls *rspb.Release) bool {
 		return relutil.All(fns...).Check(rls)
 	})
 }
@@ -116,7 +225,7 @@ func (s *Storage) ListFilterAll(fns ...relutil.FilterFunc) ([]*rspb.Release, err
 // if at least one of the filters returns true.
 func (s *Storage) ListFilterAny(fns ...relutil.FilterFunc) ([]*rspb.Release, error) {
 	s.Log("listing any releases with filter")
-	return s.Driver.List(func(rls *rspb.Release) bool {
+	return s.list(func(rls *rspb.Release) bool {
 		return relutil.Any(fns...).Check(rls)
 	})
 }
@@ -252,6 +361,12 @@ func (s *Storage) Last(name string) (*rspb.Release, error) {
 	return h[0], nil
 }
 
+// ReleaseKey returns the key a release revision is stored under. The
+// Kubernetes backed drivers use it as the name of the record object.
+func ReleaseKey(rlsname string, version int32) string {
+	return makeKey(rlsname, version)
+}
+
 // makeKey concatenates a release name and version into
 // a string with format ```<release_name>#v<version>```.
 // This key is used to uniquely identify storage objects.
@@ -19,8 +19,13 @@ package storage // import "k8s.io/helm/pkg/storage"
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/golang/protobuf/ptypes/timestamp"
+	kblabels "k8s.io/apimachinery/pkg/labels"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
 	rspb "k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/storage/driver"
 )
@@ -165,6 +170,104 @@ func TestStorageList(t *testing.T) {
 	}
 }
 
+func TestStorageListByLabels(t *testing.T) {
+	mem := driver.NewMemory()
+	counting := &listCountingDriver{Driver: mem}
+	for _, rls := range []*rspb.Release{
+		ReleaseTestData{Name: "happy-catdog", Version: 1, Status: rspb.Status_SUPERSEDED}.ToRelease(),
+		ReleaseTestData{Name: "happy-catdog", Version: 2, Status: rspb.Status_DEPLOYED}.ToRelease(),
+		ReleaseTestData{Name: "livid-human", Version: 1, Status: rspb.Status_FAILED}.ToRelease(),
+		ReleaseTestData{Name: "opulent-frog", Version: 1, Status: rspb.Status_DELETED}.ToRelease(),
+	} {
+		assertErrNil(t.Fatal, mem.Create(makeKey(rls.Name, rls.Version), rls), "Storing release")
+	}
+
+	selector, err := kblabels.Parse("STATUS in (DEPLOYED,FAILED),VERSION!=2")
+	assertErrNil(t.Fatal, err, "Parsing selector")
+
+	// The memory driver selects by label itself, a driver that does not is
+	// listed in full and matched here; both must agree.
+	for _, storage := range []*Storage{Init(mem), Init(counting)} {
+		list, err := storage.ListByLabels(selector)
+		assertErrNil(t.Fatal, err, "ListByLabels")
+		if len(list) != 1 || list[0].Name != "livid-human" {
+			t.Errorf("%s: expected only livid-human, got %v", storage.Name(), list)
+		}
+	}
+	if counting.lists != 1 {
+		t.Errorf("Expected the driver without label selection to be listed once, got %d", counting.lists)
+	}
+}
+
+func TestStorageListByLabels_IndexedLabels(t *testing.T) {
+	counting := &listCountingDriver{Driver: driver.NewMemory()}
+	storage := Init(counting)
+	storage.IndexedLabels = []string{"team"}
+	for i, team := range []string{"payments", "search"} {
+		rls := ReleaseTestData{Name: team, Version: 1, Status: rspb.Status_DEPLOYED}.ToRelease()
+		rls.Chart = &chart.Chart{Metadata: &chart.Metadata{Annotations: map[string]string{"team": team}}}
+		assertErrNil(t.Fatal, counting.Create(makeKey(rls.Name, rls.Version), rls), fmt.Sprintf("Storing release %d", i))
+	}
+
+	selector, err := kblabels.Parse("STATUS=DEPLOYED,team=search")
+	assertErrNil(t.Fatal, err, "Parsing selector")
+	list, err := storage.ListByLabels(selector)
+	assertErrNil(t.Fatal, err, "ListByLabels")
+	if len(list) != 1 || list[0].Name != "search" {
+		t.Errorf("Expected the indexed label to be matched in memory, got %v", list)
+	}
+}
+
+// listCountingDriver counts the calls to List of the driver it wraps.
+type listCountingDriver struct {
+	driver.Driver
+	lists int
+}
+
+func (d *listCountingDriver) List(filter func(*rspb.Release) bool) ([]*rspb.Release, error) {
+	d.lists++
+	return d.Driver.List(filter)
+}
+
+func TestStorageCache(t *testing.T) {
+	d := &listCountingDriver{Driver: driver.NewMemory()}
+	storage := Init(d)
+	storage.EnableCache()
+
+	rls := ReleaseTestData{Name: "happy-catdog", Version: 1, Status: rspb.Status_DEPLOYED}.ToRelease()
+	assertErrNil(t.Fatal, storage.Create(rls), "StoreRelease")
+
+	assertErrNil(t.Fatal, storage.WarmCache(), "WarmCache")
+	if d.lists != 1 {
+		t.Fatalf("Expected warming to list once, got %d", d.lists)
+	}
+
+	deployed, err := storage.ListDeployed()
+	assertErrNil(t.Fatal, err, "ListDeployed")
+	if len(deployed) != 1 || d.lists != 1 {
+		t.Fatalf("Expected 1 deployed release from the cache, got %d after %d driver lists", len(deployed), d.lists)
+	}
+
+	// modifying a listed release must not leak into the cache
+	deployed[0].Info.Status.Code = rspb.Status_DELETED
+	deployed, _ = storage.ListDeployed()
+	if len(deployed) != 1 {
+		t.Fatal("Expected cached release to be unaffected by caller modifications")
+	}
+
+	// writes invalidate the cache
+	rls.Info.Status.Code = rspb.Status_SUPERSEDED
+	assertErrNil(t.Fatal, storage.Update(rls), "UpdateRelease")
+	deployed, err = storage.ListDeployed()
+	assertErrNil(t.Fatal, err, "ListDeployed")
+	if len(deployed) != 0 {
+		t.Errorf("Expected no deployed releases after update, got %d", len(deployed))
+	}
+	if d.lists != 2 {
+		t.Errorf("Expected the driver to be listed again after a write, got %d lists", d.lists)
+	}
+}
+
 func TestStorageDeployed(t *testing.T) {
 	storage := Init(driver.NewMemory())
 
@@ -376,6 +479,106 @@ func TestStorageLast(t *testing.T) {
 	}
 }
 
+// failingCreateDriver fails to create the records of the named release.
+type failingCreateDriver struct {
+	driver.Driver
+	name string
+}
+
+func (d *failingCreateDriver) Create(key string, rls *rspb.Release) error {
+	if rls.Name == d.name {
+		return fmt.Errorf("cannot write %q", key)
+	}
+	return d.Driver.Create(key, rls)
+}
+
+func TestStorageMigrateFrom(t *testing.T) {
+	src := Init(driver.NewMemory())
+	started := &timestamp.Timestamp{Seconds: 242085845}
+	for _, tt := range []ReleaseTestData{
+		{Name: "angry-bird", Version: 1, Status: rspb.Status_SUPERSEDED},
+		{Name: "angry-bird", Version: 2, Status: rspb.Status_DEPLOYED},
+		{Name: "happy-cats", Version: 1, Status: rspb.Status_DELETED},
+		{Name: "broken-dog", Version: 1, Status: rspb.Status_FAILED},
+	} {
+		rls := tt.ToRelease()
+		rls.Info.FirstDeployed = started
+		assertErrNil(t.Fatal, src.Create(rls), "StoreRelease")
+	}
+
+	dst := Init(&failingCreateDriver{Driver: driver.NewMemory(), name: "broken-dog"})
+	existing := ReleaseTestData{Name: "happy-cats", Version: 1, Status: rspb.Status_DELETED}.ToRelease()
+	assertErrNil(t.Fatal, dst.Create(existing), "StoreRelease")
+
+	res, err := dst.MigrateFrom(src.Driver)
+	assertErrNil(t.Fatal, err, "MigrateFrom")
+	if want := (MigrationResult{Migrated: 2, Skipped: 1, Failed: 1}); res != want {
+		t.Fatalf("Expected %+v, got %+v", want, res)
+	}
+
+	for version, code := range map[int32]rspb.Status_Code{1: rspb.Status_SUPERSEDED, 2: rspb.Status_DEPLOYED} {
+		rls, err := dst.Get("angry-bird", version)
+		assertErrNil(t.Fatal, err, "QueryRelease")
+		if rls.Info.Status.Code != code {
+			t.Errorf("Expected version %d to be %s, got %s", version, code, rls.Info.Status.Code)
+		}
+		if !reflect.DeepEqual(rls.Info.FirstDeployed, started) {
+			t.Errorf("Expected version %d to keep its timestamp, got %v", version, rls.Info.FirstDeployed)
+		}
+	}
+
+	// running again migrates nothing new
+	res, err = dst.MigrateFrom(src.Driver)
+	assertErrNil(t.Fatal, err, "MigrateFrom")
+	if want := (MigrationResult{Skipped: 3, Failed: 1}); res != want {
+		t.Errorf("Expected %+v on a second run, got %+v", want, res)
+	}
+}
+
+// undecodableDriver reports a record it cannot decode alongside its releases.
+type undecodableDriver struct {
+	driver.Driver
+}
+
+func (d *undecodableDriver) ListRecords() ([]*rspb.Release, map[string]error, error) {
+	rels, err := d.List(func(*rspb.Release) bool { return true })
+	return rels, map[string]error{"lost-fox.v1": fmt.Errorf("invalid payload")}, err
+}
+
+// unreachableGetDriver fails to get the records of the named release.
+type unreachableGetDriver struct {
+	driver.Driver
+	name string
+}
+
+func (d *unreachableGetDriver) Get(key string) (*rspb.Release, error) {
+	if strings.HasPrefix(key, d.name+".") {
+		return nil, fmt.Errorf("connection refused")
+	}
+	return d.Driver.Get(key)
+}
+
+func TestStorageMigrateFromCountsUnreadableRecords(t *testing.T) {
+	src := driver.NewMemory()
+	for _, tt := range []ReleaseTestData{
+		{Name: "angry-bird", Version: 1, Status: rspb.Status_DEPLOYED},
+		{Name: "happy-cats", Version: 1, Status: rspb.Status_DEPLOYED},
+	} {
+		rls := tt.ToRelease()
+		assertErrNil(t.Fatal, src.Create(makeKey(rls.Name, rls.Version), rls), "StoreRelease")
+	}
+
+	dst := Init(&unreachableGetDriver{Driver: driver.NewMemory(), name: "happy-cats"})
+	res, err := dst.MigrateFrom(&undecodableDriver{Driver: src})
+	assertErrNil(t.Fatal, err, "MigrateFrom")
+	if want := (MigrationResult{Migrated: 1, Failed: 2}); res != want {
+		t.Errorf("Expected %+v, got %+v", want, res)
+	}
+	if _, err := dst.Get("happy-cats", 1); err == nil {
+		t.Error("Expected a release that could not be checked not to be written")
+	}
+}
+
 type ReleaseTestData struct {
 	Name      string
 	Version   int32
@@ -0,0 +1,235 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	relutil "k8s.io/helm/pkg/releaseutil"
+)
+
+const (
+	// applyAfterAnnotation lists, as comma-separated "Kind/name" pairs, the
+	// resources of the release that must be applied before the annotated one.
+	applyAfterAnnotation = "helm.sh/apply-after"
+
+	// applyAfterWaitAnnotation, when "true", makes an install, upgrade or
+	// rollback wait for the resources named in applyAfterAnnotation to be
+	// ready before the annotated resource is applied.
+	applyAfterWaitAnnotation = "helm.sh/apply-after-wait"
+)
+
+// resourceKey identifies a resource of a release as "Kind/name".
+func resourceKey(head *relutil.SimpleHead) string {
+	if head == nil || head.Metadata == nil {
+		return ""
+	}
+	return head.Kind + "/" + head.Metadata.Name
+}
+
+// applyAfter returns the resources the resource described by head must be
+// applied after, and whether they must be ready first.
+func applyAfter(head *relutil.SimpleHead) (deps []string, wait bool) {
+	if head == nil || head.Metadata == nil {
+		return nil, false
+	}
+	for _, dep := range strings.Split(head.Metadata.Annotations[applyAfterAnnotation], ",") {
+		if dep = strings.TrimSpace(dep); dep != "" {
+			deps = append(deps, dep)
+		}
+	}
+	wait, _ = strconv.ParseBool(head.Metadata.Annotations[applyAfterWaitAnnotation])
+	return deps, wait
+}
+
+// orderByApplyAfter moves manifests after the resources named in their
+// apply-after annotation, keeping the existing order otherwise. Dependencies
+// on resources outside of the release and dependency cycles are rejected.
+func orderByApplyAfter(manifests []Manifest) ([]Manifest, error) {
+	index := map[string]bool{}
+	deps := make([][]string, len(manifests))
+	ordered := false
+	for i, m := range manifests {
+		index[resourceKey(m.Head)] = true
+		deps[i], _ = applyAfter(m.Head)
+		ordered = ordered || len(deps[i]) > 0
+	}
+	if !ordered {
+		return manifests, nil
+	}
+	for i, m := range manifests {
+		for _, dep := range deps[i] {
+			if !index[dep] {
+				return nil, status.Errorf(codes.InvalidArgument, "%s: %s refers to %s, which is not part of the release", m.Name, applyAfterAnnotation, dep)
+			}
+		}
+	}
+
+	result := make([]Manifest, 0, len(manifests))
+	placed := map[string]bool{}
+	done := make([]bool, len(manifests))
+	for len(result) < len(manifests) {
+		progress := false
+		for i, m := range manifests {
+			if done[i] || !allPlaced(deps[i], placed) {
+				continue
+			}
+			result = append(result, m)
+			placed[resourceKey(m.Head)] = true
+			done[i] = true
+			progress = true
+		}
+		if !progress {
+			var cycle []string
+			for i, m := range manifests {
+				if !done[i] {
+					cycle = append(cycle, resourceKey(m.Head))
+				}
+			}
+			return nil, status.Errorf(codes.InvalidArgument, "%s annotations form a cycle between %s", applyAfterAnnotation, strings.Join(cycle, ", "))
+		}
+	}
+	return result, nil
+}
+
+func allPlaced(deps []string, placed map[string]bool) bool {
+	for _, dep := range deps {
+		if !placed[dep] {
+			return false
+		}
+	}
+	return true
+}
+
+// applyStage is a part of a release manifest that is applied at once.
+type applyStage struct {
+	manifest string
+	// wait is set when later stages depend on this one being ready.
+	wait bool
+	// crds is set when the stage holds the CustomResourceDefinitions of the
+	// release, which must be established before later stages are applied.
+	crds bool
+}
+
+// applyStages splits a release manifest, already ordered by
+// orderByApplyAfter, into stages so that every resource is applied after the
+// resources named in its apply-after annotation. CustomResourceDefinitions
+// are applied in a stage of their own, before any other resource, as custom
+// resources cannot be built until their definition is established. A manifest
+// with neither is applied in a single stage.
+func applyStages(releaseManifest string) []applyStage {
+	all := relutil.SplitManifests(releaseManifest)
+	var (
+		crds, docs []string
+		heads      []*relutil.SimpleHead
+	)
+	needsReady := map[string]bool{}
+	staged := false
+	for i := 0; i < len(all); i++ {
+		doc := all[fmt.Sprintf("manifest-%d", i)]
+		var head relutil.SimpleHead
+		if err := yaml.Unmarshal([]byte(doc), &head); err != nil {
+			docs, heads = append(docs, doc), append(heads, nil)
+			continue
+		}
+		if head.Kind == "CustomResourceDefinition" {
+			crds = append(crds, doc)
+			continue
+		}
+		docs, heads = append(docs, doc), append(heads, &head)
+		deps, wait := applyAfter(&head)
+		staged = staged || len(deps) > 0
+		for _, dep := range deps {
+			needsReady[dep] = needsReady[dep] || wait
+		}
+	}
+	if !staged && (len(crds) == 0 || len(docs) == 0) {
+		return []applyStage{{manifest: releaseManifest}}
+	}
+
+	var (
+		stages  []applyStage
+		current []string
+		pending = map[string]bool{}
+		wait    bool
+	)
+	if len(crds) > 0 {
+		stages = append(stages, applyStage{manifest: strings.Join(crds, "\n---\n"), crds: true})
+	}
+	if !staged {
+		return append(stages, applyStage{manifest: strings.Join(docs, "\n---\n")})
+	}
+	flush := func() {
+		if len(current) > 0 {
+			stages = append(stages, applyStage{manifest: strings.Join(current, "\n---\n"), wait: wait})
+		}
+		current, pending, wait = nil, map[string]bool{}, false
+	}
+	for i, head := range heads {
+		deps, _ := applyAfter(head)
+		for _, dep := range deps {
+			if pending[dep] {
+				flush()
+				break
+			}
+		}
+		key := resourceKey(head)
+		current = append(current, docs[i])
+		pending[key] = true
+		wait = wait || needsReady[key]
+	}
+	flush()
+	return stages
+}
+
+// manifestKeys returns the "Kind/name" of every resource in manifest.
+func manifestKeys(manifest string) map[string]bool {
+	keys := map[string]bool{}
+	for _, doc := range relutil.SplitManifests(manifest) {
+		var head relutil.SimpleHead
+		if err := yaml.Unmarshal([]byte(doc), &head); err == nil && resourceKey(&head) != "" {
+			keys[resourceKey(&head)] = true
+		}
+	}
+	return keys
+}
+
+// filterManifest returns the documents of manifest, in order, whose
+// "Kind/name" is accepted by keep. Documents that cannot be parsed have an
+// empty key.
+func filterManifest(manifest string, keep func(key string) bool) string {
+	all := relutil.SplitManifests(manifest)
+	var docs []string
+	for i := 0; i < len(all); i++ {
+		doc := all[fmt.Sprintf("manifest-%d", i)]
+		var head relutil.SimpleHead
+		key := ""
+		if err := yaml.Unmarshal([]byte(doc), &head); err == nil {
+			key = resourceKey(&head)
+		}
+		if keep(key) {
+			docs = append(docs, doc)
+		}
+	}
+	return strings.Join(docs, "\n---\n")
+}
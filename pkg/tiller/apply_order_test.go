@@ -0,0 +1,275 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller/environment"
+)
+
+// stagedKubeClient records the manifests passed to each create or update
+// call and whether the call waited for readiness.
+type stagedKubeClient struct {
+	environment.PrintingKubeClient
+	manifests []string
+	waits     []bool
+	// originals holds the current manifest passed to each update call.
+	originals []string
+	// established holds the number of create calls made before each wait
+	// for CustomResourceDefinitions to be established.
+	established []int
+}
+
+func (kc *stagedKubeClient) CreateWithResult(ns string, r io.Reader, opts kube.CreateOptions) ([]kube.AppliedResource, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	kc.manifests = append(kc.manifests, string(b))
+	kc.waits = append(kc.waits, opts.ShouldWait)
+	return nil, nil
+}
+
+func (kc *stagedKubeClient) UpdateWithResult(ns string, current, target io.Reader, opts kube.UpdateOptions) ([]kube.AppliedResource, error) {
+	c, err := ioutil.ReadAll(current)
+	if err != nil {
+		return nil, err
+	}
+	kc.originals = append(kc.originals, string(c))
+	return kc.CreateWithResult(ns, target, kube.CreateOptions{ShouldWait: opts.ShouldWait})
+}
+
+// Validate rejects CronTabs, as an API server does before their definition
+// is installed.
+func (kc *stagedKubeClient) Validate(ns string, r io.Reader) error {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if strings.Contains(string(b), "\nkind: CronTab") {
+		return errors.New(`no matches for kind "CronTab" in version "stable.example.com/v1"`)
+	}
+	return nil
+}
+
+func (kc *stagedKubeClient) WaitUntilCRDEstablished(r io.Reader, timeout time.Duration) error {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	kc.established = append(kc.established, len(kc.manifests))
+	if !strings.Contains(string(b), "kind: CustomResourceDefinition") {
+		return fmt.Errorf("expected CustomResourceDefinitions, got:\n%s", b)
+	}
+	return nil
+}
+
+func TestInstallRelease_ApplyAfter(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	kc := &stagedKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: ioutil.Discard}}
+	rs.env.KubeClient = kc
+
+	// ConfigMaps are installed before Deployments by kind, so the annotation
+	// has to reverse the order.
+	dependent := func(opts *chartOptions) {
+		opts.Templates = []*chart.Template{
+			{Name: "templates/config", Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app-config\n  annotations:\n    helm.sh/apply-after: Deployment/app\n    helm.sh/apply-after-wait: \"true\"\n")},
+			{Name: "templates/other", Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: unrelated\n")},
+			{Name: "templates/deployment", Data: []byte("apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: app\n")},
+		}
+	}
+	res, err := rs.InstallRelease(c, installRequest(withName("ordered"), withChart(dependent)))
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+
+	if d, cm := strings.Index(res.Release.Manifest, "kind: Deployment"), strings.Index(res.Release.Manifest, "name: app-config"); d < 0 || d > cm {
+		t.Errorf("Expected the Deployment before the ConfigMap depending on it, got manifest:\n%s", res.Release.Manifest)
+	}
+	if len(kc.manifests) != 2 {
+		t.Fatalf("Expected 2 create stages, got %d: %q", len(kc.manifests), kc.manifests)
+	}
+	if !strings.Contains(kc.manifests[0], "kind: Deployment") || strings.Contains(kc.manifests[0], "app-config") {
+		t.Errorf("Expected the first stage to create the Deployment without its dependent, got:\n%s", kc.manifests[0])
+	}
+	if !kc.waits[0] {
+		t.Error("Expected the install to wait for the Deployment to be ready before applying its dependent")
+	}
+	if !strings.Contains(kc.manifests[1], "app-config") || kc.waits[1] {
+		t.Errorf("Expected the second stage to create the ConfigMap without waiting, got wait=%t:\n%s", kc.waits[1], kc.manifests[1])
+	}
+}
+
+func TestUpdateRelease_ApplyAfter(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	kc := &stagedKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: ioutil.Discard}}
+	rs.env.KubeClient = kc
+
+	rel := releaseStub()
+	rel.Manifest = "---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: removed\n---\napiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: app\n"
+	rs.env.Releases.Create(rel)
+
+	req := &services.UpdateReleaseRequest{
+		Name: rel.Name,
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "hello"},
+			Templates: []*chart.Template{
+				{Name: "templates/config", Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app-config\n  annotations:\n    helm.sh/apply-after: Deployment/app\n    helm.sh/apply-after-wait: \"true\"\n")},
+				{Name: "templates/deployment", Data: []byte("apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: app\n")},
+			},
+		},
+	}
+	if _, err := rs.UpdateRelease(c, req); err != nil {
+		t.Fatalf("Failed upgrade: %s", err)
+	}
+
+	if len(kc.manifests) != 2 {
+		t.Fatalf("Expected 2 update stages, got %d: %q", len(kc.manifests), kc.manifests)
+	}
+	if !strings.Contains(kc.manifests[0], "kind: Deployment") || strings.Contains(kc.manifests[0], "app-config") || !kc.waits[0] {
+		t.Errorf("Expected the first stage to update the Deployment and wait for it, got wait=%t:\n%s", kc.waits[0], kc.manifests[0])
+	}
+	if !strings.Contains(kc.manifests[1], "app-config") || kc.waits[1] {
+		t.Errorf("Expected the second stage to add the ConfigMap without waiting, got wait=%t:\n%s", kc.waits[1], kc.manifests[1])
+	}
+	// The removed ConfigMap must only be deleted with the last stage.
+	if strings.Contains(kc.originals[0], "name: removed") || !strings.Contains(kc.originals[0], "name: app") {
+		t.Errorf("Expected the first stage to be compared with the current Deployment only, got:\n%s", kc.originals[0])
+	}
+	if !strings.Contains(kc.originals[1], "name: removed") || strings.Contains(kc.originals[1], "kind: Deployment") {
+		t.Errorf("Expected the last stage to be compared with the rest of the current release, got:\n%s", kc.originals[1])
+	}
+}
+
+// crontabCRD defines the CronTab kind of the stable.example.com group.
+const crontabCRD = `apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: crontabs.stable.example.com
+spec:
+  group: stable.example.com
+  names:
+    kind: CronTab
+    plural: crontabs
+`
+
+func TestInstallRelease_CRDEstablishedBeforeCustomResources(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	kc := &stagedKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: ioutil.Discard}}
+	rs.env.KubeClient = kc
+
+	crontab := func(opts *chartOptions) {
+		opts.Templates = []*chart.Template{
+			{Name: "templates/crontab", Data: []byte("apiVersion: stable.example.com/v1\nkind: CronTab\nmetadata:\n  name: my-crontab\n")},
+			{Name: "templates/crd", Data: []byte(crontabCRD)},
+		}
+	}
+	if _, err := rs.InstallRelease(c, installRequest(withName("crontabs"), withChart(crontab))); err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+
+	if len(kc.manifests) != 2 {
+		t.Fatalf("Expected 2 create stages, got %d: %q", len(kc.manifests), kc.manifests)
+	}
+	if !strings.Contains(kc.manifests[0], "kind: CustomResourceDefinition") || strings.Contains(kc.manifests[0], "\nkind: CronTab") {
+		t.Errorf("Expected the first stage to create only the CustomResourceDefinition, got:\n%s", kc.manifests[0])
+	}
+	if !strings.Contains(kc.manifests[1], "kind: CronTab") {
+		t.Errorf("Expected the second stage to create the CronTab, got:\n%s", kc.manifests[1])
+	}
+	if want := []int{1}; !reflect.DeepEqual(kc.established, want) {
+		t.Errorf("Expected to wait for the CustomResourceDefinition after the first stage only, got waits after %v", kc.established)
+	}
+}
+
+func TestUpdateRelease_CRDEstablishedBeforeCustomResources(t *testing.T) {
+	for _, schemaValidation := range []bool{false, true} {
+		c := helm.NewContext()
+		rs := rsFixture()
+		rs.SchemaValidation = schemaValidation
+		kc := &stagedKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: ioutil.Discard}}
+		rs.env.KubeClient = kc
+
+		rel := releaseStub()
+		rs.env.Releases.Create(rel)
+
+		req := &services.UpdateReleaseRequest{
+			Name: rel.Name,
+			Chart: &chart.Chart{
+				Metadata: &chart.Metadata{Name: "hello"},
+				Templates: []*chart.Template{
+					{Name: "templates/crontab", Data: []byte("apiVersion: stable.example.com/v1\nkind: CronTab\nmetadata:\n  name: my-crontab\n")},
+					{Name: "templates/crd", Data: []byte(crontabCRD)},
+				},
+			},
+		}
+		if _, err := rs.UpdateRelease(c, req); err != nil {
+			t.Fatalf("Failed upgrade with schema validation %t: %s", schemaValidation, err)
+		}
+
+		if len(kc.manifests) != 2 {
+			t.Fatalf("Expected 2 update stages, got %d: %q", len(kc.manifests), kc.manifests)
+		}
+		if !strings.Contains(kc.manifests[0], "kind: CustomResourceDefinition") || strings.Contains(kc.manifests[0], "\nkind: CronTab") {
+			t.Errorf("Expected the first stage to update only the CustomResourceDefinition, got:\n%s", kc.manifests[0])
+		}
+		if !strings.Contains(kc.manifests[1], "kind: CronTab") {
+			t.Errorf("Expected the second stage to update the CronTab, got:\n%s", kc.manifests[1])
+		}
+		if want := []int{1}; !reflect.DeepEqual(kc.established, want) {
+			t.Errorf("Expected to wait for the CustomResourceDefinition after the first stage only, got waits after %v", kc.established)
+		}
+	}
+}
+
+func TestInstallRelease_ApplyAfterInvalid(t *testing.T) {
+	for name, templates := range map[string][]*chart.Template{
+		"unknown dependency": {
+			{Name: "templates/config", Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\n  annotations:\n    helm.sh/apply-after: Secret/missing\n")},
+		},
+		"cycle": {
+			{Name: "templates/a", Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n  annotations:\n    helm.sh/apply-after: ConfigMap/b\n")},
+			{Name: "templates/b", Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: b\n  annotations:\n    helm.sh/apply-after: ConfigMap/a\n")},
+		},
+	} {
+		rs := rsFixture()
+		tpls := templates
+		req := installRequest(withName("invalid"), withChart(func(opts *chartOptions) { opts.Templates = tpls }))
+		_, err := rs.InstallRelease(helm.NewContext(), req)
+		if code := status.Code(err); code != codes.InvalidArgument {
+			t.Errorf("%s: expected code %s, got %s: %v", name, codes.InvalidArgument, code, err)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+// maxAuditEntries bounds the audit trail kept for a release. The oldest
+// entries are dropped beyond it; the AuditLog keeps a complete record.
+const maxAuditEntries = 1000
+
+// AuditStore keeps the audit trail of each release. It is kept apart from the
+// release records, so that the trail outlives revisions pruned by the history
+// limit or deleted by a purge.
+type AuditStore interface {
+	// Append adds an entry to the end of the trail of the named release.
+	Append(name string, entry *release.AuditEntry) error
+	// Entries returns the trail of the named release, oldest first.
+	Entries(name string) ([]*release.AuditEntry, error)
+}
+
+// memoryAuditStore is an AuditStore held in memory.
+type memoryAuditStore struct {
+	mu      sync.Mutex
+	entries map[string][]*release.AuditEntry
+}
+
+// NewMemoryAuditStore creates an AuditStore that is lost when Tiller exits.
+func NewMemoryAuditStore() AuditStore {
+	return &memoryAuditStore{entries: make(map[string][]*release.AuditEntry)}
+}
+
+func (m *memoryAuditStore) Append(name string, entry *release.AuditEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[name] = trimAuditEntries(append(m.entries[name], entry))
+	return nil
+}
+
+func (m *memoryAuditStore) Entries(name string) ([]*release.AuditEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*release.AuditEntry(nil), m.entries[name]...), nil
+}
+
+// configMapAuditStore keeps the trail of each release as JSON in a ConfigMap
+// named "<release>.audit".
+type configMapAuditStore struct {
+	impl corev1.ConfigMapInterface
+}
+
+// NewConfigMapAuditStore creates an AuditStore backed by the ConfigMaps of
+// impl.
+func NewConfigMapAuditStore(impl corev1.ConfigMapInterface) AuditStore {
+	return &configMapAuditStore{impl: impl}
+}
+
+func (c *configMapAuditStore) Append(name string, entry *release.AuditEntry) error {
+	// Another Tiller may append to the same trail; retry on conflicts.
+	var err error
+	for i := 0; i < 5; i++ {
+		if err = c.append(name, entry); !apierrors.IsConflict(err) && !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+	return err
+}
+
+func (c *configMapAuditStore) append(name string, entry *release.AuditEntry) error {
+	obj, err := c.impl.Get(auditConfigMapName(name), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		data, err := encodeAuditEntries([]*release.AuditEntry{entry})
+		if err != nil {
+			return err
+		}
+		_, err = c.impl.Create(&v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   auditConfigMapName(name),
+				Labels: map[string]string{"OWNER": "TILLER-AUDIT", "RELEASE": name},
+			},
+			Data: map[string]string{"entries": data},
+		})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	entries, err := decodeAuditEntries(obj.Data["entries"])
+	if err != nil {
+		return fmt.Errorf("audit trail of %s is corrupt: %s", name, err)
+	}
+	data, err := encodeAuditEntries(trimAuditEntries(append(entries, entry)))
+	if err != nil {
+		return err
+	}
+	if obj.Data == nil {
+		obj.Data = map[string]string{}
+	}
+	obj.Data["entries"] = data
+	_, err = c.impl.Update(obj)
+	return err
+}
+
+func (c *configMapAuditStore) Entries(name string) ([]*release.AuditEntry, error) {
+	obj, err := c.impl.Get(auditConfigMapName(name), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decodeAuditEntries(obj.Data["entries"])
+}
+
+func auditConfigMapName(name string) string {
+	return name + ".audit"
+}
+
+func trimAuditEntries(entries []*release.AuditEntry) []*release.AuditEntry {
+	if len(entries) > maxAuditEntries {
+		entries = entries[len(entries)-maxAuditEntries:]
+	}
+	return entries
+}
+
+func encodeAuditEntries(entries []*release.AuditEntry) (string, error) {
+	b, err := json.Marshal(entries)
+	return string(b), err
+}
+
+func decodeAuditEntries(data string) ([]*release.AuditEntry, error) {
+	if data == "" {
+		return nil, nil
+	}
+	var entries []*release.AuditEntry
+	err := json.Unmarshal([]byte(data), &entries)
+	return entries, err
+}
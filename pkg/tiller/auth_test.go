@@ -0,0 +1,106 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"k8s.io/helm/pkg/version"
+)
+
+func TestBearerTokenAuth(t *testing.T) {
+	auth := BearerTokenAuth([]string{"s3cr3t", "other"})
+	tests := []struct {
+		authorization string
+		code          codes.Code
+	}{
+		{"Bearer s3cr3t", codes.OK},
+		{"bearer other", codes.OK},
+		{"", codes.Unauthenticated},
+		{"Bearer wrong", codes.Unauthenticated},
+		{"Basic czNjcjN0", codes.Unauthenticated},
+		{"Bearer ", codes.Unauthenticated},
+	}
+	for _, tt := range tests {
+		ctx := context.Background()
+		if tt.authorization != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", tt.authorization))
+		}
+		if code := status.Code(auth(ctx)); code != tt.code {
+			t.Errorf("%q: expected code %s, got %s", tt.authorization, tt.code, code)
+		}
+	}
+}
+
+func TestUnaryInterceptorAuthentication(t *testing.T) {
+	interceptor := newUnaryInterceptor(BearerTokenAuth([]string{"s3cr3t"}))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+
+	call := func(method string, pairs ...string) error {
+		pairs = append(pairs, "x-helm-api-client", version.GetVersion())
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(pairs...))
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		return err
+	}
+
+	if err := call("/hapi.services.tiller.ReleaseService/GetVersion"); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected an unauthenticated call to be rejected, got %v", err)
+	}
+	if err := call("/hapi.services.tiller.ReleaseService/GetVersion", "authorization", "Bearer s3cr3t"); err != nil {
+		t.Errorf("Expected an authenticated call to be served, got %v", err)
+	}
+	if err := call("/grpc.health.v1.Health/Check"); err != nil {
+		t.Errorf("Expected health checks to be served without a token, got %v", err)
+	}
+}
+
+func TestLoadBearerTokens(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tiller-tokens")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "tokens")
+	if err := ioutil.WriteFile(path, []byte("# ci\ns3cr3t\n\n  other  \n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	tokens, err := LoadBearerTokens(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"s3cr3t", "other"}; !reflect.DeepEqual(tokens, want) {
+		t.Errorf("Expected tokens %v, got %v", want, tokens)
+	}
+
+	if err := ioutil.WriteFile(path, []byte("# none yet\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadBearerTokens(path); err == nil {
+		t.Error("Expected an error for a file without tokens")
+	}
+}
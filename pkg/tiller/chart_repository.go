@@ -0,0 +1,220 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/repo"
+)
+
+// chartRepoClient is the HTTP client used to talk to chart repositories.
+var chartRepoClient = &http.Client{Timeout: 5 * time.Minute}
+
+// Downloads from chart repositories larger than these limits are rejected.
+var (
+	maxChartRepoIndexBytes int64 = 64 << 20
+	maxChartArchiveBytes   int64 = 20 << 20
+)
+
+// fetchChart resolves a chart reference against its repository index and
+// loads the chart archive it points to. The archive is read straight from the
+// response body and checked against the digest in the index, if any.
+func (s *ReleaseServer) fetchChart(ref *services.ChartReference) (*chart.Chart, error) {
+	repoURL := ref.RepoUrl
+	if repoURL == "" {
+		repoURL = s.ChartRepoURL
+	}
+	if repoURL == "" {
+		return nil, status.Error(codes.InvalidArgument, "chart reference names no repository and no default repository is configured")
+	}
+	if !s.chartRepoAllowed(repoURL) {
+		return nil, status.Errorf(codes.PermissionDenied, "chart repository %s is not allowed", repoURL)
+	}
+
+	indexURL, err := resolveChartRepoURL(repoURL, "index.yaml")
+	if err != nil {
+		return nil, err
+	}
+	body, err := s.chartRepoGet(indexURL)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadAll(&sizeLimitedReader{r: body, max: maxChartRepoIndexBytes, url: indexURL})
+	body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %s", indexURL, err)
+	}
+	index, err := repo.LoadIndex(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %s", indexURL, err)
+	}
+
+	cv, err := index.Get(ref.Name, ref.Version)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "chart %s %s in %s: %s", ref.Name, ref.Version, repoURL, err)
+	}
+	if len(cv.URLs) == 0 {
+		return nil, fmt.Errorf("chart %s %s in %s has no downloadable archive", ref.Name, cv.Version, repoURL)
+	}
+	archiveURL, err := resolveChartRepoURL(repoURL, cv.URLs[0])
+	if err != nil {
+		return nil, err
+	}
+	// The index may point anywhere, so the archive must be on an allowed
+	// host too.
+	if u, err := url.Parse(archiveURL); err != nil || !s.chartRepoHostAllowed(u) {
+		return nil, status.Errorf(codes.PermissionDenied, "chart archive %s is not on an allowed repository host", archiveURL)
+	}
+
+	s.Log("downloading chart %s %s from %s", ref.Name, cv.Version, archiveURL)
+	body, err = s.chartRepoGet(archiveURL)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	archive := &sizeLimitedReader{r: body, max: maxChartArchiveBytes, url: archiveURL}
+
+	h := sha256.New()
+	ch, err := chartutil.LoadArchive(io.TeeReader(archive, h))
+	if err != nil {
+		return nil, fmt.Errorf("loading chart from %s: %s", archiveURL, err)
+	}
+	if cv.Digest != "" {
+		// Drain whatever the archive reader left unread so the digest
+		// covers the whole download.
+		if _, err := io.Copy(h, archive); err != nil {
+			return nil, err
+		}
+		if sum := hex.EncodeToString(h.Sum(nil)); sum != cv.Digest {
+			return nil, fmt.Errorf("chart %s digest mismatch: expected %s, got %s", archiveURL, cv.Digest, sum)
+		}
+	}
+	return ch, nil
+}
+
+// chartRepoAllowed reports whether charts may be downloaded from repoURL.
+// The default repository is always allowed.
+func (s *ReleaseServer) chartRepoAllowed(repoURL string) bool {
+	repoURL = strings.TrimSuffix(repoURL, "/")
+	if s.ChartRepoURL != "" && repoURL == strings.TrimSuffix(s.ChartRepoURL, "/") {
+		return true
+	}
+	for _, allowed := range s.ChartRepoAllowlist {
+		if repoURL == strings.TrimSuffix(allowed, "/") {
+			return true
+		}
+	}
+	return false
+}
+
+// chartRepoHostAllowed reports whether charts may be downloaded from the host
+// of u: the scheme and host of the default repository or of an allowlisted one.
+func (s *ReleaseServer) chartRepoHostAllowed(u *url.URL) bool {
+	for _, repoURL := range append([]string{s.ChartRepoURL}, s.ChartRepoAllowlist...) {
+		if repoURL == "" {
+			continue
+		}
+		r, err := url.Parse(repoURL)
+		if err != nil {
+			continue
+		}
+		if strings.EqualFold(r.Scheme, u.Scheme) && strings.EqualFold(r.Host, u.Host) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveChartRepoURL resolves ref, which may be relative, against the
+// repository base URL.
+func resolveChartRepoURL(base, ref string) (string, error) {
+	b, err := url.Parse(strings.TrimSuffix(base, "/") + "/")
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s as URL: %s", base, err)
+	}
+	r, err := url.Parse(ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s as URL: %s", ref, err)
+	}
+	return b.ResolveReference(r).String(), nil
+}
+
+// chartRepoGet issues a GET request and returns the response body of a
+// successful response. Redirects are only followed to allowed hosts.
+func (s *ReleaseServer) chartRepoGet(u string) (io.ReadCloser, error) {
+	client := *chartRepoClient
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		if !s.chartRepoHostAllowed(req.URL) {
+			return fmt.Errorf("redirect to %s is not on an allowed repository host", req.URL)
+		}
+		return nil
+	}
+	resp, err := client.Get(u)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to fetch %s: %s", u, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// sizeLimitedReader fails once more than max bytes have been read from r, so
+// that oversized downloads are rejected rather than truncated.
+type sizeLimitedReader struct {
+	r    io.Reader
+	max  int64
+	read int64
+	url  string
+}
+
+func (l *sizeLimitedReader) Read(p []byte) (int, error) {
+	if l.read > l.max {
+		return 0, fmt.Errorf("%s is larger than %d bytes", l.url, l.max)
+	}
+	// Read at most one byte past the limit, and hand none of the bytes over
+	// once it is exceeded.
+	if rest := l.max - l.read + 1; int64(len(p)) > rest {
+		p = p[:rest]
+	}
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	if l.read > l.max {
+		return 0, fmt.Errorf("%s is larger than %d bytes", l.url, l.max)
+	}
+	return n, err
+}
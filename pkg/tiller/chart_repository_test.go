@@ -0,0 +1,191 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+func TestInstallRelease_ChartReference(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tiller-chart-repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ch := buildChart(func(opts *chartOptions) {
+		opts.Metadata.Version = "0.1.0"
+		opts.Metadata.ApiVersion = chartutil.ApiVersionV1
+	})
+	archive, err := chartutil.Save(ch, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(data)
+	index := fmt.Sprintf(`apiVersion: v1
+entries:
+  hello:
+  - name: hello
+    version: 0.1.0
+    digest: %s
+    urls:
+    - charts/hello-0.1.0.tgz
+`, hex.EncodeToString(sum[:]))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/index.yaml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(index))
+	})
+	mux.HandleFunc("/charts/hello-0.1.0.tgz", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(data)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	rs := rsFixture()
+	req := &services.InstallReleaseRequest{
+		Name:           "fetched",
+		Namespace:      "spaced",
+		ChartReference: &services.ChartReference{RepoUrl: srv.URL, Name: "hello", Version: "0.1.0"},
+	}
+
+	// the repository is not allowlisted yet
+	if _, err := rs.InstallRelease(helm.NewContext(), req); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("Expected PermissionDenied for a repository that is not allowed, got %v", err)
+	}
+
+	rs.ChartRepoAllowlist = []string{srv.URL + "/"}
+	res, err := rs.InstallRelease(helm.NewContext(), req)
+	if err != nil {
+		t.Fatalf("Failed install from chart reference: %s", err)
+	}
+	if got := res.Release.Chart.Metadata.Version; got != "0.1.0" {
+		t.Errorf("Expected chart version 0.1.0, got %q", got)
+	}
+	if res.Release.Manifest == "" {
+		t.Error("Expected a rendered manifest")
+	}
+
+	// a default repository is used when the reference names none
+	rs = rsFixture()
+	rs.ChartRepoURL = srv.URL
+	req = &services.InstallReleaseRequest{
+		Name:           "defaulted",
+		ChartReference: &services.ChartReference{Name: "hello"},
+	}
+	if _, err := rs.InstallRelease(helm.NewContext(), req); err != nil {
+		t.Fatalf("Failed install from default repository: %s", err)
+	}
+
+	req = &services.InstallReleaseRequest{
+		Name:           "missing",
+		ChartReference: &services.ChartReference{Name: "goodbye"},
+	}
+	if _, err := rs.InstallRelease(helm.NewContext(), req); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound for a chart missing from the index, got %v", err)
+	}
+}
+
+func TestInstallRelease_ChartReferenceUntrustedDownload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tiller-chart-repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ch := buildChart(func(opts *chartOptions) {
+		opts.Metadata.Version = "0.1.0"
+	})
+	archive, err := chartutil.Save(ch, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected request to a host that is not allowed: %s", r.URL)
+	}))
+	defer other.Close()
+
+	index := fmt.Sprintf(`apiVersion: v1
+entries:
+  elsewhere:
+  - name: elsewhere
+    version: 0.1.0
+    urls:
+    - %s/elsewhere-0.1.0.tgz
+  large:
+  - name: large
+    version: 0.1.0
+    urls:
+    - large-0.1.0.tgz
+`, other.URL)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/index.yaml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(index))
+	})
+	mux.HandleFunc("/large-0.1.0.tgz", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(data)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	rs := rsFixture()
+	rs.ChartRepoURL = srv.URL
+
+	req := &services.InstallReleaseRequest{
+		Name:           "elsewhere",
+		ChartReference: &services.ChartReference{Name: "elsewhere"},
+	}
+	if _, err := rs.InstallRelease(helm.NewContext(), req); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied for an archive on another host, got %v", err)
+	}
+
+	defer func(max int64) { maxChartArchiveBytes = max }(maxChartArchiveBytes)
+	maxChartArchiveBytes = 16
+	req = &services.InstallReleaseRequest{
+		Name:           "large",
+		ChartReference: &services.ChartReference{Name: "large"},
+	}
+	_, err = rs.InstallRelease(helm.NewContext(), req)
+	if err == nil || !strings.Contains(err.Error(), "larger than 16 bytes") {
+		t.Errorf("Expected an oversized archive to be rejected, got %v", err)
+	}
+}
@@ -23,6 +23,7 @@ These dependencies are expressed as interfaces so that alternate implementations
 package environment
 
 import (
+	"context"
 	"io"
 	"time"
 
@@ -110,6 +111,10 @@ type KubeClient interface {
 	// by "\n---\n").
 	Create(namespace string, reader io.Reader, timeout int64, shouldWait bool) error
 
+	// CreateWithResult creates one or more resources like Create, and returns
+	// the UID and resource version of each created object.
+	CreateWithResult(namespace string, reader io.Reader, opts kube.CreateOptions) ([]kube.AppliedResource, error)
+
 	// Get gets one or more resources. Returned string hsa the format like kubectl
 	// provides with the column headers separating the resource types.
 	//
@@ -137,6 +142,12 @@ type KubeClient interface {
 	// by "\n---\n").
 	DeleteWithTimeout(namespace string, reader io.Reader, timeout int64, shouldWait bool) error
 
+	// WaitForDeletion waits up to timeout for deleted resources to be gone, and
+	// returns the ones still present as "Kind/name". If removeFinalizers is
+	// true, resources still present at the timeout have their finalizers
+	// cleared and are waited for again.
+	WaitForDeletion(namespace string, reader io.Reader, timeout time.Duration, removeFinalizers bool) ([]string, error)
+
 	// WatchUntilReady watch the resource in reader until it is "ready".
 	//
 	// For Jobs, "ready" means the job ran to completion (excited without error).
@@ -156,6 +167,10 @@ type KubeClient interface {
 	// by "\n---\n").
 	UpdateWithOptions(namespace string, originalReader, modifiedReader io.Reader, opts kube.UpdateOptions) error
 
+	// UpdateWithResult updates resources like UpdateWithOptions, and returns
+	// the UID and resource version of each object of the modified manifest.
+	UpdateWithResult(namespace string, originalReader, modifiedReader io.Reader, opts kube.UpdateOptions) ([]kube.AppliedResource, error)
+
 	Build(namespace string, reader io.Reader) (kube.Result, error)
 
 	// BuildUnstructured reads a stream of manifests from a reader and turns them into
@@ -180,6 +195,15 @@ type KubeClient interface {
 	WaitUntilCRDEstablished(reader io.Reader, timeout time.Duration) error
 }
 
+// ContextWatcher is implemented by KubeClients that can stop watching hook
+// resources when the request they are watched for is cancelled or its
+// deadline passes.
+type ContextWatcher interface {
+	WatchUntilReadyContext(ctx context.Context, namespace string, reader io.Reader, timeout int64, shouldWait bool) error
+}
+
+var _ ContextWatcher = (*kube.Client)(nil)
+
 // PrintingKubeClient implements KubeClient, but simply prints the reader to
 // the given output.
 type PrintingKubeClient struct {
@@ -192,6 +216,13 @@ func (p *PrintingKubeClient) Create(ns string, r io.Reader, timeout int64, shoul
 	return err
 }
 
+// CreateWithResult implements KubeClient CreateWithResult.
+//
+// It only prints out the content to be created, so no objects are returned.
+func (p *PrintingKubeClient) CreateWithResult(ns string, r io.Reader, opts kube.CreateOptions) ([]kube.AppliedResource, error) {
+	return nil, p.Create(ns, r, opts.Timeout, opts.ShouldWait)
+}
+
 // Get prints the values of what would be created with a real KubeClient.
 func (p *PrintingKubeClient) Get(ns string, r io.Reader) (string, error) {
 	_, err := io.Copy(p.Out, r)
@@ -214,6 +245,12 @@ func (p *PrintingKubeClient) DeleteWithTimeout(ns string, r io.Reader, timeout i
 	return err
 }
 
+// WaitForDeletion implements KubeClient WaitForDeletion.
+func (p *PrintingKubeClient) WaitForDeletion(ns string, r io.Reader, timeout time.Duration, removeFinalizers bool) ([]string, error) {
+	_, err := io.Copy(p.Out, r)
+	return nil, err
+}
+
 // WatchUntilReady implements KubeClient WatchUntilReady.
 func (p *PrintingKubeClient) WatchUntilReady(ns string, r io.Reader, timeout int64, shouldWait bool) error {
 	_, err := io.Copy(p.Out, r)
@@ -236,6 +273,13 @@ func (p *PrintingKubeClient) UpdateWithOptions(ns string, currentReader, modifie
 	return err
 }
 
+// UpdateWithResult implements KubeClient UpdateWithResult.
+//
+// It only prints out the content to be updated, so no objects are returned.
+func (p *PrintingKubeClient) UpdateWithResult(ns string, currentReader, modifiedReader io.Reader, opts kube.UpdateOptions) ([]kube.AppliedResource, error) {
+	return nil, p.UpdateWithOptions(ns, currentReader, modifiedReader, opts)
+}
+
 // Build implements KubeClient Build.
 func (p *PrintingKubeClient) Build(ns string, reader io.Reader) (kube.Result, error) {
 	return []*resource.Info{}, nil
@@ -43,6 +43,9 @@ type mockKubeClient struct{}
 func (k *mockKubeClient) Create(ns string, r io.Reader, timeout int64, shouldWait bool) error {
 	return nil
 }
+func (k *mockKubeClient) CreateWithResult(ns string, r io.Reader, opts kube.CreateOptions) ([]kube.AppliedResource, error) {
+	return nil, nil
+}
 func (k *mockKubeClient) Get(ns string, r io.Reader) (string, error) {
 	return "", nil
 }
@@ -58,6 +61,12 @@ func (k *mockKubeClient) Update(ns string, currentReader, modifiedReader io.Read
 func (k *mockKubeClient) UpdateWithOptions(ns string, currentReader, modifiedReader io.Reader, opts kube.UpdateOptions) error {
 	return nil
 }
+func (k *mockKubeClient) UpdateWithResult(ns string, currentReader, modifiedReader io.Reader, opts kube.UpdateOptions) ([]kube.AppliedResource, error) {
+	return nil, nil
+}
+func (k *mockKubeClient) WaitForDeletion(ns string, r io.Reader, timeout time.Duration, removeFinalizers bool) ([]string, error) {
+	return nil, nil
+}
 func (k *mockKubeClient) WatchUntilReady(ns string, r io.Reader, timeout int64, shouldWait bool) error {
 	return nil
 }
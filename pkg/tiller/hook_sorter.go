@@ -23,19 +23,41 @@ import (
 )
 
 // sortByHookWeight does an in-place sort of hooks by their supplied weight.
+//
+// Hooks of the same weight are sorted by Kind in InstallOrder, then by name.
 func sortByHookWeight(hooks []*release.Hook) []*release.Hook {
-	hs := newHookWeightSorter(hooks)
+	hs := newHookWeightSorter(hooks, InstallOrder)
 	sort.Sort(hs)
 	return hs.hooks
 }
 
+// groupByHookWeight splits hooks sorted by sortByHookWeight into runs of
+// hooks sharing the same weight, preserving their order.
+func groupByHookWeight(hooks []*release.Hook) [][]*release.Hook {
+	var groups [][]*release.Hook
+	for i, h := range hooks {
+		if i == 0 || h.Weight != hooks[i-1].Weight {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], h)
+	}
+	return groups
+}
+
 type hookWeightSorter struct {
-	hooks []*release.Hook
+	hooks    []*release.Hook
+	ordering map[string]int
 }
 
-func newHookWeightSorter(h []*release.Hook) *hookWeightSorter {
+func newHookWeightSorter(h []*release.Hook, s SortOrder) *hookWeightSorter {
+	o := make(map[string]int, len(s))
+	for v, k := range s {
+		o[k] = v
+	}
+
 	return &hookWeightSorter{
-		hooks: h,
+		hooks:    h,
+		ordering: o,
 	}
 }
 
@@ -46,8 +68,21 @@ func (hs *hookWeightSorter) Swap(i, j int) {
 }
 
 func (hs *hookWeightSorter) Less(i, j int) bool {
-	if hs.hooks[i].Weight == hs.hooks[j].Weight {
-		return hs.hooks[i].Name < hs.hooks[j].Name
+	a, b := hs.hooks[i], hs.hooks[j]
+	if a.Weight != b.Weight {
+		return a.Weight < b.Weight
+	}
+	if a.Kind != b.Kind {
+		first, aok := hs.ordering[a.Kind]
+		second, bok := hs.ordering[b.Kind]
+		// unknown kinds are last, sorted alphabetically among themselves
+		if aok != bok {
+			return aok
+		}
+		if !aok {
+			return a.Kind < b.Kind
+		}
+		return first < second
 	}
-	return hs.hooks[i].Weight < hs.hooks[j].Weight
+	return a.Name < b.Name
 }
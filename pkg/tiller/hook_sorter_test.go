@@ -17,6 +17,7 @@ limitations under the License.
 package tiller
 
 import (
+	"strings"
 	"testing"
 
 	"k8s.io/helm/pkg/proto/hapi/release"
@@ -71,3 +72,25 @@ func TestHookSorter(t *testing.T) {
 		t.Errorf("Expected %q, got %q", expect, got)
 	}
 }
+
+func TestGroupByHookWeight(t *testing.T) {
+	hooks := sortByHookWeight([]*release.Hook{
+		{Name: "c", Weight: 1},
+		{Name: "a", Weight: -1},
+		{Name: "d", Weight: 1},
+		{Name: "b", Weight: 0},
+		{Name: "e", Weight: 1},
+	})
+
+	var got []string
+	for _, group := range groupByHookWeight(hooks) {
+		names := ""
+		for _, h := range group {
+			names += h.Name
+		}
+		got = append(got, names)
+	}
+	if expect := "a b cde"; strings.Join(got, " ") != expect {
+		t.Errorf("Expected %q, got %q", expect, strings.Join(got, " "))
+	}
+}
@@ -73,7 +73,8 @@ type manifestFile struct {
 // sortManifests takes a map of filename/YAML contents, splits the file
 // by manifest entries, and sorts the entries into hook types.
 //
-// The resulting hooks struct will be populated with all of the generated hooks.
+// The resulting hooks struct will be populated with all of the generated hooks,
+// sorted by weight and then by Kind.
 // Any file that does not declare one of the hook types will be placed in the
 // 'generic' bucket.
 //
@@ -106,7 +107,7 @@ func sortManifests(files map[string]string, apis chartutil.VersionSet, sort Sort
 		}
 	}
 
-	return result.hooks, sortByKind(result.generic, sort), nil
+	return sortByHookWeight(result.hooks), sortByKind(result.generic, sort), nil
 }
 
 // sort takes a manifestFile object which may contain multiple resource definition
@@ -207,6 +208,14 @@ func (file *manifestFile) sort(result *result) error {
 				h.DeleteTimeout = timeout
 			})
 		}
+
+		if v, ok := entry.Metadata.Annotations[hooks.HookRequiresApprovalAnno]; ok {
+			approval, err := strconv.ParseBool(v)
+			if err != nil {
+				log.Printf("info: ignoring invalid hook requires approval value: %q", v)
+			}
+			h.RequiresApproval = approval
+		}
 	}
 	return nil
 }
@@ -223,7 +232,7 @@ func hasAnyAnnotation(entry util.SimpleHead) bool {
 
 func calculateHookWeight(entry util.SimpleHead) int32 {
 	hws := entry.Metadata.Annotations[hooks.HookWeightAnno]
-	hw, err := strconv.Atoi(hws)
+	hw, err := strconv.ParseInt(strings.TrimSpace(hws), 10, 32)
 	if err != nil {
 		hw = 0
 	}
@@ -335,6 +335,83 @@ func TestSortManifestsHookDeletion(t *testing.T) {
 	}
 }
 
+func TestSortManifestsHookWeight(t *testing.T) {
+	hook := func(kind, name, weight string) string {
+		m := "kind: " + kind + "\nmetadata:\n  name: " + name + "\n  annotations:\n    \"helm.sh/hook\": pre-install\n"
+		if weight != "" {
+			m += "    \"helm.sh/hook-weight\": \"" + weight + "\"\n"
+		}
+		return m
+	}
+
+	testCases := map[string]struct {
+		manifests map[string]string
+		order     []string
+		weights   []int32
+	}{
+		"Ascending weights": {
+			manifests: map[string]string{
+				"a": hook("Job", "late", "10"),
+				"b": hook("Job", "early", "2"),
+			},
+			order:   []string{"early", "late"},
+			weights: []int32{2, 10},
+		},
+		"Negative weights": {
+			manifests: map[string]string{
+				"a": hook("Job", "zero", "0"),
+				"b": hook("Job", "negative", "-5"),
+				"c": hook("Job", "positive", "+3"),
+			},
+			order:   []string{"negative", "zero", "positive"},
+			weights: []int32{-5, 0, 3},
+		},
+		"Ties sorted by kind then name": {
+			manifests: map[string]string{
+				"a": hook("Job", "b-job", "1"),
+				"b": hook("ConfigMap", "z-config", "1"),
+				"c": hook("Job", "a-job", "1"),
+				"d": hook("CustomThing", "custom", "1"),
+				"e": hook("ServiceAccount", "sa", "1"),
+			},
+			order:   []string{"z-config", "sa", "a-job", "b-job", "custom"},
+			weights: []int32{1, 1, 1, 1, 1},
+		},
+		"Missing and invalid weights default to 0": {
+			manifests: map[string]string{
+				"a": hook("Job", "missing", ""),
+				"b": hook("Job", "invalid", "heavy"),
+				"c": hook("Job", "overflow", "4294967296"),
+				"d": hook("Job", "padded", " -1 "),
+			},
+			order:   []string{"padded", "invalid", "missing", "overflow"},
+			weights: []int32{-1, 0, 0, 0},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			hs, _, err := sortManifests(tc.manifests, chartutil.NewVersionSet("v1", "v1beta1"), InstallOrder)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var order []string
+			var weights []int32
+			for _, h := range hs {
+				order = append(order, h.Name)
+				weights = append(weights, h.Weight)
+			}
+			if !reflect.DeepEqual(order, tc.order) {
+				t.Errorf("expected hooks in order %v, but got %v", tc.order, order)
+			}
+			if !reflect.DeepEqual(weights, tc.weights) {
+				t.Errorf("expected weights %v, but got %v", tc.weights, weights)
+			}
+		})
+	}
+}
+
 func TestVersionSet(t *testing.T) {
 	vs := chartutil.NewVersionSet("v1", "v1beta1", "extensions/alpha5", "batch/v1")
 
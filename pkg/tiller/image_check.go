@@ -0,0 +1,336 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/distribution/reference"
+	"github.com/ghodss/yaml"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	relutil "k8s.io/helm/pkg/releaseutil"
+)
+
+// imageRegistryClient is the HTTP client used to talk to image registries.
+var imageRegistryClient = &http.Client{Timeout: 30 * time.Second}
+
+// imageCheckTimeout bounds the time taken to verify all images of a release.
+var imageCheckTimeout = 2 * time.Minute
+
+// imageCheckParallelism is the number of images verified at the same time.
+const imageCheckParallelism = 8
+
+// dockerHubRegistry is where images without a registry host are pulled from.
+const dockerHubRegistry = "registry-1.docker.io"
+
+// tokenHosts are the token services of registries that hand out tokens from
+// a host of their own. Registry credentials are only sent to a token service
+// on the registry's host or listed here.
+var tokenHosts = map[string]string{
+	dockerHubRegistry: "auth.docker.io",
+}
+
+// manifestMediaTypes are the image manifest formats asked of registries.
+var manifestMediaTypes = []string{
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.oci.image.index.v1+json",
+}
+
+// RegistryAuth is a credential for an image registry.
+type RegistryAuth struct {
+	Username string
+	Password string
+}
+
+// LoadRegistryAuths reads registry credentials from the "auths" section of a
+// Docker config.json file, keyed by registry host.
+func LoadRegistryAuths(path string) (map[string]RegistryAuth, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var config struct {
+		Auths map[string]struct {
+			Auth     string `json:"auth"`
+			Username string `json:"username"`
+			Password string `json:"password"`
+		} `json:"auths"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("parsing %s: %s", path, err)
+	}
+
+	auths := make(map[string]RegistryAuth, len(config.Auths))
+	for host, a := range config.Auths {
+		auth := RegistryAuth{Username: a.Username, Password: a.Password}
+		if a.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(a.Auth)
+			if err != nil {
+				return nil, fmt.Errorf("parsing %s: invalid auth for %s: %s", path, host, err)
+			}
+			parts := strings.SplitN(string(decoded), ":", 2)
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("parsing %s: invalid auth for %s", path, host)
+			}
+			auth.Username, auth.Password = parts[0], parts[1]
+		}
+		auths[registryHost(host)] = auth
+	}
+	return auths, nil
+}
+
+// registryHost normalizes the registry keys found in Docker config files,
+// which may be URLs, to a host.
+func registryHost(key string) string {
+	if u, err := url.Parse(key); err == nil && u.Host != "" {
+		key = u.Host
+	}
+	switch key {
+	case "docker.io", "index.docker.io":
+		return dockerHubRegistry
+	}
+	return key
+}
+
+// verifyReleaseImages checks that every container image referenced by the
+// release's manifest and hooks exists in its registry.
+func (s *ReleaseServer) verifyReleaseImages(r *release.Release) error {
+	manifests := []string{r.Manifest}
+	for _, h := range r.Hooks {
+		manifests = append(manifests, h.Manifest)
+	}
+	images, err := referencedImages(manifests)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), imageCheckTimeout)
+	defer cancel()
+	errs := make([]error, len(images))
+	sem := make(chan struct{}, imageCheckParallelism)
+	var wg sync.WaitGroup
+	for i, image := range images {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, image string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			s.Log("verifying image %s for %s", image, r.Name)
+			errs[i] = s.verifyImage(ctx, image)
+		}(i, image)
+	}
+	wg.Wait()
+
+	var missing []string
+	for _, err := range errs {
+		if err != nil {
+			missing = append(missing, err.Error())
+		}
+	}
+	if len(missing) > 0 {
+		return status.Errorf(codes.FailedPrecondition, "release %s references unavailable images: %s", r.Name, strings.Join(missing, "; "))
+	}
+	return nil
+}
+
+// referencedImages returns the container images named in manifests, sorted
+// and without duplicates.
+func referencedImages(manifests []string) ([]string, error) {
+	seen := map[string]bool{}
+	for _, m := range manifests {
+		for _, doc := range relutil.SplitManifests(m) {
+			var obj map[string]interface{}
+			if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+				return nil, fmt.Errorf("YAML parse error: %s", err)
+			}
+			collectImages(obj, seen)
+		}
+	}
+	images := make([]string, 0, len(seen))
+	for image := range seen {
+		images = append(images, image)
+	}
+	sort.Strings(images)
+	return images, nil
+}
+
+// collectImages records the images of every containers and initContainers
+// list found anywhere in v, so pod templates nested in any kind are covered.
+func collectImages(v interface{}, seen map[string]bool) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			if k == "containers" || k == "initContainers" {
+				if containers, ok := child.([]interface{}); ok {
+					for _, c := range containers {
+						if c, ok := c.(map[string]interface{}); ok {
+							if image, ok := c["image"].(string); ok && image != "" {
+								seen[image] = true
+							}
+						}
+					}
+				}
+			}
+			collectImages(child, seen)
+		}
+	case []interface{}:
+		for _, child := range v {
+			collectImages(child, seen)
+		}
+	}
+}
+
+// verifyImage checks with a HEAD request on the image manifest that image
+// exists in its registry.
+func (s *ReleaseServer) verifyImage(ctx context.Context, image string) error {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return fmt.Errorf("%s: invalid image reference: %s", image, err)
+	}
+	ref := "latest"
+	if d, ok := named.(reference.Digested); ok {
+		ref = d.Digest().String()
+	} else if t, ok := named.(reference.Tagged); ok {
+		ref = t.Tag()
+	}
+	host := registryHost(reference.Domain(named))
+	manifestURL := fmt.Sprintf("https://%s/v2/%s/manifests/%s", host, reference.Path(named), ref)
+	auth, hasAuth := s.RegistryAuths[host]
+
+	resp, err := registryHead(ctx, manifestURL, func(req *http.Request) {
+		if hasAuth {
+			req.SetBasicAuth(auth.Username, auth.Password)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %s", image, err)
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		if !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+			return fmt.Errorf("%s: not authorized to access %s", image, host)
+		}
+		token, err := registryToken(ctx, challenge, host, auth, hasAuth)
+		if err != nil {
+			return fmt.Errorf("%s: %s", image, err)
+		}
+		resp, err = registryHead(ctx, manifestURL, func(req *http.Request) {
+			req.Header.Set("Authorization", "Bearer "+token)
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %s", image, err)
+		}
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return fmt.Errorf("%s: not found in %s", image, host)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%s: not authorized to access %s", image, host)
+	default:
+		return fmt.Errorf("%s: unexpected response from %s: %s", image, host, resp.Status)
+	}
+}
+
+// registryHead issues a HEAD request for an image manifest.
+func registryHead(ctx context.Context, u string, authorize func(*http.Request)) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodHead, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	authorize(req)
+	resp, err := imageRegistryClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+var challengeParam = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// registryToken obtains a bearer token from the token service named in a
+// registry's WWW-Authenticate challenge. The registry's credentials are only
+// sent over HTTPS to a token service the registry is known to use; any other
+// is asked for an anonymous token.
+func registryToken(ctx context.Context, challenge, host string, auth RegistryAuth, hasAuth bool) (string, error) {
+	params := map[string]string{}
+	for _, m := range challengeParam.FindAllStringSubmatch(challenge, -1) {
+		params[m[1]] = m[2]
+	}
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Host == "" {
+		return "", fmt.Errorf("invalid token realm %q", params["realm"])
+	}
+	q := realm.Query()
+	for _, p := range []string{"service", "scope"} {
+		if v, ok := params[p]; ok {
+			q.Set(p, v)
+		}
+	}
+	realm.RawQuery = q.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	req = req.WithContext(ctx)
+	if hasAuth && realm.Scheme == "https" && (realm.Host == host || realm.Host == tokenHosts[host]) {
+		req.SetBasicAuth(auth.Username, auth.Password)
+	}
+	resp, err := imageRegistryClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get registry token from %s: %s", realm.Host, resp.Status)
+	}
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("parsing registry token from %s: %s", realm.Host, err)
+	}
+	if token.Token != "" {
+		return token.Token, nil
+	}
+	return token.AccessToken, nil
+}
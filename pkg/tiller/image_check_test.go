@@ -0,0 +1,251 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+// newFakeRegistry serves the manifests of the given repository:tag images,
+// requiring a bearer token that is handed out for user:secret.
+func newFakeRegistry(t *testing.T, images ...string) *httptest.Server {
+	var srv *httptest.Server
+	srv = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			if user, pass, ok := r.BasicAuth(); !ok || user != "user" || pass != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, `{"token": "let-me-in"}`)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer let-me-in" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="fake",scope="repository:any:pull"`, srv.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Method != http.MethodHead {
+			t.Errorf("expected HEAD request, got %s", r.Method)
+		}
+		for _, image := range images {
+			parts := strings.SplitN(image, ":", 2)
+			if r.URL.Path == "/v2/"+parts[0]+"/manifests/"+parts[1] {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	return srv
+}
+
+func TestInstallReleaseVerifyImages(t *testing.T) {
+	srv := newFakeRegistry(t, "team/app:1.0", "team/sidecar:2.1")
+	defer srv.Close()
+	defer func(c *http.Client) { imageRegistryClient = c }(imageRegistryClient)
+	imageRegistryClient = srv.Client()
+	host := strings.TrimPrefix(srv.URL, "https://")
+
+	deployment := func(images ...string) func(*chartOptions) {
+		return func(opts *chartOptions) {
+			manifest := "kind: Deployment\nmetadata:\n  name: app\nspec:\n  template:\n    spec:\n      containers:\n"
+			for i, image := range images {
+				manifest += fmt.Sprintf("      - name: c%d\n        image: %s/%s\n", i, host, image)
+			}
+			opts.Templates = []*chart.Template{{Name: "templates/deployment", Data: []byte(manifest)}}
+		}
+	}
+
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.VerifyImages = true
+	rs.RegistryAuths = map[string]RegistryAuth{host: {Username: "user", Password: "secret"}}
+
+	req := installRequest(withName("present"), withChart(deployment("team/app:1.0", "team/sidecar:2.1")))
+	if _, err := rs.InstallRelease(c, req); err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+
+	req = installRequest(withName("absent"), withChart(deployment("team/app:1.0", "team/app:9.9")))
+	_, err := rs.InstallRelease(c, req)
+	if code := status.Code(err); code != codes.FailedPrecondition {
+		t.Fatalf("Expected code %s, got %s: %v", codes.FailedPrecondition, code, err)
+	}
+	if !strings.Contains(err.Error(), host+"/team/app:9.9: not found") {
+		t.Errorf("Expected error to name the missing image, got %q", err)
+	}
+	if strings.Contains(err.Error(), "team/app:1.0") {
+		t.Errorf("Expected error not to name the present image, got %q", err)
+	}
+	if _, err := rs.env.Releases.Get("absent", 1); err == nil {
+		t.Error("Expected no release to be recorded for a failed image check")
+	}
+}
+
+func TestReferencedImages(t *testing.T) {
+	manifests := []string{
+		`kind: Pod
+metadata:
+  name: web
+spec:
+  initContainers:
+  - name: init
+    image: busybox
+  containers:
+  - name: web
+    image: nginx:1.17
+`,
+		`kind: CronJob
+metadata:
+  name: backup
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+          - name: backup
+            image: example.com/tools/backup@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef
+          - name: web
+            image: nginx:1.17
+---
+kind: ConfigMap
+metadata:
+  name: settings
+data:
+  image: not-a-container
+`,
+	}
+
+	images, err := referencedImages(manifests)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []string{
+		"busybox",
+		"example.com/tools/backup@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+		"nginx:1.17",
+	}
+	if !reflect.DeepEqual(images, expect) {
+		t.Errorf("Expected images %v, got %v", expect, images)
+	}
+}
+
+func TestLoadRegistryAuths(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-registry-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	config := `{"auths": {
+		"https://index.docker.io/v1/": {"auth": "aHViOnB3"},
+		"registry.example.com": {"username": "robot", "password": "token"}
+	}}`
+	path := filepath.Join(dir, "config.json")
+	if err := ioutil.WriteFile(path, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	auths, err := LoadRegistryAuths(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := map[string]RegistryAuth{
+		dockerHubRegistry:      {Username: "hub", Password: "pw"},
+		"registry.example.com": {Username: "robot", Password: "token"},
+	}
+	if !reflect.DeepEqual(auths, expect) {
+		t.Errorf("Expected auths %v, got %v", expect, auths)
+	}
+}
+
+func TestVerifyImageTokenHost(t *testing.T) {
+	// the token service is on a host of its own, which must not be sent the
+	// registry's credentials
+	var sentAuth bool
+	tokens := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _, sentAuth = r.BasicAuth()
+		fmt.Fprint(w, `{"token": "anonymous"}`)
+	}))
+	defer tokens.Close()
+	registry := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer anonymous" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="fake"`, tokens.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer registry.Close()
+	defer func(c *http.Client) { imageRegistryClient = c }(imageRegistryClient)
+	imageRegistryClient = registry.Client()
+	host := strings.TrimPrefix(registry.URL, "https://")
+
+	rs := rsFixture()
+	rs.RegistryAuths = map[string]RegistryAuth{host: {Username: "user", Password: "secret"}}
+	if err := rs.verifyImage(context.Background(), host+"/team/app:1.0"); err != nil {
+		t.Fatalf("Failed to verify image: %s", err)
+	}
+	if sentAuth {
+		t.Error("Expected the registry credentials not to be sent to a token service on another host")
+	}
+}
+
+func TestVerifyReleaseImagesTimeout(t *testing.T) {
+	block := make(chan struct{})
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-block
+	}))
+	defer srv.Close()
+	defer close(block)
+	defer func(c *http.Client) { imageRegistryClient = c }(imageRegistryClient)
+	imageRegistryClient = srv.Client()
+	defer func(d time.Duration) { imageCheckTimeout = d }(imageCheckTimeout)
+	imageCheckTimeout = 100 * time.Millisecond
+	host := strings.TrimPrefix(srv.URL, "https://")
+
+	manifest := "kind: Pod\nspec:\n  containers:\n"
+	for i := 0; i < 2*imageCheckParallelism; i++ {
+		manifest += fmt.Sprintf("  - image: %s/team/app:%d\n", host, i)
+	}
+	start := time.Now()
+	err := rsFixture().verifyReleaseImages(&release.Release{Name: "slow", Manifest: manifest})
+	if err == nil {
+		t.Fatal("Expected the image check to time out")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the image check to be bounded by its timeout, took %s", elapsed)
+	}
+}
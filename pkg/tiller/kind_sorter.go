@@ -26,7 +26,11 @@ type SortOrder []string
 // InstallOrder is the order in which manifests should be installed (by Kind).
 //
 // Those occurring earlier in the list get installed before those occurring later in the list.
+// CustomResourceDefinitions come first; an install creates them in a stage of
+// their own and waits for them to be established before creating the custom
+// resources of the chart.
 var InstallOrder SortOrder = []string{
+	"CustomResourceDefinition",
 	"Namespace",
 	"NetworkPolicy",
 	"ResourceQuota",
@@ -39,7 +43,6 @@ var InstallOrder SortOrder = []string{
 	"PersistentVolume",
 	"PersistentVolumeClaim",
 	"ServiceAccount",
-	"CustomResourceDefinition",
 	"ClusterRole",
 	"ClusterRoleList",
 	"ClusterRoleBinding",
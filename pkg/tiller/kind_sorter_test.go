@@ -18,8 +18,10 @@ package tiller
 
 import (
 	"bytes"
+	"reflect"
 	"testing"
 
+	"k8s.io/helm/pkg/chartutil"
 	util "k8s.io/helm/pkg/releaseutil"
 )
 
@@ -168,7 +170,7 @@ func TestKindSorter(t *testing.T) {
 		order       SortOrder
 		expected    string
 	}{
-		{"install", InstallOrder, "aBbc3zde1fgh2iIjJkKlLmnopqrxstuvw!"},
+		{"install", InstallOrder, "2aBbc3zde1fghiIjJkKlLmnopqrxstuvw!"},
 		{"uninstall", UninstallOrder, "wvmutsxrqponLlKkJjIi2hgf1edz3cbBa!"},
 	} {
 		var buf bytes.Buffer
@@ -268,3 +270,34 @@ func TestKindSorterNamespaceAgainstUnknown(t *testing.T) {
 		}
 	}
 }
+
+func TestInstallOrderCRDsFirst(t *testing.T) {
+	files := map[string]string{
+		"crontab/templates/crontab.yaml": `apiVersion: stable.example.com/v1
+kind: CronTab
+metadata:
+  name: my-crontab
+`,
+		"crontab/templates/namespace.yaml": `apiVersion: v1
+kind: Namespace
+metadata:
+  name: crontabs
+`,
+		"crontab/templates/crd.yaml": `apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: crontabs.stable.example.com
+`,
+	}
+	_, generic, err := sortManifests(files, chartutil.NewVersionSet("v1", "apiextensions.k8s.io/v1beta1"), InstallOrder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kinds []string
+	for _, m := range generic {
+		kinds = append(kinds, m.Head.Kind)
+	}
+	if want := []string{"CustomResourceDefinition", "Namespace", "CronTab"}; !reflect.DeepEqual(kinds, want) {
+		t.Errorf("Expected install order %v, got %v", want, kinds)
+	}
+}
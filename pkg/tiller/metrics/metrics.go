@@ -0,0 +1,145 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics defines the Prometheus metrics Tiller exposes about the
+// releases it manages.
+package metrics // import "k8s.io/helm/pkg/tiller/metrics"
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"k8s.io/helm/pkg/storage"
+)
+
+// The release operations Tiller reports metrics for.
+const (
+	Install   = "install"
+	Upgrade   = "upgrade"
+	Rollback  = "rollback"
+	Uninstall = "uninstall"
+)
+
+var (
+	operationsTotal = map[string]*prometheus.CounterVec{}
+
+	operationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "helm_release_operation_duration_seconds",
+		Help:    "Time taken by release operations.",
+		Buckets: []float64{0.1, 0.5, 1, 5, 10, 30, 60, 120, 300, 600},
+	}, []string{"operation"})
+)
+
+func init() {
+	for _, op := range []string{Install, Upgrade, Rollback, Uninstall} {
+		operationsTotal[op] = prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "helm_release_" + op + "_total",
+			Help: "Number of release " + op + "s, by whether they succeeded.",
+		}, []string{"status"})
+	}
+}
+
+// ObserveOperation records the outcome of a release operation that started
+// at start and failed with err, if err is not nil.
+func ObserveOperation(operation string, start time.Time, err error) {
+	status := "success"
+	if err != nil {
+		status = "failure"
+	}
+	if c, ok := operationsTotal[operation]; ok {
+		c.WithLabelValues(status).Inc()
+	}
+	operationDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}
+
+// Collectors returns the collectors of the release metrics, including a gauge
+// of the release records held by releases.
+func Collectors(releases *storage.Storage) []prometheus.Collector {
+	collectors := []prometheus.Collector{operationDuration, &releaseCollector{releases: releases}}
+	for _, op := range []string{Install, Upgrade, Rollback, Uninstall} {
+		collectors = append(collectors, operationsTotal[op])
+	}
+	return collectors
+}
+
+var releasesDesc = prometheus.NewDesc(
+	"helm_releases",
+	"Number of release records in storage, by status.",
+	[]string{"status"}, nil,
+)
+
+// releaseCountInterval is how long release counts are served before they
+// are counted again. Counting decodes every record in storage, so it is done
+// in the background rather than on every scrape.
+var releaseCountInterval = 30 * time.Second
+
+// releaseCollector reports the release records in storage, counted at most
+// once per releaseCountInterval.
+type releaseCollector struct {
+	releases *storage.Storage
+
+	mu       sync.Mutex
+	counts   map[string]int
+	err      error
+	counted  time.Time
+	counting bool
+}
+
+func (c *releaseCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- releasesDesc
+}
+
+func (c *releaseCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	if c.counted.IsZero() {
+		// Nothing has been counted yet; count before the first scrape returns.
+		c.mu.Unlock()
+		c.count()
+		c.mu.Lock()
+	} else if !c.counting && time.Since(c.counted) >= releaseCountInterval {
+		c.counting = true
+		go c.count()
+	}
+	counts, err := c.counts, c.err
+	c.mu.Unlock()
+
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(releasesDesc, err)
+		return
+	}
+	for status, n := range counts {
+		ch <- prometheus.MustNewConstMetric(releasesDesc, prometheus.GaugeValue, float64(n), status)
+	}
+}
+
+// count counts the release records in storage by status and stores the
+// result for later scrapes.
+func (c *releaseCollector) count() {
+	rels, err := c.releases.ListReleases()
+	counts := map[string]int{}
+	for _, r := range rels {
+		counts[strings.ToLower(r.Info.Status.Code.String())]++
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts, c.err = counts, err
+	c.counted = time.Now()
+	c.counting = false
+}
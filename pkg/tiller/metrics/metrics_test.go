@@ -0,0 +1,157 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/storage"
+	"k8s.io/helm/pkg/storage/driver"
+)
+
+func TestMetrics(t *testing.T) {
+	releases := storage.Init(driver.NewMemory())
+	for i, code := range []release.Status_Code{release.Status_SUPERSEDED, release.Status_DEPLOYED, release.Status_DEPLOYED} {
+		rel := &release.Release{
+			Name:    fmt.Sprintf("rel%d", i),
+			Version: 1,
+			Info:    &release.Info{Status: &release.Status{Code: code}},
+		}
+		if err := releases.Create(rel); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reg := prometheus.NewRegistry()
+	for _, c := range Collectors(releases) {
+		reg.MustRegister(c)
+	}
+
+	ObserveOperation(Install, time.Now(), nil)
+	ObserveOperation(Install, time.Now(), errors.New("failed"))
+	ObserveOperation(Install, time.Now(), nil)
+	ObserveOperation(Rollback, time.Now(), nil)
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]map[string]float64{}
+	for _, f := range families {
+		got[f.GetName()] = map[string]float64{}
+		for _, m := range f.GetMetric() {
+			got[f.GetName()][labelValue(m)] = value(m)
+		}
+	}
+
+	expect := map[string]map[string]float64{
+		"helm_release_install_total":              {"success": 2, "failure": 1},
+		"helm_release_rollback_total":             {"success": 1},
+		"helm_release_operation_duration_seconds": {"install": 3, "rollback": 1},
+		"helm_releases":                           {"deployed": 2, "superseded": 1},
+	}
+	for name, values := range expect {
+		for label, v := range values {
+			if got[name][label] != v {
+				t.Errorf("Expected %s{%s} to be %v, got %v", name, label, v, got[name][label])
+			}
+		}
+	}
+}
+
+func TestReleaseCountsAreCached(t *testing.T) {
+	defer func(d time.Duration) { releaseCountInterval = d }(releaseCountInterval)
+	releaseCountInterval = time.Hour
+
+	releases := storage.Init(driver.NewMemory())
+	create := func(name string) {
+		rel := &release.Release{
+			Name:    name,
+			Version: 1,
+			Info:    &release.Info{Status: &release.Status{Code: release.Status_DEPLOYED}},
+		}
+		if err := releases.Create(rel); err != nil {
+			t.Fatal(err)
+		}
+	}
+	deployed := func(c prometheus.Collector) float64 {
+		reg := prometheus.NewRegistry()
+		reg.MustRegister(c)
+		families, err := reg.Gather()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, f := range families {
+			for _, m := range f.GetMetric() {
+				if labelValue(m) == "deployed" {
+					return value(m)
+				}
+			}
+		}
+		return 0
+	}
+
+	c := &releaseCollector{releases: releases}
+	create("rel0")
+	if n := deployed(c); n != 1 {
+		t.Fatalf("Expected 1 deployed release, got %v", n)
+	}
+
+	create("rel1")
+	if n := deployed(c); n != 1 {
+		t.Errorf("Expected the cached count of 1 before the interval passed, got %v", n)
+	}
+
+	releaseCountInterval = 0
+	deployed(c) // starts counting again in the background
+	for i := 0; i < 100; i++ {
+		c.mu.Lock()
+		n := c.counts["deployed"]
+		c.mu.Unlock()
+		if n == 2 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Error("Expected the counts to be refreshed in the background")
+}
+
+func labelValue(m *dto.Metric) string {
+	if len(m.GetLabel()) == 0 {
+		return ""
+	}
+	return m.GetLabel()[0].GetValue()
+}
+
+func value(m *dto.Metric) float64 {
+	switch {
+	case m.GetCounter() != nil:
+		return m.GetCounter().GetValue()
+	case m.GetGauge() != nil:
+		return m.GetGauge().GetValue()
+	case m.GetHistogram() != nil:
+		return float64(m.GetHistogram().GetSampleCount())
+	}
+	return 0
+}
@@ -0,0 +1,204 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	relutil "k8s.io/helm/pkg/releaseutil"
+)
+
+// estimatedResourcesAnnotation is the chart annotation declaring the resources
+// a release of the chart consumes, as comma-separated ResourceQuota resource
+// names and quantities, e.g. "requests.cpu=2,requests.memory=4Gi,pods=6".
+const estimatedResourcesAnnotation = "helm.sh/estimated-resources"
+
+// quotaAliases maps quota resource names to the equivalent names a footprint
+// may use instead.
+var quotaAliases = map[v1.ResourceName]v1.ResourceName{
+	v1.ResourceCPU:            v1.ResourceRequestsCPU,
+	v1.ResourceMemory:         v1.ResourceRequestsMemory,
+	v1.ResourceRequestsCPU:    v1.ResourceCPU,
+	v1.ResourceRequestsMemory: v1.ResourceMemory,
+}
+
+// checkQuotas rejects the release if its estimated footprint, added to what
+// the namespace already uses, exceeds a hard limit of any of the namespace's
+// ResourceQuotas.
+func (s *ReleaseServer) checkQuotas(r *release.Release) error {
+	quotas, err := s.clientset.CoreV1().ResourceQuotas(r.Namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("listing resource quotas in %s: %s", r.Namespace, err)
+	}
+	if len(quotas.Items) == 0 {
+		return nil
+	}
+
+	footprint, err := releaseFootprint(r)
+	if err != nil {
+		return err
+	}
+
+	var exceeded []string
+	for _, q := range quotas.Items {
+		for name, hard := range q.Status.Hard {
+			need, ok := footprint[name]
+			if !ok {
+				// Quotas on "cpu" and "memory" count requests.
+				need, ok = footprint[quotaAliases[name]]
+			}
+			if !ok {
+				continue
+			}
+			total := q.Status.Used[name]
+			total.Add(need)
+			if total.Cmp(hard) > 0 {
+				used := q.Status.Used[name]
+				exceeded = append(exceeded, fmt.Sprintf("%s %s: needs %s, %s of %s used", q.Name, name, need.String(), used.String(), hard.String()))
+			}
+		}
+	}
+	if len(exceeded) > 0 {
+		sort.Strings(exceeded)
+		return status.Errorf(codes.ResourceExhausted, "release %s exceeds the resource quotas of namespace %s: %s", r.Name, r.Namespace, strings.Join(exceeded, "; "))
+	}
+	return nil
+}
+
+// releaseFootprint returns the resources the release declares in its chart's
+// estimated-resources annotation or, without one, the sum of the requests
+// and limits of the pods its manifest creates.
+func releaseFootprint(r *release.Release) (v1.ResourceList, error) {
+	if r.Chart != nil && r.Chart.Metadata != nil {
+		if declared, ok := r.Chart.Metadata.Annotations[estimatedResourcesAnnotation]; ok {
+			footprint, err := parseFootprint(declared)
+			if err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "chart annotation %s: %s", estimatedResourcesAnnotation, err)
+			}
+			return footprint, nil
+		}
+	}
+	return manifestFootprint(r.Manifest)
+}
+
+func parseFootprint(s string) (v1.ResourceList, error) {
+	footprint := v1.ResourceList{}
+	for _, entry := range strings.Split(s, ",") {
+		parts := strings.SplitN(strings.TrimSpace(entry), "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("expected name=quantity, got %q", entry)
+		}
+		q, err := resource.ParseQuantity(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid quantity for %s: %s", parts[0], err)
+		}
+		addResource(footprint, v1.ResourceName(strings.TrimSpace(parts[0])), q)
+	}
+	return footprint, nil
+}
+
+// podTemplateKinds maps the kinds whose pods are counted by the manifest
+// footprint to the path of their pod spec. DaemonSets are left out, as their
+// size depends on the cluster.
+var podTemplateKinds = map[string][]string{
+	"Pod":                   {"spec"},
+	"Deployment":            {"spec", "template", "spec"},
+	"ReplicaSet":            {"spec", "template", "spec"},
+	"ReplicationController": {"spec", "template", "spec"},
+	"StatefulSet":           {"spec", "template", "spec"},
+	"Job":                   {"spec", "template", "spec"},
+}
+
+func manifestFootprint(manifest string) (v1.ResourceList, error) {
+	footprint := v1.ResourceList{}
+	for _, doc := range relutil.SplitManifests(manifest) {
+		var raw map[string]interface{}
+		if err := yaml.Unmarshal([]byte(doc), &raw); err != nil {
+			return nil, fmt.Errorf("YAML parse error: %s", err)
+		}
+		kind, _ := raw["kind"].(string)
+		path, ok := podTemplateKinds[kind]
+		if !ok {
+			continue
+		}
+		spec, ok := lookupMap(raw, path)
+		if !ok {
+			continue
+		}
+		replicas := int64(1)
+		if kind != "Pod" {
+			if top, ok := lookupMap(raw, []string{"spec"}); ok {
+				if n, ok := top["replicas"].(float64); ok {
+					replicas = int64(n)
+				}
+			}
+		}
+		b, err := yaml.Marshal(spec)
+		if err != nil {
+			return nil, err
+		}
+		var pod v1.PodSpec
+		if err := yaml.Unmarshal(b, &pod); err != nil {
+			return nil, fmt.Errorf("%s pod spec: %s", kind, err)
+		}
+
+		addResource(footprint, v1.ResourcePods, *resource.NewQuantity(replicas, resource.DecimalSI))
+		for _, c := range pod.Containers {
+			for name, q := range c.Resources.Requests {
+				addResource(footprint, "requests."+name, scaleQuantity(q, replicas))
+			}
+			for name, q := range c.Resources.Limits {
+				addResource(footprint, "limits."+name, scaleQuantity(q, replicas))
+			}
+		}
+	}
+	return footprint, nil
+}
+
+func lookupMap(m map[string]interface{}, path []string) (map[string]interface{}, bool) {
+	for _, key := range path {
+		next, ok := m[key].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		m = next
+	}
+	return m, true
+}
+
+func scaleQuantity(q resource.Quantity, n int64) resource.Quantity {
+	return *resource.NewMilliQuantity(q.MilliValue()*n, q.Format)
+}
+
+func addResource(list v1.ResourceList, name v1.ResourceName, q resource.Quantity) {
+	if cur, ok := list[name]; ok {
+		cur.Add(q)
+		list[name] = cur
+		return
+	}
+	list[name] = q.DeepCopy()
+}
@@ -0,0 +1,95 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+func TestInstallReleaseEnforceQuotas(t *testing.T) {
+	quota := &v1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "compute", Namespace: "spaced"},
+		Status: v1.ResourceQuotaStatus{
+			Hard: v1.ResourceList{v1.ResourceCPU: resource.MustParse("4"), v1.ResourceLimitsMemory: resource.MustParse("8Gi")},
+			Used: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1500m"), v1.ResourceLimitsMemory: resource.MustParse("2Gi")},
+		},
+	}
+	rs := NewReleaseServer(MockEnvironment(), fake.NewSimpleClientset(quota), false)
+	rs.EnforceQuotas = true
+	c := helm.NewContext()
+
+	declared := func(footprint string) func(*chartOptions) {
+		return func(opts *chartOptions) {
+			opts.Metadata.Annotations = map[string]string{estimatedResourcesAnnotation: footprint}
+		}
+	}
+
+	req := installRequest(withName("small"), withChart(declared("requests.cpu=2,limits.memory=4Gi")))
+	if _, err := rs.InstallRelease(c, req); err != nil {
+		t.Fatalf("Failed install within quota: %s", err)
+	}
+
+	req = installRequest(withName("large"), withChart(declared("requests.cpu=3,limits.memory=4Gi")))
+	_, err := rs.InstallRelease(c, req)
+	if code := status.Code(err); code != codes.ResourceExhausted {
+		t.Fatalf("Expected code %s, got %s: %v", codes.ResourceExhausted, code, err)
+	}
+	if !strings.Contains(err.Error(), "compute cpu: needs 3, 1500m of 4 used") {
+		t.Errorf("Expected error to describe the exceeded quota, got %q", err)
+	}
+	if _, err := rs.env.Releases.Get("large", 1); err == nil {
+		t.Error("Expected no release to be recorded for an over-budget install")
+	}
+
+	// Without an annotation, the pod resources of the manifest are summed.
+	deployment := func(opts *chartOptions) {
+		opts.Templates = []*chart.Template{{Name: "templates/deployment", Data: []byte(`kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 4
+  template:
+    spec:
+      containers:
+      - name: web
+        resources:
+          requests:
+            cpu: 500m
+          limits:
+            memory: 2Gi
+`)}}
+	}
+	req = installRequest(withName("manifest"), withChart(deployment))
+	_, err = rs.InstallRelease(c, req)
+	if code := status.Code(err); code != codes.ResourceExhausted {
+		t.Fatalf("Expected code %s, got %s: %v", codes.ResourceExhausted, code, err)
+	}
+	if !strings.Contains(err.Error(), "compute limits.memory: needs 8Gi, 2Gi of 8Gi used") || strings.Contains(err.Error(), "compute cpu") {
+		t.Errorf("Expected only limits.memory to be exceeded, got %q", err)
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"fmt"
+
+	ctx "golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+// ApproveRelease completes an upgrade that paused before a post-upgrade hook
+// annotated with helm.sh/hook-requires-approval. The post-upgrade hooks are
+// run and, if they succeed, the release replaces the deployed one.
+func (s *ReleaseServer) ApproveRelease(c ctx.Context, req *services.ApproveReleaseRequest) (res *services.ApproveReleaseResponse, err error) {
+	defer func() { s.recordAudit(c, auditedRelease(req.Name, "", res.GetRelease()), "approve", err) }()
+
+	if err := validateReleaseName(req.Name); err != nil {
+		s.Log("approveRelease: Release name is invalid: %s", req.Name)
+		return nil, err
+	}
+	unlock, err := s.locks.lock(c, req.Name, s.LockTimeout)
+	if err != nil {
+		s.Log("approveRelease: %s", err)
+		return nil, err
+	}
+	defer unlock()
+
+	var rel *release.Release
+	if req.Version <= 0 {
+		rel, err = s.env.Releases.Last(req.Name)
+	} else {
+		rel, err = s.env.Releases.Get(req.Name, req.Version)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if rel.Info.Status.Code != release.Status_PENDING_APPROVAL {
+		return nil, status.Errorf(codes.FailedPrecondition, "release %s version %d is %s, not awaiting approval", rel.Name, rel.Version, rel.Info.Status.Code)
+	}
+	res = &services.ApproveReleaseResponse{Release: rel}
+
+	// The paused upgrade is completed as UpdateRelease would have.
+	original, err := s.env.Releases.Deployed(rel.Name)
+	if err != nil {
+		return res, err
+	}
+	upgrade := &services.UpdateReleaseRequest{Name: rel.Name, Timeout: req.Timeout}
+	s.Log("running approved post-upgrade hooks for %s", rel.Name)
+	if _, err := s.finishUpdate(c, original, rel, upgrade, &services.UpdateReleaseResponse{Release: rel}); err != nil {
+		msg := fmt.Sprintf("Release %q failed post-upgrade: %s", rel.Name, err)
+		s.Log("warning: %s", msg)
+		rel.Info.Status.Code = release.Status_FAILED
+		rel.Info.Description = msg
+		s.recordRelease(rel, true)
+		return res, err
+	}
+
+	if err := s.updateRelease(rel); err != nil {
+		return res, err
+	}
+	if s.AutoRollbackWindow > 0 {
+		go s.watchReleaseHealth(rel, original.Version, s.AutoRollbackWindow)
+	}
+	return res, nil
+}
+
+// requiresApproval reports whether any of the hooks for the given event must
+// wait for the release to be approved.
+func requiresApproval(hs []*release.Hook, hook string) bool {
+	code := events[hook]
+	for _, h := range hs {
+		if !h.RequiresApproval {
+			continue
+		}
+		for _, e := range h.Events {
+			if e == code {
+				return true
+			}
+		}
+	}
+	return false
+}
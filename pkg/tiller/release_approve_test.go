@@ -0,0 +1,108 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+var manifestWithApprovalHook = `kind: ConfigMap
+metadata:
+  name: test-migration
+  annotations:
+    "helm.sh/hook": post-upgrade
+    "helm.sh/hook-requires-approval": "true"
+data:
+  name: value`
+
+func TestApproveRelease(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+
+	req := &services.UpdateReleaseRequest{
+		Name: rel.Name,
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "hello"},
+			Templates: []*chart.Template{
+				{Name: "templates/hello", Data: []byte("hello: world")},
+				{Name: "templates/migration", Data: []byte(manifestWithApprovalHook)},
+			},
+		},
+	}
+	res, err := rs.UpdateRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed updated: %s", err)
+	}
+
+	pending, err := rs.env.Releases.Get(rel.Name, 2)
+	if err != nil {
+		t.Fatalf("Expected pending release to be stored: %s", err)
+	}
+	if pending.Info.Status.Code != release.Status_PENDING_APPROVAL {
+		t.Fatalf("Expected status PENDING_APPROVAL, got %s", pending.Info.Status.Code)
+	}
+	if len(pending.Hooks) != 1 || !pending.Hooks[0].RequiresApproval {
+		t.Fatalf("Expected one hook requiring approval, got %v", pending.Hooks)
+	}
+	if pending.Hooks[0].LastRun != nil {
+		t.Error("Expected post-upgrade hook not to run before approval")
+	}
+	if deployed, err := rs.env.Releases.Deployed(rel.Name); err != nil || deployed.Version != 1 {
+		t.Errorf("Expected version 1 to stay deployed until approval, got %v (%v)", deployed, err)
+	}
+
+	ares, err := rs.ApproveRelease(c, &services.ApproveReleaseRequest{Name: res.Release.Name})
+	if err != nil {
+		t.Fatalf("Failed approval: %s", err)
+	}
+	if ares.Release.Hooks[0].LastRun == nil {
+		t.Error("Expected post-upgrade hook to run after approval")
+	}
+
+	approved, err := rs.env.Releases.Get(rel.Name, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if approved.Info.Status.Code != release.Status_DEPLOYED {
+		t.Errorf("Expected status DEPLOYED after approval, got %s", approved.Info.Status.Code)
+	}
+	if approved.Info.Description != "Upgrade complete" {
+		t.Errorf("Expected the approved upgrade to complete, got %q", approved.Info.Description)
+	}
+	previous, err := rs.env.Releases.Get(rel.Name, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if previous.Info.Status.Code != release.Status_SUPERSEDED {
+		t.Errorf("Expected previous release to be SUPERSEDED, got %s", previous.Info.Status.Code)
+	}
+
+	_, err = rs.ApproveRelease(c, &services.ApproveReleaseRequest{Name: rel.Name})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition approving a deployed release, got %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	ctx "golang.org/x/net/context"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	relutil "k8s.io/helm/pkg/releaseutil"
+	"k8s.io/helm/pkg/timeconv"
+)
+
+// GetReleaseAudit returns the audit trail of a release. Entries recorded on
+// the release records by older versions of Tiller come first.
+func (s *ReleaseServer) GetReleaseAudit(c ctx.Context, req *services.GetReleaseAuditRequest) (*services.GetReleaseAuditResponse, error) {
+	if err := validateReleaseName(req.Name); err != nil {
+		s.Log("getReleaseAudit: Release name is invalid: %s", req.Name)
+		return nil, err
+	}
+
+	entries, err := s.AuditStore.Entries(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	h, err := s.env.Releases.History(req.Name)
+	if err != nil && len(entries) == 0 {
+		// the trail outlives a purged release
+		return nil, err
+	}
+	relutil.SortByRevision(h)
+
+	var res services.GetReleaseAuditResponse
+	for _, rel := range h {
+		res.Entries = append(res.Entries, rel.Audit...)
+	}
+	res.Entries = append(res.Entries, entries...)
+	return &res, nil
+}
+
+// auditedRelease returns the first of rels that is set, or a release with
+// the given name and namespace for an operation rejected before its release
+// was known.
+func auditedRelease(name, namespace string, rels ...*release.Release) *release.Release {
+	for _, rel := range rels {
+		if rel != nil {
+			return rel
+		}
+	}
+	return &release.Release{Name: name, Namespace: namespace}
+}
+
+// recordAudit adds an entry for action on the release to its audit trail,
+// failing if opErr is set, and writes it to the audit log. Only releases with
+// a valid name have an audit trail.
+func (s *ReleaseServer) recordAudit(c ctx.Context, rel *release.Release, action string, opErr error) {
+	if rel == nil || (!s.Audit && s.AuditLog == nil) {
+		return
+	}
+	now := time.Now()
+	entry := &release.AuditEntry{
+		Principal: principalFromContext(c),
+		Action:    action,
+		Timestamp: timeconv.Timestamp(now),
+		Outcome:   release.AuditEntry_SUCCESS,
+	}
+	if opErr != nil {
+		entry.Outcome = release.AuditEntry_FAILURE
+		entry.Message = opErr.Error()
+	}
+	if s.AuditLog != nil {
+		err := s.AuditLog.Write(AuditRecord{
+			Time:      now.UTC(),
+			Action:    action,
+			Release:   rel.Name,
+			Namespace: rel.Namespace,
+			Version:   rel.Version,
+			Principal: entry.Principal,
+			Outcome:   entry.Outcome.String(),
+			Error:     entry.Message,
+		})
+		if err != nil {
+			s.Log("warning: failed to write audit log for %s of %s: %s", action, rel.Name, err)
+		}
+	}
+	if !s.Audit || validateReleaseName(rel.Name) != nil {
+		return
+	}
+	if err := s.AuditStore.Append(rel.Name, entry); err != nil {
+		s.Log("warning: failed to record audit entry for %s of %s: %s", action, rel.Name, err)
+	}
+}
+
+// principalFromContext identifies the client of a request by the common name
+// of its TLS certificate, or by a fingerprint of its bearer token, falling
+// back to its network address.
+func principalFromContext(c ctx.Context) string {
+	p, ok := peer.FromContext(c)
+	if ok {
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(info.State.PeerCertificates) > 0 {
+			return info.State.PeerCertificates[0].Subject.CommonName
+		}
+	}
+	if token, err := bearerToken(c); err == nil {
+		sum := sha256.Sum256([]byte(token))
+		return "token:" + hex.EncodeToString(sum[:4])
+	}
+	if !ok {
+		return "unknown"
+	}
+	if p.Addr != nil {
+		return p.Addr.String()
+	}
+	return "unknown"
+}
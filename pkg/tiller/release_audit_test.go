@@ -0,0 +1,235 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+func TestGetReleaseAudit(t *testing.T) {
+	c := peer.NewContext(context.TODO(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 7), Port: 4242}})
+	rs := rsFixture()
+	rs.Audit = true
+
+	if _, err := rs.InstallRelease(c, installRequest(withName("audited"))); err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	upgrade := &services.UpdateReleaseRequest{Name: "audited", Chart: buildChart()}
+	if _, err := rs.UpdateRelease(c, upgrade); err != nil {
+		t.Fatalf("Failed upgrade: %s", err)
+	}
+
+	res, err := rs.GetReleaseAudit(c, &services.GetReleaseAuditRequest{Name: "audited"})
+	if err != nil {
+		t.Fatalf("Failed to get audit: %s", err)
+	}
+	if len(res.Entries) != 2 {
+		t.Fatalf("Expected 2 audit entries, got %d: %v", len(res.Entries), res.Entries)
+	}
+	for i, action := range []string{"install", "upgrade"} {
+		e := res.Entries[i]
+		if e.Action != action {
+			t.Errorf("Expected entry %d to be %q, got %q", i, action, e.Action)
+		}
+		if e.Outcome != release.AuditEntry_SUCCESS {
+			t.Errorf("Expected entry %d to succeed, got %s", i, e.Outcome)
+		}
+		if e.Principal != "10.0.0.7:4242" {
+			t.Errorf("Expected entry %d principal 10.0.0.7:4242, got %q", i, e.Principal)
+		}
+		if e.Timestamp == nil {
+			t.Errorf("Expected entry %d to have a timestamp", i)
+		}
+	}
+	if res.Entries[1].Timestamp.Seconds < res.Entries[0].Timestamp.Seconds {
+		t.Error("Expected audit entries to be ordered oldest first")
+	}
+}
+
+func TestGetReleaseAudit_OutlivesRevisions(t *testing.T) {
+	c := context.TODO()
+	rs := rsFixture()
+	rs.Audit = true
+	rs.env.Releases.MaxHistory = 1
+
+	if _, err := rs.InstallRelease(c, installRequest(withName("pruned"))); err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := rs.UpdateRelease(c, &services.UpdateReleaseRequest{Name: "pruned", Chart: buildChart()}); err != nil {
+			t.Fatalf("Failed upgrade: %s", err)
+		}
+	}
+	if _, err := rs.UninstallRelease(c, &services.UninstallReleaseRequest{Name: "pruned", Purge: true}); err != nil {
+		t.Fatalf("Failed uninstall: %s", err)
+	}
+
+	res, err := rs.GetReleaseAudit(c, &services.GetReleaseAuditRequest{Name: "pruned"})
+	if err != nil {
+		t.Fatalf("Failed to get audit: %s", err)
+	}
+	var actions []string
+	for _, e := range res.Entries {
+		actions = append(actions, e.Action)
+	}
+	if got := strings.Join(actions, ","); got != "install,upgrade,upgrade,uninstall" {
+		t.Errorf("Expected the whole trail after pruning and purging, got %s", got)
+	}
+}
+
+func TestConfigMapAuditStore(t *testing.T) {
+	store := NewConfigMapAuditStore(fake.NewSimpleClientset().CoreV1().ConfigMaps("kube-system"))
+
+	if entries, err := store.Entries("none"); err != nil || len(entries) != 0 {
+		t.Fatalf("Expected no entries for an unknown release, got %v, %v", entries, err)
+	}
+	for _, action := range []string{"install", "upgrade"} {
+		if err := store.Append("stored", &release.AuditEntry{Action: action, Principal: "me"}); err != nil {
+			t.Fatalf("Failed to append %s: %s", action, err)
+		}
+	}
+	entries, err := store.Entries("stored")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Action != "install" || entries[1].Action != "upgrade" || entries[1].Principal != "me" {
+		t.Errorf("Expected the appended entries in order, got %v", entries)
+	}
+}
+
+func TestAuditLog(t *testing.T) {
+	c := metadata.NewIncomingContext(context.TODO(), metadata.Pairs("authorization", "Bearer s3cr3t"))
+	var buf bytes.Buffer
+	rs := rsFixture()
+	rs.AuditLog = NewAuditLog(&buf)
+
+	if _, err := rs.InstallRelease(c, installRequest(withName("logged"))); err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	// the records themselves are left alone unless Audit is enabled
+	rel, err := rs.env.Releases.Get("logged", 1)
+	if err != nil {
+		t.Fatalf("Failed to get release: %s", err)
+	}
+	if len(rel.Audit) > 0 {
+		t.Errorf("Expected no audit entries on the release record, got %v", rel.Audit)
+	}
+
+	rs.env.KubeClient = newUpdateFailingKubeClient()
+	upgrade := &services.UpdateReleaseRequest{Name: "logged", Chart: buildChart()}
+	if _, err := rs.UpdateRelease(c, upgrade); err == nil {
+		t.Fatal("Expected the upgrade to fail")
+	}
+	if _, err := rs.UninstallRelease(c, &services.UninstallReleaseRequest{Name: "logged", Purge: true}); err != nil {
+		t.Fatalf("Failed uninstall: %s", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 audit records, got %d:\n%s", len(lines), buf.String())
+	}
+	want := []struct {
+		action  string
+		version int32
+		outcome string
+	}{
+		{"install", 1, "SUCCESS"},
+		{"upgrade", 2, "FAILURE"},
+		{"uninstall", 2, "SUCCESS"},
+	}
+	for i, line := range lines {
+		var r AuditRecord
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			t.Fatalf("Invalid audit record %q: %s", line, err)
+		}
+		if r.Action != want[i].action || r.Version != want[i].version || r.Outcome != want[i].outcome {
+			t.Errorf("Expected record %d to be %+v, got %+v", i, want[i], r)
+		}
+		if r.Release != "logged" || r.Time.IsZero() {
+			t.Errorf("Expected record %d to name the release and time, got %+v", i, r)
+		}
+		if !strings.HasPrefix(r.Principal, "token:") || strings.Contains(r.Principal, "s3cr3t") {
+			t.Errorf("Expected record %d to identify the token without revealing it, got %q", i, r.Principal)
+		}
+		if (r.Error != "") != (r.Outcome == "FAILURE") {
+			t.Errorf("Expected record %d to have an error only on failure, got %q", i, r.Error)
+		}
+	}
+}
+
+func TestAuditLog_RejectedOperations(t *testing.T) {
+	c := context.TODO()
+	var buf bytes.Buffer
+	rs := rsFixture()
+	rs.Audit = true
+	rs.AuditLog = NewAuditLog(&buf)
+	rs.MaxValuesBytes = 1
+
+	req := installRequest(withName("rejected"))
+	req.Namespace = "spaced"
+	req.Values = &chart.Config{Raw: "name: value"}
+	if _, err := rs.InstallRelease(c, req); err == nil {
+		t.Fatal("Expected the install to be rejected")
+	}
+	if _, err := rs.UninstallRelease(c, &services.UninstallReleaseRequest{Name: "rejected"}); err == nil {
+		t.Fatal("Expected the uninstall of a missing release to fail")
+	}
+	if _, err := rs.UninstallRelease(c, &services.UninstallReleaseRequest{Name: "in valid"}); err == nil {
+		t.Fatal("Expected the uninstall of an invalid name to fail")
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	want := []AuditRecord{
+		{Action: "install", Release: "rejected", Namespace: "spaced", Outcome: "FAILURE"},
+		{Action: "uninstall", Release: "rejected", Outcome: "FAILURE"},
+		{Action: "uninstall", Release: "in valid", Outcome: "FAILURE"},
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("Expected %d audit records, got %d:\n%s", len(want), len(lines), buf.String())
+	}
+	for i, line := range lines {
+		var r AuditRecord
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			t.Fatalf("Invalid audit record %q: %s", line, err)
+		}
+		if r.Action != want[i].Action || r.Release != want[i].Release || r.Namespace != want[i].Namespace || r.Outcome != want[i].Outcome || r.Error == "" {
+			t.Errorf("Expected record %d to be %+v, got %+v", i, want[i], r)
+		}
+	}
+
+	// rejections are on the trail of a release with a valid name
+	res, err := rs.GetReleaseAudit(c, &services.GetReleaseAuditRequest{Name: "rejected"})
+	if err != nil {
+		t.Fatalf("Failed to get audit: %s", err)
+	}
+	if len(res.Entries) != 2 || res.Entries[0].Action != "install" || res.Entries[0].Outcome != release.AuditEntry_FAILURE {
+		t.Errorf("Expected the rejected install and uninstall on the trail, got %v", res.Entries)
+	}
+}
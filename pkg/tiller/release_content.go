@@ -17,9 +17,17 @@ limitations under the License.
 package tiller
 
 import (
+	"fmt"
+
+	"github.com/ghodss/yaml"
 	ctx "golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
+	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
+	relutil "k8s.io/helm/pkg/releaseutil"
+	storageerrors "k8s.io/helm/pkg/storage/errors"
 )
 
 // GetReleaseContent gets all of the stored information for the given release.
@@ -29,11 +37,93 @@ func (s *ReleaseServer) GetReleaseContent(c ctx.Context, req *services.GetReleas
 		return nil, err
 	}
 
+	if req.ExcludeChart {
+		rel, err := s.env.Releases.GetContent(req.Name, req.Version)
+		return contentResponse(rel, req.SplitManifest), err
+	}
+
+	var (
+		rel *release.Release
+		err error
+	)
 	if req.Version <= 0 {
-		rel, err := s.env.Releases.Last(req.Name)
+		rel, err = s.env.Releases.Last(req.Name)
+	} else {
+		rel, err = s.env.Releases.Get(req.Name, req.Version)
+	}
+	if err != nil {
 		return &services.GetReleaseContentResponse{Release: rel}, err
 	}
+	if err := checkStoredChart(rel); err != nil {
+		return nil, err
+	}
+	return contentResponse(rel, req.SplitManifest), nil
+}
+
+// contentResponse returns rel, along with the documents of its manifest if
+// split is set.
+func contentResponse(rel *release.Release, split bool) *services.GetReleaseContentResponse {
+	res := &services.GetReleaseContentResponse{Release: rel}
+	if split && rel != nil {
+		res.Manifests = splitManifest(rel.Manifest)
+	}
+	return res
+}
+
+// splitManifest splits manifest into the documents of its resources, in
+// order, skipping documents that describe no resource.
+func splitManifest(manifest string) []*services.ManifestDocument {
+	split, _ := parseManifest(manifest)
+	return split
+}
+
+// parseManifest splits manifest like splitManifest, and also returns, in
+// order, the documents that cannot be parsed.
+func parseManifest(manifest string) (split []*services.ManifestDocument, unparsable []string) {
+	docs := relutil.SplitManifests(manifest)
+	for i := 0; i < len(docs); i++ {
+		doc := docs[fmt.Sprintf("manifest-%d", i)]
+		var head relutil.SimpleHead
+		if err := yaml.Unmarshal([]byte(doc), &head); err != nil {
+			unparsable = append(unparsable, doc)
+			continue
+		}
+		if head.Kind == "" {
+			continue
+		}
+		d := &services.ManifestDocument{ApiVersion: head.Version, Kind: head.Kind, Content: doc}
+		if head.Metadata != nil {
+			d.Name = head.Metadata.Name
+			d.Namespace = head.Metadata.Namespace
+		}
+		split = append(split, d)
+	}
+	return split, unparsable
+}
 
-	rel, err := s.env.Releases.Get(req.Name, req.Version)
-	return &services.GetReleaseContentResponse{Release: rel}, err
+// GetReleaseContents gets the content of each of the requested releases. A
+// release that cannot be retrieved does not fail the others: its result
+// carries the status code and message of the error instead.
+func (s *ReleaseServer) GetReleaseContents(c ctx.Context, req *services.GetReleaseContentsRequest) (*services.GetReleaseContentsResponse, error) {
+	res := &services.GetReleaseContentsResponse{Results: make([]*services.ReleaseContentResult, len(req.Releases))}
+	for i, r := range req.Releases {
+		if err := c.Err(); err != nil {
+			return nil, err
+		}
+		result := &services.ReleaseContentResult{}
+		rc, err := s.GetReleaseContent(c, r)
+		if err != nil {
+			st := status.Convert(err)
+			code := st.Code()
+			if storageerrors.IsReleaseNotFound(err) {
+				code = codes.NotFound
+			}
+			result.Code = int32(code)
+			result.Error = st.Message()
+		} else {
+			result.Release = rc.Release
+		}
+		res.Results[i] = result
+	}
+	return res, nil
 }
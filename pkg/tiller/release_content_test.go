@@ -17,8 +17,12 @@ limitations under the License.
 package tiller
 
 import (
+	"strings"
 	"testing"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	"k8s.io/helm/pkg/helm"
 	"k8s.io/helm/pkg/proto/hapi/services"
 )
@@ -40,3 +44,150 @@ func TestGetReleaseContent(t *testing.T) {
 		t.Errorf("Expected %q, got %q", rel.Chart.Metadata.Name, res.Release.Chart.Metadata.Name)
 	}
 }
+
+func TestGetReleaseContentExcludeChart(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	if err := rs.env.Releases.Create(rel); err != nil {
+		t.Fatalf("Could not store mock release: %s", err)
+	}
+
+	for _, version := range []int32{0, 1} {
+		res, err := rs.GetReleaseContent(c, &services.GetReleaseContentRequest{Name: rel.Name, Version: version, ExcludeChart: true})
+		if err != nil {
+			t.Fatalf("Error getting release content: %s", err)
+		}
+		if res.Release.Manifest != rel.Manifest {
+			t.Errorf("Expected manifest %q, got %q", rel.Manifest, res.Release.Manifest)
+		}
+		if res.Release.Chart.Metadata.Name != rel.Chart.Metadata.Name {
+			t.Errorf("Expected chart %q, got %q", rel.Chart.Metadata.Name, res.Release.Chart.Metadata.Name)
+		}
+		if len(res.Release.Chart.Templates) != 0 {
+			t.Errorf("Expected no chart templates, got %d", len(res.Release.Chart.Templates))
+		}
+	}
+	if len(rel.Chart.Templates) == 0 {
+		t.Error("Expected the stored release to keep its chart templates")
+	}
+}
+
+func TestGetReleaseContentMissingChart(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rel.Chart = nil
+	if err := rs.env.Releases.Create(rel); err != nil {
+		t.Fatalf("Could not store mock release: %s", err)
+	}
+
+	_, err := rs.GetReleaseContent(c, &services.GetReleaseContentRequest{Name: rel.Name, Version: 1})
+	if code := status.Code(err); code != codes.FailedPrecondition {
+		t.Fatalf("Expected code %s, got %s: %v", codes.FailedPrecondition, code, err)
+	}
+
+	// The manifest alone is still available.
+	res, err := rs.GetReleaseContent(c, &services.GetReleaseContentRequest{Name: rel.Name, ExcludeChart: true})
+	if err != nil {
+		t.Fatalf("Error getting release content without chart: %s", err)
+	}
+	if res.Release.Manifest != rel.Manifest {
+		t.Errorf("Expected manifest %q, got %q", rel.Manifest, res.Release.Manifest)
+	}
+}
+
+func TestGetReleaseContents(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	if err := rs.env.Releases.Create(rel); err != nil {
+		t.Fatalf("Could not store mock release: %s", err)
+	}
+
+	res, err := rs.GetReleaseContents(c, &services.GetReleaseContentsRequest{
+		Releases: []*services.GetReleaseContentRequest{
+			{Name: rel.Name, Version: 1},
+			{Name: "missing", Version: 1},
+			{Name: rel.Name, ExcludeChart: true},
+			{Name: "Invalid_Name"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Error getting release contents: %s", err)
+	}
+	if len(res.Results) != 4 {
+		t.Fatalf("Expected 4 results, got %d", len(res.Results))
+	}
+
+	for _, i := range []int{0, 2} {
+		r := res.Results[i]
+		if r.Code != int32(codes.OK) || r.Error != "" {
+			t.Errorf("Expected result %d to succeed, got code %d: %s", i, r.Code, r.Error)
+		}
+		if r.Release == nil || r.Release.Name != rel.Name {
+			t.Errorf("Expected result %d to hold release %q, got %v", i, rel.Name, r.Release)
+		}
+	}
+	if r := res.Results[1]; r.Code != int32(codes.NotFound) || r.Release != nil || r.Error == "" {
+		t.Errorf("Expected a not found result for a missing release, got code %d: %q", r.Code, r.Error)
+	}
+	if r := res.Results[3]; r.Code == int32(codes.OK) || r.Error == "" {
+		t.Errorf("Expected an error for an invalid release name, got code %d", r.Code)
+	}
+}
+
+func TestGetReleaseContentSplitManifest(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rel.Manifest = `---
+# Source: hello/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: hello
+---
+# Source: hello/templates/empty.yaml
+---
+# Source: hello/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: hello
+  namespace: other
+`
+	if err := rs.env.Releases.Create(rel); err != nil {
+		t.Fatalf("Could not store mock release: %s", err)
+	}
+
+	for _, exclude := range []bool{false, true} {
+		res, err := rs.GetReleaseContent(c, &services.GetReleaseContentRequest{Name: rel.Name, Version: 1, ExcludeChart: exclude, SplitManifest: true})
+		if err != nil {
+			t.Fatalf("Error getting release content: %s", err)
+		}
+		if len(res.Manifests) != 2 {
+			t.Fatalf("Expected 2 manifests, got %d: %v", len(res.Manifests), res.Manifests)
+		}
+		want := []services.ManifestDocument{
+			{ApiVersion: "v1", Kind: "Service", Name: "hello"},
+			{ApiVersion: "apps/v1", Kind: "Deployment", Name: "hello", Namespace: "other"},
+		}
+		for i, m := range res.Manifests {
+			if m.ApiVersion != want[i].ApiVersion || m.Kind != want[i].Kind || m.Name != want[i].Name || m.Namespace != want[i].Namespace {
+				t.Errorf("Expected manifest %d to be %s/%s %s in %q, got %s/%s %s in %q", i, want[i].ApiVersion, want[i].Kind, want[i].Name, want[i].Namespace, m.ApiVersion, m.Kind, m.Name, m.Namespace)
+			}
+			if !strings.Contains(m.Content, "kind: "+want[i].Kind) {
+				t.Errorf("Expected manifest %d to hold its document, got %q", i, m.Content)
+			}
+		}
+	}
+
+	res, err := rs.GetReleaseContent(c, &services.GetReleaseContentRequest{Name: rel.Name, Version: 1})
+	if err != nil {
+		t.Fatalf("Error getting release content: %s", err)
+	}
+	if len(res.Manifests) != 0 {
+		t.Errorf("Expected no split manifests unless requested, got %d", len(res.Manifests))
+	}
+}
@@ -0,0 +1,104 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"fmt"
+	"time"
+
+	ctx "golang.org/x/net/context"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/timeconv"
+)
+
+// maxGCInterval bounds how long a deleted release may outlive its TTL.
+const maxGCInterval = 10 * time.Minute
+
+// CollectDeletedReleases purges the records of releases that were deleted
+// more than ttl ago, checking until stop is closed. Releases are checked once
+// before stop is.
+func (s *ReleaseServer) CollectDeletedReleases(ttl time.Duration, stop <-chan struct{}) {
+	interval := ttl
+	if interval > maxGCInterval {
+		interval = maxGCInterval
+	}
+	for {
+		if _, err := s.collectDeletedReleases(ttl); err != nil {
+			s.Log("warning: failed to collect deleted releases: %s", err)
+		}
+		select {
+		case <-stop:
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// collectDeletedReleases deletes every revision of the releases whose latest
+// revision was deleted more than ttl ago, and returns how many releases it
+// purged. A release that was installed again under the same name is kept.
+func (s *ReleaseServer) collectDeletedReleases(ttl time.Duration) (int, error) {
+	deleted, err := s.env.Releases.ListDeleted()
+	if err != nil {
+		return 0, err
+	}
+
+	cutoff := time.Now().Add(-ttl)
+	seen := map[string]bool{}
+	purged := 0
+	for _, rel := range deleted {
+		if seen[rel.Name] {
+			continue
+		}
+		seen[rel.Name] = true
+
+		ok, err := s.purgeIfDeletedBefore(rel.Name, cutoff)
+		if err != nil {
+			s.Log("gc: %s", err)
+			continue
+		}
+		if ok {
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// purgeIfDeletedBefore purges the named release if its latest revision was
+// deleted before cutoff. The release is locked and checked again, as it may
+// have been installed again since it was listed.
+func (s *ReleaseServer) purgeIfDeletedBefore(name string, cutoff time.Time) (bool, error) {
+	unlock, err := s.locks.lock(ctx.Background(), name, s.LockTimeout)
+	if err != nil {
+		return false, fmt.Errorf("failed to lock release %s: %s", name, err)
+	}
+	defer unlock()
+
+	last, err := s.env.Releases.Last(name)
+	if err != nil {
+		return false, fmt.Errorf("failed to load release %s: %s", name, err)
+	}
+	if last.Info.Status.Code != release.Status_DELETED || last.Info.Deleted == nil || timeconv.Time(last.Info.Deleted).After(cutoff) {
+		return false, nil
+	}
+	if err := s.purgeRelease(name); err != nil {
+		return false, fmt.Errorf("failed to purge release %s: %s", name, err)
+	}
+	s.Log("gc: purged release %s, deleted at %s", name, timeconv.String(last.Info.Deleted))
+	return true, nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/timeconv"
+)
+
+func TestCollectDeletedReleases(t *testing.T) {
+	rs := rsFixture()
+
+	old := namedReleaseStub("old-deleted", release.Status_SUPERSEDED)
+	oldDeleted := namedReleaseStub("old-deleted", release.Status_DELETED)
+	oldDeleted.Version = 2
+	oldDeleted.Info.Deleted = timeconv.Timestamp(time.Now().Add(-2 * time.Hour))
+
+	recent := namedReleaseStub("recently-deleted", release.Status_DELETED)
+	recent.Info.Deleted = timeconv.Timestamp(time.Now().Add(-time.Minute))
+
+	deployed := namedReleaseStub("still-deployed", release.Status_DEPLOYED)
+
+	for _, rel := range []*release.Release{old, oldDeleted, recent, deployed} {
+		if err := rs.env.Releases.Create(rel); err != nil {
+			t.Fatalf("Could not store mock release: %s", err)
+		}
+	}
+
+	stop := make(chan struct{})
+	close(stop)
+	rs.CollectDeletedReleases(time.Hour, stop)
+
+	if h, err := rs.env.Releases.History("old-deleted"); err != nil || len(h) != 0 {
+		t.Errorf("Expected every revision of old-deleted to be purged, got %v (%v)", h, err)
+	}
+	for _, name := range []string{"recently-deleted", "still-deployed"} {
+		if _, err := rs.env.Releases.Last(name); err != nil {
+			t.Errorf("Expected %s to be kept: %s", name, err)
+		}
+	}
+}
+
+func TestCollectDeletedReleasesWaitsForOperations(t *testing.T) {
+	rs := rsFixture()
+
+	deleted := namedReleaseStub("reinstalled", release.Status_DELETED)
+	deleted.Info.Deleted = timeconv.Timestamp(time.Now().Add(-2 * time.Hour))
+	if err := rs.env.Releases.Create(deleted); err != nil {
+		t.Fatalf("Could not store mock release: %s", err)
+	}
+
+	// an install of the same name is in progress when the release is listed
+	unlock, err := rs.locks.lock(context.Background(), "reinstalled", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan int)
+	go func() {
+		purged, err := rs.collectDeletedReleases(time.Hour)
+		if err != nil {
+			t.Error(err)
+		}
+		done <- purged
+	}()
+
+	reinstalled := namedReleaseStub("reinstalled", release.Status_DEPLOYED)
+	reinstalled.Version = 2
+	if err := rs.env.Releases.Create(reinstalled); err != nil {
+		t.Fatalf("Could not store mock release: %s", err)
+	}
+	unlock()
+
+	if purged := <-done; purged != 0 {
+		t.Errorf("Expected nothing to be purged, got %d releases", purged)
+	}
+	if h, err := rs.env.Releases.History("reinstalled"); err != nil || len(h) != 2 {
+		t.Errorf("Expected the reinstalled release to be kept, got %v (%v)", h, err)
+	}
+}
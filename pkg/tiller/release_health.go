@@ -0,0 +1,138 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	ctx "golang.org/x/net/context"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	relutil "k8s.io/helm/pkg/releaseutil"
+	"k8s.io/helm/pkg/tiller/metrics"
+)
+
+// healthCheckInterval is how often the health of an upgraded release is
+// checked during its auto rollback window.
+var healthCheckInterval = 10 * time.Second
+
+// watchReleaseHealth checks the Deployments of r until window has passed, and
+// rolls r back to version previous if its health regresses: a Deployment that
+// was available becomes unavailable, or fails to progress. Monitoring stops
+// early if another revision of the release is recorded in the meantime.
+func (s *ReleaseServer) watchReleaseHealth(r *release.Release, previous int32, window time.Duration) {
+	deadline := time.Now().Add(window)
+	wasReady := false
+	for time.Now().Before(deadline) {
+		time.Sleep(healthCheckInterval)
+
+		if last, err := s.env.Releases.Last(r.Name); err != nil || last.Version != r.Version {
+			return
+		}
+		failed, unavailable, err := s.deploymentHealth(r)
+		if err != nil {
+			s.Log("warning: failed to check the health of %s: %s", r.Name, err)
+			continue
+		}
+		if len(failed) == 0 && len(unavailable) == 0 {
+			wasReady = true
+			continue
+		}
+		if len(failed) == 0 && !wasReady {
+			// Still rolling out.
+			continue
+		}
+		unhealthy := append(failed, unavailable...)
+
+		if err := s.rollBackUnhealthy(r, previous, unhealthy); err != nil {
+			s.Log("warning: failed to roll back %s: %s", r.Name, err)
+		}
+		return
+	}
+}
+
+// rollBackUnhealthy rolls r back to version previous, unless another revision
+// of the release was recorded since its health was checked. The check and the
+// rollback are made while holding the release's lock.
+func (s *ReleaseServer) rollBackUnhealthy(r *release.Release, previous int32, unhealthy []string) error {
+	unlock, err := s.locks.lock(ctx.Background(), r.Name, s.LockTimeout)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if last, err := s.env.Releases.Last(r.Name); err != nil || last.Version != r.Version {
+		return err
+	}
+
+	s.Log("release %s became unhealthy after upgrade to version %d (%s), rolling back to version %d", r.Name, r.Version, strings.Join(unhealthy, ", "), previous)
+	start := time.Now()
+	res, err := s.rollbackRelease(ctx.Background(), &services.RollbackReleaseRequest{
+		Name:        r.Name,
+		Version:     previous,
+		Description: fmt.Sprintf("Rollback to %d: %s unhealthy after upgrade", previous, strings.Join(unhealthy, ", ")),
+	})
+	metrics.ObserveOperation(metrics.Rollback, start, err)
+	s.recordAudit(ctx.Background(), auditedRelease(r.Name, r.Namespace, res.GetRelease()), "rollback", err)
+	return err
+}
+
+// deploymentHealth returns the Deployments of r that failed to progress, and
+// those that are not fully available, as "Deployment/name".
+func (s *ReleaseServer) deploymentHealth(r *release.Release) (failed, unavailable []string, err error) {
+	for _, doc := range relutil.SplitManifests(r.Manifest) {
+		var head relutil.SimpleHead
+		if err := yaml.Unmarshal([]byte(doc), &head); err != nil || head.Kind != "Deployment" || head.Metadata == nil {
+			continue
+		}
+		d, err := s.clientset.AppsV1().Deployments(r.Namespace).Get(head.Metadata.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, err
+		}
+		name := "Deployment/" + d.Name
+		switch {
+		case deploymentFailed(d):
+			failed = append(failed, name)
+		case !deploymentAvailable(d):
+			unavailable = append(unavailable, name)
+		}
+	}
+	return failed, unavailable, nil
+}
+
+func deploymentAvailable(d *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+	return d.Status.AvailableReplicas >= replicas && d.Status.UnavailableReplicas == 0
+}
+
+func deploymentFailed(d *appsv1.Deployment) bool {
+	for _, c := range d.Status.Conditions {
+		if c.Type == appsv1.DeploymentProgressing && c.Status == v1.ConditionFalse && c.Reason == "ProgressDeadlineExceeded" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+var manifestWithDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 2
+`
+
+func TestUpdateReleaseAutoRollback(t *testing.T) {
+	defer func(d time.Duration) { healthCheckInterval = d }(healthCheckInterval)
+	healthCheckInterval = 10 * time.Millisecond
+
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.AutoRollbackWindow = 10 * time.Second
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+
+	replicas := int32(2)
+	deployments := rs.clientset.AppsV1().Deployments(rel.Namespace)
+	d, err := deployments.Create(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: rel.Namespace},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status:     appsv1.DeploymentStatus{Replicas: 2, AvailableReplicas: 2},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := &services.UpdateReleaseRequest{
+		Name: rel.Name,
+		Chart: &chart.Chart{
+			Metadata:  &chart.Metadata{Name: "hello"},
+			Templates: []*chart.Template{{Name: "templates/web", Data: []byte(manifestWithDeployment)}},
+		},
+	}
+	if _, err := rs.UpdateRelease(c, req); err != nil {
+		t.Fatalf("Failed updated: %s", err)
+	}
+
+	// Let the watcher see the Deployment available before it regresses.
+	time.Sleep(50 * time.Millisecond)
+	d.Status.AvailableReplicas, d.Status.UnavailableReplicas = 1, 1
+	if _, err := deployments.UpdateStatus(d); err != nil {
+		t.Fatal(err)
+	}
+
+	var last *release.Release
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if last, err = rs.env.Releases.Last(rel.Name); err == nil && last.Version == 3 {
+			break
+		}
+	}
+	if last == nil || last.Version != 3 {
+		t.Fatalf("Expected the upgrade to be rolled back as version 3, got %v", last)
+	}
+	if last.Info.Status.Code != release.Status_DEPLOYED || last.Manifest != rel.Manifest {
+		t.Errorf("Expected version 3 to redeploy version 1, got %s with manifest %q", last.Info.Status.Code, last.Manifest)
+	}
+	if !strings.Contains(last.Info.Description, "Deployment/web") {
+		t.Errorf("Expected the rollback description to name the unhealthy Deployment, got %q", last.Info.Description)
+	}
+}
+
+func TestRollBackUnhealthyWaitsForOperations(t *testing.T) {
+	rs := rsFixture()
+	v1 := namedReleaseStub("flaky", release.Status_SUPERSEDED)
+	v2 := namedReleaseStub("flaky", release.Status_DEPLOYED)
+	v2.Version = 2
+	for _, r := range []*release.Release{v1, v2} {
+		if err := rs.env.Releases.Create(r); err != nil {
+			t.Fatalf("Could not store mock release: %s", err)
+		}
+	}
+
+	// an upgrade to version 3 is in progress when version 2 turns unhealthy
+	unlock, err := rs.locks.lock(context.Background(), "flaky", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error)
+	go func() { done <- rs.rollBackUnhealthy(v2, 1, []string{"Deployment/web"}) }()
+
+	v3 := namedReleaseStub("flaky", release.Status_DEPLOYED)
+	v3.Version = 3
+	if err := rs.env.Releases.Create(v3); err != nil {
+		t.Fatalf("Could not store mock release: %s", err)
+	}
+	unlock()
+
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if last, err := rs.env.Releases.Last("flaky"); err != nil || last.Version != 3 {
+		t.Errorf("Expected version 3 not to be rolled back, got %v (%v)", last, err)
+	}
+}
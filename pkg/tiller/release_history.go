@@ -35,6 +35,15 @@ func (s *ReleaseServer) GetHistory(ctx context.Context, req *tpb.GetHistoryReque
 	if err != nil {
 		return nil, err
 	}
+	// Release records are not labelled with their namespace, so it cannot be
+	// part of the query. The query already selects only the records of this
+	// release by its NAME label, so the namespace is matched here instead of
+	// listing every release with a filter.
+	if req.Namespace != "" {
+		if h, err = filterByNamespace(req.Namespace, h); err != nil {
+			return nil, err
+		}
+	}
 
 	relutil.Reverse(h, relutil.SortByRevision)
 
@@ -113,3 +113,32 @@ func TestGetHistory_WithNoRevisions(t *testing.T) {
 		}
 	}
 }
+
+func TestGetHistory_WithNamespace(t *testing.T) {
+	srv := rsFixture()
+	for i, ns := range []string{"staging", "staging", "prod"} {
+		rls := &rpb.Release{
+			Name:      "moved-bird",
+			Version:   int32(i + 1),
+			Namespace: ns,
+			Info:      &rpb.Info{Status: &rpb.Status{Code: rpb.Status_SUPERSEDED}},
+		}
+		if err := srv.env.Releases.Create(rls); err != nil {
+			t.Fatalf("Failed to create release: %s", err)
+		}
+	}
+
+	for ns, expect := range map[string][]int32{"": {3, 2, 1}, "staging": {2, 1}, "prod": {3}, "dev": nil} {
+		res, err := srv.GetHistory(helm.NewContext(), &tpb.GetHistoryRequest{Name: "moved-bird", Max: 256, Namespace: ns})
+		if err != nil {
+			t.Fatalf("Failed to get history in namespace %q: %s", ns, err)
+		}
+		var got []int32
+		for _, rls := range res.Releases {
+			got = append(got, rls.Version)
+		}
+		if !reflect.DeepEqual(got, expect) {
+			t.Errorf("Expected revisions %v in namespace %q, got %v", expect, ns, got)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	ctx "golang.org/x/net/context"
+
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+// GetReleaseHooks returns the hooks stored with a release, without its chart.
+func (s *ReleaseServer) GetReleaseHooks(c ctx.Context, req *services.GetReleaseHooksRequest) (*services.GetReleaseHooksResponse, error) {
+	if err := validateReleaseName(req.Name); err != nil {
+		s.Log("getReleaseHooks: Release name is invalid: %s", req.Name)
+		return nil, err
+	}
+
+	rel, err := s.env.Releases.GetContent(req.Name, req.Version)
+	if err != nil {
+		return nil, err
+	}
+	return &services.GetReleaseHooksResponse{Hooks: rel.Hooks}, nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+var manifestWithWeightedHooks = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: late-cm
+  annotations:
+    "helm.sh/hook": pre-install,pre-upgrade
+    "helm.sh/hook-weight": "5"
+    "helm.sh/hook-delete-policy": hook-succeeded,before-hook-creation
+data:
+  name: value
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: early-cm
+  annotations:
+    "helm.sh/hook": post-install
+    "helm.sh/hook-weight": "-5"
+data:
+  name: value
+`
+
+func TestGetReleaseHooks(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	req := installRequest(withName("hooked"))
+	req.Chart.Templates = []*chart.Template{{Name: "templates/hooks", Data: []byte(manifestWithWeightedHooks)}}
+	if _, err := rs.InstallRelease(c, req); err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+
+	res, err := rs.GetReleaseHooks(c, &services.GetReleaseHooksRequest{Name: "hooked"})
+	if err != nil {
+		t.Fatalf("Failed to get hooks: %s", err)
+	}
+
+	expect, _, err := sortManifests(map[string]string{"hello/templates/hooks": manifestWithWeightedHooks}, chartutil.NewVersionSet("v1"), InstallOrder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Hooks) != len(expect) {
+		t.Fatalf("Expected %d hooks, got %d", len(expect), len(res.Hooks))
+	}
+	for i, h := range res.Hooks {
+		got := *h
+		// LastRun is set when the hook runs during the install.
+		got.LastRun = nil
+		if !proto.Equal(&got, expect[i]) {
+			t.Errorf("Expected hook %d to be %v, got %v", i, expect[i], &got)
+		}
+	}
+
+	if _, err := rs.GetReleaseHooks(c, &services.GetReleaseHooksRequest{Name: "hooked", Version: 2}); err == nil {
+		t.Error("Expected an error for a missing version")
+	}
+}
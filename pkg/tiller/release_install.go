@@ -19,21 +19,79 @@ package tiller
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	ctx "golang.org/x/net/context"
+	"google.golang.org/grpc/status"
 
 	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/hooks"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
 	relutil "k8s.io/helm/pkg/releaseutil"
+	"k8s.io/helm/pkg/tiller/metrics"
 	"k8s.io/helm/pkg/timeconv"
 )
 
 // InstallRelease installs a release and stores the release record.
-func (s *ReleaseServer) InstallRelease(c ctx.Context, req *services.InstallReleaseRequest) (*services.InstallReleaseResponse, error) {
+func (s *ReleaseServer) InstallRelease(c ctx.Context, req *services.InstallReleaseRequest) (res *services.InstallReleaseResponse, err error) {
+	defer func(start time.Time) { metrics.ObserveOperation(metrics.Install, start, err) }(time.Now())
+	defer func() {
+		if !req.DryRun {
+			s.recordAudit(c, auditedRelease(req.Name, req.Namespace, res.GetRelease()), "install", err)
+		}
+	}()
+
+	// A nameless install that is retried with the same request id gets the
+	// name generated on its first attempt, replacing whatever that left behind.
+	reqID := requestIDFromContext(c)
+	if req.Name == "" && reqID != "" {
+		if name, ok := s.names.get(reqID); ok {
+			s.Log("reusing name %s generated for request %s", name, reqID)
+			req.Name = name
+			req.ReuseName = true
+		}
+	}
+
+	// Kubernetes operations must not outlive the client's deadline.
+	req.Timeout = requestTimeout(c, req.Timeout)
+
+	if req.Namespace == "" {
+		req.Namespace = s.DefaultNamespace
+	}
+
+	if req.Name != "" {
+		unlock, err := s.locks.lock(c, req.Name, s.LockTimeout)
+		if err != nil {
+			s.Log("installRelease: %s", err)
+			return nil, err
+		}
+		defer unlock()
+	}
+
+	if req.Values, err = mergeSetValues(req.Values, req.SetValues); err != nil {
+		s.Log("rejected install of %s: %s", req.Name, err)
+		return nil, err
+	}
+	if err := s.checkValuesSize(req.Values); err != nil {
+		s.Log("rejected install of %s: %s", req.Name, err)
+		return nil, err
+	}
+
+	if req.Chart == nil && req.ChartReference != nil {
+		ch, err := s.fetchChart(req.ChartReference)
+		if err != nil {
+			s.Log("failed to fetch chart %s: %s", req.ChartReference.Name, err)
+			return nil, err
+		}
+		req.Chart = ch
+	}
+
 	s.Log("preparing install for %s", req.Name)
 	rel, err := s.prepareRelease(req)
+	if req.Name == "" && reqID != "" && rel != nil {
+		s.names.put(reqID, rel.Name)
+	}
 	if err != nil {
 		s.Log("failed install prepare step: %s", err)
 		res := &services.InstallReleaseResponse{Release: rel}
@@ -46,21 +104,56 @@ func (s *ReleaseServer) InstallRelease(c ctx.Context, req *services.InstallRelea
 		return res, err
 	}
 
+	if s.VerifyImages && !req.DryRun {
+		if err := s.verifyReleaseImages(rel); err != nil {
+			s.Log("failed install image verification: %s", err)
+			return &services.InstallReleaseResponse{Release: rel}, err
+		}
+	}
+
+	if s.EnforceQuotas && !req.DryRun {
+		if err := s.checkQuotas(rel); err != nil {
+			s.Log("failed install quota check: %s", err)
+			return &services.InstallReleaseResponse{Release: rel}, err
+		}
+	}
+
 	s.Log("performing install for %s", req.Name)
-	res, err := s.performRelease(rel, req)
+	res, err = s.performRelease(c, rel, req)
+	if err == nil && req.Atomic && !req.DryRun && c.Err() != nil {
+		// The client went away or its deadline passed while installing, so
+		// nobody will learn that the install succeeded. Treat it as failed.
+		err = status.FromContextError(c.Err()).Err()
+	}
 	if err != nil {
 		s.Log("failed install perform step: %s", err)
+		if req.Atomic && !req.DryRun {
+			s.cleanupAtomicInstall(rel, req, err)
+		}
 	}
 	return res, err
 }
 
+// cleanupAtomicInstall deletes the resources of a failed atomic install and
+// records the release as failed.
+func (s *ReleaseServer) cleanupAtomicInstall(r *release.Release, req *services.InstallReleaseRequest, cause error) {
+	s.Log("atomic install of %s failed, deleting its resources", r.Name)
+	_, _, errs := s.ReleaseModule.Delete(r, &services.UninstallReleaseRequest{Name: r.Name, Timeout: req.Timeout}, s.env)
+	for _, e := range errs {
+		s.Log("error: %v", e)
+	}
+	r.Info.Status.Code = release.Status_FAILED
+	r.Info.Description = fmt.Sprintf("Release %q failed and its resources were deleted: %s", r.Name, cause)
+	s.recordRelease(r, true)
+}
+
 // prepareRelease builds a release for an install operation.
 func (s *ReleaseServer) prepareRelease(req *services.InstallReleaseRequest) (*release.Release, error) {
 	if req.Chart == nil {
 		return nil, errMissingChart
 	}
 
-	name, err := s.uniqName(req.Name, req.ReuseName)
+	name, err := s.releaseName(req)
 	if err != nil {
 		return nil, err
 	}
@@ -70,7 +163,15 @@ func (s *ReleaseServer) prepareRelease(req *services.InstallReleaseRequest) (*re
 		return nil, err
 	}
 
-	revision := 1
+	if err := s.applyDefaultValuesFile(req.Chart); err != nil {
+		return nil, err
+	}
+
+	revision, err := s.baseRevision(name, req.BaseRevision)
+	if err != nil {
+		return nil, err
+	}
+
 	ts := timeconv.Now()
 	options := chartutil.ReleaseOptions{
 		Name:      name,
@@ -130,6 +231,26 @@ func (s *ReleaseServer) prepareRelease(req *services.InstallReleaseRequest) (*re
 	return rel, nil
 }
 
+// baseRevision returns the revision a new release named name is first stored
+// at. A requested base of 0 means the default of 1; any other base must be
+// positive and above every revision already recorded under that name.
+func (s *ReleaseServer) baseRevision(name string, base int32) (int, error) {
+	if base == 0 {
+		return 1, nil
+	}
+	if base < 0 {
+		return 0, fmt.Errorf("%s: base revision must be positive, got %d", errInvalidRevision, base)
+	}
+	if h, err := s.env.Releases.History(name); err == nil {
+		for _, rel := range h {
+			if rel.Version >= base {
+				return 0, fmt.Errorf("base revision %d collides with existing revision %d of release %s", base, rel.Version, name)
+			}
+		}
+	}
+	return int(base), nil
+}
+
 func hasCRDHook(hs []*release.Hook) bool {
 	for _, h := range hs {
 		for _, e := range h.Events {
@@ -142,7 +263,7 @@ func hasCRDHook(hs []*release.Hook) bool {
 }
 
 // performRelease runs a release.
-func (s *ReleaseServer) performRelease(r *release.Release, req *services.InstallReleaseRequest) (*services.InstallReleaseResponse, error) {
+func (s *ReleaseServer) performRelease(c ctx.Context, r *release.Release, req *services.InstallReleaseRequest) (*services.InstallReleaseResponse, error) {
 	res := &services.InstallReleaseResponse{Release: r}
 	manifestDoc := []byte(r.Manifest)
 
@@ -157,7 +278,7 @@ func (s *ReleaseServer) performRelease(r *release.Release, req *services.Install
 
 		// Here's the problem with dry runs and CRDs: We can't install a CRD
 		// during a dry run, which means it cannot be validated.
-		if err := validateManifest(s.env.KubeClient, req.Namespace, manifestDoc); err != nil {
+		if err := s.validateRendered(req.Namespace, manifestDoc); err != nil {
 			return res, err
 		}
 
@@ -167,7 +288,9 @@ func (s *ReleaseServer) performRelease(r *release.Release, req *services.Install
 
 	// crd-install hooks
 	if !req.DisableHooks && !req.DisableCrdHook {
-		if err := s.execHook(r.Hooks, r.Name, r.Namespace, hooks.CRDInstall, req.Timeout); err != nil {
+		results, err := s.execHookWithResults(c, r.Hooks, r.Name, r.Namespace, hooks.CRDInstall, req.Timeout)
+		res.HookResults = append(res.HookResults, results...)
+		if err != nil {
 			fmt.Printf("Finished installing CRD: %s", err)
 			return res, err
 		}
@@ -176,13 +299,15 @@ func (s *ReleaseServer) performRelease(r *release.Release, req *services.Install
 	}
 
 	// Because the CRDs are installed, they are used for validation during this step.
-	if err := validateManifest(s.env.KubeClient, req.Namespace, manifestDoc); err != nil {
+	if err := s.validateRendered(req.Namespace, manifestDoc); err != nil {
 		return res, fmt.Errorf("validation failed: %s", err)
 	}
 
 	// pre-install hooks
 	if !req.DisableHooks {
-		if err := s.execHook(r.Hooks, r.Name, r.Namespace, hooks.PreInstall, req.Timeout); err != nil {
+		results, err := s.execHookWithResults(c, r.Hooks, r.Name, r.Namespace, hooks.PreInstall, req.Timeout)
+		res.HookResults = append(res.HookResults, results...)
+		if err != nil {
 			return res, err
 		}
 	} else {
@@ -204,8 +329,11 @@ func (s *ReleaseServer) performRelease(r *release.Release, req *services.Install
 		s.recordRelease(old, true)
 
 		// update new release with next revision number
-		// so as to append to the old release's history
-		r.Version = old.Version + 1
+		// so as to append to the old release's history,
+		// unless a higher base revision was requested
+		if r.Version <= old.Version {
+			r.Version = old.Version + 1
+		}
 		updateReq := &services.UpdateReleaseRequest{
 			Wait:     req.Wait,
 			Recreate: false,
@@ -239,7 +367,9 @@ func (s *ReleaseServer) performRelease(r *release.Release, req *services.Install
 
 	// post-install hooks
 	if !req.DisableHooks {
-		if err := s.execHook(r.Hooks, r.Name, r.Namespace, hooks.PostInstall, req.Timeout); err != nil {
+		results, err := s.execHookWithResults(c, r.Hooks, r.Name, r.Namespace, hooks.PostInstall, req.Timeout)
+		res.HookResults = append(res.HookResults, results...)
+		if err != nil {
 			msg := fmt.Sprintf("Release %q failed post-install: %s", r.Name, err)
 			s.Log("warning: %s", msg)
 			r.Info.Status.Code = release.Status_FAILED
@@ -258,10 +388,8 @@ func (s *ReleaseServer) performRelease(r *release.Release, req *services.Install
 	// This is a tricky case. The release has been created, but the result
 	// cannot be recorded. The truest thing to tell the user is that the
 	// release was created. However, the user will not be able to do anything
-	// further with this release.
-	//
-	// One possible strategy would be to do a timed retry to see if we can get
-	// this stored in the future.
+	// further with this release. recordRelease retries the write as long as
+	// the retry budget allows.
 	s.recordRelease(r, true)
 
 	return res, nil
@@ -17,14 +17,27 @@ limitations under the License.
 package tiller
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
 	"strings"
 	"testing"
 
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
 	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/kube"
 	"k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
+	relutil "k8s.io/helm/pkg/releaseutil"
+	"k8s.io/helm/pkg/tiller/environment"
 	"k8s.io/helm/pkg/version"
 )
 
@@ -268,6 +281,260 @@ func TestInstallRelease_WrongTillerVersion(t *testing.T) {
 	}
 }
 
+func TestInstallRelease_MaxRenderBytes(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.MaxRenderBytes = 1024
+
+	runaway := func(opts *chartOptions) {
+		opts.Templates = append(opts.Templates, &chart.Template{
+			Name: "templates/runaway",
+			Data: []byte("{{ range until 100000 }}key-{{ . }}: some rendered value\n{{ end }}"),
+		})
+	}
+	req := installRequest(withName("runaway"), withChart(runaway))
+
+	_, err := rs.InstallRelease(c, req)
+	if err == nil {
+		t.Fatal("Expected install to be aborted for exceeding the render limit")
+	}
+	if code := status.Code(err); code != codes.ResourceExhausted {
+		t.Errorf("Expected code %s, got %s: %s", codes.ResourceExhausted, code, err)
+	}
+	if _, err := rs.env.Releases.Get("runaway", 1); err == nil {
+		t.Error("Expected no release to be recorded")
+	}
+
+	// The same chart renders fine without a limit.
+	rs.MaxRenderBytes = 0
+	if _, err := rs.InstallRelease(c, req); err != nil {
+		t.Errorf("Expected install without limit to succeed, got %s", err)
+	}
+}
+
+func TestInstallRelease_UndeclaredKind(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	withKinds := func(kinds string, templates ...*chart.Template) chartOption {
+		return func(opts *chartOptions) {
+			opts.Metadata.Annotations = map[string]string{"helm.sh/expected-kinds": kinds}
+			opts.Templates = templates
+		}
+	}
+	deployment := &chart.Template{Name: "templates/deployment", Data: []byte("apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\n")}
+	clusterRole := &chart.Template{Name: "templates/clusterrole", Data: []byte("apiVersion: rbac.authorization.k8s.io/v1\nkind: ClusterRole\nmetadata:\n  name: admin\n")}
+
+	req := installRequest(withName("sneaky"), withChart(withKinds("Deployment, Service", deployment, clusterRole)))
+	_, err := rs.InstallRelease(c, req)
+	if err == nil {
+		t.Fatal("Expected install rendering an undeclared ClusterRole to fail")
+	}
+	if code := status.Code(err); code != codes.InvalidArgument {
+		t.Errorf("Expected code %s, got %s: %s", codes.InvalidArgument, code, err)
+	}
+	if !strings.Contains(err.Error(), "ClusterRole") {
+		t.Errorf("Expected error to name the undeclared kind, got %q", err)
+	}
+	if _, err := rs.env.Releases.Get("sneaky", 1); err == nil {
+		t.Error("Expected no release to be recorded")
+	}
+
+	req = installRequest(withName("honest"), withChart(withKinds("Deployment,ClusterRole", deployment, clusterRole)))
+	if _, err := rs.InstallRelease(c, req); err != nil {
+		t.Errorf("Expected install with all kinds declared to succeed, got %s", err)
+	}
+}
+
+func TestInstallRelease_DuplicateResources(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	duplicated := func(opts *chartOptions) {
+		opts.Templates = []*chart.Template{
+			{Name: "templates/first", Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\ndata:\n  from: first\n")},
+			{Name: "templates/second", Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n  namespace: spaced\ndata:\n  from: second\n")},
+			{Name: "templates/other", Data: []byte("apiVersion: v1\nkind: Secret\nmetadata:\n  name: settings\n")},
+		}
+	}
+	req := installRequest(withName("doubled"), withChart(duplicated))
+
+	_, err := rs.InstallRelease(c, req)
+	if code := status.Code(err); code != codes.InvalidArgument {
+		t.Fatalf("Expected code %s, got %s: %v", codes.InvalidArgument, code, err)
+	}
+	if !strings.Contains(err.Error(), "ConfigMap spaced/settings") || strings.Contains(err.Error(), "Secret") {
+		t.Errorf("Expected error to list only the duplicated ConfigMap, got %q", err)
+	}
+
+	rs.AllowDuplicateResources = true
+	res, err := rs.InstallRelease(c, req)
+	if err != nil {
+		t.Fatalf("Expected install with duplicates allowed to succeed, got %s", err)
+	}
+	if strings.Contains(res.Release.Manifest, "from: first") || !strings.Contains(res.Release.Manifest, "from: second") {
+		t.Errorf("Expected only the last duplicate to be kept, got manifest:\n%s", res.Release.Manifest)
+	}
+	if !strings.Contains(res.Release.Manifest, "kind: Secret") {
+		t.Errorf("Expected non-duplicated resources to be kept, got manifest:\n%s", res.Release.Manifest)
+	}
+}
+
+// cancellingKubeClient cancels the request context once it has created the
+// resources of a release, as if the client went away mid-install.
+type cancellingKubeClient struct {
+	*mockHooksKubeClient
+	cancel context.CancelFunc
+}
+
+func (kc *cancellingKubeClient) CreateWithResult(ns string, r io.Reader, opts kube.CreateOptions) ([]kube.AppliedResource, error) {
+	defer kc.cancel()
+	return kc.mockHooksKubeClient.CreateWithResult(ns, r, opts)
+}
+
+func TestInstallRelease_AtomicCleanupOnCancel(t *testing.T) {
+	c, cancel := context.WithCancel(helm.NewContext())
+	defer cancel()
+
+	kc := &mockHooksKubeClient{Resources: map[string]*mockHooksManifest{}}
+	rs := rsFixture()
+	rs.env.KubeClient = &cancellingKubeClient{mockHooksKubeClient: kc, cancel: cancel}
+
+	configMap := func(opts *chartOptions) {
+		opts.Templates = []*chart.Template{
+			{Name: "templates/cm", Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: partial\n")},
+		}
+	}
+	req := installRequest(withName("aborted"), withChart(configMap))
+	req.Atomic = true
+
+	_, err := rs.InstallRelease(c, req)
+	if code := status.Code(err); code != codes.Canceled {
+		t.Fatalf("Expected code %s, got %s: %v", codes.Canceled, code, err)
+	}
+	if len(kc.Resources) != 0 {
+		t.Errorf("Expected created resources to be cleaned up, found %d", len(kc.Resources))
+	}
+	rel, err := rs.env.Releases.Get("aborted", 1)
+	if err != nil {
+		t.Fatalf("Expected release to be recorded: %s", err)
+	}
+	if rel.Info.Status.Code != release.Status_FAILED {
+		t.Errorf("Expected release status FAILED, got %s", rel.Info.Status.Code)
+	}
+}
+
+// partialCreateKubeClient creates the documents of a manifest one at a time,
+// failing at the first one named broken.
+type partialCreateKubeClient struct {
+	*mockHooksKubeClient
+	created []string
+}
+
+func (kc *partialCreateKubeClient) CreateWithResult(ns string, r io.Reader, opts kube.CreateOptions) ([]kube.AppliedResource, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	docs := relutil.SplitManifests(string(b))
+	for i := 0; i < len(docs); i++ {
+		doc := docs[fmt.Sprintf("manifest-%d", i)]
+		if strings.Contains(doc, "name: broken") {
+			return nil, errors.New("ConfigMap \"broken\" is invalid")
+		}
+		if err := kc.Create(ns, strings.NewReader(doc), opts.Timeout, opts.ShouldWait); err != nil {
+			return nil, err
+		}
+		kc.created = append(kc.created, doc)
+	}
+	return nil, nil
+}
+
+func TestInstallRelease_AtomicCleanupOnPartialFailure(t *testing.T) {
+	kc := &partialCreateKubeClient{mockHooksKubeClient: &mockHooksKubeClient{Resources: map[string]*mockHooksManifest{}}}
+	rs := rsFixture()
+	rs.env.KubeClient = kc
+
+	configMaps := func(opts *chartOptions) {
+		opts.Templates = []*chart.Template{
+			{Name: "templates/a", Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: first\n")},
+			{Name: "templates/b", Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: broken\n")},
+			{Name: "templates/c", Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: third\n")},
+		}
+	}
+	req := installRequest(withName("half-done"), withChart(configMaps))
+	req.Atomic = true
+
+	if _, err := rs.InstallRelease(helm.NewContext(), req); err == nil {
+		t.Fatal("Expected the install to fail")
+	}
+	if len(kc.created) == 0 {
+		t.Fatal("Expected a resource to be created before the failure")
+	}
+	if len(kc.Resources) != 0 {
+		t.Errorf("Expected no orphaned resources, found %d", len(kc.Resources))
+	}
+	rel, err := rs.env.Releases.Get("half-done", 1)
+	if err != nil {
+		t.Fatalf("Expected release to be recorded: %s", err)
+	}
+	if rel.Info.Status.Code != release.Status_FAILED {
+		t.Errorf("Expected release status FAILED, got %s", rel.Info.Status.Code)
+	}
+}
+
+func TestInstallRelease_RetryReusesGeneratedName(t *testing.T) {
+	c := metadata.NewIncomingContext(context.TODO(), metadata.Pairs("x-helm-request-id", "retry-me"))
+	rs := rsFixture()
+	rs.env.KubeClient = newCreateFailingKubeClient()
+
+	if _, err := rs.InstallRelease(c, installRequest()); err == nil {
+		t.Fatal("Expected first install attempt to fail")
+	}
+	first, err := rs.env.Releases.ListReleases()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first) != 1 || first[0].Info.Status.Code != release.Status_FAILED {
+		t.Fatalf("Expected one failed release after first attempt, got %v", first)
+	}
+	name := first[0].Name
+
+	rs.env.KubeClient = &environment.PrintingKubeClient{Out: ioutil.Discard}
+	res, err := rs.InstallRelease(c, installRequest())
+	if err != nil {
+		t.Fatalf("Expected retry to succeed, got %s", err)
+	}
+	if res.Release.Name != name {
+		t.Errorf("Expected retry to reuse name %q, got %q", name, res.Release.Name)
+	}
+	if res.Release.Info.Status.Code != release.Status_DEPLOYED {
+		t.Errorf("Expected retried release to be deployed, got %s", res.Release.Info.Status.Code)
+	}
+
+	h, err := rs.env.Releases.History(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	all, err := rs.env.Releases.ListReleases()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != len(h) {
+		t.Errorf("Expected all records to belong to release %q, got %d of %d", name, len(h), len(all))
+	}
+
+	// A different request id gets a fresh name.
+	other := metadata.NewIncomingContext(context.TODO(), metadata.Pairs("x-helm-request-id", "another"))
+	res, err = rs.InstallRelease(other, installRequest())
+	if err != nil {
+		t.Fatalf("Expected install to succeed, got %s", err)
+	}
+	if res.Release.Name == name {
+		t.Errorf("Expected a new name for a different request id, got %q", name)
+	}
+}
+
 func TestInstallRelease_WithChartAndDependencyParentNotes(t *testing.T) {
 	c := helm.NewContext()
 	rs := rsFixture()
@@ -467,6 +734,100 @@ func TestInstallRelease_FailedHooks(t *testing.T) {
 	}
 }
 
+func hookResultsChart(opts *chartOptions) {
+	hook := func(name, event, weight string) *chart.Template {
+		return &chart.Template{
+			Name: "templates/" + name,
+			Data: []byte(fmt.Sprintf("kind: Job\nmetadata:\n  name: %s\n  annotations:\n    \"helm.sh/hook\": %s\n    \"helm.sh/hook-weight\": %q\n", name, event, weight)),
+		}
+	}
+	opts.Templates = []*chart.Template{
+		{Name: "templates/hello", Data: []byte("hello: world")},
+		hook("migrate", "pre-install", "1"),
+		hook("setup", "pre-install", "0"),
+		hook("notify", "post-install", "0"),
+	}
+}
+
+func hookResultsString(results []*release.HookResult) string {
+	var s []string
+	for _, r := range results {
+		s = append(s, fmt.Sprintf("%s:%s:%s", r.Name, r.Event, r.Phase))
+	}
+	return strings.Join(s, " ")
+}
+
+func TestInstallRelease_HookResults(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	res, err := rs.InstallRelease(c, installRequest(withChart(hookResultsChart)))
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+
+	expect := "setup:PRE_INSTALL:SUCCEEDED migrate:PRE_INSTALL:SUCCEEDED notify:POST_INSTALL:SUCCEEDED"
+	if got := hookResultsString(res.HookResults); got != expect {
+		t.Errorf("Expected hook results %q, got %q", expect, got)
+	}
+}
+
+func TestInstallRelease_FailedHookResults(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.KubeClient = newHookFailingKubeClient()
+
+	res, err := rs.InstallRelease(c, installRequest(withChart(hookResultsChart)))
+	if err == nil {
+		t.Fatal("Expected failed install")
+	}
+
+	expect := "setup:PRE_INSTALL:FAILED migrate:PRE_INSTALL:SKIPPED"
+	if got := hookResultsString(res.HookResults); got != expect {
+		t.Errorf("Expected hook results %q, got %q", expect, got)
+	}
+}
+
+func TestInstallRelease_FailedHookResultsOverGRPC(t *testing.T) {
+	rs := rsFixture()
+	rs.env.KubeClient = newHookFailingKubeClient()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := NewServer()
+	services.RegisterReleaseServiceServer(srv, rs)
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	c := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("x-helm-api-client", version.GetVersion()))
+	res, err := services.NewReleaseServiceClient(conn).InstallRelease(c, installRequest(withChart(hookResultsChart)))
+	if err == nil {
+		t.Fatal("Expected failed install")
+	}
+	if res != nil {
+		t.Errorf("Expected gRPC to drop the response of a failed call, got %v", res)
+	}
+
+	var results []*release.HookResult
+	for _, d := range status.Convert(err).Details() {
+		if r, ok := d.(*release.HookResult); ok {
+			results = append(results, r)
+		}
+	}
+	expect := "setup:PRE_INSTALL:FAILED migrate:PRE_INSTALL:SKIPPED"
+	if got := hookResultsString(results); got != expect {
+		t.Errorf("Expected hook results %q in the error details, got %q", expect, got)
+	}
+}
+
 func TestInstallRelease_ReuseName(t *testing.T) {
 	c := helm.NewContext()
 	rs := rsFixture()
@@ -548,3 +909,293 @@ func TestInstallRelease_Description(t *testing.T) {
 		t.Errorf("Expected description %q. Got %q", customDescription, desc)
 	}
 }
+
+func TestInstallRelease_BaseRevision(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	req := installRequest(withName("migrated"))
+	req.BaseRevision = 5
+	res, err := rs.InstallRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if res.Release.Version != 5 {
+		t.Errorf("Expected release version 5, got %d", res.Release.Version)
+	}
+	if _, err := rs.env.Releases.Get("migrated", 5); err != nil {
+		t.Errorf("Expected release to be stored at revision 5: %s", err)
+	}
+
+	upd, err := rs.UpdateRelease(c, &services.UpdateReleaseRequest{
+		Name:  "migrated",
+		Chart: buildChart(),
+	})
+	if err != nil {
+		t.Fatalf("Failed update: %s", err)
+	}
+	if upd.Release.Version != 6 {
+		t.Errorf("Expected updated release version 6, got %d", upd.Release.Version)
+	}
+}
+
+func TestInstallRelease_InvalidBaseRevision(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.Releases.Create(namedReleaseStub("taken", release.Status_DELETED))
+
+	tests := []struct {
+		name   string
+		base   int32
+		expect string
+	}{
+		{"negative", -1, "base revision must be positive"},
+		{"taken", 1, "collides with existing revision"},
+	}
+	for _, tt := range tests {
+		req := installRequest(withName(tt.name), withReuseName())
+		req.BaseRevision = tt.base
+		_, err := rs.InstallRelease(c, req)
+		if err == nil {
+			t.Errorf("%s: expected install with base revision %d to fail", tt.name, tt.base)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.expect) {
+			t.Errorf("%s: expected %q to contain %q", tt.name, err, tt.expect)
+		}
+	}
+}
+
+type appliedReportingKubeClient struct {
+	environment.PrintingKubeClient
+	applied []kube.AppliedResource
+}
+
+func (a *appliedReportingKubeClient) CreateWithResult(ns string, r io.Reader, opts kube.CreateOptions) ([]kube.AppliedResource, error) {
+	return a.applied, nil
+}
+
+func TestInstallRelease_AppliedResources(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.KubeClient = &appliedReportingKubeClient{
+		PrintingKubeClient: environment.PrintingKubeClient{Out: ioutil.Discard},
+		applied: []kube.AppliedResource{
+			{Kind: "ConfigMap", Namespace: "spaced", Name: "hello", UID: "uid-1", ResourceVersion: "12"},
+		},
+	}
+
+	res, err := rs.InstallRelease(c, installRequest(withName("applied")))
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+
+	stored, err := rs.env.Releases.Get("applied", 1)
+	if err != nil {
+		t.Fatalf("Expected release to be stored: %s", err)
+	}
+	for _, rel := range []*release.Release{res.Release, stored} {
+		if len(rel.AppliedResources) != 1 {
+			t.Fatalf("Expected 1 applied resource, got %v", rel.AppliedResources)
+		}
+		a := rel.AppliedResources[0]
+		if a.Uid != "uid-1" || a.ResourceVersion != "12" || a.Kind != "ConfigMap" || a.Name != "hello" {
+			t.Errorf("Unexpected applied resource %v", a)
+		}
+	}
+}
+
+func TestInstallRelease_DefaultValuesFile(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.DefaultValuesFile = "values.prod.yaml"
+
+	withProdValues := func(opts *chartOptions) {
+		opts.Values = &chart.Config{Raw: "env: dev\nreplicas: 1\n"}
+		opts.Files = append(opts.Files, &chart.Any{
+			TypeUrl: "values.prod.yaml",
+			Value:   []byte("env: prod\n"),
+		})
+		opts.Templates = append(opts.Templates, &chart.Template{
+			Name: "templates/env",
+			Data: []byte("env: {{ .Values.env }}\nreplicas: {{ .Values.replicas }}"),
+		})
+	}
+
+	// The client sends empty values when none were given on the command line.
+	req := installRequest(withName("prod"), withChart(withProdValues))
+	req.Values = &chart.Config{Raw: ""}
+	res, err := rs.InstallRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if !strings.Contains(res.Release.Manifest, "env: prod\nreplicas: 1") {
+		t.Errorf("Expected default values file to be applied, got manifest:\n%s", res.Release.Manifest)
+	}
+
+	req = installRequest(withName("override"), withChart(withProdValues))
+	req.Values = &chart.Config{Raw: "env: staging\n"}
+	res, err = rs.InstallRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if !strings.Contains(res.Release.Manifest, "env: staging") {
+		t.Errorf("Expected user values to override default values file, got manifest:\n%s", res.Release.Manifest)
+	}
+
+	// A chart without the file installs with its own defaults.
+	res, err = rs.InstallRelease(c, installRequest(withName("plain")))
+	if err != nil {
+		t.Fatalf("Failed install of chart without default values file: %s", err)
+	}
+}
+
+// schemaKubeClient fails validation of resources lacking a spec.
+type schemaKubeClient struct {
+	environment.PrintingKubeClient
+}
+
+func (kc *schemaKubeClient) Validate(ns string, r io.Reader) error {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(string(b), "spec:") {
+		return errors.New(`error validating data: ValidationError(Deployment): missing required field "spec"`)
+	}
+	return nil
+}
+
+func TestInstallRelease_SchemaValidation(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.SchemaValidation = true
+	rs.env.KubeClient = &schemaKubeClient{environment.PrintingKubeClient{Out: ioutil.Discard}}
+
+	deployment := func(name string, spec bool) *chart.Template {
+		data := "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: " + name + "\n"
+		if spec {
+			data += "spec:\n  replicas: 1\n"
+		}
+		return &chart.Template{Name: "templates/" + name + ".yaml", Data: []byte(data)}
+	}
+	req := installRequest(withName("invalid"), withChart(func(opts *chartOptions) {
+		opts.Templates = []*chart.Template{deployment("good", true), deployment("bad-a", false), deployment("bad-b", false)}
+	}))
+	_, err := rs.InstallRelease(c, req)
+	if err == nil {
+		t.Fatal("Expected validation to fail")
+	}
+	for _, want := range []string{
+		`hello/templates/bad-a.yaml: error validating data: ValidationError(Deployment): missing required field "spec"`,
+		`hello/templates/bad-b.yaml: error validating data`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error to contain %q, got %q", want, err)
+		}
+	}
+	if strings.Contains(err.Error(), "templates/good.yaml") {
+		t.Errorf("Expected the valid template not to be reported, got %q", err)
+	}
+}
+
+func TestInstallRelease_DefaultNamespace(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.DefaultNamespace = "apps"
+
+	req := installRequest(withName("defaulted"))
+	req.Namespace = ""
+	res, err := rs.InstallRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if res.Release.Namespace != "apps" {
+		t.Errorf("Expected release namespace 'apps', got '%s'.", res.Release.Namespace)
+	}
+
+	res, err = rs.InstallRelease(c, installRequest(withName("explicit")))
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if res.Release.Namespace != "spaced" {
+		t.Errorf("Expected the requested namespace 'spaced' to be kept, got '%s'.", res.Release.Namespace)
+	}
+}
+
+func TestInstallRelease_SetValues(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	withDefaults := func(opts *chartOptions) {
+		opts.Values = &chart.Config{Raw: "image:\n  repo: nginx\n  tag: \"1.0\"\nreplicas: 1\ndebug: false\nannotations:\n  team: web\n"}
+		opts.Templates = append(opts.Templates, &chart.Template{
+			Name: "templates/values",
+			Data: []byte(`image: {{ .Values.image.repo }}:{{ .Values.image.tag }}
+replicas: {{ .Values.replicas }} {{ kindOf .Values.replicas }}
+debug: {{ .Values.debug }} {{ kindOf .Values.debug }}
+team: {{ .Values.annotations.team | default "none" }}`),
+		})
+	}
+
+	req := installRequest(withName("set"), withChart(withDefaults))
+	req.Values = &chart.Config{Raw: "replicas: 2\nimage:\n  tag: \"2.0\"\n"}
+	req.SetValues = []string{"replicas=3", "image.tag=3.0", "debug=true", "image.tag=3.1", "annotations.team=null"}
+	res, err := rs.InstallRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	for _, want := range []string{
+		// set values override values, and later ones earlier ones
+		"image: nginx:3.1",
+		// typed like numbers and booleans given in values
+		"replicas: 3 float64",
+		"debug: true bool",
+		// null removes the chart default
+		"team: none",
+	} {
+		if !strings.Contains(res.Release.Manifest, want) {
+			t.Errorf("Expected manifest to contain %q, got:\n%s", want, res.Release.Manifest)
+		}
+	}
+	if !strings.Contains(res.Release.Config.Raw, "replicas: 3") {
+		t.Errorf("Expected set values to be stored with the release, got config:\n%s", res.Release.Config.Raw)
+	}
+
+	req = installRequest(withName("invalid"))
+	req.SetValues = []string{"replicas"}
+	if _, err := rs.InstallRelease(c, req); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Expected InvalidArgument for a set value without '=', got %v", err)
+	}
+}
+
+func TestInstallReleaseMaxValuesBytes(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.MaxValuesBytes = 1024
+
+	req := installRequest(withName("oversized"))
+	req.Values = &chart.Config{Raw: "blob: " + strings.Repeat("x", 2048) + "\n"}
+	_, err := rs.InstallRelease(c, req)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Expected InvalidArgument for oversized values, got %v", err)
+	}
+	if _, err := rs.env.Releases.Last("oversized"); err == nil {
+		t.Error("Expected no release to be stored for oversized values")
+	}
+
+	req = installRequest(withName("normal"))
+	req.Values = &chart.Config{Raw: "name: value\n"}
+	if _, err := rs.InstallRelease(c, req); err != nil {
+		t.Fatalf("Failed install with values under the limit: %s", err)
+	}
+
+	upd := &services.UpdateReleaseRequest{
+		Name:   "normal",
+		Chart:  buildChart(),
+		Values: &chart.Config{Raw: "blob: " + strings.Repeat("x", 2048) + "\n"},
+	}
+	if _, err := rs.UpdateRelease(c, upd); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for oversized upgrade values, got %v", err)
+	}
+}
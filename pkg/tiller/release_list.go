@@ -19,8 +19,12 @@ package tiller
 import (
 	"fmt"
 	"regexp"
+	"time"
 
 	"github.com/golang/protobuf/proto"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
 	relutil "k8s.io/helm/pkg/releaseutil"
@@ -32,18 +36,22 @@ func (s *ReleaseServer) ListReleases(req *services.ListReleasesRequest, stream s
 		req.StatusCodes = []release.Status_Code{release.Status_DEPLOYED}
 	}
 
-	//rels, err := s.env.Releases.ListDeployed()
-	rels, err := s.env.Releases.ListFilterAll(func(r *release.Release) bool {
-		for _, sc := range req.StatusCodes {
-			if sc == r.Info.Status.Code {
-				return true
-			}
-		}
-		return false
-	})
+	// The status codes are selected by the STATUS label of the release
+	// records, by the storage backend where the driver supports it. Records
+	// are not labelled with their namespace, so it is matched here.
+	selector, err := statusSelector(req.StatusCodes)
+	if err != nil {
+		return err
+	}
+	rels, err := s.env.Releases.ListByLabels(selector)
 	if err != nil {
 		return err
 	}
+	if req.ChartName != "" || req.ChartVersion != "" {
+		rels = relutil.FilterFunc(func(r *release.Release) bool {
+			return chartMatches(r, req.ChartName, req.ChartVersion)
+		}).Filter(rels)
+	}
 
 	if req.Namespace != "" {
 		rels, err = filterByNamespace(req.Namespace, rels)
@@ -156,6 +164,19 @@ func (s *ReleaseServer) partition(rels []*release.Release, cap int) <-chan []*re
 	return chunks
 }
 
+// statusSelector selects the release records with any of the status codes.
+func statusSelector(codes []release.Status_Code) (labels.Selector, error) {
+	values := make([]string, len(codes))
+	for i, code := range codes {
+		values[i] = code.String()
+	}
+	req, err := labels.NewRequirement("STATUS", selection.In, values)
+	if err != nil {
+		return nil, err
+	}
+	return labels.NewSelector().Add(*req), nil
+}
+
 func filterByNamespace(namespace string, rels []*release.Release) ([]*release.Release, error) {
 	matches := []*release.Release{}
 	for _, r := range rels {
@@ -166,6 +187,19 @@ func filterByNamespace(namespace string, rels []*release.Release) ([]*release.Re
 	return matches, nil
 }
 
+// chartMatches reports whether r was deployed from a chart with the given name
+// and version. An empty name or version matches any.
+func chartMatches(r *release.Release, name, version string) bool {
+	if name == "" && version == "" {
+		return true
+	}
+	md := r.GetChart().GetMetadata()
+	if md == nil {
+		return false
+	}
+	return (name == "" || md.Name == name) && (version == "" || md.Version == version)
+}
+
 func filterReleases(filter string, rels []*release.Release) ([]*release.Release, error) {
 	preg, err := regexp.Compile(filter)
 	if err != nil {
@@ -179,3 +213,19 @@ func filterReleases(filter string, rels []*release.Release) ([]*release.Release,
 	}
 	return matches, nil
 }
+
+// WarmReleaseCache reloads the storage release cache every interval until stop
+// is closed, so that list requests are served without a round trip to the
+// storage backend. The cache is always warmed once before stop is checked.
+func (s *ReleaseServer) WarmReleaseCache(interval time.Duration, stop <-chan struct{}) {
+	for {
+		if err := s.env.Releases.WarmCache(); err != nil {
+			s.Log("warning: failed to warm release cache: %s", err)
+		}
+		select {
+		case <-stop:
+			return
+		case <-time.After(interval):
+		}
+	}
+}
@@ -17,12 +17,16 @@ limitations under the License.
 package tiller
 
 import (
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/storage"
+	"k8s.io/helm/pkg/storage/driver"
 )
 
 func TestListReleases(t *testing.T) {
@@ -46,6 +50,35 @@ func TestListReleases(t *testing.T) {
 	}
 }
 
+func TestWarmReleaseCache(t *testing.T) {
+	rs := rsFixture()
+	rs.env.Releases.EnableCache()
+	rel := releaseStub()
+	if err := rs.env.Releases.Create(rel); err != nil {
+		t.Fatalf("Could not store mock release: %s", err)
+	}
+
+	stop := make(chan struct{})
+	close(stop)
+	rs.WarmReleaseCache(time.Hour, stop)
+
+	// A release written behind the storage's back is only visible if the
+	// listing goes to the driver instead of the warmed cache.
+	hidden := releaseStub()
+	hidden.Name = "hidden"
+	if err := rs.env.Releases.Driver.Create(hidden.Name+".v1", hidden); err != nil {
+		t.Fatalf("Could not store mock release: %s", err)
+	}
+
+	mrs := &mockListServer{}
+	if err := rs.ListReleases(&services.ListReleasesRequest{Offset: "", Limit: 64}, mrs); err != nil {
+		t.Fatalf("Failed listing: %s", err)
+	}
+	if len(mrs.val.Releases) != 1 || mrs.val.Releases[0].Name != rel.Name {
+		t.Errorf("Expected only the warmed release %q to be listed, got %v", rel.Name, mrs.val.Releases)
+	}
+}
+
 func TestListReleasesByStatus(t *testing.T) {
 	rs := rsFixture()
 	stubs := []*release.Release{
@@ -190,6 +223,39 @@ func TestListReleasesSortByChartName(t *testing.T) {
 	}
 }
 
+// labelListingDriver can only list releases by their labels.
+type labelListingDriver struct {
+	*driver.Memory
+}
+
+func (d labelListingDriver) List(func(*release.Release) bool) ([]*release.Release, error) {
+	return nil, errors.New("listed every release")
+}
+
+func TestListReleasesByStatusLabel(t *testing.T) {
+	rs := rsFixture()
+	mem := driver.NewMemory()
+	rs.env.Releases = storage.Init(labelListingDriver{mem})
+	for i, code := range []release.Status_Code{release.Status_DEPLOYED, release.Status_FAILED, release.Status_DELETED} {
+		rel := namedReleaseStub(fmt.Sprintf("rel-%d", i), code)
+		if err := rs.env.Releases.Create(rel); err != nil {
+			t.Fatalf("Could not store mock release: %s", err)
+		}
+	}
+
+	mrs := &mockListServer{}
+	req := &services.ListReleasesRequest{
+		StatusCodes: []release.Status_Code{release.Status_FAILED, release.Status_DELETED},
+		SortBy:      services.ListSort_NAME,
+	}
+	if err := rs.ListReleases(req, mrs); err != nil {
+		t.Fatalf("Failed listing: %s", err)
+	}
+	if len(mrs.val.Releases) != 2 || mrs.val.Releases[0].Name != "rel-1" || mrs.val.Releases[1].Name != "rel-2" {
+		t.Errorf("Expected rel-1 and rel-2, got %v", mrs.val.Releases)
+	}
+}
+
 func TestListReleasesFilter(t *testing.T) {
 	rs := rsFixture()
 	names := []string{
@@ -275,6 +341,58 @@ func TestReleasesNamespace(t *testing.T) {
 	}
 }
 
+func TestReleasesChart(t *testing.T) {
+	rs := rsFixture()
+
+	charts := []struct {
+		release, name, version string
+	}{
+		{"axon", "nginx", "1.0.0"},
+		{"dendrite", "nginx", "1.1.0"},
+		{"neuron", "redis", "1.0.0"},
+		{"ribosome", "nginx", "1.0.0"},
+	}
+	for _, c := range charts {
+		rel := releaseStub()
+		rel.Name = c.release
+		rel.Chart.Metadata = &chart.Metadata{Name: c.name, Version: c.version}
+		if err := rs.env.Releases.Create(rel); err != nil {
+			t.Fatalf("Could not store mock release: %s", err)
+		}
+	}
+
+	tests := []struct {
+		name, version string
+		expect        []string
+	}{
+		{"nginx", "", []string{"axon", "dendrite", "ribosome"}},
+		{"nginx", "1.0.0", []string{"axon", "ribosome"}},
+		{"", "1.0.0", []string{"axon", "neuron", "ribosome"}},
+		{"memcached", "", nil},
+	}
+	for _, tt := range tests {
+		mrs := &mockListServer{}
+		req := &services.ListReleasesRequest{
+			Limit:        64,
+			SortBy:       services.ListSort_NAME,
+			ChartName:    tt.name,
+			ChartVersion: tt.version,
+		}
+		if err := rs.ListReleases(req, mrs); err != nil {
+			t.Fatalf("Failed listing: %s", err)
+		}
+		var got []string
+		if mrs.val != nil {
+			for _, r := range mrs.val.Releases {
+				got = append(got, r.Name)
+			}
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.expect) {
+			t.Errorf("chart %q version %q: expected %v, got %v", tt.name, tt.version, tt.expect, got)
+		}
+	}
+}
+
 func TestReleasePartition(t *testing.T) {
 	var rl []*release.Release
 	rs := rsFixture()
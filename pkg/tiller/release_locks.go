@@ -0,0 +1,94 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"sync"
+	"time"
+
+	ctx "golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// releaseLocks serializes the operations that change the same release, so
+// that they do not record conflicting versions of it, while operations on
+// different releases still run in parallel.
+type releaseLocks struct {
+	mu    sync.Mutex
+	locks map[string]*releaseLock
+}
+
+// releaseLock is held by the operation changing a release. Its channel holds
+// a token while the lock is taken.
+type releaseLock struct {
+	held chan struct{}
+	// waiters counts the operations holding or waiting for the lock, so that
+	// it is forgotten once nobody needs it.
+	waiters int
+}
+
+// lock waits for the lock of the named release, giving up with the Aborted
+// code once timeout has passed, or with the context's error once it is done.
+// A timeout of 0 or less waits as long as the context allows. The returned
+// function releases the lock.
+func (l *releaseLocks) lock(c ctx.Context, name string, timeout time.Duration) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = map[string]*releaseLock{}
+	}
+	rl, ok := l.locks[name]
+	if !ok {
+		rl = &releaseLock{held: make(chan struct{}, 1)}
+		l.locks[name] = rl
+	}
+	rl.waiters++
+	l.mu.Unlock()
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		t := time.NewTimer(timeout)
+		defer t.Stop()
+		expired = t.C
+	}
+	select {
+	case rl.held <- struct{}{}:
+		return func() {
+			<-rl.held
+			l.forget(name, rl)
+		}, nil
+	case <-expired:
+		l.forget(name, rl)
+		return nil, status.Errorf(codes.Aborted, "another operation on release %s is in progress, try again later", name)
+	case <-c.Done():
+		l.forget(name, rl)
+		return nil, status.FromContextError(c.Err()).Err()
+	}
+}
+
+// forget drops a waiter of rl, removing the lock once it has none left.
+func (l *releaseLocks) forget(name string, rl *releaseLock) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	rl.waiters--
+	if rl.waiters == 0 {
+		delete(l.locks, name)
+	}
+}
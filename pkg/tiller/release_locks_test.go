@@ -0,0 +1,103 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+func TestReleaseLocks(t *testing.T) {
+	var l releaseLocks
+	c := context.Background()
+
+	unlock, err := l.lock(c, "angry-panda", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// other releases are not held up
+	unlockOther, err := l.lock(c, "happy-panda", 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Expected another release to be locked independently, got %s", err)
+	}
+	unlockOther()
+
+	if _, err := l.lock(c, "angry-panda", 10*time.Millisecond); status.Code(err) != codes.Aborted {
+		t.Fatalf("Expected a held lock to time out with Aborted, got %v", err)
+	}
+	cancelled, cancel := context.WithCancel(c)
+	cancel()
+	if _, err := l.lock(cancelled, "angry-panda", 0); status.Code(err) != codes.Canceled {
+		t.Fatalf("Expected a cancelled wait to fail with Canceled, got %v", err)
+	}
+
+	acquired := make(chan struct{})
+	go func() {
+		unlock, err := l.lock(c, "angry-panda", time.Second)
+		if err != nil {
+			t.Errorf("Expected the lock once released, got %s", err)
+		}
+		close(acquired)
+		unlock()
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("Expected the lock to be waited for")
+	case <-time.After(20 * time.Millisecond):
+	}
+	unlock()
+	<-acquired
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.locks) != 0 {
+		t.Errorf("Expected unused locks to be forgotten, got %d", len(l.locks))
+	}
+}
+
+func TestUpdateReleaseLocked(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.LockTimeout = 10 * time.Millisecond
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+
+	unlock, err := rs.locks.lock(c, rel.Name, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := &services.UpdateReleaseRequest{Name: rel.Name, Chart: buildChart()}
+	if _, err := rs.UpdateRelease(c, req); status.Code(err) != codes.Aborted {
+		t.Fatalf("Expected an upgrade of a locked release to be aborted, got %v", err)
+	}
+	if _, err := rs.env.Releases.Get(rel.Name, 2); err == nil {
+		t.Error("Expected no new version to be recorded by the aborted upgrade")
+	}
+
+	unlock()
+	if _, err := rs.UpdateRelease(c, req); err != nil {
+		t.Fatalf("Failed upgrade once unlocked: %s", err)
+	}
+}
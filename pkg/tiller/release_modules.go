@@ -20,8 +20,12 @@ import (
 	"bytes"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 
 	"k8s.io/helm/pkg/chartutil"
@@ -31,6 +35,8 @@ import (
 	"k8s.io/helm/pkg/proto/hapi/services"
 	relutil "k8s.io/helm/pkg/releaseutil"
 	"k8s.io/helm/pkg/rudder"
+	"k8s.io/helm/pkg/storage"
+	"k8s.io/helm/pkg/storage/driver"
 	"k8s.io/helm/pkg/tiller/environment"
 )
 
@@ -40,58 +46,242 @@ type ReleaseModule interface {
 	Update(current, target *release.Release, req *services.UpdateReleaseRequest, env *environment.Environment) error
 	Rollback(current, target *release.Release, req *services.RollbackReleaseRequest, env *environment.Environment) error
 	Status(r *release.Release, req *services.GetReleaseStatusRequest, env *environment.Environment) (string, error)
-	Delete(r *release.Release, req *services.UninstallReleaseRequest, env *environment.Environment) (string, []error)
+	Delete(r *release.Release, req *services.UninstallReleaseRequest, env *environment.Environment) (string, []string, []error)
 }
 
 // LocalReleaseModule is a local implementation of ReleaseModule
 type LocalReleaseModule struct {
 	clientset kubernetes.Interface
+
+	// DeleteParallelism is the number of resources of the same kind deleted
+	// at once when uninstalling a release. Values below 1 mean one at a time.
+	DeleteParallelism int
+
+	// SetOwnerReferences makes the ConfigMap or Secret holding a release
+	// record an owner of the release's resources, so that deleting the record
+	// garbage collects them. Only resources in StorageNamespace are affected.
+	SetOwnerReferences bool
+
+	// StorageNamespace is the namespace release records are stored in.
+	StorageNamespace string
+
+	// DeletionTimeout is how long Delete waits for the deleted resources to
+	// be gone from the cluster. Resources still present afterwards, such as
+	// ones held by finalizers, are reported as errors. 0 disables the wait.
+	DeletionTimeout time.Duration
+
+	// ForceFinalizerRemoval clears the finalizers of resources still present
+	// when DeletionTimeout expires, instead of only reporting them.
+	ForceFinalizerRemoval bool
 }
 
-// Create creates a release via kubeclient from provided environment
+// Create creates a release via kubeclient from provided environment.
+//
+// Resources annotated to be applied after others are created in a later
+// stage than the resources they depend on, and CustomResourceDefinitions are
+// created and established before anything else.
 func (m *LocalReleaseModule) Create(r *release.Release, req *services.InstallReleaseRequest, env *environment.Environment) error {
-	b := bytes.NewBufferString(r.Manifest)
-	return env.KubeClient.Create(r.Namespace, b, req.Timeout, req.Wait)
+	owner, err := m.releaseOwner(r, env)
+	if err != nil {
+		return err
+	}
+	var applied []kube.AppliedResource
+	for _, stage := range applyStages(r.Manifest) {
+		var res []kube.AppliedResource
+		res, err = env.KubeClient.CreateWithResult(r.Namespace, bytes.NewBufferString(stage.manifest), kube.CreateOptions{
+			Timeout:    req.Timeout,
+			ShouldWait: req.Wait || stage.wait,
+			Owner:      owner,
+			Release:    r.Name,
+		})
+		applied = append(applied, res...)
+		if err != nil {
+			break
+		}
+		if stage.crds {
+			if err = env.KubeClient.WaitUntilCRDEstablished(bytes.NewBufferString(stage.manifest), time.Duration(req.Timeout)*time.Second); err != nil {
+				break
+			}
+		}
+	}
+	r.AppliedResources = toAppliedResources(applied)
+	return err
 }
 
-// Update performs an update from current to target release
+// Update performs an update from current to target release, in the same
+// stages as Create.
 func (m *LocalReleaseModule) Update(current, target *release.Release, req *services.UpdateReleaseRequest, env *environment.Environment) error {
-	c := bytes.NewBufferString(current.Manifest)
-	t := bytes.NewBufferString(target.Manifest)
-	return env.KubeClient.UpdateWithOptions(target.Namespace, c, t, kube.UpdateOptions{
-		Force:         req.Force,
-		Recreate:      req.Recreate,
-		Timeout:       req.Timeout,
-		ShouldWait:    req.Wait,
-		CleanupOnFail: req.CleanupOnFail,
+	owner, err := m.releaseOwner(target, env)
+	if err != nil {
+		return err
+	}
+	return updateInStages(current, target, req.Wait, env, kube.UpdateOptions{
+		Force:                  req.Force,
+		Recreate:               req.Recreate,
+		Timeout:                req.Timeout,
+		CleanupOnFail:          req.CleanupOnFail,
+		Owner:                  owner,
+		Release:                target.Name,
+		PodTemplateAnnotations: restartAnnotations(target, req.Restart),
 	})
 }
 
-// Rollback performs a rollback from current to target release
+// Rollback performs a rollback from current to target release, in the same
+// stages as Create.
 func (m *LocalReleaseModule) Rollback(current, target *release.Release, req *services.RollbackReleaseRequest, env *environment.Environment) error {
-	c := bytes.NewBufferString(current.Manifest)
-	t := bytes.NewBufferString(target.Manifest)
-	return env.KubeClient.UpdateWithOptions(target.Namespace, c, t, kube.UpdateOptions{
+	owner, err := m.releaseOwner(target, env)
+	if err != nil {
+		return err
+	}
+	return updateInStages(current, target, req.Wait, env, kube.UpdateOptions{
 		Force:         req.Force,
 		Recreate:      req.Recreate,
 		Timeout:       req.Timeout,
-		ShouldWait:    req.Wait,
 		CleanupOnFail: req.CleanupOnFail,
+		Owner:         owner,
+		Release:       target.Name,
 	})
 }
 
+// updateInStages updates the resources of current to those of target, one
+// apply stage of target at a time. Each stage is compared with the resources
+// of current it holds, and resources of current left out of target are
+// deleted with the last stage.
+func updateInStages(current, target *release.Release, wait bool, env *environment.Environment, opts kube.UpdateOptions) error {
+	stages := applyStages(target.Manifest)
+	earlier := map[string]bool{}
+	var (
+		applied []kube.AppliedResource
+		err     error
+	)
+	for i, stage := range stages {
+		keys := manifestKeys(stage.manifest)
+		original := current.Manifest
+		if i < len(stages)-1 {
+			original = filterManifest(current.Manifest, func(key string) bool { return keys[key] })
+		} else if len(earlier) > 0 {
+			original = filterManifest(current.Manifest, func(key string) bool { return !earlier[key] })
+		}
+		opts.ShouldWait = wait || stage.wait
+		var res []kube.AppliedResource
+		res, err = env.KubeClient.UpdateWithResult(target.Namespace, bytes.NewBufferString(original), bytes.NewBufferString(stage.manifest), opts)
+		applied = append(applied, res...)
+		if err != nil {
+			break
+		}
+		if stage.crds {
+			if err = env.KubeClient.WaitUntilCRDEstablished(bytes.NewBufferString(stage.manifest), time.Duration(opts.Timeout)*time.Second); err != nil {
+				break
+			}
+		}
+		for key := range keys {
+			earlier[key] = true
+		}
+	}
+	target.AppliedResources = toAppliedResources(applied)
+	return err
+}
+
 // Status returns kubectl-like formatted status of release objects
 func (m *LocalReleaseModule) Status(r *release.Release, req *services.GetReleaseStatusRequest, env *environment.Environment) (string, error) {
 	return env.KubeClient.Get(r.Namespace, bytes.NewBufferString(r.Manifest))
 }
 
 // Delete deletes the release and returns manifests that were kept in the deletion process
-func (m *LocalReleaseModule) Delete(rel *release.Release, req *services.UninstallReleaseRequest, env *environment.Environment) (kept string, errs []error) {
+func (m *LocalReleaseModule) Delete(rel *release.Release, req *services.UninstallReleaseRequest, env *environment.Environment) (kept string, keptResources []string, errs []error) {
 	vs, err := GetVersionSet(m.clientset.Discovery())
 	if err != nil {
-		return rel.Manifest, []error{fmt.Errorf("Could not get apiVersions from Kubernetes: %v", err)}
+		return rel.Manifest, nil, []error{fmt.Errorf("Could not get apiVersions from Kubernetes: %v", err)}
+	}
+	kept, keptResources, errs = DeleteReleaseParallel(rel, vs, env.KubeClient, m.DeleteParallelism)
+	if m.DeletionTimeout > 0 {
+		errs = append(errs, waitForReleaseDeletion(rel, vs, env.KubeClient, m.DeletionTimeout, m.ForceFinalizerRemoval)...)
+	}
+	return kept, keptResources, errs
+}
+
+// waitForReleaseDeletion waits for the deleted resources of rel to be removed
+// from the cluster, and returns an error naming the ones still present after
+// timeout.
+func waitForReleaseDeletion(rel *release.Release, vs chartutil.VersionSet, kubeClient environment.KubeClient, timeout time.Duration, removeFinalizers bool) []error {
+	_, files, err := sortManifests(relutil.SplitManifests(rel.Manifest), vs, UninstallOrder)
+	if err != nil {
+		// DeleteReleaseParallel has already reported the corrupted record.
+		return nil
+	}
+	_, filesToDelete := filterManifestsToKeep(files)
+	if len(filesToDelete) == 0 {
+		return nil
+	}
+	contents := make([]string, 0, len(filesToDelete))
+	for _, file := range filesToDelete {
+		contents = append(contents, file.Content)
+	}
+
+	remaining, err := kubeClient.WaitForDeletion(rel.Namespace, bytes.NewBufferString(strings.Join(contents, "\n---\n")), timeout, removeFinalizers)
+	if err != nil {
+		return []error{fmt.Errorf("waiting for deletion of release %q: %s", rel.Name, err)}
+	}
+	if len(remaining) > 0 {
+		return []error{fmt.Errorf("release %q: resources not deleted within %s: %s", rel.Name, timeout, strings.Join(remaining, ", "))}
+	}
+	return nil
+}
+
+// releaseOwner returns the object holding the release record as the owner of
+// the release's resources. It returns nil if owner references are disabled or
+// the storage driver does not keep records in Kubernetes objects.
+func (m *LocalReleaseModule) releaseOwner(r *release.Release, env *environment.Environment) (*kube.Owner, error) {
+	if !m.SetOwnerReferences {
+		return nil, nil
+	}
+	key := storage.ReleaseKey(r.Name, r.Version)
+	var (
+		kind string
+		obj  metav1.Object
+		err  error
+	)
+	switch env.Releases.Name() {
+	case driver.ConfigMapsDriverName:
+		kind = "ConfigMap"
+		obj, err = m.clientset.CoreV1().ConfigMaps(m.StorageNamespace).Get(key, metav1.GetOptions{})
+	case driver.SecretsDriverName:
+		kind = "Secret"
+		obj, err = m.clientset.CoreV1().Secrets(m.StorageNamespace).Get(key, metav1.GetOptions{})
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not get release record %s to own its resources: %s", key, err)
 	}
-	return DeleteRelease(rel, vs, env.KubeClient)
+	return &kube.Owner{
+		Namespace: m.StorageNamespace,
+		Reference: metav1.OwnerReference{
+			APIVersion: "v1",
+			Kind:       kind,
+			Name:       key,
+			UID:        obj.GetUID(),
+		},
+	}, nil
+}
+
+// toAppliedResources converts the objects reported by the kube client into
+// their release record representation.
+func toAppliedResources(applied []kube.AppliedResource) []*release.AppliedResource {
+	if len(applied) == 0 {
+		return nil
+	}
+	out := make([]*release.AppliedResource, 0, len(applied))
+	for _, a := range applied {
+		out = append(out, &release.AppliedResource{
+			Kind:            a.Kind,
+			Namespace:       a.Namespace,
+			Name:            a.Name,
+			Uid:             a.UID,
+			ResourceVersion: a.ResourceVersion,
+		})
+	}
+	return out
 }
 
 // RemoteReleaseModule is a ReleaseModule which calls Rudder service to operate on a release
@@ -142,24 +332,34 @@ func (m *RemoteReleaseModule) Status(r *release.Release, req *services.GetReleas
 }
 
 // Delete calls rudder.DeleteRelease
-func (m *RemoteReleaseModule) Delete(r *release.Release, req *services.UninstallReleaseRequest, env *environment.Environment) (string, []error) {
+func (m *RemoteReleaseModule) Delete(r *release.Release, req *services.UninstallReleaseRequest, env *environment.Environment) (string, []string, []error) {
 	deleteRequest := &rudderAPI.DeleteReleaseRequest{Release: r}
 	resp, err := rudder.DeleteRelease(deleteRequest)
 
 	errs := make([]error, 0)
 	result := ""
+	var kept []string
 
 	if err != nil {
 		errs = append(errs, err)
 	}
 	if resp != nil {
 		result = resp.Release.Manifest
+		kept = resp.KeptResources
 	}
-	return result, errs
+	return result, kept, errs
 }
 
 // DeleteRelease is a helper that allows Rudder to delete a release without exposing most of Tiller inner functions
-func DeleteRelease(rel *release.Release, vs chartutil.VersionSet, kubeClient environment.KubeClient) (kept string, errs []error) {
+func DeleteRelease(rel *release.Release, vs chartutil.VersionSet, kubeClient environment.KubeClient) (kept string, keptResources []string, errs []error) {
+	return DeleteReleaseParallel(rel, vs, kubeClient, 1)
+}
+
+// DeleteReleaseParallel deletes the resources of a release like DeleteRelease,
+// but deletes up to parallelism resources of the same kind at once. Kinds are
+// still deleted one after the other, in UninstallOrder. Errors are sorted by
+// message.
+func DeleteReleaseParallel(rel *release.Release, vs chartutil.VersionSet, kubeClient environment.KubeClient, parallelism int) (kept string, keptResources []string, errs []error) {
 	manifests := relutil.SplitManifests(rel.Manifest)
 	_, files, err := sortManifests(manifests, vs, UninstallOrder)
 	if err != nil {
@@ -167,32 +367,71 @@ func DeleteRelease(rel *release.Release, vs chartutil.VersionSet, kubeClient env
 		// FIXME: One way to delete at this point would be to try a label-based
 		// deletion. The problem with this is that we could get a false positive
 		// and delete something that was not legitimately part of this release.
-		return rel.Manifest, []error{fmt.Errorf("corrupted release record. You must manually delete the resources: %s", err)}
+		return rel.Manifest, nil, []error{fmt.Errorf("corrupted release record. You must manually delete the resources: %s", err)}
 	}
 
 	filesToKeep, filesToDelete := filterManifestsToKeep(files)
 	if len(filesToKeep) > 0 {
-		kept = summarizeKeptManifests(filesToKeep, kubeClient, rel.Namespace)
+		kept, keptResources = summarizeKeptManifests(filesToKeep, kubeClient, rel.Namespace)
+	}
+
+	if parallelism < 1 {
+		parallelism = 1
 	}
 
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		kind string
+	)
+	sem := make(chan struct{}, parallelism)
 	errs = []error{}
 	for _, file := range filesToDelete {
 		b := bytes.NewBufferString(strings.TrimSpace(file.Content))
 		if b.Len() == 0 {
 			continue
 		}
-		if err := kubeClient.Delete(rel.Namespace, b); err != nil {
-			log.Printf("uninstall: Failed deletion of %q: %s", rel.Name, err)
-			if err == kube.ErrNoObjectsVisited {
-				// Rewrite the message from "no objects visited"
-				obj := ""
-				if file.Head != nil && file.Head.Metadata != nil {
-					obj = "[" + file.Head.Kind + "] " + file.Head.Metadata.Name
-				}
-				err = fmt.Errorf("release %q: object %q not found, skipping delete", rel.Name, obj)
+		// files are sorted by kind, so wait for every resource of the
+		// previous kind to be gone before moving on to the next one.
+		if file.Head != nil && file.Head.Kind != kind {
+			wg.Wait()
+			kind = file.Head.Kind
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(file Manifest, b *bytes.Buffer) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := deleteManifest(rel, file, b, kubeClient); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
 			}
-			errs = append(errs, err)
+		}(file, b)
+	}
+	wg.Wait()
+	// Resources of a kind are deleted concurrently, so their errors arrive in
+	// no particular order.
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+	return kept, keptResources, errs
+}
+
+func deleteManifest(rel *release.Release, file Manifest, b *bytes.Buffer, kubeClient environment.KubeClient) error {
+	err := kubeClient.Delete(rel.Namespace, b)
+	if err == nil {
+		return nil
+	}
+	log.Printf("uninstall: Failed deletion of %q: %s", rel.Name, err)
+	if err == kube.ErrNoObjectsVisited {
+		// Rewrite the message from "no objects visited"
+		obj := ""
+		if file.Head != nil && file.Head.Metadata != nil {
+			obj = "[" + file.Head.Kind + "] " + file.Head.Metadata.Name
 		}
+		err = fmt.Errorf("release %q: object %q not found, skipping delete", rel.Name, obj)
 	}
-	return kept, errs
+	return err
 }
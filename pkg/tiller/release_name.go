@@ -0,0 +1,73 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig"
+
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+// releaseNameData is the data available to a release name template.
+type releaseNameData struct {
+	// Namespace is the namespace the release is installed into.
+	Namespace string
+	// ChartName is the name of the chart being installed.
+	ChartName string
+}
+
+// ParseReleaseNameTemplate parses a template used to generate the names of
+// releases installed without one, such as
+// "{{.Namespace}}-{{.ChartName}}-{{randAlphaNum 5 | lower}}". The sprig
+// functions available to chart templates can be used.
+func ParseReleaseNameTemplate(text string) (*template.Template, error) {
+	return template.New("release-name").Option("missingkey=error").Funcs(sprig.TxtFuncMap()).Parse(text)
+}
+
+// releaseName returns the name of the release installed by req. Names are
+// generated from ReleaseNameTemplate when it is set and req names no release.
+func (s *ReleaseServer) releaseName(req *services.InstallReleaseRequest) (string, error) {
+	if req.Name != "" || s.ReleaseNameTemplate == nil {
+		return s.uniqName(req.Name, req.ReuseName)
+	}
+
+	data := releaseNameData{Namespace: req.Namespace}
+	if req.Chart.Metadata != nil {
+		data.ChartName = req.Chart.Metadata.Name
+	}
+	name, err := s.generateUniqName(func() (string, error) {
+		var b bytes.Buffer
+		if err := s.ReleaseNameTemplate.Execute(&b, data); err != nil {
+			return "", fmt.Errorf("executing release name template: %s", err)
+		}
+		return strings.TrimSpace(b.String()), nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if err := validateReleaseName(name); err != nil {
+		return "", fmt.Errorf("release name template generated %q: %s", name, err)
+	}
+
+	s.Log("info: Created new release name %s", name)
+	return name, nil
+}
@@ -0,0 +1,78 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+	"text/template"
+
+	"k8s.io/helm/pkg/helm"
+)
+
+func TestInstallRelease_ReleaseNameTemplate(t *testing.T) {
+	rs := rsFixture()
+	tpl, err := ParseReleaseNameTemplate("{{.Namespace}}-{{.ChartName}}-{{randAlphaNum 5 | lower}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs.ReleaseNameTemplate = tpl
+
+	res, err := rs.InstallRelease(helm.NewContext(), installRequest())
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if !regexp.MustCompile(`^spaced-hello-[a-z0-9]{5}$`).MatchString(res.Release.Name) {
+		t.Errorf("Expected release name to match the template, got %q", res.Release.Name)
+	}
+}
+
+func TestInstallRelease_ReleaseNameTemplateCollision(t *testing.T) {
+	rs := rsFixture()
+	taken := releaseStub()
+	taken.Name = "spaced-hello-0"
+	rs.env.Releases.Create(taken)
+
+	n := 0
+	next := func() string {
+		defer func() { n++ }()
+		return fmt.Sprint(n)
+	}
+	rs.ReleaseNameTemplate = template.Must(template.New("release-name").Funcs(template.FuncMap{"next": next}).Parse("{{.Namespace}}-{{.ChartName}}-{{next}}"))
+
+	res, err := rs.InstallRelease(helm.NewContext(), installRequest())
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if res.Release.Name != "spaced-hello-1" {
+		t.Errorf("Expected a second name to be generated after the collision, got %q", res.Release.Name)
+	}
+}
+
+func TestInstallRelease_ReleaseNameTemplateInvalidName(t *testing.T) {
+	rs := rsFixture()
+	tpl, err := ParseReleaseNameTemplate("{{.ChartName}}_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs.ReleaseNameTemplate = tpl
+
+	if _, err := rs.InstallRelease(helm.NewContext(), installRequest()); err == nil {
+		t.Error("Expected an error for a generated name that is not a valid release name")
+	}
+}
@@ -0,0 +1,65 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	tpb "k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/storage/driver"
+)
+
+// ReencryptReleases rewrites stored releases that are not sealed with the
+// primary storage encryption key. Releases are otherwise only re-encrypted
+// when they are next written. Each release is rewritten while holding its
+// lock, so that an operation on it cannot record a revision in between.
+func (s *ReleaseServer) ReencryptReleases(c context.Context, req *tpb.ReencryptReleasesRequest) (*tpb.ReencryptReleasesResponse, error) {
+	re, ok := s.env.Releases.Driver.(driver.Reencrypter)
+	if !ok {
+		return nil, status.Errorf(codes.FailedPrecondition, "storage driver %s does not support encryption", s.env.Releases.Name())
+	}
+
+	names, err := re.StaleReleases()
+	if err != nil {
+		s.Log("reencrypt: failed to list releases: %s", err)
+		return nil, err
+	}
+	var total int
+	for _, name := range names {
+		n, err := s.reencryptRelease(c, re, name)
+		total += n
+		if err != nil {
+			s.Log("reencrypt: failed after %d releases: %s", total, err)
+			return nil, err
+		}
+	}
+	s.Log("re-encrypted %d releases", total)
+	return &tpb.ReencryptReleasesResponse{Reencrypted: int32(total)}, nil
+}
+
+// reencryptRelease rewrites the records of the named release while holding
+// its lock.
+func (s *ReleaseServer) reencryptRelease(c context.Context, re driver.Reencrypter, name string) (int, error) {
+	unlock, err := s.locks.lock(c, name, s.LockTimeout)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+	return re.Reencrypt(name)
+}
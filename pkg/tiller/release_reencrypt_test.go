@@ -0,0 +1,82 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	tpb "k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/storage"
+	"k8s.io/helm/pkg/storage/driver"
+)
+
+func TestReencryptReleases_UnsupportedDriver(t *testing.T) {
+	rs := rsFixture()
+
+	_, err := rs.ReencryptReleases(context.TODO(), &tpb.ReencryptReleasesRequest{})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("Expected FailedPrecondition for the memory driver, got %v", err)
+	}
+}
+
+// staleDriver reports every release as needing re-encryption, and records
+// the releases it re-encrypted.
+type staleDriver struct {
+	driver.Driver
+	reencrypted []string
+}
+
+func (d *staleDriver) StaleReleases() ([]string, error) {
+	return []string{"angry-bird", "happy-cats"}, nil
+}
+
+func (d *staleDriver) Reencrypt(name string) (int, error) {
+	d.reencrypted = append(d.reencrypted, name)
+	return 1, nil
+}
+
+func TestReencryptReleasesLocksEachRelease(t *testing.T) {
+	rs := rsFixture()
+	rs.LockTimeout = 10 * time.Millisecond
+	d := &staleDriver{Driver: driver.NewMemory()}
+	rs.env.Releases = storage.Init(d)
+
+	unlock, err := rs.locks.lock(context.TODO(), "happy-cats", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = rs.ReencryptReleases(context.TODO(), &tpb.ReencryptReleasesRequest{})
+	if status.Code(err) != codes.Aborted {
+		t.Errorf("Expected Aborted while another operation holds a release, got %v", err)
+	}
+	unlock()
+
+	d.reencrypted = nil
+	res, err := rs.ReencryptReleases(context.TODO(), &tpb.ReencryptReleasesRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Reencrypted != 2 || !reflect.DeepEqual(d.reencrypted, []string{"angry-bird", "happy-cats"}) {
+		t.Errorf("Expected both releases to be re-encrypted, got %d: %v", res.Reencrypted, d.reencrypted)
+	}
+}
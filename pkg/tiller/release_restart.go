@@ -0,0 +1,40 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"strconv"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+// restartedRevisionAnnotation is set on the pod template of the workloads
+// restarted by an upgrade to the revision of that upgrade. Changing it makes
+// the controller roll the pods out again.
+const restartedRevisionAnnotation = "helm.sh/restarted-revision"
+
+// restartAnnotations returns the pod template annotations that make upgrading
+// to r restart the Deployments and StatefulSets of its manifest, if restart
+// is set. They are applied along with the upgrade, so that waiting for it
+// waits for the restarted pods too. Workloads that are not part of the
+// release are never touched.
+func restartAnnotations(r *release.Release, restart bool) map[string]string {
+	if !restart {
+		return nil
+	}
+	return map[string]string{restartedRevisionAnnotation: strconv.Itoa(int(r.Version))}
+}
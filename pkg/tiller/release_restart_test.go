@@ -0,0 +1,100 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller/environment"
+)
+
+// updateOptionsKubeClient records the options of each update.
+type updateOptionsKubeClient struct {
+	environment.PrintingKubeClient
+	opts []kube.UpdateOptions
+}
+
+func (kc *updateOptionsKubeClient) UpdateWithResult(ns string, currentReader, modifiedReader io.Reader, opts kube.UpdateOptions) ([]kube.AppliedResource, error) {
+	kc.opts = append(kc.opts, opts)
+	return nil, nil
+}
+
+func TestUpdateReleaseRestart(t *testing.T) {
+	for _, force := range []bool{false, true} {
+		c := helm.NewContext()
+		rs := rsFixture()
+		kc := &updateOptionsKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: ioutil.Discard}}
+		rs.env.KubeClient = kc
+		rel := releaseStub()
+		rs.env.Releases.Create(rel)
+
+		req := &services.UpdateReleaseRequest{
+			Name: rel.Name,
+			Chart: &chart.Chart{
+				Metadata:  &chart.Metadata{Name: "hello"},
+				Templates: []*chart.Template{{Name: "templates/web", Data: []byte(manifestWithDeployment)}},
+			},
+			Restart: true,
+			Wait:    true,
+			Force:   force,
+		}
+		if _, err := rs.UpdateRelease(c, req); err != nil {
+			t.Fatalf("force=%t: failed update: %s", force, err)
+		}
+
+		if len(kc.opts) != 1 {
+			t.Fatalf("force=%t: expected 1 update, got %d", force, len(kc.opts))
+		}
+		opts := kc.opts[0]
+		if got := opts.PodTemplateAnnotations[restartedRevisionAnnotation]; got != "2" {
+			t.Errorf("force=%t: expected the update to restart the workloads of revision 2, got %q", force, got)
+		}
+		if !opts.ShouldWait {
+			t.Errorf("force=%t: expected the restart to be applied by the update that waits", force)
+		}
+	}
+}
+
+func TestUpdateReleaseWithoutRestart(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	kc := &updateOptionsKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: ioutil.Discard}}
+	rs.env.KubeClient = kc
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+
+	req := &services.UpdateReleaseRequest{
+		Name: rel.Name,
+		Chart: &chart.Chart{
+			Metadata:  &chart.Metadata{Name: "hello"},
+			Templates: []*chart.Template{{Name: "templates/web", Data: []byte(manifestWithDeployment)}},
+		},
+	}
+	if _, err := rs.UpdateRelease(c, req); err != nil {
+		t.Fatalf("Failed updated: %s", err)
+	}
+
+	if len(kc.opts) != 1 || len(kc.opts[0].PodTemplateAnnotations) != 0 {
+		t.Errorf("expected web not to be restarted unless requested, got %+v", kc.opts)
+	}
+}
@@ -20,17 +20,39 @@ import (
 	"fmt"
 	"k8s.io/helm/pkg/storage"
 	"strings"
+	"time"
 
 	ctx "golang.org/x/net/context"
 
+	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/hooks"
+	"k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller/metrics"
 	"k8s.io/helm/pkg/timeconv"
 )
 
 // RollbackRelease rolls back to a previous version of the given release.
-func (s *ReleaseServer) RollbackRelease(c ctx.Context, req *services.RollbackReleaseRequest) (*services.RollbackReleaseResponse, error) {
+func (s *ReleaseServer) RollbackRelease(c ctx.Context, req *services.RollbackReleaseRequest) (res *services.RollbackReleaseResponse, err error) {
+	defer func(start time.Time) { metrics.ObserveOperation(metrics.Rollback, start, err) }(time.Now())
+	defer func() {
+		if !req.DryRun {
+			s.recordAudit(c, auditedRelease(req.Name, "", res.GetRelease()), "rollback", err)
+		}
+	}()
+
+	unlock, err := s.locks.lock(c, req.Name, s.LockTimeout)
+	if err != nil {
+		s.Log("rollbackRelease: %s", err)
+		return nil, err
+	}
+	defer unlock()
+	return s.rollbackRelease(c, req)
+}
+
+// rollbackRelease rolls back the release, which the caller must have locked.
+func (s *ReleaseServer) rollbackRelease(c ctx.Context, req *services.RollbackReleaseRequest) (*services.RollbackReleaseResponse, error) {
 	s.Log("preparing rollback of %s", req.Name)
 	currentRelease, targetRelease, err := s.prepareRollback(req)
 	if err != nil {
@@ -39,19 +61,19 @@ func (s *ReleaseServer) RollbackRelease(c ctx.Context, req *services.RollbackRel
 
 	if !req.DryRun {
 		s.Log("creating rolled back release for %s", req.Name)
-		if err := s.env.Releases.Create(targetRelease); err != nil {
+		if err := s.createRelease(targetRelease); err != nil {
 			return nil, err
 		}
 	}
 	s.Log("performing rollback of %s", req.Name)
-	res, err := s.performRollback(currentRelease, targetRelease, req)
+	res, err := s.performRollback(c, currentRelease, targetRelease, req)
 	if err != nil {
 		return res, err
 	}
 
 	if !req.DryRun {
 		s.Log("updating status for rolled back release for %s", req.Name)
-		if err := s.env.Releases.Update(targetRelease); err != nil {
+		if err := s.updateRelease(targetRelease); err != nil {
 			return res, err
 		}
 	}
@@ -87,6 +109,9 @@ func (s *ReleaseServer) prepareRollback(req *services.RollbackReleaseRequest) (*
 	if err != nil {
 		return nil, nil, err
 	}
+	if err := checkStoredChart(previousRelease); err != nil {
+		return nil, nil, err
+	}
 
 	description := req.Description
 	if req.Description == "" {
@@ -115,10 +140,69 @@ func (s *ReleaseServer) prepareRollback(req *services.RollbackReleaseRequest) (*
 		Hooks:    previousRelease.Hooks,
 	}
 
+	if req.ReuseValues {
+		if err := s.renderRollbackWithCurrentValues(currentRelease, targetRelease); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	return currentRelease, targetRelease, nil
 }
 
-func (s *ReleaseServer) performRollback(currentRelease, targetRelease *release.Release, req *services.RollbackReleaseRequest) (*services.RollbackReleaseResponse, error) {
+// renderRollbackWithCurrentValues merges the values of the current release
+// over those of the target revision, and renders the target's chart with
+// them in place of the target's stored manifest, hooks and notes.
+func (s *ReleaseServer) renderRollbackWithCurrentValues(currentRelease, targetRelease *release.Release) error {
+	s.Log("reusing the values of %s (v%d) for the rollback", currentRelease.Name, currentRelease.Version)
+	vals, err := readConfig(targetRelease.Config)
+	if err != nil {
+		return fmt.Errorf("failed to read values of the target revision: %s", err)
+	}
+	current, err := readConfig(currentRelease.Config)
+	if err != nil {
+		return fmt.Errorf("failed to read current values: %s", err)
+	}
+	vals.MergeInto(current)
+	raw, err := vals.YAML()
+	if err != nil {
+		return err
+	}
+	targetRelease.Config = &chart.Config{Raw: raw}
+
+	options := chartutil.ReleaseOptions{
+		Name:      targetRelease.Name,
+		Time:      targetRelease.Info.LastDeployed,
+		Namespace: targetRelease.Namespace,
+		IsUpgrade: true,
+		Revision:  int(targetRelease.Version),
+	}
+	caps, err := capabilities(s.clientset.Discovery())
+	if err != nil {
+		return err
+	}
+	valuesToRender, err := chartutil.ToRenderValuesCaps(targetRelease.Chart, targetRelease.Config, options, caps)
+	if err != nil {
+		return err
+	}
+	hooks, manifestDoc, notesTxt, err := s.renderResources(targetRelease.Chart, valuesToRender, false, caps.APIVersions)
+	if err != nil {
+		return err
+	}
+	targetRelease.Manifest = manifestDoc.String()
+	targetRelease.Hooks = hooks
+	targetRelease.Info.Status.Notes = notesTxt
+	return s.validateRendered(targetRelease.Namespace, []byte(targetRelease.Manifest))
+}
+
+// readConfig parses the YAML values of cfg, which may be empty.
+func readConfig(cfg *chart.Config) (chartutil.Values, error) {
+	if cfg == nil {
+		return chartutil.Values{}, nil
+	}
+	return chartutil.ReadValues([]byte(cfg.Raw))
+}
+
+func (s *ReleaseServer) performRollback(c ctx.Context, currentRelease, targetRelease *release.Release, req *services.RollbackReleaseRequest) (*services.RollbackReleaseResponse, error) {
 	res := &services.RollbackReleaseResponse{Release: targetRelease}
 
 	if req.DryRun {
@@ -128,7 +212,7 @@ func (s *ReleaseServer) performRollback(currentRelease, targetRelease *release.R
 
 	// pre-rollback hooks
 	if !req.DisableHooks {
-		if err := s.execHook(targetRelease.Hooks, targetRelease.Name, targetRelease.Namespace, hooks.PreRollback, req.Timeout); err != nil {
+		if err := s.execHook(c, targetRelease.Hooks, targetRelease.Name, targetRelease.Namespace, hooks.PreRollback, req.Timeout); err != nil {
 			return res, err
 		}
 	} else {
@@ -148,7 +232,7 @@ func (s *ReleaseServer) performRollback(currentRelease, targetRelease *release.R
 
 	// post-rollback hooks
 	if !req.DisableHooks {
-		if err := s.execHook(targetRelease.Hooks, targetRelease.Name, targetRelease.Namespace, hooks.PostRollback, req.Timeout); err != nil {
+		if err := s.execHook(c, targetRelease.Hooks, targetRelease.Name, targetRelease.Namespace, hooks.PostRollback, req.Timeout); err != nil {
 			return res, err
 		}
 	}
@@ -20,7 +20,11 @@ import (
 	"strings"
 	"testing"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
 )
@@ -319,6 +323,27 @@ func TestRollbackReleaseFailure(t *testing.T) {
 	}
 }
 
+func TestRollbackReleaseMissingChart(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rel.Chart = nil
+	rel.Info.Status.Code = release.Status_SUPERSEDED
+	rs.env.Releases.Create(rel)
+	rs.env.Releases.Create(upgradeReleaseVersion(releaseStub()))
+
+	_, err := rs.RollbackRelease(c, &services.RollbackReleaseRequest{Name: rel.Name, Version: 1})
+	if code := status.Code(err); code != codes.FailedPrecondition {
+		t.Fatalf("Expected code %s, got %s: %v", codes.FailedPrecondition, code, err)
+	}
+	if !strings.Contains(err.Error(), "stored without its chart") {
+		t.Errorf("Expected error to explain the chart is missing, got %q", err)
+	}
+	if _, err := rs.env.Releases.Get(rel.Name, 3); err == nil {
+		t.Error("Expected no rolled back release to be recorded")
+	}
+}
+
 func TestRollbackReleaseWithCustomDescription(t *testing.T) {
 	c := helm.NewContext()
 	rs := rsFixture()
@@ -350,3 +375,41 @@ func TestRollbackReleaseWithCustomDescription(t *testing.T) {
 		t.Errorf("Expected Description to be %q, got %q", customDescription, res.Release.Info.Description)
 	}
 }
+
+func TestRollbackReleaseReuseValues(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	rel := releaseStub()
+	rel.Info.Status.Code = release.Status_SUPERSEDED
+	rel.Chart.Templates = append(rel.Chart.Templates, &chart.Template{
+		Name: "templates/config",
+		Data: []byte("replicas: {{ .Values.replicas }}\nimage: {{ .Values.image }}"),
+	})
+	rel.Config = &chart.Config{Raw: "replicas: 1\nimage: old\n"}
+	rs.env.Releases.Create(rel)
+
+	upgradedRel := upgradeReleaseVersion(rel)
+	upgradedRel.Config = &chart.Config{Raw: "replicas: 3\n"}
+	rs.env.Releases.Create(upgradedRel)
+
+	res, err := rs.RollbackRelease(c, &services.RollbackReleaseRequest{Name: rel.Name, ReuseValues: true})
+	if err != nil {
+		t.Fatalf("Failed rollback: %s", err)
+	}
+	if !strings.Contains(res.Release.Manifest, "replicas: 3") || !strings.Contains(res.Release.Manifest, "image: old") {
+		t.Errorf("Expected the current replicas merged over the target's image, got manifest:\n%s", res.Release.Manifest)
+	}
+	if res.Release.Config.Raw != "image: old\nreplicas: 3\n" {
+		t.Errorf("Expected merged values to be stored, got %q", res.Release.Config.Raw)
+	}
+
+	// Without ReuseValues the target revision is restored wholesale.
+	res, err = rs.RollbackRelease(c, &services.RollbackReleaseRequest{Name: rel.Name, Version: 1})
+	if err != nil {
+		t.Fatalf("Failed rollback: %s", err)
+	}
+	if res.Release.Config.Raw != rel.Config.Raw {
+		t.Errorf("Expected values of revision 1, got %q", res.Release.Config.Raw)
+	}
+}
@@ -20,22 +20,33 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io"
+	"math"
 	"path"
 	"regexp"
 	"strings"
+	"sync"
+	"text/template"
 	"time"
 
+	"github.com/ghodss/yaml"
+	"github.com/golang/protobuf/proto"
 	"github.com/technosophos/moniker"
+	ctx "golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/kubernetes"
 
 	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/engine"
 	"k8s.io/helm/pkg/hooks"
 	"k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
 	relutil "k8s.io/helm/pkg/releaseutil"
+	"k8s.io/helm/pkg/strvals"
 	"k8s.io/helm/pkg/tiller/environment"
 	"k8s.io/helm/pkg/timeconv"
 	"k8s.io/helm/pkg/version"
@@ -54,6 +65,10 @@ const (
 	// wants to see this file after rendering in the status command. However, it must be a suffix
 	// since there can be filepath in front of it.
 	notesFileSuffix = "NOTES.txt"
+
+	// expectedKindsAnnotation is a chart metadata annotation holding a
+	// comma-separated list of the resource kinds the chart may render.
+	expectedKindsAnnotation = "helm.sh/expected-kinds"
 )
 
 var (
@@ -87,6 +102,102 @@ type ReleaseServer struct {
 	env       *environment.Environment
 	clientset kubernetes.Interface
 	Log       func(string, ...interface{})
+
+	// MaxRenderBytes limits the size of the rendered output of a chart. Values
+	// of 0 or less impose no limit.
+	MaxRenderBytes int64
+
+	// MaxValuesBytes limits the size of the values supplied with an install
+	// or upgrade request. Values of 0 or less impose no limit.
+	MaxValuesBytes int64
+
+	// RenderParallelism bounds how many subcharts of a chart are rendered
+	// concurrently. Values of 1 or less render serially.
+	RenderParallelism int
+
+	// DefaultValuesFile names a values file inside charts that is layered over
+	// the chart's values.yaml, beneath any values supplied by the client.
+	// Charts without such a file are rendered as usual.
+	DefaultValuesFile string
+
+	// ChartRepoURL is the chart repository used for install chart references
+	// that do not name a repository.
+	ChartRepoURL string
+
+	// ChartRepoAllowlist lists the repository URLs, besides ChartRepoURL, that
+	// charts may be downloaded from.
+	ChartRepoAllowlist []string
+
+	// Audit records who performed each install, upgrade, rollback and
+	// uninstall in AuditStore.
+	Audit bool
+
+	// AuditStore keeps the audit trail recorded when Audit is enabled.
+	AuditStore AuditStore
+
+	// AuditLog, if set, is written a record of each install, upgrade,
+	// rollback, uninstall and approval, whether or not Audit is enabled.
+	AuditLog *AuditLog
+
+	// AllowDuplicateResources keeps the last of several manifests rendering
+	// the same resource instead of rejecting the chart.
+	AllowDuplicateResources bool
+
+	// ReleaseNameTemplate generates the names of releases installed without
+	// one. When nil, random names are generated.
+	ReleaseNameTemplate *template.Template
+
+	// SkipUnchangedUpgrades returns the deployed release instead of creating
+	// a new version when an upgrade renders the same manifests from the same
+	// values.
+	SkipUnchangedUpgrades bool
+
+	// ParallelHooks runs hooks for the same event that share a weight
+	// concurrently, waiting for all of them before moving on to the next
+	// weight.
+	ParallelHooks bool
+
+	// VerifyImages checks that the container images a release references
+	// exist in their registries before installing or upgrading it.
+	VerifyImages bool
+
+	// RegistryAuths holds the credentials used by VerifyImages, keyed by
+	// registry host.
+	RegistryAuths map[string]RegistryAuth
+
+	// EnforceQuotas rejects installs whose estimated resource footprint does
+	// not fit the ResourceQuotas of the target namespace.
+	EnforceQuotas bool
+
+	// StatusWatcher, if set, posts a webhook when a release enters one of
+	// its watched statuses.
+	StatusWatcher *StatusWatcher
+
+	// RetryBudget limits how often failed operations, such as writing a
+	// release record, are retried. When nil, they are not retried.
+	RetryBudget *RetryBudget
+
+	// AutoRollbackWindow is how long the Deployments of an upgraded release
+	// are watched, to roll it back to the version it replaced if they become
+	// unhealthy. Values of 0 or less disable automatic rollbacks.
+	AutoRollbackWindow time.Duration
+
+	// DefaultNamespace is the namespace releases are installed into when the
+	// install request names none.
+	DefaultNamespace string
+
+	// SchemaValidation validates the rendered manifests of installs and
+	// upgrades one at a time, so that schema validation errors name the
+	// template of each invalid resource.
+	SchemaValidation bool
+
+	// LockTimeout is how long an operation changing a release waits for
+	// another one in progress on the same release before giving up. Values of
+	// 0 or less wait as long as the request allows.
+	LockTimeout time.Duration
+
+	names *generatedNames
+	locks *releaseLocks
 }
 
 // NewReleaseServer creates a new release server.
@@ -105,6 +216,9 @@ func NewReleaseServer(env *environment.Environment, clientset kubernetes.Interfa
 		clientset:     clientset,
 		ReleaseModule: releaseModule,
 		Log:           func(_ string, _ ...interface{}) {},
+		names:         &generatedNames{},
+		locks:         &releaseLocks{},
+		AuditStore:    NewMemoryAuditStore(),
 	}
 }
 
@@ -175,6 +289,77 @@ func (s *ReleaseServer) reuseValues(req *services.UpdateReleaseRequest, current
 	return nil
 }
 
+// checkValuesSize rejects values whose serialized size exceeds
+// MaxValuesBytes, before any rendering is done with them.
+func (s *ReleaseServer) checkValuesSize(values *chart.Config) error {
+	if s.MaxValuesBytes <= 0 || values == nil {
+		return nil
+	}
+	if n := proto.Size(values); int64(n) > s.MaxValuesBytes {
+		return status.Errorf(codes.InvalidArgument, "values are %d bytes, exceeding the limit of %d bytes", n, s.MaxValuesBytes)
+	}
+	return nil
+}
+
+// mergeSetValues applies values, each a key=value pair in the syntax of
+// helm's --set flag, in order over the values of config, and returns the
+// result as a new config.
+func mergeSetValues(config *chart.Config, values []string) (*chart.Config, error) {
+	if len(values) == 0 {
+		return config, nil
+	}
+	merged := chartutil.Values{}
+	if config != nil && config.Raw != "" {
+		vals, err := chartutil.ReadValues([]byte(config.Raw))
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "cannot parse values: %s", err)
+		}
+		merged = vals
+	}
+	for _, v := range values {
+		if err := strvals.ParseInto(v, merged); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid set value %q: %s", v, err)
+		}
+	}
+	raw, err := merged.YAML()
+	if err != nil {
+		return nil, err
+	}
+	return &chart.Config{Raw: raw}, nil
+}
+
+// applyDefaultValuesFile merges the chart file named by DefaultValuesFile, if
+// present, into the chart's default values.
+func (s *ReleaseServer) applyDefaultValuesFile(ch *chart.Chart) error {
+	if s.DefaultValuesFile == "" {
+		return nil
+	}
+	for _, f := range ch.Files {
+		if f.TypeUrl != s.DefaultValuesFile {
+			continue
+		}
+		overrides, err := chartutil.ReadValues(f.Value)
+		if err != nil {
+			return fmt.Errorf("cannot parse %s of chart %s: %s", s.DefaultValuesFile, ch.Metadata.Name, err)
+		}
+		base := chartutil.Values{}
+		if ch.Values != nil && ch.Values.Raw != "" {
+			if base, err = chartutil.ReadValues([]byte(ch.Values.Raw)); err != nil {
+				return err
+			}
+		}
+		base.MergeInto(overrides)
+		raw, err := base.YAML()
+		if err != nil {
+			return err
+		}
+		s.Log("using %s as default values for chart %s", s.DefaultValuesFile, ch.Metadata.Name)
+		ch.Values = &chart.Config{Raw: raw}
+		return nil
+	}
+	return nil
+}
+
 func (s *ReleaseServer) uniqName(start string, reuse bool) (string, error) {
 
 	// If a name is supplied, we check to see if that name is taken. If not, it
@@ -216,9 +401,20 @@ func (s *ReleaseServer) uniqName(start string, reuse bool) (string, error) {
 }
 
 func (s *ReleaseServer) createUniqName(m moniker.Namer) (string, error) {
+	return s.generateUniqName(func() (string, error) {
+		return m.NameSep("-"), nil
+	})
+}
+
+// generateUniqName calls next until it returns a name no release has been
+// installed under, giving up after a few tries.
+func (s *ReleaseServer) generateUniqName(next func() (string, error)) (string, error) {
 	maxTries := 5
 	for i := 0; i < maxTries; i++ {
-		name := m.NameSep("-")
+		name, err := next()
+		if err != nil {
+			return "ERROR", err
+		}
 		if len(name) > releaseNameMaxLen {
 			name = name[:releaseNameMaxLen]
 		}
@@ -242,6 +438,17 @@ func (s *ReleaseServer) engine(ch *chart.Chart) environment.Engine {
 			s.Log("warning: %s requested non-existent template engine %s", ch.Metadata.Name, ch.Metadata.Engine)
 		}
 	}
+	if e, ok := renderer.(*engine.Engine); ok && (s.MaxRenderBytes > 0 || s.RenderParallelism > 1) {
+		// The engine is shared across requests, so configure a copy.
+		configured := *e
+		if s.MaxRenderBytes > 0 {
+			configured.MaxRenderBytes = s.MaxRenderBytes
+		}
+		if s.RenderParallelism > 1 {
+			configured.Parallelism = s.RenderParallelism
+		}
+		renderer = &configured
+	}
 	return renderer
 }
 
@@ -354,6 +561,9 @@ func (s *ReleaseServer) renderResources(ch *chart.Chart, values chartutil.Values
 	s.Log("rendering %s chart using values", ch.GetMetadata().Name)
 	renderer := s.engine(ch)
 	files, err := renderer.Render(ch, values)
+	if err == engine.ErrRenderSizeExceeded {
+		return nil, nil, "", status.Errorf(codes.ResourceExhausted, "chart %s: %s (limit %d bytes)", ch.Metadata.Name, err, s.MaxRenderBytes)
+	}
 	if err != nil {
 		return nil, nil, "", err
 	}
@@ -401,6 +611,24 @@ func (s *ReleaseServer) renderResources(ch *chart.Chart, values chartutil.Values
 		return nil, b, "", err
 	}
 
+	if err := validateExpectedKinds(ch, manifests); err != nil {
+		return nil, nil, "", err
+	}
+
+	var namespace string
+	if rel, ok := values["Release"].(map[string]interface{}); ok {
+		namespace, _ = rel["Namespace"].(string)
+	}
+	manifests, err = s.checkDuplicateResources(manifests, namespace)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	manifests, err = orderByApplyAfter(manifests)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
 	// Aggregate all valid manifests into one big doc.
 	b := bytes.NewBuffer(nil)
 	for _, m := range manifests {
@@ -411,22 +639,125 @@ func (s *ReleaseServer) renderResources(ch *chart.Chart, values chartutil.Values
 	return hooks, b, notes, nil
 }
 
+// validateExpectedKinds rejects manifests whose kind is not listed in the
+// chart's expected-kinds annotation. Charts without the annotation are not
+// checked. Hooks are not subject to the check.
+func validateExpectedKinds(ch *chart.Chart, manifests []Manifest) error {
+	declared, ok := ch.Metadata.Annotations[expectedKindsAnnotation]
+	if !ok {
+		return nil
+	}
+	expected := map[string]bool{}
+	for _, kind := range strings.Split(declared, ",") {
+		if kind = strings.TrimSpace(kind); kind != "" {
+			expected[kind] = true
+		}
+	}
+	for _, m := range manifests {
+		if m.Head == nil || expected[m.Head.Kind] {
+			continue
+		}
+		return status.Errorf(codes.InvalidArgument, "chart %s renders undeclared kind %s in %s (expected one of: %s)", ch.Metadata.Name, m.Head.Kind, m.Name, declared)
+	}
+	return nil
+}
+
+// checkDuplicateResources looks for manifests that render the same resource,
+// taking manifests without a namespace to be in the release namespace.
+// Duplicates are rejected unless AllowDuplicateResources is set, in which
+// case only the last manifest for each resource is kept.
+func (s *ReleaseServer) checkDuplicateResources(manifests []Manifest, namespace string) ([]Manifest, error) {
+	keys := make([]string, len(manifests))
+	last := map[string]int{}
+	var dups []string
+	for i, m := range manifests {
+		var head struct {
+			Kind     string `json:"kind"`
+			Metadata struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"metadata"`
+		}
+		if err := yaml.Unmarshal([]byte(m.Content), &head); err != nil || head.Kind == "" || head.Metadata.Name == "" {
+			continue
+		}
+		if head.Metadata.Namespace == "" {
+			head.Metadata.Namespace = namespace
+		}
+		key := fmt.Sprintf("%s %s/%s", head.Kind, head.Metadata.Namespace, head.Metadata.Name)
+		if j, ok := last[key]; ok {
+			dups = append(dups, fmt.Sprintf("%s (in %s and %s)", key, manifests[j].Name, m.Name))
+		}
+		keys[i] = key
+		last[key] = i
+	}
+	if len(dups) == 0 {
+		return manifests, nil
+	}
+	if !s.AllowDuplicateResources {
+		return nil, status.Errorf(codes.InvalidArgument, "manifest contains duplicate resources: %s", strings.Join(dups, "; "))
+	}
+
+	s.Log("warning: keeping the last of duplicate resources: %s", strings.Join(dups, "; "))
+	kept := make([]Manifest, 0, len(manifests))
+	for i, m := range manifests {
+		if keys[i] == "" || last[keys[i]] == i {
+			kept = append(kept, m)
+		}
+	}
+	return kept, nil
+}
+
+// recordAttempts is how many times writing a release record is attempted,
+// budget permitting.
+const recordAttempts = 3
+
 // recordRelease with an update operation in case reuse has been set.
 func (s *ReleaseServer) recordRelease(r *release.Release, reuse bool) {
 	if reuse {
-		if err := s.env.Releases.Update(r); err != nil {
+		if err := s.retry(recordAttempts, func() error { return s.env.Releases.Update(r) }); err != nil {
 			s.Log("warning: Failed to update release %s: %s", r.Name, err)
+			return
 		}
-	} else if err := s.env.Releases.Create(r); err != nil {
+	} else if err := s.retry(recordAttempts, func() error { return s.env.Releases.Create(r) }); err != nil {
 		s.Log("warning: Failed to record release %s: %s", r.Name, err)
+		return
 	}
+	s.notifyStatus(r)
 }
 
-func (s *ReleaseServer) execHook(hs []*release.Hook, name, namespace, hook string, timeout int64) error {
+// createRelease stores a new release record, reporting its status to the
+// StatusWatcher.
+func (s *ReleaseServer) createRelease(r *release.Release) error {
+	if err := s.env.Releases.Create(r); err != nil {
+		return err
+	}
+	s.notifyStatus(r)
+	return nil
+}
+
+// updateRelease updates a release record, reporting its status to the
+// StatusWatcher.
+func (s *ReleaseServer) updateRelease(r *release.Release) error {
+	if err := s.env.Releases.Update(r); err != nil {
+		return err
+	}
+	s.notifyStatus(r)
+	return nil
+}
+
+func (s *ReleaseServer) execHook(c ctx.Context, hs []*release.Hook, name, namespace, hook string, timeout int64) error {
+	_, err := s.execHookWithResults(c, hs, name, namespace, hook, timeout)
+	return err
+}
+
+// execHookWithResults runs the hooks for an event like execHook, returning
+// the outcome of each of them in the order they ran.
+func (s *ReleaseServer) execHookWithResults(c ctx.Context, hs []*release.Hook, name, namespace, hook string, timeout int64) ([]*release.HookResult, error) {
 	kubeCli := s.env.KubeClient
 	code, ok := events[hook]
 	if !ok {
-		return fmt.Errorf("unknown hook %s", hook)
+		return nil, fmt.Errorf("unknown hook %s", hook)
 	}
 
 	s.Log("executing %d %s hooks for %s", len(hs), hook, name)
@@ -441,35 +772,49 @@ func (s *ReleaseServer) execHook(hs []*release.Hook, name, namespace, hook strin
 
 	executingHooks = sortByHookWeight(executingHooks)
 
-	for _, h := range executingHooks {
-		if err := s.deleteHookByPolicy(h, hooks.BeforeHookCreation, name, namespace, hook, kubeCli); err != nil {
-			return err
+	results := make([]*release.HookResult, len(executingHooks))
+	for i, h := range executingHooks {
+		results[i] = &release.HookResult{Name: h.Name, Event: code, Phase: release.HookResult_SKIPPED}
+	}
+	run := func(i int) error {
+		start := time.Now()
+		err := s.runHook(c, executingHooks[i], name, namespace, hook, timeout)
+		results[i].DurationMs = int64(time.Since(start) / time.Millisecond)
+		if err != nil {
+			results[i].Phase = release.HookResult_FAILED
+		} else {
+			results[i].Phase = release.HookResult_SUCCEEDED
 		}
+		return err
+	}
 
-		b := bytes.NewBufferString(h.Manifest)
-		if err := kubeCli.Create(namespace, b, timeout, false); err != nil {
-			s.Log("warning: Release %s %s %s failed: %s", name, hook, h.Path, err)
-			return err
-		}
-		// No way to rewind a bytes.Buffer()?
-		b.Reset()
-		b.WriteString(h.Manifest)
-
-		// We can't watch CRDs, but need to wait until they reach the established state before continuing
-		if hook != hooks.CRDInstall {
-			if err := kubeCli.WatchUntilReady(namespace, b, timeout, false); err != nil {
-				s.Log("warning: Release %s %s %s could not complete: %s", name, hook, h.Path, err)
-				// If a hook is failed, checkout the annotation of the hook to determine whether the hook should be deleted
-				// under failed condition. If so, then clear the corresponding resource object in the hook
-				if err := s.deleteHookByPolicy(h, hooks.HookFailed, name, namespace, hook, kubeCli); err != nil {
-					return err
+	next := 0
+	for _, group := range groupByHookWeight(executingHooks) {
+		first := next
+		next += len(group)
+		if !s.ParallelHooks || len(group) == 1 {
+			for i := first; i < next; i++ {
+				if err := run(i); err != nil {
+					return results, err
 				}
-				return err
 			}
-		} else {
-			if err := kubeCli.WaitUntilCRDEstablished(b, time.Duration(timeout)*time.Second); err != nil {
-				s.Log("warning: Release %s %s %s could not complete: %s", name, hook, h.Path, err)
-				return err
+			continue
+		}
+
+		s.Log("executing %d %s hooks with weight %d for %s in parallel", len(group), hook, group[0].Weight, name)
+		errs := make([]error, len(group))
+		var wg sync.WaitGroup
+		for i := range group {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				errs[i] = run(first + i)
+			}(i)
+		}
+		wg.Wait()
+		for _, err := range errs {
+			if err != nil {
+				return results, err
 			}
 		}
 	}
@@ -479,19 +824,181 @@ func (s *ReleaseServer) execHook(hs []*release.Hook, name, namespace, hook strin
 	// under succeeded condition. If so, then clear the corresponding resource object in each hook
 	for _, h := range executingHooks {
 		if err := s.deleteHookByPolicy(h, hooks.HookSucceeded, name, namespace, hook, kubeCli); err != nil {
-			return err
+			return results, err
 		}
 		h.LastRun = timeconv.Now()
 	}
 
+	return results, nil
+}
+
+// runHook creates the resources of a single hook and waits for them to
+// complete, or for c to be done.
+func (s *ReleaseServer) runHook(c ctx.Context, h *release.Hook, name, namespace, hook string, timeout int64) error {
+	if c.Err() != nil {
+		return status.FromContextError(c.Err()).Err()
+	}
+	kubeCli := s.env.KubeClient
+	if err := s.deleteHookByPolicy(h, hooks.BeforeHookCreation, name, namespace, hook, kubeCli); err != nil {
+		return err
+	}
+
+	b := bytes.NewBufferString(h.Manifest)
+	if err := kubeCli.Create(namespace, b, timeout, false); err != nil {
+		s.Log("warning: Release %s %s %s failed: %s", name, hook, h.Path, err)
+		return err
+	}
+	// No way to rewind a bytes.Buffer()?
+	b.Reset()
+	b.WriteString(h.Manifest)
+
+	// We can't watch CRDs, but need to wait until they reach the established state before continuing
+	if hook != hooks.CRDInstall {
+		if err := watchUntilReady(c, kubeCli, namespace, b, timeout); err != nil {
+			s.Log("warning: Release %s %s %s could not complete: %s", name, hook, h.Path, err)
+			// If a hook is failed, checkout the annotation of the hook to determine whether the hook should be deleted
+			// under failed condition. If so, then clear the corresponding resource object in the hook
+			if err := s.deleteHookByPolicy(h, hooks.HookFailed, name, namespace, hook, kubeCli); err != nil {
+				return err
+			}
+			return err
+		}
+	} else {
+		if err := kubeCli.WaitUntilCRDEstablished(b, time.Duration(timeout)*time.Second); err != nil {
+			s.Log("warning: Release %s %s %s could not complete: %s", name, hook, h.Path, err)
+			return err
+		}
+	}
 	return nil
 }
 
+// watchUntilReady waits for the resources in r to be ready, giving up when c
+// is done if the client supports it.
+func watchUntilReady(c ctx.Context, kubeCli environment.KubeClient, namespace string, r io.Reader, timeout int64) error {
+	cw, ok := kubeCli.(environment.ContextWatcher)
+	if !ok {
+		return kubeCli.WatchUntilReady(namespace, r, timeout, false)
+	}
+	if err := cw.WatchUntilReadyContext(c, namespace, r, timeout, false); err != nil {
+		if c.Err() != nil {
+			return status.FromContextError(c.Err()).Err()
+		}
+		return err
+	}
+	return nil
+}
+
+// requestTimeout returns the timeout, in seconds, of the Kubernetes
+// operations of a request with context c and timeout, so that they end by
+// the deadline of the request.
+func requestTimeout(c ctx.Context, timeout int64) int64 {
+	deadline, ok := c.Deadline()
+	if !ok {
+		return timeout
+	}
+	remaining := int64(math.Ceil(time.Until(deadline).Seconds()))
+	if remaining < 1 {
+		remaining = 1
+	}
+	if timeout <= 0 || remaining < timeout {
+		return remaining
+	}
+	return timeout
+}
+
+// validateRendered validates the rendered manifest of an install, upgrade or
+// rollback. With SchemaValidation, each resource is validated on its own, and
+// the errors of all invalid resources are returned along with their
+// templates.
+//
+// Custom resources whose definition is part of the same manifest are not
+// validated, as their kind is unknown to the API server until the definition
+// is installed.
+func (s *ReleaseServer) validateRendered(ns string, manifest []byte) error {
+	docs, unparsable := parseManifest(string(manifest))
+	var contents []string
+	for _, d := range withoutDefinedKinds(docs) {
+		contents = append(contents, d.Content)
+	}
+	// Unparsable documents are validated too, so that their parse errors
+	// are reported.
+	contents = append(contents, unparsable...)
+	if !s.SchemaValidation {
+		return validateManifest(s.env.KubeClient, ns, []byte(strings.Join(contents, "\n---\n")))
+	}
+	var errs []string
+	for _, doc := range contents {
+		if err := validateManifest(s.env.KubeClient, ns, []byte(doc)); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", manifestSource(doc), err))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+// withoutDefinedKinds returns docs without the resources of the kinds defined
+// by the CustomResourceDefinitions among them.
+func withoutDefinedKinds(docs []*services.ManifestDocument) []*services.ManifestDocument {
+	defined := map[string]bool{}
+	for _, d := range docs {
+		if d.Kind != "CustomResourceDefinition" {
+			continue
+		}
+		var crd struct {
+			Spec struct {
+				Group string
+				Names struct {
+					Kind string
+				}
+			}
+		}
+		if err := yaml.Unmarshal([]byte(d.Content), &crd); err == nil {
+			defined[crd.Spec.Group+"/"+crd.Spec.Names.Kind] = true
+		}
+	}
+	if len(defined) == 0 {
+		return docs
+	}
+	var kept []*services.ManifestDocument
+	for _, d := range docs {
+		group := ""
+		if slash := strings.LastIndex(d.ApiVersion, "/"); slash >= 0 {
+			group = d.ApiVersion[:slash]
+		}
+		if !defined[group+"/"+d.Kind] {
+			kept = append(kept, d)
+		}
+	}
+	return kept
+}
+
+// manifestSource returns the template named by the "# Source:" comment that
+// starts a rendered manifest.
+func manifestSource(doc string) string {
+	const prefix = "# Source: "
+	line := strings.SplitN(doc, "\n", 2)[0]
+	if !strings.HasPrefix(line, prefix) {
+		return "manifest"
+	}
+	return strings.TrimPrefix(line, prefix)
+}
+
 func validateManifest(c environment.KubeClient, ns string, manifest []byte) error {
 	r := bytes.NewReader(manifest)
 	return c.Validate(ns, r)
 }
 
+// checkStoredChart fails with FailedPrecondition if rel was stored without its
+// chart, which corrupted records and some older Tiller versions left behind.
+func checkStoredChart(rel *release.Release) error {
+	if rel.Chart == nil {
+		return status.Errorf(codes.FailedPrecondition, "release %s (v%d) was stored without its chart and cannot be used", rel.Name, rel.Version)
+	}
+	return nil
+}
+
 func validateReleaseName(releaseName string) error {
 	if releaseName == "" {
 		return errMissingRelease
@@ -508,15 +1015,32 @@ func (s *ReleaseServer) deleteHookByPolicy(h *release.Hook, policy string, name,
 	b := bytes.NewBufferString(h.Manifest)
 	if hookHasDeletePolicy(h, policy) {
 		s.Log("deleting %s hook %s for release %s due to %q policy", hook, h.Name, name, policy)
-		waitForDelete := h.DeleteTimeout > 0
-		if errHookDelete := kubeCli.DeleteWithTimeout(namespace, b, h.DeleteTimeout, waitForDelete); errHookDelete != nil {
-			s.Log("warning: Release %s %s %S could not be deleted: %s", name, hook, h.Path, errHookDelete)
+		timeout := hookDeleteTimeout(h)
+		waitForDelete := timeout > 0
+		if errHookDelete := kubeCli.DeleteWithTimeout(namespace, b, timeout, waitForDelete); errHookDelete != nil {
+			s.Log("warning: Release %s %s %s could not be deleted: %s", name, hook, h.Path, errHookDelete)
 			return errHookDelete
 		}
 	}
 	return nil
 }
 
+// hookDeleteTimeout returns how long to wait for h to be deleted. Hooks
+// recorded before delete timeouts were parsed carry none, so they get the
+// default unless their manifest sets the annotation, which may be 0 to not wait.
+func hookDeleteTimeout(h *release.Hook) int64 {
+	if h.DeleteTimeout > 0 {
+		return h.DeleteTimeout
+	}
+	var head relutil.SimpleHead
+	if err := yaml.Unmarshal([]byte(h.Manifest), &head); err == nil && head.Metadata != nil {
+		if _, ok := head.Metadata.Annotations[hooks.HookDeleteTimeoutAnno]; ok {
+			return h.DeleteTimeout
+		}
+	}
+	return defaultHookDeleteTimeoutInSeconds
+}
+
 // hookHasDeletePolicy determines whether the defined hook deletion policy matches the hook deletion polices
 // supported by helm. If so, mark the hook as one should be deleted.
 func hookHasDeletePolicy(h *release.Hook, policy string) bool {
@@ -23,6 +23,8 @@ import (
 	"io/ioutil"
 	"os"
 	"regexp"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -30,7 +32,9 @@ import (
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/technosophos/moniker"
 	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/cli-runtime/pkg/resource"
 	"k8s.io/client-go/kubernetes/fake"
@@ -41,6 +45,7 @@ import (
 	"k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
+	relutil "k8s.io/helm/pkg/releaseutil"
 	"k8s.io/helm/pkg/storage"
 	"k8s.io/helm/pkg/storage/driver"
 	"k8s.io/helm/pkg/tiller/environment"
@@ -526,6 +531,10 @@ func (u *updateFailingKubeClient) UpdateWithOptions(namespace string, originalRe
 	return errors.New("Failed update in kube client")
 }
 
+func (u *updateFailingKubeClient) UpdateWithResult(namespace string, originalReader, modifiedReader io.Reader, opts kube.UpdateOptions) ([]kube.AppliedResource, error) {
+	return nil, u.UpdateWithOptions(namespace, originalReader, modifiedReader, opts)
+}
+
 func newHookFailingKubeClient() *hookFailingKubeClient {
 	return &hookFailingKubeClient{
 		PrintingKubeClient: environment.PrintingKubeClient{Out: ioutil.Discard},
@@ -540,6 +549,20 @@ func (h *hookFailingKubeClient) WatchUntilReady(ns string, r io.Reader, timeout
 	return errors.New("Failed watch")
 }
 
+func newCreateFailingKubeClient() *createFailingKubeClient {
+	return &createFailingKubeClient{
+		PrintingKubeClient: environment.PrintingKubeClient{Out: ioutil.Discard},
+	}
+}
+
+type createFailingKubeClient struct {
+	environment.PrintingKubeClient
+}
+
+func (c *createFailingKubeClient) CreateWithResult(ns string, r io.Reader, opts kube.CreateOptions) ([]kube.AppliedResource, error) {
+	return nil, errors.New("Failed create in kube client")
+}
+
 func newDeleteFailingKubeClient() *deleteFailingKubeClient {
 	return &deleteFailingKubeClient{
 		PrintingKubeClient: environment.PrintingKubeClient{Out: ioutil.Discard},
@@ -628,6 +651,9 @@ func (kc *mockHooksKubeClient) Create(ns string, r io.Reader, timeout int64, sho
 
 	return nil
 }
+func (kc *mockHooksKubeClient) CreateWithResult(ns string, r io.Reader, opts kube.CreateOptions) ([]kube.AppliedResource, error) {
+	return nil, kc.Create(ns, r, opts.Timeout, opts.ShouldWait)
+}
 func (kc *mockHooksKubeClient) Get(ns string, r io.Reader) (string, error) {
 	return "", nil
 }
@@ -644,6 +670,9 @@ func (kc *mockHooksKubeClient) DeleteWithTimeout(ns string, r io.Reader, timeout
 
 	return nil
 }
+func (kc *mockHooksKubeClient) WaitForDeletion(ns string, r io.Reader, timeout time.Duration, removeFinalizers bool) ([]string, error) {
+	return nil, nil
+}
 func (kc *mockHooksKubeClient) WatchUntilReady(ns string, r io.Reader, timeout int64, shouldWait bool) error {
 	paramManifest, err := kc.makeManifest(r)
 	if err != nil {
@@ -667,6 +696,9 @@ func (kc *mockHooksKubeClient) Update(ns string, currentReader, modifiedReader i
 func (kc *mockHooksKubeClient) UpdateWithOptions(ns string, currentReader, modifiedReader io.Reader, opts kube.UpdateOptions) error {
 	return nil
 }
+func (kc *mockHooksKubeClient) UpdateWithResult(ns string, currentReader, modifiedReader io.Reader, opts kube.UpdateOptions) ([]kube.AppliedResource, error) {
+	return nil, nil
+}
 func (kc *mockHooksKubeClient) Build(ns string, reader io.Reader) (kube.Result, error) {
 	return []*resource.Info{}, nil
 }
@@ -729,7 +761,7 @@ name: value`, hookName, extraAnnotationsStr),
 }
 
 func execHookShouldSucceed(rs *ReleaseServer, hook *release.Hook, releaseName string, namespace string, hookType string) error {
-	err := rs.execHook([]*release.Hook{hook}, releaseName, namespace, hookType, 600)
+	err := rs.execHook(context.Background(), []*release.Hook{hook}, releaseName, namespace, hookType, 600)
 	if err != nil {
 		return fmt.Errorf("expected hook %s to be successful: %s", hook.Name, err)
 	}
@@ -737,7 +769,7 @@ func execHookShouldSucceed(rs *ReleaseServer, hook *release.Hook, releaseName st
 }
 
 func execHookShouldFail(rs *ReleaseServer, hook *release.Hook, releaseName string, namespace string, hookType string) error {
-	err := rs.execHook([]*release.Hook{hook}, releaseName, namespace, hookType, 600)
+	err := rs.execHook(context.Background(), []*release.Hook{hook}, releaseName, namespace, hookType, 600)
 	if err == nil {
 		return fmt.Errorf("expected hook %s to be failed", hook.Name)
 	}
@@ -745,7 +777,7 @@ func execHookShouldFail(rs *ReleaseServer, hook *release.Hook, releaseName strin
 }
 
 func execHookShouldFailWithError(rs *ReleaseServer, hook *release.Hook, releaseName string, namespace string, hookType string, expectedError error) error {
-	err := rs.execHook([]*release.Hook{hook}, releaseName, namespace, hookType, 600)
+	err := rs.execHook(context.Background(), []*release.Hook{hook}, releaseName, namespace, hookType, 600)
 	if err != expectedError {
 		return fmt.Errorf("expected hook %s to fail with error %v, got %v", hook.Name, expectedError, err)
 	}
@@ -774,6 +806,70 @@ func newDeletePolicyContext() *deletePolicyContext {
 	}
 }
 
+// terminatingKubeClient keeps deleted hook resources around for terminating
+// seconds, only reporting them gone when the caller waits that long.
+type terminatingKubeClient struct {
+	*mockHooksKubeClient
+	terminating int64
+	timeouts    []int64
+}
+
+func (kc *terminatingKubeClient) DeleteWithTimeout(ns string, r io.Reader, timeout int64, shouldWait bool) error {
+	manifest, err := kc.makeManifest(r)
+	if err != nil {
+		return err
+	}
+	kc.timeouts = append(kc.timeouts, timeout)
+	if !shouldWait {
+		return nil
+	}
+	if timeout < kc.terminating {
+		return fmt.Errorf("timed out waiting for %s to be deleted", manifest.Metadata.Name)
+	}
+	delete(kc.Resources, manifest.Metadata.Name)
+	return nil
+}
+
+func TestHookDeletionWaitsForDeleteTimeout(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		annotation    string
+		deleteTimeout int64
+		terminating   int64
+		expected      int64
+		waited        bool
+	}{
+		{"hook delete timeout", "600", 600, 300, 600, true},
+		{"default delete timeout", "", 0, 30, defaultHookDeleteTimeoutInSeconds, true},
+		{"no wait", "0", 0, 30, 0, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := newDeletePolicyContext()
+			kc := &terminatingKubeClient{mockHooksKubeClient: ctx.KubeClient, terminating: tc.terminating}
+			ctx.ReleaseServer.env.KubeClient = kc
+
+			annotations := map[string]string{"helm.sh/hook-delete-policy": "hook-succeeded"}
+			if tc.annotation != "" {
+				annotations[hooks.HookDeleteTimeoutAnno] = tc.annotation
+			}
+			hook := deletePolicyHookStub(ctx.HookName, annotations, []release.Hook_DeletePolicy{release.Hook_SUCCEEDED})
+			hook.DeleteTimeout = tc.deleteTimeout
+
+			if err := execHookShouldSucceed(ctx.ReleaseServer, hook, ctx.ReleaseName, ctx.Namespace, hooks.PreInstall); err != nil {
+				t.Fatal(err)
+			}
+			if _, hasResource := kc.Resources[hook.Name]; hasResource && tc.waited {
+				t.Errorf("expected resource %s to be gone before the hook returned", hook.Name)
+			} else if !hasResource && !tc.waited {
+				t.Errorf("expected the hook to return without waiting for %s to be deleted", hook.Name)
+			}
+			if len(kc.timeouts) != 1 || kc.timeouts[0] != tc.expected {
+				t.Errorf("expected one deletion waiting %d seconds, got %v", tc.expected, kc.timeouts)
+			}
+		})
+	}
+}
+
 func TestSuccessfulHookWithoutDeletePolicy(t *testing.T) {
 	ctx := newDeletePolicyContext()
 	hook := deletePolicyHookStub(ctx.HookName, nil, nil)
@@ -1060,3 +1156,313 @@ func TestFailedThenSuccessfulHookWithMixedDeletePolicies(t *testing.T) {
 		t.Errorf("expected resource %s to be unexisting after hook succeeded", hook.Name)
 	}
 }
+
+// parallelHooksKubeClient holds the hooks named in barrier until all of them
+// are being watched at the same time, failing those named in fail.
+type parallelHooksKubeClient struct {
+	environment.PrintingKubeClient
+
+	mu       sync.Mutex
+	barrier  map[string]bool
+	fail     map[string]bool
+	inFlight int
+	arrived  int
+	started  []string
+	overlaps []string
+}
+
+func newParallelHooksKubeClient(barrier, fail []string) *parallelHooksKubeClient {
+	kc := &parallelHooksKubeClient{
+		PrintingKubeClient: environment.PrintingKubeClient{Out: ioutil.Discard},
+		barrier:            map[string]bool{},
+		fail:               map[string]bool{},
+	}
+	for _, n := range barrier {
+		kc.barrier[n] = true
+	}
+	for _, n := range fail {
+		kc.fail[n] = true
+	}
+	return kc
+}
+
+func (kc *parallelHooksKubeClient) WatchUntilReady(ns string, r io.Reader, timeout int64, shouldWait bool) error {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	name := string(b)
+
+	kc.mu.Lock()
+	kc.started = append(kc.started, name)
+	if kc.inFlight > 0 && !kc.barrier[name] {
+		kc.overlaps = append(kc.overlaps, name)
+	}
+	kc.inFlight++
+	if kc.barrier[name] {
+		kc.arrived++
+		deadline := time.Now().Add(5 * time.Second)
+		for kc.arrived < len(kc.barrier) && time.Now().Before(deadline) {
+			kc.mu.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			kc.mu.Lock()
+		}
+		if kc.arrived < len(kc.barrier) {
+			kc.inFlight--
+			kc.mu.Unlock()
+			return fmt.Errorf("hook %s was not run in parallel with the rest of its weight", name)
+		}
+	}
+	kc.inFlight--
+	kc.mu.Unlock()
+
+	if kc.fail[name] {
+		return fmt.Errorf("hook %s failed", name)
+	}
+	return nil
+}
+
+func parallelHookStubs(weights map[string]int32) []*release.Hook {
+	var hs []*release.Hook
+	for name, weight := range weights {
+		hs = append(hs, &release.Hook{
+			Name:     name,
+			Kind:     "Job",
+			Path:     name,
+			Manifest: name,
+			Weight:   weight,
+			Events:   []release.Hook_Event{release.Hook_PRE_INSTALL},
+		})
+	}
+	return hs
+}
+
+func TestExecHookParallel(t *testing.T) {
+	rs := rsFixture()
+	rs.ParallelHooks = true
+	kc := newParallelHooksKubeClient([]string{"first-a", "first-b", "first-c"}, nil)
+	rs.env.KubeClient = kc
+
+	hs := parallelHookStubs(map[string]int32{"first-a": 0, "first-b": 0, "first-c": 0, "second": 1})
+	if err := rs.execHook(context.Background(), hs, "flying-carp", "river", hooks.PreInstall, 600); err != nil {
+		t.Fatalf("expected hooks to succeed: %s", err)
+	}
+
+	if len(kc.started) != 4 || kc.started[3] != "second" {
+		t.Errorf("expected the weight 1 hook to run last, got %v", kc.started)
+	}
+	if len(kc.overlaps) != 0 {
+		t.Errorf("expected the weight 1 hook to wait for the weight 0 hooks, got overlaps %v", kc.overlaps)
+	}
+	for _, h := range hs {
+		if h.LastRun == nil {
+			t.Errorf("expected hook %s to record its last run", h.Name)
+		}
+	}
+}
+
+func TestExecHookParallelFailureAbortsLaterWeights(t *testing.T) {
+	rs := rsFixture()
+	rs.ParallelHooks = true
+	kc := newParallelHooksKubeClient([]string{"first-a", "first-b"}, []string{"first-b"})
+	rs.env.KubeClient = kc
+
+	hs := parallelHookStubs(map[string]int32{"first-a": 0, "first-b": 0, "second": 1})
+	err := rs.execHook(context.Background(), hs, "flying-carp", "river", hooks.PreInstall, 600)
+	if err == nil || err.Error() != "hook first-b failed" {
+		t.Fatalf("expected hook first-b to fail, got %v", err)
+	}
+	for _, name := range kc.started {
+		if name == "second" {
+			t.Errorf("expected the weight 1 hook not to run after its predecessors failed")
+		}
+	}
+}
+
+func TestExecHookSerialByDefault(t *testing.T) {
+	rs := rsFixture()
+	kc := newParallelHooksKubeClient(nil, nil)
+	rs.env.KubeClient = kc
+
+	hs := parallelHookStubs(map[string]int32{"b": 0, "a": 0, "c": 1})
+	if err := rs.execHook(context.Background(), hs, "flying-carp", "river", hooks.PreInstall, 600); err != nil {
+		t.Fatalf("expected hooks to succeed: %s", err)
+	}
+	if got := strings.Join(kc.started, ","); got != "a,b,c" {
+		t.Errorf("expected hooks to run one at a time in order a,b,c, got %s", got)
+	}
+	if len(kc.overlaps) != 0 {
+		t.Errorf("expected no hooks to overlap, got %v", kc.overlaps)
+	}
+}
+
+// blockingWatchKubeClient blocks in WatchUntilReadyContext until its context
+// is done.
+type blockingWatchKubeClient struct {
+	environment.PrintingKubeClient
+	watching chan struct{}
+}
+
+func (kc *blockingWatchKubeClient) WatchUntilReadyContext(c context.Context, ns string, r io.Reader, timeout int64, shouldWait bool) error {
+	close(kc.watching)
+	<-c.Done()
+	return c.Err()
+}
+
+func TestExecHookCancelledDuringWait(t *testing.T) {
+	rs := rsFixture()
+	kc := &blockingWatchKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: ioutil.Discard}, watching: make(chan struct{})}
+	rs.env.KubeClient = kc
+
+	c, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-kc.watching
+		cancel()
+	}()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- rs.execHook(c, parallelHookStubs(map[string]int32{"slow": 0, "later": 1}), "flying-carp", "river", hooks.PreInstall, 600)
+	}()
+	select {
+	case err := <-done:
+		if status.Code(err) != codes.Canceled {
+			t.Errorf("Expected the hook wait to be cancelled, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Hook wait was not stopped by the cancelled context")
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	if got := requestTimeout(context.Background(), 300); got != 300 {
+		t.Errorf("Expected the timeout of a request without deadline to be kept, got %d", got)
+	}
+
+	c, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	for _, tt := range []struct {
+		timeout, want int64
+	}{
+		{300, 30},
+		{0, 30},
+		{10, 10},
+	} {
+		if got := requestTimeout(c, tt.timeout); got != tt.want {
+			t.Errorf("requestTimeout(30s deadline, %d) = %d, expected %d", tt.timeout, got, tt.want)
+		}
+	}
+
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	if got := requestTimeout(expired, 300); got != 1 {
+		t.Errorf("Expected an expired deadline to leave a timeout of 1 second, got %d", got)
+	}
+}
+
+var manifestWithAllHooks = `kind: ConfigMap
+metadata:
+  name: every-hook
+  annotations:
+    "helm.sh/hook": pre-install,post-install,pre-upgrade,post-upgrade,pre-rollback,post-rollback,pre-delete,post-delete
+data:
+  name: value`
+
+// recordingKubeClient records the names of the resources it applies and
+// deletes, and of the hooks it creates.
+type recordingKubeClient struct {
+	environment.PrintingKubeClient
+	mu                      sync.Mutex
+	applied, deleted, hooks []string
+}
+
+func (kc *recordingKubeClient) record(list *[]string, r io.Reader) {
+	b, _ := ioutil.ReadAll(r)
+	kc.mu.Lock()
+	defer kc.mu.Unlock()
+	docs := relutil.SplitManifests(string(b))
+	for i := 0; i < len(docs); i++ {
+		var head relutil.SimpleHead
+		if err := yaml.Unmarshal([]byte(docs[fmt.Sprintf("manifest-%d", i)]), &head); err == nil && head.Metadata != nil {
+			*list = append(*list, head.Metadata.Name)
+		}
+	}
+}
+
+func (kc *recordingKubeClient) Create(ns string, r io.Reader, timeout int64, shouldWait bool) error {
+	kc.record(&kc.hooks, r)
+	return nil
+}
+
+func (kc *recordingKubeClient) CreateWithResult(ns string, r io.Reader, opts kube.CreateOptions) ([]kube.AppliedResource, error) {
+	kc.record(&kc.applied, r)
+	return nil, nil
+}
+
+func (kc *recordingKubeClient) UpdateWithResult(ns string, currentReader, modifiedReader io.Reader, opts kube.UpdateOptions) ([]kube.AppliedResource, error) {
+	kc.record(&kc.applied, modifiedReader)
+	return nil, nil
+}
+
+func (kc *recordingKubeClient) Delete(ns string, r io.Reader) error {
+	kc.record(&kc.deleted, r)
+	return nil
+}
+
+func TestDisableHooksStillAppliesManifests(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	kc := &recordingKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: ioutil.Discard}}
+	rs.env.KubeClient = kc
+
+	ch := buildChart(func(opts *chartOptions) {
+		opts.Templates = []*chart.Template{
+			{Name: "templates/config", Data: []byte("kind: ConfigMap\nmetadata:\n  name: app-config\ndata:\n  replicas: \"{{ .Values.replicas }}\"\n")},
+			{Name: "templates/hooks", Data: []byte(manifestWithAllHooks)},
+		}
+	})
+	expectApplied := func(op string) {
+		t.Helper()
+		if len(kc.applied) != 1 || kc.applied[0] != "app-config" {
+			t.Errorf("%s: expected app-config to be applied, got %v", op, kc.applied)
+		}
+		kc.applied = nil
+	}
+
+	res, err := rs.InstallRelease(c, &services.InstallReleaseRequest{
+		Name: "hookless", Namespace: "spaced", Chart: ch, DisableHooks: true,
+		Values: &chart.Config{Raw: "replicas: 1"},
+	})
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	expectApplied("install")
+	if len(res.Release.Hooks) != 1 || res.Release.Hooks[0].LastRun != nil {
+		t.Errorf("Expected the hook to be recorded but not run, got %v", res.Release.Hooks)
+	}
+
+	_, err = rs.UpdateRelease(c, &services.UpdateReleaseRequest{
+		Name: "hookless", Chart: ch, DisableHooks: true,
+		Values: &chart.Config{Raw: "replicas: 2"},
+	})
+	if err != nil {
+		t.Fatalf("Failed upgrade: %s", err)
+	}
+	expectApplied("upgrade")
+
+	if _, err := rs.RollbackRelease(c, &services.RollbackReleaseRequest{Name: "hookless", Version: 1, DisableHooks: true}); err != nil {
+		t.Fatalf("Failed rollback: %s", err)
+	}
+	expectApplied("rollback")
+
+	if _, err := rs.UninstallRelease(c, &services.UninstallReleaseRequest{Name: "hookless", DisableHooks: true}); err != nil {
+		t.Fatalf("Failed uninstall: %s", err)
+	}
+	if len(kc.deleted) != 1 || kc.deleted[0] != "app-config" {
+		t.Errorf("uninstall: expected app-config to be deleted, got %v", kc.deleted)
+	}
+
+	if len(kc.hooks) != 0 {
+		t.Errorf("Expected no hooks to run, got %v", kc.hooks)
+	}
+}
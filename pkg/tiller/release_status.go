@@ -61,6 +61,11 @@ func (s *ReleaseServer) GetReleaseStatus(c ctx.Context, req *services.GetRelease
 		Namespace: rel.Namespace,
 		Info:      rel.Info,
 	}
+	annotations, err := s.env.Releases.GetAnnotations(rel.Name, rel.Version)
+	if err != nil {
+		s.Log("warning: failed to get record annotations of %s: %s", rel.Name, err)
+	}
+	statusResp.RecordAnnotations = annotations
 
 	// Ok, we got the status of the release as we had jotted down, now we need to match the
 	// manifest we stashed away with reality from the cluster.
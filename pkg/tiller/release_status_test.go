@@ -19,9 +19,14 @@ package tiller
 import (
 	"testing"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
 	"k8s.io/helm/pkg/helm"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/storage"
+	"k8s.io/helm/pkg/storage/driver"
 )
 
 func TestGetReleaseStatus(t *testing.T) {
@@ -63,3 +68,37 @@ func TestGetReleaseStatusDeleted(t *testing.T) {
 		t.Errorf("Expected %d, got %d", release.Status_DELETED, res.Info.Status.Code)
 	}
 }
+
+func TestGetReleaseStatusRecordAnnotations(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	configMaps := fake.NewSimpleClientset().CoreV1().ConfigMaps("kube-system")
+	cfgmaps := driver.NewConfigMaps(configMaps)
+	cfgmaps.Annotations = map[string]string{"team": "payments", "environment": "prod"}
+	rs.env.Releases = storage.Init(cfgmaps)
+
+	rel := releaseStub()
+	if err := rs.env.Releases.Create(rel); err != nil {
+		t.Fatalf("Could not store mock release: %s", err)
+	}
+	rel.Info.Description = "updated"
+	if err := rs.env.Releases.Update(rel); err != nil {
+		t.Fatalf("Could not update mock release: %s", err)
+	}
+
+	record, err := configMaps.Get(rel.Name+".v1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Could not get release record: %s", err)
+	}
+	if record.Annotations["team"] != "payments" || record.Annotations["environment"] != "prod" {
+		t.Errorf("Expected annotations on the stored record, got %v", record.Annotations)
+	}
+
+	res, err := rs.GetReleaseStatus(c, &services.GetReleaseStatusRequest{Name: rel.Name})
+	if err != nil {
+		t.Fatalf("Error getting release status: %s", err)
+	}
+	if res.RecordAnnotations["team"] != "payments" || res.RecordAnnotations["environment"] != "prod" {
+		t.Errorf("Expected record annotations in status, got %v", res.RecordAnnotations)
+	}
+}
@@ -0,0 +1,185 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+// statusWebhookClient is the HTTP client used to deliver status webhooks.
+var statusWebhookClient = &http.Client{Timeout: 10 * time.Second}
+
+// maxObservedReleases bounds how many releases a StatusWatcher remembers the
+// status of. Beyond it, an arbitrary release is forgotten, and its next
+// watched status is reported as if first seen.
+var maxObservedReleases = 10000
+
+// StatusEvent is the JSON body posted to a status webhook.
+type StatusEvent struct {
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace"`
+	Version     int32  `json:"version"`
+	Status      string `json:"status"`
+	Previous    string `json:"previous,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// StatusWatcher posts a StatusEvent to a webhook when a release enters one
+// of the watched statuses.
+//
+// Only transitions are reported: a release that fails again, without having
+// left the watched status for a settled one in between, is not reported
+// twice. Pending statuses are passed through on the way to a settled one and
+// do not count as leaving.
+type StatusWatcher struct {
+	url      string
+	statuses map[release.Status_Code]bool
+
+	mu   sync.Mutex
+	last map[string]observedStatus
+}
+
+// observedStatus is the latest settled status seen for a release.
+type observedStatus struct {
+	version int32
+	code    release.Status_Code
+}
+
+// NewStatusWatcher creates a StatusWatcher posting to url when a release
+// enters any of statuses.
+func NewStatusWatcher(url string, statuses []release.Status_Code) *StatusWatcher {
+	w := &StatusWatcher{
+		url:      url,
+		statuses: make(map[release.Status_Code]bool, len(statuses)),
+		last:     map[string]observedStatus{},
+	}
+	for _, st := range statuses {
+		w.statuses[st] = true
+	}
+	return w
+}
+
+// ParseStatuses converts release status names, such as FAILED, to codes.
+func ParseStatuses(names []string) ([]release.Status_Code, error) {
+	codes := make([]release.Status_Code, 0, len(names))
+	for _, name := range names {
+		code, ok := release.Status_Code_value[strings.ToUpper(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("unknown release status %q", name)
+		}
+		codes = append(codes, release.Status_Code(code))
+	}
+	return codes, nil
+}
+
+// observe records the status of r and returns the event to send, if r
+// entered a watched status. Records of revisions older than the latest one
+// seen, such as the superseded predecessor of an upgrade, are ignored.
+func (w *StatusWatcher) observe(r *release.Release) *StatusEvent {
+	code := r.Info.Status.Code
+	if isPending(code) {
+		return nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	prev, seen := w.last[r.Name]
+	if seen && r.Version < prev.version {
+		return nil
+	}
+	if !seen && len(w.last) >= maxObservedReleases {
+		for name := range w.last {
+			delete(w.last, name)
+			break
+		}
+	}
+	w.last[r.Name] = observedStatus{version: r.Version, code: code}
+
+	if !w.statuses[code] || (seen && prev.code == code) {
+		return nil
+	}
+	ev := &StatusEvent{
+		Name:        r.Name,
+		Namespace:   r.Namespace,
+		Version:     r.Version,
+		Status:      code.String(),
+		Description: r.Info.Description,
+	}
+	if seen {
+		ev.Previous = prev.code.String()
+	}
+	return ev
+}
+
+// forget drops what was observed of the named release, once its records are
+// gone.
+func (w *StatusWatcher) forget(name string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.last, name)
+}
+
+// send posts ev to the webhook.
+func (w *StatusWatcher) send(ev *StatusEvent) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	resp, err := statusWebhookClient.Post(w.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("status webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+func isPending(code release.Status_Code) bool {
+	switch code {
+	case release.Status_PENDING_INSTALL, release.Status_PENDING_UPGRADE,
+		release.Status_PENDING_ROLLBACK, release.Status_PENDING_APPROVAL:
+		return true
+	}
+	return false
+}
+
+// notifyStatus delivers a status webhook in the background if r entered a
+// watched status, so a slow receiver does not hold up the release.
+func (s *ReleaseServer) notifyStatus(r *release.Release) {
+	if s.StatusWatcher == nil || r.Info == nil || r.Info.Status == nil {
+		return
+	}
+	ev := s.StatusWatcher.observe(r)
+	if ev == nil {
+		return
+	}
+	go func() {
+		if err := s.StatusWatcher.send(ev); err != nil {
+			s.Log("warning: failed to send status webhook for %s: %s", r.Name, err)
+		}
+	}()
+}
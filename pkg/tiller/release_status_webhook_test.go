@@ -0,0 +1,164 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+func TestStatusWebhookFiresOnceOnFailure(t *testing.T) {
+	events := make(chan StatusEvent, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ev StatusEvent
+		if err := json.NewDecoder(r.Body).Decode(&ev); err != nil {
+			t.Errorf("Failed to decode status event: %s", err)
+		}
+		events <- ev
+	}))
+	defer srv.Close()
+
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.Log = t.Logf
+	rs.StatusWatcher = NewStatusWatcher(srv.URL, []release.Status_Code{release.Status_FAILED})
+
+	if _, err := rs.InstallRelease(c, installRequest(withName("angry-panda"))); err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+
+	rs.env.KubeClient = newUpdateFailingKubeClient()
+	upgrade := &services.UpdateReleaseRequest{
+		Name:         "angry-panda",
+		DisableHooks: true,
+		Chart: &chart.Chart{
+			Metadata:  &chart.Metadata{Name: "hello"},
+			Templates: []*chart.Template{{Name: "templates/something", Data: []byte("hello: world")}},
+		},
+	}
+	// Failing repeatedly is a single transition to FAILED.
+	for i := 0; i < 2; i++ {
+		if _, err := rs.UpdateRelease(c, upgrade); err == nil {
+			t.Fatal("Expected failed update")
+		}
+	}
+
+	select {
+	case ev := <-events:
+		if ev.Name != "angry-panda" || ev.Version != 2 || ev.Status != "FAILED" || ev.Previous != "DEPLOYED" {
+			t.Errorf("Unexpected status event %+v", ev)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected a status webhook for the failed upgrade")
+	}
+	select {
+	case ev := <-events:
+		t.Errorf("Expected exactly one status webhook, also got %+v", ev)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestStatusWebhookFiresAgainAfterRecovery(t *testing.T) {
+	events := make(chan StatusEvent, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ev StatusEvent
+		if err := json.NewDecoder(r.Body).Decode(&ev); err != nil {
+			t.Errorf("Failed to decode status event: %s", err)
+		}
+		events <- ev
+	}))
+	defer srv.Close()
+
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.Log = t.Logf
+	rs.StatusWatcher = NewStatusWatcher(srv.URL, []release.Status_Code{release.Status_FAILED})
+
+	if _, err := rs.InstallRelease(c, installRequest(withName("moody-panda"))); err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	working := rs.env.KubeClient
+	upgrade := &services.UpdateReleaseRequest{
+		Name:         "moody-panda",
+		DisableHooks: true,
+		Chart: &chart.Chart{
+			Metadata:  &chart.Metadata{Name: "hello"},
+			Templates: []*chart.Template{{Name: "templates/something", Data: []byte("hello: world")}},
+		},
+	}
+
+	// FAILED, then DEPLOYED by a successful upgrade, then FAILED again.
+	for i, fail := range []bool{true, false, true} {
+		rs.env.KubeClient = working
+		if fail {
+			rs.env.KubeClient = newUpdateFailingKubeClient()
+		}
+		if _, err := rs.UpdateRelease(c, upgrade); (err != nil) != fail {
+			t.Fatalf("Upgrade %d: expected failure %v, got %v", i, fail, err)
+		}
+	}
+
+	// webhooks are delivered concurrently, so in any order
+	got := map[int32]StatusEvent{}
+	for len(got) < 2 {
+		select {
+		case ev := <-events:
+			got[ev.Version] = ev
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected two status webhooks, got %v", got)
+		}
+	}
+	for _, version := range []int32{2, 4} {
+		if ev := got[version]; ev.Status != "FAILED" || ev.Previous != "DEPLOYED" {
+			t.Errorf("Expected version %d to fail after DEPLOYED, got %+v", version, ev)
+		}
+	}
+}
+
+func TestStatusWatcherForgets(t *testing.T) {
+	defer func(max int) { maxObservedReleases = max }(maxObservedReleases)
+	maxObservedReleases = 2
+
+	w := NewStatusWatcher("", []release.Status_Code{release.Status_FAILED})
+	for _, name := range []string{"one", "two", "three"} {
+		w.observe(releaseWithStatus(name, release.Status_DEPLOYED))
+	}
+	if len(w.last) != 2 {
+		t.Errorf("Expected at most 2 observed releases, got %d", len(w.last))
+	}
+
+	w.forget("three")
+	if _, ok := w.last["three"]; ok {
+		t.Error("Expected a forgotten release to be dropped")
+	}
+}
+
+func releaseWithStatus(name string, code release.Status_Code) *release.Release {
+	return &release.Release{
+		Name:    name,
+		Version: 1,
+		Info:    &release.Info{Status: &release.Status{Code: code}},
+	}
+}
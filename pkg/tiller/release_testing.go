@@ -17,6 +17,11 @@ limitations under the License.
 package tiller
 
 import (
+	"github.com/ghodss/yaml"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"k8s.io/helm/pkg/hooks"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
 	reltesting "k8s.io/helm/pkg/releasetesting"
@@ -57,6 +62,21 @@ func (s *ReleaseServer) RunReleaseTest(req *services.TestReleaseRequest, stream
 		s.Log("error creating test suite for %s: %s", rel.Name, err)
 		return err
 	}
+	for i, m := range tSuite.TestManifests {
+		if tSuite.TestManifests[i], err = labelTestManifest(m, rel.Name); err != nil {
+			s.Log("error labeling test manifest for %s: %s", rel.Name, err)
+			return err
+		}
+	}
+
+	// Test pods left over from an earlier run without cleanup would make
+	// creating this run's pods fail.
+	if req.Cleanup {
+		if err := s.deleteTestResources(rel.Name, rel.Namespace); err != nil {
+			s.Log("test: Failed to clean up earlier test resources for %s: %s", rel.Name, err)
+			return err
+		}
+	}
 
 	if err := tSuite.Run(testEnv); err != nil {
 		s.Log("error running test suite for %s: %s", rel.Name, err)
@@ -74,7 +94,9 @@ func (s *ReleaseServer) RunReleaseTest(req *services.TestReleaseRequest, stream
 	}
 
 	if req.Cleanup {
-		testEnv.DeleteTestPods(tSuite.TestManifests)
+		if err := s.deleteTestResources(rel.Name, rel.Namespace); err != nil {
+			s.Log("test: Failed to clean up test resources for %s: %s", rel.Name, err)
+		}
 	}
 
 	if err := s.env.Releases.Update(rel); err != nil {
@@ -83,3 +105,48 @@ func (s *ReleaseServer) RunReleaseTest(req *services.TestReleaseRequest, stream
 
 	return nil
 }
+
+// labelTestManifest stamps a test manifest with the labels used to find the
+// test resources of a release after the run.
+func labelTestManifest(manifest, releaseName string) (string, error) {
+	var obj map[string]interface{}
+	if err := yaml.Unmarshal([]byte(manifest), &obj); err != nil {
+		return "", err
+	}
+	md, ok := obj["metadata"].(map[string]interface{})
+	if !ok {
+		md = map[string]interface{}{}
+		obj["metadata"] = md
+	}
+	lbs, ok := md["labels"].(map[string]interface{})
+	if !ok {
+		lbs = map[string]interface{}{}
+		md["labels"] = lbs
+	}
+	lbs[hooks.TestLabel] = "true"
+	lbs[hooks.TestReleaseLabel] = releaseName
+
+	b, err := yaml.Marshal(obj)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// deleteTestResources deletes every test pod of the named release, including
+// ones left behind by earlier runs that were not cleaned up.
+func (s *ReleaseServer) deleteTestResources(name, namespace string) error {
+	selector := labels.Set{hooks.TestLabel: "true", hooks.TestReleaseLabel: name}.AsSelector()
+	pods := s.clientset.CoreV1().Pods(namespace)
+	list, err := pods.List(metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return err
+	}
+	for _, pod := range list.Items {
+		s.Log("deleting test pod %s for release %s", pod.Name, name)
+		if err := pods.Delete(pod.Name, &metav1.DeleteOptions{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
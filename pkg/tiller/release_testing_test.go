@@ -17,10 +17,19 @@ limitations under the License.
 package tiller
 
 import (
+	"io"
+	"io/ioutil"
 	"testing"
 
+	"github.com/ghodss/yaml"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"k8s.io/helm/pkg/hooks"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller/environment"
 )
 
 func TestRunReleaseTest(t *testing.T) {
@@ -34,3 +43,118 @@ func TestRunReleaseTest(t *testing.T) {
 		t.Fatalf("failed to run release tests on %s: %s", rel.Name, err)
 	}
 }
+
+// podCreatingKubeClient records created test pods in a clientset so that the
+// labels stamped on them can be inspected.
+type podCreatingKubeClient struct {
+	environment.PrintingKubeClient
+	clientset kubernetes.Interface
+}
+
+func (p *podCreatingKubeClient) Create(ns string, r io.Reader, timeout int64, shouldWait bool) error {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	var pod v1.Pod
+	if err := yaml.Unmarshal(b, &pod); err != nil {
+		return err
+	}
+	_, err = p.clientset.CoreV1().Pods(ns).Create(&pod)
+	return err
+}
+
+func TestRunReleaseTest_LabelsAndCleanup(t *testing.T) {
+	rs := rsFixture()
+	rs.env.KubeClient = &podCreatingKubeClient{
+		PrintingKubeClient: environment.PrintingKubeClient{Out: ioutil.Discard},
+		clientset:          rs.clientset,
+	}
+	rel := namedReleaseStub("nemo", release.Status_DEPLOYED)
+	rel.Namespace = "spaced"
+	rs.env.Releases.Create(rel)
+
+	req := &services.TestReleaseRequest{Name: "nemo", Timeout: 2}
+	if err := rs.RunReleaseTest(req, mockRunReleaseTestServer{}); err != nil {
+		t.Fatalf("failed to run release tests on %s: %s", rel.Name, err)
+	}
+
+	pods, err := rs.clientset.CoreV1().Pods("spaced").List(metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pods.Items) != 1 {
+		t.Fatalf("expected 1 test pod, got %d", len(pods.Items))
+	}
+	lbs := pods.Items[0].Labels
+	if lbs[hooks.TestLabel] != "true" {
+		t.Errorf("expected label %s=true, got %q", hooks.TestLabel, lbs[hooks.TestLabel])
+	}
+	if lbs[hooks.TestReleaseLabel] != "nemo" {
+		t.Errorf("expected label %s=nemo, got %q", hooks.TestReleaseLabel, lbs[hooks.TestReleaseLabel])
+	}
+
+	// A pod of another release must survive the cleanup.
+	other := &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:   "dory-test",
+		Labels: map[string]string{hooks.TestLabel: "true", hooks.TestReleaseLabel: "dory"},
+	}}
+	if _, err := rs.clientset.CoreV1().Pods("spaced").Create(other); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rs.deleteTestResources("nemo", "spaced"); err != nil {
+		t.Fatalf("failed to clean up test resources: %s", err)
+	}
+	pods, err = rs.clientset.CoreV1().Pods("spaced").List(metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pods.Items) != 1 || pods.Items[0].Name != "dory-test" {
+		t.Errorf("expected only the other release's test pod to remain, got %v", pods.Items)
+	}
+}
+
+func TestRunReleaseTest_CleanupBeforeRun(t *testing.T) {
+	rs := rsFixture()
+	rs.env.KubeClient = &podCreatingKubeClient{
+		PrintingKubeClient: environment.PrintingKubeClient{Out: ioutil.Discard},
+		clientset:          rs.clientset,
+	}
+	rel := namedReleaseStub("nemo", release.Status_DEPLOYED)
+	rel.Namespace = "spaced"
+	rs.env.Releases.Create(rel)
+
+	// A test pod left behind by an earlier run without cleanup.
+	stale := &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:   "finding-nemo,",
+		Labels: map[string]string{hooks.TestLabel: "true", hooks.TestReleaseLabel: "nemo"},
+	}}
+	if _, err := rs.clientset.CoreV1().Pods("spaced").Create(stale); err != nil {
+		t.Fatal(err)
+	}
+
+	req := &services.TestReleaseRequest{Name: "nemo", Timeout: 2, Cleanup: true}
+	if err := rs.RunReleaseTest(req, mockRunReleaseTestServer{}); err != nil {
+		t.Fatalf("failed to run release tests on %s: %s", rel.Name, err)
+	}
+
+	stored, err := rs.env.Releases.Get("nemo", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := stored.Info.Status.LastTestSuiteRun.Results
+	if len(results) != 1 {
+		t.Fatalf("expected 1 test result, got %d", len(results))
+	}
+	if results[0].Status == release.TestRun_FAILURE {
+		t.Errorf("expected the test pod to be created after removing the stale one, got %q", results[0].Info)
+	}
+	pods, err := rs.clientset.CoreV1().Pods("spaced").List(metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pods.Items) != 0 {
+		t.Errorf("expected test pods to be cleaned up after the run, got %v", pods.Items)
+	}
+}
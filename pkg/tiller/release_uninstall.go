@@ -19,6 +19,7 @@ package tiller
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	ctx "golang.org/x/net/context"
 
@@ -26,15 +27,25 @@ import (
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
 	relutil "k8s.io/helm/pkg/releaseutil"
+	"k8s.io/helm/pkg/tiller/metrics"
 	"k8s.io/helm/pkg/timeconv"
 )
 
 // UninstallRelease deletes all of the resources associated with this release, and marks the release DELETED.
-func (s *ReleaseServer) UninstallRelease(c ctx.Context, req *services.UninstallReleaseRequest) (*services.UninstallReleaseResponse, error) {
+func (s *ReleaseServer) UninstallRelease(c ctx.Context, req *services.UninstallReleaseRequest) (res *services.UninstallReleaseResponse, err error) {
+	defer func(start time.Time) { metrics.ObserveOperation(metrics.Uninstall, start, err) }(time.Now())
+	defer func() { s.recordAudit(c, auditedRelease(req.Name, "", res.GetRelease()), "uninstall", err) }()
+
 	if err := validateReleaseName(req.Name); err != nil {
 		s.Log("uninstallRelease: Release name is invalid: %s", req.Name)
 		return nil, err
 	}
+	unlock, err := s.locks.lock(c, req.Name, s.LockTimeout)
+	if err != nil {
+		s.Log("uninstallRelease: %s", err)
+		return nil, err
+	}
+	defer unlock()
 
 	rels, err := s.env.Releases.History(req.Name)
 	if err != nil {
@@ -52,7 +63,7 @@ func (s *ReleaseServer) UninstallRelease(c ctx.Context, req *services.UninstallR
 	// already marked deleted?
 	if rel.Info.Status.Code == release.Status_DELETED {
 		if req.Purge {
-			if err := s.purgeReleases(rels...); err != nil {
+			if err := s.purgeRelease(req.Name); err != nil {
 				s.Log("uninstall: Failed to purge the release: %s", err)
 				return nil, err
 			}
@@ -65,10 +76,10 @@ func (s *ReleaseServer) UninstallRelease(c ctx.Context, req *services.UninstallR
 	rel.Info.Status.Code = release.Status_DELETING
 	rel.Info.Deleted = timeconv.Now()
 	rel.Info.Description = "Deletion in progress (or silently failed)"
-	res := &services.UninstallReleaseResponse{Release: rel}
+	res = &services.UninstallReleaseResponse{Release: rel}
 
 	if !req.DisableHooks {
-		if err := s.execHook(rel.Hooks, rel.Name, rel.Namespace, hooks.PreDelete, req.Timeout); err != nil {
+		if err := s.execHook(c, rel.Hooks, rel.Name, rel.Namespace, hooks.PreDelete, req.Timeout); err != nil {
 			return res, err
 		}
 	} else {
@@ -77,12 +88,13 @@ func (s *ReleaseServer) UninstallRelease(c ctx.Context, req *services.UninstallR
 
 	// From here on out, the release is currently considered to be in Status_DELETING
 	// state.
-	if err := s.env.Releases.Update(rel); err != nil {
+	if err := s.updateRelease(rel); err != nil {
 		s.Log("uninstall: Failed to store updated release: %s", err)
 	}
 
-	kept, errs := s.ReleaseModule.Delete(rel, req, s.env)
+	kept, keptResources, errs := s.ReleaseModule.Delete(rel, req, s.env)
 	res.Info = kept
+	res.KeptResources = keptResources
 
 	es := make([]string, 0, len(errs))
 	for _, e := range errs {
@@ -91,7 +103,7 @@ func (s *ReleaseServer) UninstallRelease(c ctx.Context, req *services.UninstallR
 	}
 
 	if !req.DisableHooks {
-		if err := s.execHook(rel.Hooks, rel.Name, rel.Namespace, hooks.PostDelete, req.Timeout); err != nil {
+		if err := s.execHook(c, rel.Hooks, rel.Name, rel.Namespace, hooks.PostDelete, req.Timeout); err != nil {
 			es = append(es, err.Error())
 		}
 	}
@@ -105,28 +117,28 @@ func (s *ReleaseServer) UninstallRelease(c ctx.Context, req *services.UninstallR
 
 	if req.Purge {
 		s.Log("purge requested for %s", req.Name)
-		err := s.purgeReleases(rels...)
+		err := s.purgeRelease(req.Name)
 		if err != nil {
 			s.Log("uninstall: Failed to purge the release: %s", err)
 		}
 		return res, err
 	}
 
-	if err := s.env.Releases.Update(rel); err != nil {
-		s.Log("uninstall: Failed to store updated release: %s", err)
+	var deleteErr error
+	if len(es) > 0 {
+		deleteErr = fmt.Errorf("deletion completed with %d error(s): %s", len(es), strings.Join(es, "; "))
 	}
 
-	if len(es) > 0 {
-		return res, fmt.Errorf("deletion completed with %d error(s): %s", len(es), strings.Join(es, "; "))
+	if err := s.updateRelease(rel); err != nil {
+		s.Log("uninstall: Failed to store updated release: %s", err)
 	}
-	return res, nil
+	return res, deleteErr
 }
 
-func (s *ReleaseServer) purgeReleases(rels ...*release.Release) error {
-	for _, rel := range rels {
-		if _, err := s.env.Releases.Delete(rel.Name, rel.Version); err != nil {
-			return err
-		}
+func (s *ReleaseServer) purgeRelease(name string) error {
+	_, err := s.env.Releases.DeleteAll(name)
+	if err == nil && s.StatusWatcher != nil {
+		s.StatusWatcher.forget(name)
 	}
-	return nil
+	return err
 }
@@ -17,12 +17,20 @@ limitations under the License.
 package tiller
 
 import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/helm"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller/environment"
 )
 
 func TestUninstallRelease(t *testing.T) {
@@ -157,6 +165,11 @@ func TestUninstallReleaseWithKeepPolicy(t *testing.T) {
 			t.Errorf("unexpected output: %s", res.Info)
 		}
 	}
+
+	want := []string{"ConfigMap/test-cm-keep-a", "ConfigMap/test-cm-keep-b"}
+	if !reflect.DeepEqual(res.KeptResources, want) {
+		t.Errorf("Expected kept resources %v, got %v", want, res.KeptResources)
+	}
 }
 
 func TestUninstallReleaseNoHooks(t *testing.T) {
@@ -228,3 +241,158 @@ data:
 		t.Errorf("Expected delete error message to contain object name, got:" + err.Error())
 	}
 }
+
+// concurrentDeleteKubeClient blocks each deletion until the test releases it,
+// reporting the kind being deleted on entered and recording how many
+// deletions are in flight at once.
+type concurrentDeleteKubeClient struct {
+	environment.PrintingKubeClient
+	entered   chan string
+	release   chan struct{}
+	mu        sync.Mutex
+	active    int
+	maxActive int
+}
+
+func (c *concurrentDeleteKubeClient) Delete(ns string, r io.Reader) error {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	kind := strings.TrimPrefix(strings.SplitN(string(b), "\n", 2)[0], "kind: ")
+
+	c.mu.Lock()
+	c.active++
+	if c.active > c.maxActive {
+		c.maxActive = c.active
+	}
+	c.mu.Unlock()
+
+	c.entered <- kind
+	<-c.release
+
+	c.mu.Lock()
+	c.active--
+	c.mu.Unlock()
+	return nil
+}
+
+func TestDeleteReleaseParallel(t *testing.T) {
+	var manifests []string
+	for i := 0; i < 4; i++ {
+		manifests = append(manifests, fmt.Sprintf("kind: ConfigMap\nmetadata:\n  name: cm-%d\n", i))
+	}
+	for i := 0; i < 4; i++ {
+		manifests = append(manifests, fmt.Sprintf("kind: Service\nmetadata:\n  name: svc-%d\n", i))
+	}
+	rel := &release.Release{
+		Name:      "parallel",
+		Namespace: "spaced",
+		Manifest:  "---\n" + strings.Join(manifests, "---\n"),
+	}
+
+	for _, tt := range []struct {
+		parallelism int
+		expectMax   int
+	}{
+		{parallelism: 0, expectMax: 1},
+		{parallelism: 1, expectMax: 1},
+		{parallelism: 2, expectMax: 2},
+		{parallelism: 10, expectMax: 4},
+	} {
+		kc := &concurrentDeleteKubeClient{
+			PrintingKubeClient: environment.PrintingKubeClient{Out: ioutil.Discard},
+			entered:            make(chan string),
+			release:            make(chan struct{}),
+		}
+		done := make(chan []error, 1)
+		go func(parallelism int) {
+			_, _, errs := DeleteReleaseParallel(rel, chartutil.DefaultVersionSet, kc, parallelism)
+			done <- errs
+		}(tt.parallelism)
+
+		// Services come before ConfigMaps in UninstallOrder and must all be
+		// gone before the first ConfigMap is deleted. Within a kind, wait
+		// for expectMax deletions to be in flight before releasing one.
+		for _, kind := range []string{"Service", "ConfigMap"} {
+			remaining, inflight := 4, 0
+			for remaining > 0 || inflight > 0 {
+				if remaining > 0 && inflight < tt.expectMax {
+					select {
+					case got := <-kc.entered:
+						if got != kind {
+							t.Fatalf("parallelism %d: expected a %s deletion, got %s", tt.parallelism, kind, got)
+						}
+					case <-time.After(10 * time.Second):
+						t.Fatalf("parallelism %d: expected %d concurrent %s deletions, got %d", tt.parallelism, inflight+1, kind, inflight)
+					}
+					remaining--
+					inflight++
+					continue
+				}
+				kc.release <- struct{}{}
+				inflight--
+			}
+		}
+
+		if errs := <-done; len(errs) != 0 {
+			t.Fatalf("parallelism %d: unexpected errors: %v", tt.parallelism, errs)
+		}
+		if kc.maxActive != tt.expectMax {
+			t.Errorf("parallelism %d: expected at most %d concurrent deletions, got %d", tt.parallelism, tt.expectMax, kc.maxActive)
+		}
+	}
+}
+
+// stuckDeletionKubeClient simulates a resource held in Terminating by a
+// finalizer: it is only gone once its finalizers have been removed.
+type stuckDeletionKubeClient struct {
+	environment.PrintingKubeClient
+	waited           string
+	removeFinalizers bool
+}
+
+func (c *stuckDeletionKubeClient) WaitForDeletion(ns string, r io.Reader, timeout time.Duration, removeFinalizers bool) ([]string, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	c.waited = string(b)
+	c.removeFinalizers = removeFinalizers
+	if removeFinalizers {
+		return nil, nil
+	}
+	return []string{"ConfigMap/configmap-stuck"}, nil
+}
+
+func TestUninstallReleaseStuckTerminating(t *testing.T) {
+	for _, force := range []bool{false, true} {
+		rs := rsFixture()
+		rel := releaseStub()
+		rel.Manifest = "kind: ConfigMap\nmetadata:\n  name: configmap-stuck\n  finalizers:\n  - example.com/stuck\n"
+		rs.env.Releases.Create(rel)
+		kc := &stuckDeletionKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: ioutil.Discard}}
+		rs.env.KubeClient = kc
+		m := rs.ReleaseModule.(*LocalReleaseModule)
+		m.DeletionTimeout = time.Second
+		m.ForceFinalizerRemoval = force
+
+		res, err := rs.UninstallRelease(helm.NewContext(), &services.UninstallReleaseRequest{Name: rel.Name})
+		if !strings.Contains(kc.waited, "configmap-stuck") {
+			t.Errorf("force=%t: expected to wait for deletion of configmap-stuck, waited for %q", force, kc.waited)
+		}
+		if kc.removeFinalizers != force {
+			t.Errorf("force=%t: expected finalizer removal to be %t", force, force)
+		}
+		if force {
+			if err != nil {
+				t.Errorf("force=%t: unexpected error: %s", force, err)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), "ConfigMap/configmap-stuck") {
+			t.Errorf("force=%t: expected error naming the stuck resource, got %v", force, err)
+		}
+		if res.Release.Info.Status.Code != release.Status_DELETED {
+			t.Errorf("force=%t: expected status DELETED, got %s", force, res.Release.Info.Status.Code)
+		}
+	}
+}
@@ -17,8 +17,12 @@ limitations under the License.
 package tiller
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"strings"
+	"time"
 
 	ctx "golang.org/x/net/context"
 
@@ -26,15 +30,37 @@ import (
 	"k8s.io/helm/pkg/hooks"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller/metrics"
 	"k8s.io/helm/pkg/timeconv"
 )
 
 // UpdateRelease takes an existing release and new information, and upgrades the release.
-func (s *ReleaseServer) UpdateRelease(c ctx.Context, req *services.UpdateReleaseRequest) (*services.UpdateReleaseResponse, error) {
+func (s *ReleaseServer) UpdateRelease(c ctx.Context, req *services.UpdateReleaseRequest) (res *services.UpdateReleaseResponse, err error) {
+	defer func(start time.Time) { metrics.ObserveOperation(metrics.Upgrade, start, err) }(time.Now())
+	var updatedRelease *release.Release
+	defer func() {
+		if !req.DryRun {
+			s.recordAudit(c, auditedRelease(req.Name, "", res.GetRelease(), updatedRelease), "upgrade", err)
+		}
+	}()
+
 	if err := validateReleaseName(req.Name); err != nil {
 		s.Log("updateRelease: Release name is invalid: %s", req.Name)
 		return nil, err
 	}
+	unlock, err := s.locks.lock(c, req.Name, s.LockTimeout)
+	if err != nil {
+		s.Log("updateRelease: %s", err)
+		return nil, err
+	}
+	defer unlock()
+
+	if err := s.checkValuesSize(req.Values); err != nil {
+		s.Log("rejected update of %s: %s", req.Name, err)
+		return nil, err
+	}
+	// Kubernetes operations must not outlive the client's deadline.
+	req.Timeout = requestTimeout(c, req.Timeout)
 	s.Log("preparing update for %s", req.Name)
 	currentRelease, updatedRelease, err := s.prepareUpdate(req)
 	if err != nil {
@@ -42,29 +68,44 @@ func (s *ReleaseServer) UpdateRelease(c ctx.Context, req *services.UpdateRelease
 		if req.Force {
 			// Use the --force, Luke.
 			s.Log("performing force update for %s", req.Name)
-			return s.performUpdateForce(req)
+			return s.performUpdateForce(c, req)
 		}
 		return nil, err
 	}
 
+	if s.SkipUnchangedUpgrades && !req.Force && !req.Recreate && releaseDigest(currentRelease) == releaseDigest(updatedRelease) {
+		s.Log("skipping upgrade of %s: nothing changed since version %d", req.Name, currentRelease.Version)
+		return &services.UpdateReleaseResponse{Release: currentRelease}, nil
+	}
+
+	if s.VerifyImages && !req.DryRun {
+		if err := s.verifyReleaseImages(updatedRelease); err != nil {
+			s.Log("failed update image verification: %s", err)
+			return nil, err
+		}
+	}
+
 	if !req.DryRun {
 		s.Log("creating updated release for %s", req.Name)
-		if err := s.env.Releases.Create(updatedRelease); err != nil {
+		if err := s.createRelease(updatedRelease); err != nil {
 			return nil, err
 		}
 	}
 
 	s.Log("performing update for %s", req.Name)
-	res, err := s.performUpdate(currentRelease, updatedRelease, req)
+	res, err = s.performUpdate(c, currentRelease, updatedRelease, req)
 	if err != nil {
 		return res, err
 	}
 
 	if !req.DryRun {
 		s.Log("updating status for updated release for %s", req.Name)
-		if err := s.env.Releases.Update(updatedRelease); err != nil {
+		if err := s.updateRelease(updatedRelease); err != nil {
 			return res, err
 		}
+		if s.AutoRollbackWindow > 0 && updatedRelease.Info.Status.Code == release.Status_DEPLOYED {
+			go s.watchReleaseHealth(updatedRelease, currentRelease.Version, s.AutoRollbackWindow)
+		}
 	}
 
 	return res, nil
@@ -82,6 +123,10 @@ func (s *ReleaseServer) prepareUpdate(req *services.UpdateReleaseRequest) (*rele
 		return nil, nil, err
 	}
 
+	if err := s.applyDefaultValuesFile(req.Chart); err != nil {
+		return nil, nil, err
+	}
+
 	// determine if values will be reused
 	if err := s.reuseValues(req, currentRelease); err != nil {
 		return nil, nil, err
@@ -140,12 +185,12 @@ func (s *ReleaseServer) prepareUpdate(req *services.UpdateReleaseRequest) (*rele
 	if len(notesTxt) > 0 {
 		updatedRelease.Info.Status.Notes = notesTxt
 	}
-	err = validateManifest(s.env.KubeClient, currentRelease.Namespace, manifestDoc.Bytes())
+	err = s.validateRendered(currentRelease.Namespace, manifestDoc.Bytes())
 	return currentRelease, updatedRelease, err
 }
 
 // performUpdateForce performs the same action as a `helm delete && helm install --replace`.
-func (s *ReleaseServer) performUpdateForce(req *services.UpdateReleaseRequest) (*services.UpdateReleaseResponse, error) {
+func (s *ReleaseServer) performUpdateForce(c ctx.Context, req *services.UpdateReleaseRequest) (*services.UpdateReleaseResponse, error) {
 	// find the last release with the given name
 	oldRelease, err := s.env.Releases.Last(req.Name)
 	if err != nil {
@@ -194,7 +239,9 @@ func (s *ReleaseServer) performUpdateForce(req *services.UpdateReleaseRequest) (
 
 	// pre-delete hooks
 	if !req.DisableHooks {
-		if err := s.execHook(oldRelease.Hooks, oldRelease.Name, oldRelease.Namespace, hooks.PreDelete, req.Timeout); err != nil {
+		results, err := s.execHookWithResults(c, oldRelease.Hooks, oldRelease.Name, oldRelease.Namespace, hooks.PreDelete, req.Timeout)
+		res.HookResults = append(res.HookResults, results...)
+		if err != nil {
 			return res, err
 		}
 	} else {
@@ -202,7 +249,7 @@ func (s *ReleaseServer) performUpdateForce(req *services.UpdateReleaseRequest) (
 	}
 
 	// delete manifests from the old release
-	_, errs := s.ReleaseModule.Delete(oldRelease, nil, s.env)
+	_, _, errs := s.ReleaseModule.Delete(oldRelease, nil, s.env)
 
 	oldRelease.Info.Status.Code = release.Status_DELETED
 	oldRelease.Info.Description = "Deletion complete"
@@ -219,14 +266,18 @@ func (s *ReleaseServer) performUpdateForce(req *services.UpdateReleaseRequest) (
 
 	// post-delete hooks
 	if !req.DisableHooks {
-		if err := s.execHook(oldRelease.Hooks, oldRelease.Name, oldRelease.Namespace, hooks.PostDelete, req.Timeout); err != nil {
+		results, err := s.execHookWithResults(c, oldRelease.Hooks, oldRelease.Name, oldRelease.Namespace, hooks.PostDelete, req.Timeout)
+		res.HookResults = append(res.HookResults, results...)
+		if err != nil {
 			return res, err
 		}
 	}
 
 	// pre-install hooks
 	if !req.DisableHooks {
-		if err := s.execHook(newRelease.Hooks, newRelease.Name, newRelease.Namespace, hooks.PreInstall, req.Timeout); err != nil {
+		results, err := s.execHookWithResults(c, newRelease.Hooks, newRelease.Name, newRelease.Namespace, hooks.PreInstall, req.Timeout)
+		res.HookResults = append(res.HookResults, results...)
+		if err != nil {
 			return res, err
 		}
 	}
@@ -243,7 +294,9 @@ func (s *ReleaseServer) performUpdateForce(req *services.UpdateReleaseRequest) (
 
 	// post-install hooks
 	if !req.DisableHooks {
-		if err := s.execHook(newRelease.Hooks, newRelease.Name, newRelease.Namespace, hooks.PostInstall, req.Timeout); err != nil {
+		results, err := s.execHookWithResults(c, newRelease.Hooks, newRelease.Name, newRelease.Namespace, hooks.PostInstall, req.Timeout)
+		res.HookResults = append(res.HookResults, results...)
+		if err != nil {
 			msg := fmt.Sprintf("Release %q failed post-install: %s", newRelease.Name, err)
 			s.Log("warning: %s", msg)
 			newRelease.Info.Status.Code = release.Status_FAILED
@@ -264,7 +317,7 @@ func (s *ReleaseServer) performUpdateForce(req *services.UpdateReleaseRequest) (
 	return res, nil
 }
 
-func (s *ReleaseServer) performUpdate(originalRelease, updatedRelease *release.Release, req *services.UpdateReleaseRequest) (*services.UpdateReleaseResponse, error) {
+func (s *ReleaseServer) performUpdate(c ctx.Context, originalRelease, updatedRelease *release.Release, req *services.UpdateReleaseRequest) (*services.UpdateReleaseResponse, error) {
 	res := &services.UpdateReleaseResponse{Release: updatedRelease}
 
 	if req.DryRun {
@@ -275,7 +328,9 @@ func (s *ReleaseServer) performUpdate(originalRelease, updatedRelease *release.R
 
 	// pre-upgrade hooks
 	if !req.DisableHooks {
-		if err := s.execHook(updatedRelease.Hooks, updatedRelease.Name, updatedRelease.Namespace, hooks.PreUpgrade, req.Timeout); err != nil {
+		results, err := s.execHookWithResults(c, updatedRelease.Hooks, updatedRelease.Name, updatedRelease.Namespace, hooks.PreUpgrade, req.Timeout)
+		res.HookResults = append(res.HookResults, results...)
+		if err != nil {
 			return res, err
 		}
 	} else {
@@ -291,9 +346,24 @@ func (s *ReleaseServer) performUpdate(originalRelease, updatedRelease *release.R
 		return res, err
 	}
 
+	if !req.DisableHooks && requiresApproval(updatedRelease.Hooks, hooks.PostUpgrade) {
+		s.Log("post-upgrade hooks of %s are awaiting approval", updatedRelease.Name)
+		updatedRelease.Info.Status.Code = release.Status_PENDING_APPROVAL
+		updatedRelease.Info.Description = "Awaiting approval to run post-upgrade hooks"
+		return res, nil
+	}
+	return s.finishUpdate(c, originalRelease, updatedRelease, req, res)
+}
+
+// finishUpdate runs the post-upgrade hooks of an upgraded release and, if they
+// succeed, makes it replace the original release. It completes both upgrades
+// and approved upgrades that were awaiting approval.
+func (s *ReleaseServer) finishUpdate(c ctx.Context, originalRelease, updatedRelease *release.Release, req *services.UpdateReleaseRequest, res *services.UpdateReleaseResponse) (*services.UpdateReleaseResponse, error) {
 	// post-upgrade hooks
 	if !req.DisableHooks {
-		if err := s.execHook(updatedRelease.Hooks, updatedRelease.Name, updatedRelease.Namespace, hooks.PostUpgrade, req.Timeout); err != nil {
+		results, err := s.execHookWithResults(c, updatedRelease.Hooks, updatedRelease.Name, updatedRelease.Namespace, hooks.PostUpgrade, req.Timeout)
+		res.HookResults = append(res.HookResults, results...)
+		if err != nil {
 			return res, err
 		}
 	}
@@ -310,3 +380,17 @@ func (s *ReleaseServer) performUpdate(originalRelease, updatedRelease *release.R
 
 	return res, nil
 }
+
+// releaseDigest hashes what an upgrade would apply for a release: its values,
+// its rendered manifest and its hooks.
+func releaseDigest(rel *release.Release) string {
+	h := sha256.New()
+	io.WriteString(h, rel.Config.GetRaw())
+	io.WriteString(h, "\x00")
+	io.WriteString(h, rel.Manifest)
+	for _, hook := range rel.Hooks {
+		io.WriteString(h, "\x00")
+		io.WriteString(h, hook.Manifest)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
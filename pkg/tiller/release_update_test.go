@@ -538,6 +538,39 @@ func TestUpdateReleaseNoChanges(t *testing.T) {
 	}
 }
 
+func TestUpdateReleaseSkipUnchanged(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.SkipUnchangedUpgrades = true
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+
+	upgrade := func(values string) *release.Release {
+		res, err := rs.UpdateRelease(c, &services.UpdateReleaseRequest{
+			Name:   rel.Name,
+			Chart:  rel.GetChart(),
+			Values: &chart.Config{Raw: values},
+		})
+		if err != nil {
+			t.Fatalf("Failed updated: %s", err)
+		}
+		return res.Release
+	}
+
+	if v := upgrade("name: value").Version; v != 2 {
+		t.Fatalf("Expected first upgrade to create version 2, got %d", v)
+	}
+	if v := upgrade("name: value").Version; v != 2 {
+		t.Errorf("Expected unchanged upgrade to return version 2, got %d", v)
+	}
+	if h, _ := rs.env.Releases.History(rel.Name); len(h) != 2 {
+		t.Errorf("Expected unchanged upgrade not to create a version, got %d versions", len(h))
+	}
+	if v := upgrade("name: other").Version; v != 3 {
+		t.Errorf("Expected upgrade with changed values to create version 3, got %d", v)
+	}
+}
+
 func TestUpdateReleaseCustomDescription(t *testing.T) {
 	c := helm.NewContext()
 	rs := rsFixture()
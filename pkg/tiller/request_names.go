@@ -0,0 +1,81 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"sync"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/metadata"
+)
+
+// requestIDHeader is the gRPC metadata key clients use to identify a request
+// across retries.
+const requestIDHeader = "x-helm-request-id"
+
+// maxGeneratedNames bounds the number of request ids whose generated release
+// name is remembered. The oldest entries are forgotten first.
+const maxGeneratedNames = 1024
+
+func requestIDFromContext(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v, ok := md[requestIDHeader]; ok && len(v) > 0 {
+			return v[0]
+		}
+	}
+	return ""
+}
+
+// generatedNames remembers the release names generated for nameless installs,
+// keyed by request id, so that a retried request is granted the same name
+// instead of leaving a trail of randomly named failed releases behind.
+type generatedNames struct {
+	mu    sync.Mutex
+	names map[string]string
+	order []string
+}
+
+// get returns the name previously generated for id, if any.
+func (g *generatedNames) get(id string) (string, bool) {
+	if g == nil {
+		return "", false
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	name, ok := g.names[id]
+	return name, ok
+}
+
+// put records name as generated for id.
+func (g *generatedNames) put(id, name string) {
+	if g == nil {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.names == nil {
+		g.names = map[string]string{}
+	}
+	if _, ok := g.names[id]; !ok {
+		g.order = append(g.order, id)
+	}
+	g.names[id] = name
+	for len(g.order) > maxGeneratedNames {
+		delete(g.names, g.order[0])
+		g.order = g.order[1:]
+	}
+}
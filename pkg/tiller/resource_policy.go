@@ -18,6 +18,7 @@ package tiller
 
 import (
 	"bytes"
+	"sort"
 	"strings"
 
 	"k8s.io/helm/pkg/kube"
@@ -43,8 +44,13 @@ func filterManifestsToKeep(manifests []Manifest) ([]Manifest, []Manifest) {
 	return keep, remaining
 }
 
-func summarizeKeptManifests(manifests []Manifest, kubeClient environment.KubeClient, namespace string) string {
-	var message string
+// summarizeKeptManifests returns a message listing the manifests that are
+// still present in the cluster, and the same resources as sorted "Kind/name".
+func summarizeKeptManifests(manifests []Manifest, kubeClient environment.KubeClient, namespace string) (string, []string) {
+	var (
+		message string
+		kept    []string
+	)
 	for _, m := range manifests {
 		// check if m is in fact present from k8s client's POV.
 		output, err := kubeClient.Get(namespace, bytes.NewBufferString(m.Content))
@@ -57,6 +63,8 @@ func summarizeKeptManifests(manifests []Manifest, kubeClient environment.KubeCli
 			message = "These resources were kept due to the resource policy:\n"
 		}
 		message = message + details
+		kept = append(kept, m.Head.Kind+"/"+m.Head.Metadata.Name)
 	}
-	return message
+	sort.Strings(kept)
+	return message, kept
 }
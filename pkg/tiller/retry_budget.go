@@ -0,0 +1,123 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	ctx "golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// retryBackoff is how long to wait before the first retry of a failed
+// operation. It doubles with each further retry.
+var retryBackoff = 500 * time.Millisecond
+
+// RetryBudget is a token bucket shared by every operation Tiller retries, so
+// that a broad outage cannot turn into a storm of retries. Each retry takes a
+// token; tokens are refilled at a steady rate up to the bucket's size.
+type RetryBudget struct {
+	mu     sync.Mutex
+	tokens float64
+	max    float64
+	rate   float64 // tokens per second
+	last   time.Time
+	now    func() time.Time
+}
+
+// NewRetryBudget creates a budget allowing perMinute retries every minute,
+// all of which may be spent at once.
+func NewRetryBudget(perMinute int) *RetryBudget {
+	return &RetryBudget{
+		tokens: float64(perMinute),
+		max:    float64(perMinute),
+		rate:   float64(perMinute) / 60,
+		last:   time.Now(),
+		now:    time.Now,
+	}
+}
+
+// Allow takes a token from the budget, reporting whether a retry may be made.
+// A nil budget allows none.
+func (b *RetryBudget) Allow() bool {
+	if b == nil {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.max {
+		b.tokens = b.max
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// retry calls fn until it succeeds, fails with an error that is not
+// transient, or has been called attempts times, backing off between calls.
+// Every retry is paid for from the server's RetryBudget; once that is spent,
+// the last error is returned without waiting.
+func (s *ReleaseServer) retry(attempts int, fn func() error) error {
+	backoff := retryBackoff
+	err := fn()
+	for i := 1; err != nil && isTransient(err) && i < attempts; i++ {
+		if !s.RetryBudget.Allow() {
+			break
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+		err = fn()
+	}
+	return err
+}
+
+// isTransient reports whether err may go away if the operation is retried:
+// the server was unavailable, overloaded or timed out, the network failed, or
+// the object was changed concurrently. Errors such as a release that already
+// exists or an invalid request are not.
+func isTransient(err error) bool {
+	if err == ctx.DeadlineExceeded {
+		return true
+	}
+	if _, ok := err.(net.Error); ok {
+		return true
+	}
+	if st, ok := status.FromError(err); ok {
+		switch st.Code() {
+		case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+			return true
+		}
+		return false
+	}
+	if apiErr, ok := err.(apierrors.APIStatus); ok {
+		// AlreadyExists shares the 409 code with Conflict, so match the reason.
+		code := apiErr.Status().Code
+		return apierrors.IsConflict(err) || code == http.StatusTooManyRequests || code >= 500 ||
+			apierrors.IsServerTimeout(err) || apierrors.IsTimeout(err)
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"k8s.io/helm/pkg/storage/driver"
+)
+
+func TestRetryBudgetExhausted(t *testing.T) {
+	defer func(b time.Duration) { retryBackoff = b }(retryBackoff)
+	retryBackoff = time.Millisecond
+
+	now := time.Now()
+	rs := rsFixture()
+	rs.RetryBudget = NewRetryBudget(2)
+	rs.RetryBudget.now = func() time.Time { return now }
+	rs.RetryBudget.last = now
+
+	calls := 0
+	failing := func() error {
+		calls++
+		return status.Error(codes.Unavailable, "storage unavailable")
+	}
+
+	// The first operation spends the whole budget on its retries.
+	if err := rs.retry(3, failing); err == nil {
+		t.Fatal("Expected the operation to fail")
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 attempts while the budget lasts, got %d", calls)
+	}
+
+	// The next fails fast, without retrying.
+	calls = 0
+	if err := rs.retry(3, failing); err == nil {
+		t.Fatal("Expected the operation to fail")
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 attempt once the budget is spent, got %d", calls)
+	}
+
+	// The budget refills over time.
+	now = now.Add(30 * time.Second)
+	calls = 0
+	rs.retry(3, failing)
+	if calls != 2 {
+		t.Errorf("Expected 2 attempts after half a minute, got %d", calls)
+	}
+
+	// Without a budget nothing is retried.
+	rs.RetryBudget = nil
+	calls = 0
+	rs.retry(3, failing)
+	if calls != 1 {
+		t.Errorf("Expected 1 attempt without a budget, got %d", calls)
+	}
+}
+
+func TestRetryOnlyTransientErrors(t *testing.T) {
+	defer func(b time.Duration) { retryBackoff = b }(retryBackoff)
+	retryBackoff = time.Millisecond
+
+	rs := rsFixture()
+	cm := schema.GroupResource{Resource: "configmaps"}
+	tests := []struct {
+		err   error
+		calls int
+	}{
+		{status.Error(codes.Unavailable, "unavailable"), 3},
+		{apierrors.NewConflict(cm, "sh.helm.v1", errors.New("changed")), 3},
+		{apierrors.NewTooManyRequests("slow down", 1), 3},
+		{apierrors.NewInternalError(errors.New("etcd")), 3},
+		{&net.OpError{Op: "dial", Err: errors.New("connection refused")}, 3},
+		{driver.ErrReleaseExists("angry-bird"), 1},
+		{apierrors.NewAlreadyExists(cm, "sh.helm.v1"), 1},
+		{status.Error(codes.InvalidArgument, "invalid"), 1},
+		{errors.New("unknown"), 1},
+	}
+	for _, tt := range tests {
+		rs.RetryBudget = NewRetryBudget(10)
+		calls := 0
+		rs.retry(3, func() error {
+			calls++
+			return tt.err
+		})
+		if calls != tt.calls {
+			t.Errorf("%v: expected %d attempts, got %d", tt.err, tt.calls, calls)
+		}
+	}
+}
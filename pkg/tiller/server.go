@@ -21,11 +21,14 @@ import (
 	"log"
 	"strings"
 
+	"github.com/golang/protobuf/proto"
 	goprom "github.com/grpc-ecosystem/go-grpc-prometheus"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
+	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/version"
 )
 
@@ -35,21 +38,35 @@ const maxMsgSize = 1024 * 1024 * 20
 
 // DefaultServerOpts returns the set of default grpc ServerOption's that Tiller requires.
 func DefaultServerOpts() []grpc.ServerOption {
-	return []grpc.ServerOption{
-		grpc.MaxRecvMsgSize(maxMsgSize),
-		grpc.MaxSendMsgSize(maxMsgSize),
-		grpc.UnaryInterceptor(newUnaryInterceptor()),
-		grpc.StreamInterceptor(newStreamInterceptor()),
-	}
+	return serverOpts(nil)
 }
 
 // NewServer creates a new grpc server.
 func NewServer(opts ...grpc.ServerOption) *grpc.Server {
-	return grpc.NewServer(append(DefaultServerOpts(), opts...)...)
+	return NewAuthenticatedServer(nil, opts...)
+}
+
+// NewAuthenticatedServer creates a new grpc server that rejects the calls auth
+// does not accept, except for health checks. A nil auth accepts every call.
+func NewAuthenticatedServer(auth Authenticator, opts ...grpc.ServerOption) *grpc.Server {
+	return grpc.NewServer(append(serverOpts(auth), opts...)...)
+}
+
+func serverOpts(auth Authenticator) []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.MaxRecvMsgSize(maxMsgSize),
+		grpc.MaxSendMsgSize(maxMsgSize),
+		grpc.UnaryInterceptor(newUnaryInterceptor(auth)),
+		grpc.StreamInterceptor(newStreamInterceptor(auth)),
+	}
 }
 
-func newUnaryInterceptor() grpc.UnaryServerInterceptor {
+func newUnaryInterceptor(auth Authenticator) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		if err := authenticate(ctx, auth, info.FullMethod); err != nil {
+			log.Println(err)
+			return nil, err
+		}
 		if err := checkClientVersion(ctx); err != nil {
 			// whitelist GetVersion() from the version check
 			if _, m := splitMethod(info.FullMethod); m != "GetVersion" {
@@ -57,12 +74,43 @@ func newUnaryInterceptor() grpc.UnaryServerInterceptor {
 				return nil, err
 			}
 		}
-		return goprom.UnaryServerInterceptor(ctx, req, info, handler)
+		resp, err = goprom.UnaryServerInterceptor(ctx, req, info, handler)
+		return resp, withHookResults(resp, err)
 	}
 }
 
-func newStreamInterceptor() grpc.StreamServerInterceptor {
+// hookResulter is implemented by the responses of the operations that run
+// hooks.
+type hookResulter interface {
+	GetHookResults() []*release.HookResult
+}
+
+// withHookResults attaches the hook results of resp to err as status details.
+// gRPC drops the response of a failed call, which would otherwise lose the
+// results of the hooks that ran before it failed.
+func withHookResults(resp interface{}, err error) error {
+	r, ok := resp.(hookResulter)
+	if err == nil || !ok || len(r.GetHookResults()) == 0 {
+		return err
+	}
+	details := make([]proto.Message, 0, len(r.GetHookResults()))
+	for _, result := range r.GetHookResults() {
+		details = append(details, result)
+	}
+	st, detailsErr := status.Convert(err).WithDetails(details...)
+	if detailsErr != nil {
+		log.Printf("warning: failed to attach hook results to %q: %s", err, detailsErr)
+		return err
+	}
+	return st.Err()
+}
+
+func newStreamInterceptor(auth Authenticator) grpc.StreamServerInterceptor {
 	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := authenticate(ss.Context(), auth, info.FullMethod); err != nil {
+			log.Println(err)
+			return err
+		}
 		if err := checkClientVersion(ss.Context()); err != nil {
 			log.Println(err)
 			return err
@@ -71,6 +119,17 @@ func newStreamInterceptor() grpc.StreamServerInterceptor {
 	}
 }
 
+// authenticate has auth check a call to fullMethod, if auth is set.
+func authenticate(ctx context.Context, auth Authenticator, fullMethod string) error {
+	if auth == nil {
+		return nil
+	}
+	if s, _ := splitMethod(fullMethod); s == healthService {
+		return nil
+	}
+	return auth(ctx)
+}
+
 func splitMethod(fullMethod string) (string, string) {
 	if frags := strings.Split(fullMethod, "/"); len(frags) == 3 {
 		return frags[1], frags[2]
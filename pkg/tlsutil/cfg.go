@@ -38,6 +38,28 @@ type Options struct {
 	ServerName string
 	// Server-only options
 	ClientAuth tls.ClientAuthType
+	// MinVersion is the lowest TLS version the server accepts. It defaults
+	// to TLS 1.2.
+	MinVersion uint16
+	// CipherSuites restricts the cipher suites the server negotiates for
+	// TLS 1.2 and earlier. If empty, Go's defaults are used.
+	CipherSuites []uint16
+	// ReloadCertificates makes the server load CertFile and KeyFile again
+	// when they change on disk, so rotated certificates are picked up by new
+	// connections without a restart.
+	ReloadCertificates bool
+	// SessionTicketsDisabled stops the server from issuing session tickets,
+	// so every connection does a full handshake.
+	SessionTicketsDisabled bool
+	// OCSPStapleFile is a DER-encoded OCSP response to staple to the server
+	// certificate. It is read again when it changes if ReloadCertificates
+	// is set.
+	OCSPStapleFile string
+	// OCSPResponder is the URL of an OCSP responder to fetch the staple from
+	// when OCSPStapleFile is not set. CertFile must then contain the issuer
+	// after the server certificate. The response is fetched again when its
+	// next update is due.
+	OCSPResponder string
 }
 
 // ClientConfig returns a TLS configuration for use by a Helm client.
@@ -69,10 +91,10 @@ func ClientConfig(opts Options) (cfg *tls.Config, err error) {
 
 // ServerConfig returns a TLS configuration for use by the Tiller server.
 func ServerConfig(opts Options) (cfg *tls.Config, err error) {
-	var cert *tls.Certificate
+	var reloader *certReloader
 	var pool *x509.CertPool
 
-	if cert, err = CertFromFilePair(opts.CertFile, opts.KeyFile); err != nil {
+	if reloader, err = newCertReloader(opts.CertFile, opts.KeyFile); err != nil {
 		if os.IsNotExist(err) {
 			return nil, fmt.Errorf("could not load x509 key pair (cert: %q, key: %q): %v", opts.CertFile, opts.KeyFile, err)
 		}
@@ -84,6 +106,37 @@ func ServerConfig(opts Options) (cfg *tls.Config, err error) {
 		}
 	}
 
-	cfg = &tls.Config{MinVersion: tls.VersionTLS12, ClientAuth: opts.ClientAuth, Certificates: []tls.Certificate{*cert}, ClientCAs: pool}
+	minVersion := opts.MinVersion
+	if minVersion == 0 {
+		minVersion = tls.VersionTLS12
+	}
+
+	cfg = &tls.Config{
+		MinVersion:             minVersion,
+		CipherSuites:           opts.CipherSuites,
+		SessionTicketsDisabled: opts.SessionTicketsDisabled,
+		ClientAuth:             opts.ClientAuth,
+		ClientCAs:              pool,
+	}
+
+	getCertificate := reloader.getCertificate
+	if !opts.ReloadCertificates {
+		getCertificate = func(*tls.ClientHelloInfo) (*tls.Certificate, error) { return reloader.cert, nil }
+	}
+	if opts.OCSPStapleFile != "" || opts.OCSPResponder != "" {
+		s := &stapler{get: getCertificate, file: opts.OCSPStapleFile, responder: opts.OCSPResponder}
+		if err = s.refresh(reloader.cert); err != nil {
+			return nil, fmt.Errorf("could not load OCSP staple: %v", err)
+		}
+		getCertificate = s.getCertificate
+	}
+
+	// A certificate that never changes is served as is.
+	if !opts.ReloadCertificates && opts.OCSPResponder == "" {
+		cert, _ := getCertificate(nil)
+		cfg.Certificates = []tls.Certificate{*cert}
+	} else {
+		cfg.GetCertificate = getCertificate
+	}
 	return cfg, nil
 }
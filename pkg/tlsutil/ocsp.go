@@ -0,0 +1,199 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tlsutil
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+// ocspClient is the HTTP client used to query OCSP responders.
+var ocspClient = &http.Client{Timeout: 10 * time.Second}
+
+// ocspRefresh is how long a fetched response is stapled when the responder
+// does not say when the next update is due.
+const ocspRefresh = time.Hour
+
+// ocspRetry is how long renewing a staple waits after a failure. It doubles
+// with each consecutive failure, up to ocspRefresh.
+var ocspRetry = time.Minute
+
+// stapler attaches an OCSP response to the certificates returned by get.
+// The response is read from file, again whenever the file changes, or
+// fetched from responder, again once it is due for an update. Either is also
+// renewed when get returns a different certificate.
+type stapler struct {
+	get       func(*tls.ClientHelloInfo) (*tls.Certificate, error)
+	file      string
+	responder string
+
+	mu         sync.Mutex
+	leaf       []byte // the certificate the staple is for
+	staple     []byte
+	mod        time.Time
+	next       time.Time
+	refreshing bool      // a renewal is under way
+	failures   int       // consecutive failed renewals
+	retry      time.Time // no renewal is attempted before then
+}
+
+// getCertificate implements tls.Config.GetCertificate. A staple that is due
+// is renewed in the background, so handshakes never wait for the responder.
+// Until the renewal succeeds, the previous staple keeps being served as long
+// as the certificate is the same; a new certificate is served without one.
+func (s *stapler) getCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	cert, err := s.get(hello)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.refreshing && !time.Now().Before(s.retry) && s.due(cert) {
+		s.refreshing = true
+		go s.refreshInBackground(cert)
+	}
+	c := *cert
+	if s.leaf != nil && bytes.Equal(s.leaf, cert.Certificate[0]) {
+		c.OCSPStaple = s.staple
+	}
+	return &c, nil
+}
+
+// due reports whether the staple for cert is missing or out of date. The
+// caller must hold s.mu.
+func (s *stapler) due(cert *tls.Certificate) bool {
+	if s.leaf == nil || !bytes.Equal(s.leaf, cert.Certificate[0]) {
+		return true
+	}
+	if s.file != "" {
+		info, err := os.Stat(s.file)
+		return err != nil || !info.ModTime().Equal(s.mod)
+	}
+	return !time.Now().Before(s.next)
+}
+
+// refreshInBackground renews the staple for cert, backing off after failures.
+func (s *stapler) refreshInBackground(cert *tls.Certificate) {
+	err := s.refresh(cert)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refreshing = false
+	if err == nil {
+		s.failures, s.retry = 0, time.Time{}
+		return
+	}
+	s.failures++
+	wait := ocspRefresh
+	if s.failures < 16 && ocspRetry<<uint(s.failures-1) < ocspRefresh {
+		wait = ocspRetry << uint(s.failures-1)
+	}
+	s.retry = time.Now().Add(wait)
+}
+
+// refresh renews the staple for cert. The caller must not hold s.mu, which is
+// only taken to store the renewed staple.
+func (s *stapler) refresh(cert *tls.Certificate) error {
+	if s.file != "" {
+		info, err := os.Stat(s.file)
+		if err != nil {
+			return err
+		}
+		staple, err := ioutil.ReadFile(s.file)
+		if err != nil {
+			return err
+		}
+		s.mu.Lock()
+		s.leaf, s.staple, s.mod = cert.Certificate[0], staple, info.ModTime()
+		s.mu.Unlock()
+		return nil
+	}
+
+	staple, next, err := fetchStaple(s.responder, cert)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.leaf, s.staple, s.next = cert.Certificate[0], staple, next
+	s.mu.Unlock()
+	return nil
+}
+
+// fetchStaple asks responder for the status of cert, whose chain must include
+// its issuer, and returns the response along with when it should be renewed.
+func fetchStaple(responder string, cert *tls.Certificate) ([]byte, time.Time, error) {
+	if len(cert.Certificate) < 2 {
+		return nil, time.Time{}, errors.New("the certificate chain must include the issuer to request an OCSP response")
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	issuer, err := x509.ParseCertificate(cert.Certificate[1])
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	req, err := ocsp.CreateRequest(leaf, issuer, nil)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	resp, err := ocspClient.Post(responder, "application/ocsp-request", bytes.NewReader(req))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, time.Time{}, fmt.Errorf("OCSP responder returned %s", resp.Status)
+	}
+	staple, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	parsed, err := ocsp.ParseResponseForCert(staple, leaf, issuer)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("invalid OCSP response: %v", err)
+	}
+	if parsed.Status != ocsp.Good {
+		return nil, time.Time{}, fmt.Errorf("OCSP responder reports the certificate as %s", ocspStatus(parsed.Status))
+	}
+	next := parsed.NextUpdate
+	if next.IsZero() {
+		next = time.Now().Add(ocspRefresh)
+	}
+	return staple, next, nil
+}
+
+func ocspStatus(status int) string {
+	switch status {
+	case ocsp.Revoked:
+		return "revoked"
+	case ocsp.Unknown:
+		return "unknown"
+	}
+	return "good"
+}
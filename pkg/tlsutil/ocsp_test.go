@@ -0,0 +1,265 @@
+/*
+Copyright The Helm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tlsutil
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+func TestServerConfigSessionTicketsAndStaple(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tlsutil-ocsp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	staple := []byte("stapled response")
+	stapleFile := filepath.Join(dir, "ocsp.der")
+	if err := ioutil.WriteFile(stapleFile, staple, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := ServerConfig(Options{
+		CertFile:               testfile(t, testCertFile),
+		KeyFile:                testfile(t, testKeyFile),
+		SessionTicketsDisabled: true,
+		OCSPStapleFile:         stapleFile,
+	})
+	if err != nil {
+		t.Fatalf("error building tls server config: %v", err)
+	}
+	if !cfg.SessionTicketsDisabled {
+		t.Error("expecting session tickets to be disabled")
+	}
+	if len(cfg.Certificates) != 1 {
+		t.Fatalf("expecting 1 server certificate, got %d", len(cfg.Certificates))
+	}
+	if got := cfg.Certificates[0].OCSPStaple; !bytes.Equal(got, staple) {
+		t.Errorf("expecting staple %q, got %q", staple, got)
+	}
+
+	if _, err := ServerConfig(Options{
+		CertFile:       testfile(t, testCertFile),
+		KeyFile:        testfile(t, testKeyFile),
+		OCSPStapleFile: filepath.Join(dir, "missing.der"),
+	}); err == nil {
+		t.Error("expecting an error for a missing staple file")
+	}
+}
+
+func TestServerConfigOCSPResponder(t *testing.T) {
+	ca, caKey, der, caDER, key := newTestChain(t)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "tlsutil-ocsp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	chain := append(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})...)
+	if err := ioutil.WriteFile(certFile, chain, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var served []byte
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		body, _ := ioutil.ReadAll(r.Body)
+		req, err := ocsp.ParseRequest(body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		served, err = ocsp.CreateResponse(ca, ca, ocsp.Response{
+			Status:       ocsp.Good,
+			SerialNumber: req.SerialNumber,
+			ThisUpdate:   time.Now().Add(-time.Minute),
+			NextUpdate:   time.Now().Add(time.Hour),
+		}, caKey)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(served)
+	}))
+	defer srv.Close()
+
+	cfg, err := ServerConfig(Options{CertFile: certFile, KeyFile: keyFile, OCSPResponder: srv.URL})
+	if err != nil {
+		t.Fatalf("error building tls server config: %v", err)
+	}
+	if cfg.GetCertificate == nil {
+		t.Fatal("expecting the certificate to be served through GetCertificate")
+	}
+	for i := 0; i < 2; i++ {
+		cert, err := cfg.GetCertificate(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(served) == 0 || !bytes.Equal(cert.OCSPStaple, served) {
+			t.Errorf("expecting the responder's response to be stapled")
+		}
+	}
+	if requests != 1 {
+		t.Errorf("expecting the staple to be fetched once before its next update, got %d requests", requests)
+	}
+}
+
+// newTestChain returns a CA and a server certificate it issued, along with
+// their keys.
+func newTestChain(t *testing.T) (ca *x509.Certificate, caKey *ecdsa.PrivateKey, der, caDER []byte, key *ecdsa.PrivateKey) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err = x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, err = x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "tiller"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err = x509.CreateCertificate(rand.Reader, tmpl, ca, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ca, caKey, der, caDER, key
+}
+
+func TestStaplerRenewsInBackground(t *testing.T) {
+	_, _, der, caDER, _ := newTestChain(t)
+	cert := &tls.Certificate{Certificate: [][]byte{der, caDER}}
+
+	var requests int32
+	unblock := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		<-unblock
+		http.Error(w, "try later", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+	defer func() {
+		select {
+		case <-unblock:
+		default:
+			close(unblock)
+		}
+	}()
+
+	s := &stapler{
+		get:       func(*tls.ClientHelloInfo) (*tls.Certificate, error) { return cert, nil },
+		responder: srv.URL,
+	}
+
+	// handshakes do not wait for a slow responder
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 3; i++ {
+			c, err := s.getCertificate(nil)
+			if err != nil {
+				t.Error(err)
+			} else if c.OCSPStaple != nil {
+				t.Errorf("expecting no staple before one was fetched, got %q", c.OCSPStaple)
+			}
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expecting handshakes not to wait for the OCSP responder")
+	}
+	close(unblock)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		s.mu.Lock()
+		refreshing := s.refreshing
+		s.mu.Unlock()
+		if !refreshing {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expecting the renewal to finish")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// after the failure, renewals back off instead of being retried by
+	// every handshake
+	for i := 0; i < 3; i++ {
+		if _, err := s.getCertificate(nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("expecting a single request to the responder, got %d", n)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures != 1 || !s.retry.After(time.Now()) {
+		t.Errorf("expecting a retry to be scheduled after the failure, got %d failures, retry at %s", s.failures, s.retry)
+	}
+}
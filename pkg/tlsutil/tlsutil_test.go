@@ -19,6 +19,7 @@ package tlsutil
 import (
 	"crypto/tls"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -74,6 +75,60 @@ func TestServerConfig(t *testing.T) {
 	}
 }
 
+func TestServerConfigHardening(t *testing.T) {
+	opts := Options{
+		CertFile:     testfile(t, testCertFile),
+		KeyFile:      testfile(t, testKeyFile),
+		MinVersion:   tls.VersionTLS13,
+		CipherSuites: []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256},
+	}
+
+	cfg, err := ServerConfig(opts)
+	if err != nil {
+		t.Fatalf("error building tls server config: %v", err)
+	}
+	if got := cfg.MinVersion; got != tls.VersionTLS13 {
+		t.Errorf("expecting TLS version 1.3, got %d", got)
+	}
+	if got := cfg.CipherSuites; len(got) != 1 || got[0] != tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256 {
+		t.Errorf("expecting only TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, got %v", got)
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	for name, expect := range map[string]uint16{"1.2": tls.VersionTLS12, "TLS1.3": tls.VersionTLS13} {
+		v, err := ParseVersion(name)
+		if err != nil {
+			t.Errorf("error parsing %q: %v", name, err)
+		}
+		if v != expect {
+			t.Errorf("expecting version %d for %q, got %d", expect, name, v)
+		}
+	}
+	if _, err := ParseVersion("1.4"); err == nil {
+		t.Error("expecting error for unknown TLS version")
+	}
+}
+
+func TestParseCipherSuites(t *testing.T) {
+	ids, err := ParseCipherSuites([]string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", " TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384"})
+	if err != nil {
+		t.Fatalf("error parsing cipher suites: %v", err)
+	}
+	expect := []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384}
+	if len(ids) != len(expect) || ids[0] != expect[0] || ids[1] != expect[1] {
+		t.Errorf("expecting %v, got %v", expect, ids)
+	}
+
+	_, err = ParseCipherSuites([]string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_BOGUS"})
+	if err == nil {
+		t.Fatal("expecting error for unknown cipher suite")
+	}
+	if !strings.Contains(err.Error(), "TLS_BOGUS") || !strings.Contains(err.Error(), "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256") {
+		t.Errorf("expecting error to name the unknown suite and list supported ones, got %q", err)
+	}
+}
+
 func testfile(t *testing.T, file string) (path string) {
 	var err error
 	if path, err = filepath.Abs(filepath.Join(tlsTestDir, file)); err != nil {